@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"neat_mobile_app_backend/internal/config"
+	"neat_mobile_app_backend/internal/database"
+	"neat_mobile_app_backend/internal/modules/reconciliation"
+)
+
+// reconcile matches a Providus settlement/statement file against
+// wallet_transactions and stores the break report for the reporting endpoints.
+//
+//	go run ./cmd/reconcile -file providus-2024-05-01.csv
+//	go run ./cmd/reconcile -file statement.json -from 2024-05-01 -to 2024-05-01
+func main() {
+	_ = godotenv.Load()
+
+	file := flag.String("file", "", "path to the settlement file (csv or json)")
+	format := flag.String("format", "", "statement format: csv or json (default: from file extension)")
+	from := flag.String("from", "", "first day of the period, YYYY-MM-DD (default: earliest line date)")
+	to := flag.String("to", "", "last day of the period, inclusive, YYYY-MM-DD (default: latest line date)")
+	timeout := flag.Duration("timeout", 10*time.Minute, "overall timeout for the run")
+	flag.Parse()
+
+	if strings.TrimSpace(*file) == "" {
+		log.Fatal("-file is required")
+	}
+
+	cfg := config.Load()
+	if strings.TrimSpace(cfg.DBUrl) == "" {
+		log.Fatal("DB_URL is required")
+	}
+
+	input := reconciliation.StatementInput{
+		Provider: reconciliation.ProviderProvidus,
+		Source:   filepath.Base(*file),
+		Format:   reconciliation.StatementFormat(strings.ToLower(strings.TrimSpace(*format))),
+	}
+	if input.Format == "" {
+		input.Format = reconciliation.FormatFromName(*file)
+	}
+
+	if v := strings.TrimSpace(*from); v != "" {
+		t, err := time.ParseInLocation(time.DateOnly, v, time.UTC)
+		if err != nil {
+			log.Fatalf("invalid -from: %v", err)
+		}
+		input.From = &t
+	}
+	if v := strings.TrimSpace(*to); v != "" {
+		t, err := time.ParseInLocation(time.DateOnly, v, time.UTC)
+		if err != nil {
+			log.Fatalf("invalid -to: %v", err)
+		}
+		t = t.AddDate(0, 0, 1)
+		input.To = &t
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("open statement: %v", err)
+	}
+	defer f.Close()
+	input.Body = f
+
+	db, err := database.NewPostgres(cfg.DBUrl)
+	if err != nil {
+		log.Fatalf("db connect failed: %v", err)
+	}
+	if err := database.Migrate(db); err != nil {
+		log.Fatalf("migration failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	service := reconciliation.NewService(reconciliation.NewRepository(db))
+	summary, err := service.ReconcileStatement(ctx, input)
+	if err != nil {
+		log.Fatalf("reconciliation failed: %v", err)
+	}
+
+	log.Printf("reconciliation complete: run=%s lines=%d matched=%d breaks=%d",
+		summary.Run.ID, summary.Run.Lines, summary.Run.Matched, summary.Run.Breaks)
+	for typ, count := range summary.BreakCounts {
+		log.Printf("  %s: %d", typ, count)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"neat_mobile_app_backend/internal/config"
+	"neat_mobile_app_backend/internal/database"
+	"neat_mobile_app_backend/internal/modules/wallet"
+)
+
+// replay-webhooks reprocesses Providus credit callbacks whose inbox rows are in
+// the failed state, or were left in received by a process that died mid-way.
+// Replays go through the same dedupe as live callbacks, so a row that was
+// credited in the meantime is only marked processed.
+func main() {
+	_ = godotenv.Load()
+
+	limit := flag.Int("limit", 100, "maximum number of failed or stale inbox rows to replay")
+	timeout := flag.Duration("timeout", 5*time.Minute, "overall timeout for the replay run")
+	flag.Parse()
+
+	cfg := config.Load()
+	if strings.TrimSpace(cfg.DBUrl) == "" {
+		log.Fatal("DB_URL is required")
+	}
+
+	db, err := database.NewPostgres(cfg.DBUrl)
+	if err != nil {
+		log.Fatalf("db connect failed: %v", err)
+	}
+	if err := database.Migrate(db); err != nil {
+		log.Fatalf("migration failed: %v", err)
+	}
+
+	// Replays never call out to the provider, so no provider client is needed.
+	walletService := wallet.NewService(wallet.NewRepository(db), nil, nil, wallet.SettlementAccount{}, nil, nil, "", nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	summary, err := walletService.ReplayFailedCreditWebhooks(ctx, *limit)
+	if err != nil {
+		log.Fatalf("replay failed: %v", err)
+	}
+
+	log.Printf("replay complete: scanned=%d processed=%d ignored=%d failed=%d",
+		summary.Scanned, summary.Processed, summary.Ignored, summary.Failed)
+}
@@ -20,6 +20,7 @@ import (
 	"neat_mobile_app_backend/internal/middleware"
 	"neat_mobile_app_backend/internal/modules/autorepayment"
 	"neat_mobile_app_backend/internal/modules/device"
+	"neat_mobile_app_backend/internal/modules/limits"
 	"neat_mobile_app_backend/internal/modules/notification"
 	"neat_mobile_app_backend/internal/modules/wallet"
 	"neat_mobile_app_backend/providers/baas"
@@ -70,6 +71,7 @@ func NewRouter(cfg config.Config) (*gin.Engine, func(), error) {
 		cbaClient,
 		notificationService,
 		settlementAccount,
+		limits.NewService(limits.NewRepository(db), cfg.TransferLimitAmount*100),
 	)
 
 	r := gin.New()
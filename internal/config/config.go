@@ -10,6 +10,12 @@ type Config struct {
 	NotificationPort           string
 	DBUrl                      string
 	JWTSecret                  string
+	JWTKeyEncryptionKey        string
+	JWTSigningAlgorithm        string
+	JWTKeyRotationDays         int
+	JWTJWKSURL                 string
+	JWTHMACAcceptUntil         string // RFC 3339; stop accepting HMAC tokens at this time once key rotation is on
+	GeoIPDatabasePath          string // MaxMind GeoLite2/GeoIP2 City .mmdb used to locate login IPs
 	Pepper                     string
 	TermiiApiKey               string
 	TermiiSenderID             string
@@ -47,8 +53,11 @@ type Config struct {
 	B2AssetsBucketName         string
 	PDFShiftAPIKey             string
 	AppName                    string
-	TransferLimitAmount        string
+	TransferLimitAmount        int64 // naira; caps every tier's per-transaction transfer limit when set
 	ActivationCapKobo          int64
+	HighValueTransferAmount    int64 // naira; transfers this large need an authenticator code from enrolled users
+	CardIssuanceFee            int64 // naira; charged by the card provider for each new card
+	DepositToleranceKobo       int64 // how far a credit may miss an expected deposit and still settle it
 
 	LoginRateLimitIPMaxAttempts    int
 	LoginRateLimitEmailMaxAttempts int
@@ -57,6 +66,10 @@ type Config struct {
 
 	WalletProvider string
 
+	TransferRequeryDelayMinutes int
+
+	PayLinkBaseURL string
+
 	XpressPublicKey  string
 	XpressPrivateKey string
 	XpressBaseURL    string
@@ -72,6 +85,12 @@ func Load() Config {
 		NotificationPort:           notificationPort,
 		DBUrl:                      getEnv("DB_URL", ""),
 		JWTSecret:                  getEnv("JWT_SECRET", ""),
+		JWTKeyEncryptionKey:        getEnv("JWT_KEY_ENCRYPTION_KEY", ""),
+		JWTSigningAlgorithm:        getEnv("JWT_SIGNING_ALGORITHM", "ES256"),
+		JWTKeyRotationDays:         getEnvInt("JWT_KEY_ROTATION_DAYS", 30),
+		JWTJWKSURL:                 getEnv("JWT_JWKS_URL", ""),
+		JWTHMACAcceptUntil:         getEnv("JWT_HMAC_ACCEPT_UNTIL", ""),
+		GeoIPDatabasePath:          getEnv("GEOIP_DB_PATH", ""),
 		Pepper:                     getEnv("PEPPER", ""),
 		TermiiApiKey:               getEnv("TERMII_APIKEY", ""),
 		TermiiSenderID:             getEnv("TERMII_SENDERID", ""),
@@ -109,8 +128,11 @@ func Load() Config {
 		B2AssetsBucketName:         getEnv("B2_ASSETS_BUCKET", ""),
 		PDFShiftAPIKey:             getEnv("PDFSHIFT_API_KEY", ""),
 		AppName:                    getEnv("APPNAME", "NeatPay"),
-		TransferLimitAmount:        getEnv("TRF_LIMIT_AMOUNT", ""),
+		TransferLimitAmount:        int64(getEnvInt("TRF_LIMIT_AMOUNT", 0)),
 		ActivationCapKobo:          int64(getEnvInt("ACTIVATION_CAP_KOBO", 2_000_000)),
+		HighValueTransferAmount:    int64(getEnvInt("HIGH_VALUE_TRANSFER_AMOUNT", 100_000)),
+		CardIssuanceFee:            int64(getEnvInt("CARD_ISSUANCE_FEE", 0)),
+		DepositToleranceKobo:       int64(getEnvInt("EXPECTED_DEPOSIT_TOLERANCE_KOBO", 100)),
 
 		LoginRateLimitIPMaxAttempts:    getEnvInt("LOGIN_RATE_LIMIT_IP_MAX_ATTEMPTS", 20),
 		LoginRateLimitEmailMaxAttempts: getEnvInt("LOGIN_RATE_LIMIT_EMAIL_MAX_ATTEMPTS", 5),
@@ -119,6 +141,10 @@ func Load() Config {
 
 		WalletProvider: getEnv("WALLET_PROVIDER", "providus"),
 
+		TransferRequeryDelayMinutes: getEnvInt("TRANSFER_REQUERY_DELAY_MINUTES", 5),
+
+		PayLinkBaseURL: getEnv("PAY_LINK_BASE_URL", "https://neatpay.app/pay"),
+
 		XpressPublicKey:  getEnv("XPRESS_PUBLIC_KEY", ""),
 		XpressPrivateKey: getEnv("XPRESS_PRIVATE_KEY", ""),
 		XpressBaseURL:    getEnv("XPRESS_BASE_URL", ""),
@@ -2,14 +2,20 @@ package database
 
 import (
 	"context"
+	"log"
 	"neat_mobile_app_backend/internal/modules/account"
 	"neat_mobile_app_backend/internal/modules/auth"
 	"neat_mobile_app_backend/internal/modules/auth/otp"
 	"neat_mobile_app_backend/internal/modules/autorepayment"
 	"neat_mobile_app_backend/internal/modules/card"
 	"neat_mobile_app_backend/internal/modules/device"
+	"neat_mobile_app_backend/internal/modules/dispute"
+	"neat_mobile_app_backend/internal/modules/kyc"
+	"neat_mobile_app_backend/internal/modules/ledger"
 	"neat_mobile_app_backend/internal/modules/loanproduct"
 	"neat_mobile_app_backend/internal/modules/neatsave"
+	"neat_mobile_app_backend/internal/modules/reconciliation"
+	"neat_mobile_app_backend/internal/modules/reporting"
 	"neat_mobile_app_backend/internal/modules/transaction"
 	"neat_mobile_app_backend/internal/modules/vas"
 	"neat_mobile_app_backend/internal/modules/wallet"
@@ -186,6 +192,31 @@ func Migrate(db *gorm.DB) error {
 		return err
 	}
 
+	// Expected deposits used to store expected_amount in naira; move them to
+	// kobo, the unit actual_amount and the matcher work in.
+	if err := db.Exec(`
+		DO $$
+		BEGIN
+			IF EXISTS (
+				SELECT 1
+				FROM information_schema.columns
+				WHERE table_schema = current_schema()
+				  AND table_name = 'wallet_expected_deposits'
+				  AND column_name = 'expected_amount'
+			) THEN
+				ALTER TABLE wallet_expected_deposits
+				ADD COLUMN IF NOT EXISTS expected_amount_kobo bigint NOT NULL DEFAULT 0;
+
+				UPDATE wallet_expected_deposits
+				SET expected_amount_kobo = expected_amount * 100;
+
+				ALTER TABLE wallet_expected_deposits DROP COLUMN expected_amount;
+			END IF;
+		END $$;
+	`).Error; err != nil {
+		return err
+	}
+
 	if err := db.AutoMigrate(
 		&models.User{},
 		&models.BVNRecord{},
@@ -194,11 +225,15 @@ func Migrate(db *gorm.DB) error {
 		&models.NotificationTicket{},
 		&models.AuthSession{},
 		&models.RefreshToken{},
+		&models.JWTSigningKey{},
 		&models.VerificationRecord{},
 		&models.FaceCheckRecord{},
 		&auth.RegistrationJob{},
+		&auth.LoginRiskAssessment{},
 		&models.PendingDeviceSession{},
 		&otp.OTPModel{},
+		&otp.TOTPEnrollment{},
+		&otp.TOTPRecoveryCode{},
 		&device.UserDevice{},
 		&device.DeviceChallenge{},
 		&loanproduct.LoanProduct{},
@@ -208,8 +243,21 @@ func Migrate(db *gorm.DB) error {
 		&loanproduct.CustomerEvent{},
 		&wallet.CustomerWallet{},
 		&transaction.Transaction{},
+		&transaction.CategoryBudget{},
 		&wallet.Beneficiary{},
 		&wallet.ExpectedDeposit{},
+		&wallet.WebhookInbox{},
+		&wallet.ScheduledTransfer{},
+		&wallet.ScheduledTransferRun{},
+		&wallet.TransferBatch{},
+		&wallet.PaymentRequest{},
+		&wallet.BankEntry{},
+		&wallet.AccountNameLookup{},
+		&wallet.WalletStatusEvent{},
+		&kyc.TierUpgradeRequest{},
+		&dispute.Dispute{},
+		&dispute.DisputeAttachment{},
+		&dispute.DisputeEvent{},
 		&account.AccountReportJob{},
 		&neatsave.SavingsGoal{},
 		&neatsave.AutoSaveRule{},
@@ -217,10 +265,64 @@ func Migrate(db *gorm.DB) error {
 		&autorepayment.AutoRepaymentAttempt{},
 		&card.Card{},
 		&vas.VASBeneficiary{},
+		&ledger.Account{},
+		&ledger.JournalEntry{},
+		&ledger.Posting{},
+		&reconciliation.Run{},
+		&reconciliation.Break{},
+		&reporting.BalanceSyncRun{},
+		&reporting.BalanceDrift{},
 	); err != nil {
 		return err
 	}
 
+	if err := ledger.SeedSystemAccounts(db); err != nil {
+		return err
+	}
+
+	// A provider reference may only ever credit a wallet once. Credits that
+	// were already duplicated before this rule existed are kept for review
+	// but moved out of the way of the index first.
+	if err := quarantineDuplicateCredits(db); err != nil {
+		return err
+	}
+	if err := db.Exec(`
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_wallet_transactions_credit_provider_reference
+		ON wallet_transactions (provider_reference)
+		WHERE type = 'credit' AND provider_reference <> ''
+	`).Error; err != nil {
+		return err
+	}
+
+	// Webhook credits used to be stored without a category, which kept them
+	// out of settlement reconciliation.
+	if err := db.Exec(`
+		UPDATE wallet_transactions
+		SET transaction_category = 'transfer_from',
+			source = CASE WHEN source = '' THEN 'credit' ELSE source END
+		WHERE type = 'credit' AND transaction_category = '' AND provider_reference <> ''
+	`).Error; err != nil {
+		return err
+	}
+
+	// Open payment requests from before deposits carried their code.
+	if err := db.Exec(`
+		UPDATE wallet_expected_deposits d
+		SET payment_request_code = p.code
+		FROM wallet_payment_requests p
+		WHERE p.expected_deposit_id = d.id AND COALESCE(d.payment_request_code, '') = ''
+	`).Error; err != nil {
+		return err
+	}
+
+	if err := db.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_wallet_transactions_batch_id
+		ON wallet_transactions ((metadata->>'batch_id'))
+		WHERE metadata->>'batch_id' IS NOT NULL
+	`).Error; err != nil {
+		return err
+	}
+
 	if err := db.Exec(`
 		DO $$
 		BEGIN
@@ -533,3 +635,43 @@ func Migrate(db *gorm.DB) error {
 
 	return nil
 }
+
+// quarantineDuplicateCredits keeps the earliest credit for each provider
+// reference and tags the rest: their reference gets a "#duplicate:<id>"
+// suffix and their metadata points at the credit that was kept, so they can be
+// found and reversed by hand. It does nothing once the unique index exists.
+func quarantineDuplicateCredits(db *gorm.DB) error {
+	var indexed int64
+	if err := db.Raw(`
+		SELECT COUNT(*) FROM pg_indexes
+		WHERE schemaname = current_schema()
+		  AND indexname = 'idx_wallet_transactions_credit_provider_reference'
+	`).Scan(&indexed).Error; err != nil {
+		return err
+	}
+	if indexed > 0 {
+		return nil
+	}
+
+	result := db.Exec(`
+		UPDATE wallet_transactions t
+		SET provider_reference = t.provider_reference || '#duplicate:' || t.id,
+			metadata = COALESCE(t.metadata, '{}'::jsonb) || jsonb_build_object('duplicate_credit_of', d.kept_id)
+		FROM (
+			SELECT id,
+				first_value(id) OVER w AS kept_id,
+				row_number() OVER w AS rn
+			FROM wallet_transactions
+			WHERE type = 'credit' AND provider_reference <> ''
+			WINDOW w AS (PARTITION BY provider_reference ORDER BY created_at, id)
+		) d
+		WHERE t.id = d.id AND d.rn > 1
+	`)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("database: quarantined %d duplicate provider credits for review", result.RowsAffected)
+	}
+	return nil
+}
@@ -88,6 +88,9 @@ var (
 	ErrCreatingSavingsGoal             = errors.New("Failed to create savings goal")
 	ErrFetchingUserGoals               = errors.New("Failed to fetch user goals")
 	ErrFetchingGoalSummary             = errors.New("Failed to fetch goal summary")
+	ErrExpectedDepositNotFound         = errors.New("Deposit request not found")
+	ErrFetchingExpectedDeposit         = errors.New("Failed to fetch deposit request")
+	ErrInitiatingDeposit               = errors.New("Failed to initiate deposit")
 	ErrSMSDeliveryFailed               = errors.New("SMS delivery failed")
 	ErrSMSServiceNotConfigured         = errors.New("SMS service not configured")
 	ErrRequestingForCard               = errors.New("Failed to request for card")
@@ -105,6 +108,71 @@ var (
 	ErrValidatingCable                 = errors.New("Failed to validate cable account")
 	ErrPayingCableBill                 = errors.New("Failed to pay cable bill")
 	ErrVASAmbiguous                    = errors.New("VAS request outcome unknown — possible timeout")
+	ErrTransferAmbiguous               = errors.New("Transfer is processing — its final status will be confirmed shortly")
+	ErrTransactionNotFound             = errors.New("Transaction not found")
+	ErrTransactionNotReversible        = errors.New("Only debit transactions that moved funds can be reversed")
+	ErrTransactionAlreadyReversed      = errors.New("Transaction has already been reversed")
+	ErrReversingTransaction            = errors.New("Failed to reverse transaction")
+	ErrTransferOutcomePending          = errors.New("Transfer is still pending at the provider and cannot be reversed yet")
+	ErrScheduledTransferNotFound       = errors.New("Scheduled transfer not found")
+	ErrInvalidSchedule                 = errors.New("Invalid schedule: check the frequency, start and end dates")
+	ErrScheduledTransferClosed         = errors.New("Scheduled transfer has been completed or cancelled")
+	ErrScheduledTransfer               = errors.New("Failed to process scheduled transfer request")
+	ErrInvalidBulkRecipients           = errors.New("One or more recipients could not be validated")
+	ErrTooManyBulkRecipients           = errors.New("A bulk transfer can have at most 100 recipients")
+	ErrTransferBatchNotFound           = errors.New("Transfer batch not found")
+	ErrFetchingTransferBatch           = errors.New("Failed to fetch transfer batch")
+	ErrP2PRecipientNotFound            = errors.New("Recipient not found")
+	ErrSelfTransfer                    = errors.New("You cannot transfer to your own wallet")
+	ErrPaymentRequestNotFound          = errors.New("Payment request not found")
+	ErrPaymentRequestClosed            = errors.New("Payment request is no longer payable")
+	ErrPaymentRequest                  = errors.New("Failed to process payment request")
+	ErrPerTransactionLimitExceeded     = errors.New("Amount exceeds your per-transaction limit")
+	ErrDailyLimitExceeded              = errors.New("Amount exceeds your daily limit")
+	ErrMonthlyLimitExceeded            = errors.New("Amount exceeds your monthly limit")
+	ErrActivationCapExceeded           = errors.New("Amount exceeds your remaining activation limit")
+	ErrCheckingLimits                  = errors.New("Failed to check transaction limits")
+	ErrTierUpgradePending              = errors.New("You already have a tier upgrade under review")
+	ErrInvalidTierUpgrade              = errors.New("Requested tier is not available for your account")
+	ErrMissingKYCDocument              = errors.New("Address proof and selfie are required")
+	ErrInvalidKYCDocument              = errors.New("Documents must be jpeg, png, webp or pdf files up to 8MB")
+	ErrNINRequired                     = errors.New("NIN is required for this upgrade")
+	ErrTierUpgradeNotFound             = errors.New("Tier upgrade request not found")
+	ErrTierUpgradeReviewed             = errors.New("Tier upgrade request has already been reviewed")
+	ErrTierUpgrade                     = errors.New("Failed to process tier upgrade request")
+	ErrBudgetNotFound                  = errors.New("Budget not found")
+	ErrInvalidBudgetCategory           = errors.New("Budgets can only be set on spending categories")
+	ErrSavingBudget                    = errors.New("Failed to save budget")
+	ErrFetchingInsights                = errors.New("Failed to fetch spending insights")
+	ErrReceiptUnavailable              = errors.New("A receipt is only available once a transaction has completed")
+	ErrGeneratingReceipt               = errors.New("Failed to generate transaction receipt")
+	ErrDisputeNotFound                 = errors.New("Dispute not found")
+	ErrDisputeAlreadyOpen              = errors.New("A dispute is already open for this transaction")
+	ErrInvalidDisputeAttachment        = errors.New("Attachments must be up to 3 JPEG, PNG, WebP or PDF files of at most 8MB each")
+	ErrDisputeClosed                   = errors.New("Dispute has already been closed")
+	ErrInvalidDisputeTransition        = errors.New("Dispute cannot move to that status")
+	ErrDispute                         = errors.New("Failed to process dispute")
+	ErrBeneficiaryNotFound             = errors.New("Beneficiary not found")
+	ErrBeneficiaryExists               = errors.New("Beneficiary already saved")
+	ErrBeneficiaryNameMismatch         = errors.New("Account name does not match the bank's records")
+	ErrUpdatingBeneficiary             = errors.New("Failed to update beneficiary")
+	ErrWalletFrozen                    = errors.New("Your wallet is frozen. Please contact support")
+	ErrWalletPostNoDebit               = errors.New("Debits are not allowed on this wallet. Please contact support")
+	ErrWalletClosed                    = errors.New("This wallet has been closed")
+	ErrRecipientWalletUnavailable      = errors.New("Recipient wallet cannot receive funds")
+	ErrInvalidWalletStatusChange       = errors.New("Wallet cannot move to that status")
+	ErrInvalidWalletStatusReason       = errors.New("Invalid wallet status reason code")
+	ErrWalletBalanceNotZero            = errors.New("Wallet still holds funds")
+	ErrClosureAccountMismatch          = errors.New("Closure payouts must go to an account in your name")
+	ErrWalletStatus                    = errors.New("Failed to update wallet status")
+	ErrSessionNotFound                 = errors.New("Session not found")
+	ErrDeviceNotFound                  = errors.New("Device not found")
+	ErrRevokingSession                 = errors.New("Failed to revoke session")
+	ErrTOTPNotEnabled                  = errors.New("Authenticator app is not enabled")
+	ErrTOTPAlreadyEnabled              = errors.New("Authenticator app is already enabled")
+	ErrTOTPEnrollmentNotFound          = errors.New("No authenticator enrollment in progress")
+	ErrInvalidTOTPCode                 = errors.New("Invalid authenticator code")
+	ErrSecondFactorRequired            = errors.New("An authenticator code is required for this transfer")
 	ErrFetchingAllCategories           = errors.New("Failed to fetch all categories")
 	ErrInvalidPhoneNumber              = errors.New("Invalid nigerian phone number")
 	ErrInvalidProductAmount            = errors.New("Product amount mismatch")
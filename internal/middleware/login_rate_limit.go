@@ -226,6 +226,17 @@ func (l *LoginRateLimiter) extractEmail(c *gin.Context) string {
 	return (phone)
 }
 
+// RecentLoginFailures returns the failed logins counted in the current window
+// for ip and for the phone number.
+func (l *LoginRateLimiter) RecentLoginFailures(ip, phone string) (ipFailures, phoneFailures int) {
+	now := l.nowFn().UTC()
+	ipFailures = l.ipAttempts.failures(normalizeIP(ip), now)
+	if normalized, err := NormalizeNigerianNumber(phone); err == nil {
+		phoneFailures = l.emailAttempts.failures(normalized, now)
+	}
+	return ipFailures, phoneFailures
+}
+
 func (l *LoginRateLimiter) nextBlockedUntil(ip, email string, now time.Time) (time.Time, bool) {
 	var blockedUntil time.Time
 	if until, blocked := l.ipAttempts.blockedUntil(ip, now); blocked {
@@ -323,6 +334,22 @@ func (s *attemptStore) recordFailure(key string, maxAttempts int, now time.Time,
 	shard.entries[key] = state
 }
 
+func (s *attemptStore) failures(key string, now time.Time) int {
+	if key == "" {
+		return 0
+	}
+
+	shard := s.shard(key)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	state, ok := shard.entries[key]
+	if !ok || state.WindowStart.IsZero() || now.Sub(state.WindowStart) > s.window {
+		return 0
+	}
+	return state.Count
+}
+
 func (s *attemptStore) reset(key string) {
 	if key == "" {
 		return
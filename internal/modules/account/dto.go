@@ -1,6 +1,9 @@
 package account
 
-import "time"
+import (
+	"neat_mobile_app_backend/internal/modules/limits"
+	"time"
+)
 
 type AccountSummaryResponse struct {
 	Data AccountSummary `json:"data"`
@@ -57,9 +60,13 @@ type GetLatestAccountStatementResponse struct {
 }
 
 type AccountLimitResponse struct {
-	ActivationCap ActivationCap `json:"activation_cap"`
-	Outflow       Outflow       `json:"out_flow"`
-	Inflow        Inflow        `json:"in_flow"`
+	Tier          int                   `json:"tier"`
+	WalletTier    int                   `json:"wallet_tier"`
+	KYCLevel      int                   `json:"kyc_level"`
+	Channels      []limits.ChannelUsage `json:"channels"`
+	ActivationCap ActivationCap         `json:"activation_cap"`
+	Outflow       Outflow               `json:"out_flow"`
+	Inflow        Inflow                `json:"in_flow"`
 }
 
 type ActivationCap struct {
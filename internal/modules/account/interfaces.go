@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"neat_mobile_app_backend/internal/modules/device"
+	"neat_mobile_app_backend/internal/modules/limits"
 	"time"
 )
 
@@ -18,3 +19,7 @@ type UploadService interface {
 type DeviceVerifier interface {
 	VerifyUserDevice(ctx context.Context, mobileUserID, deviceID string) (*device.UserDevice, error)
 }
+
+type LimitReporter interface {
+	Summary(ctx context.Context, mobileUserID string) (*limits.Summary, error)
+}
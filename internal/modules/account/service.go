@@ -28,11 +28,11 @@ type Service struct {
 	Notifier       *notification.Service
 	PDFShiftAPIKey string
 	DeviceVerifier DeviceVerifier
-	TrfLimitAmount string
+	Limits         LimitReporter
 }
 
-func NewService(repo *Repository, b2 UploadService, notifier *notification.Service, pdfShiftAPIKey string, deviceVerifier DeviceVerifier, trfLimitAmount string) *Service {
-	return &Service{Repo: repo, B2: b2, Notifier: notifier, PDFShiftAPIKey: pdfShiftAPIKey, DeviceVerifier: deviceVerifier, TrfLimitAmount: trfLimitAmount}
+func NewService(repo *Repository, b2 UploadService, notifier *notification.Service, pdfShiftAPIKey string, deviceVerifier DeviceVerifier, limitReporter LimitReporter) *Service {
+	return &Service{Repo: repo, B2: b2, Notifier: notifier, PDFShiftAPIKey: pdfShiftAPIKey, DeviceVerifier: deviceVerifier, Limits: limitReporter}
 }
 
 func (s *Service) GetAccountSummary(ctx context.Context, mobileUserID string) (*AccountSummary, error) {
@@ -524,66 +524,152 @@ func (s *Service) generatePDF(ctx context.Context, key, walletID, mobileUserID s
 		Transactions:     rows,
 	}
 
-	// render HTML template
-	tmpl, err := template.ParseFiles("templates/account_statement.html")
+	html, err := renderTemplate("templates/account_statement.html", data)
 	if err != nil {
-		log.Printf("failed to parse statement template: %v", err)
+		log.Printf("failed to render statement template: %v", err)
 		return appErr.ErrGeneratingAccountStatement
 	}
 
-	var htmlBuf bytes.Buffer
-	if err := tmpl.Execute(&htmlBuf, data); err != nil {
-		log.Printf("failed to render statement template: %v", err)
+	pdfBytes, err := s.convertHTML(ctx, html, "pdf")
+	if err != nil {
+		log.Printf("failed to convert statement to PDF: %v", err)
+		return appErr.ErrGeneratingAccountStatement
+	}
+
+	log.Printf("generatePDF: size=%d bytes for key=%s", len(pdfBytes), key)
+
+	if err := s.B2.UploadDocument(ctx, key, bytes.NewReader(pdfBytes), "application/pdf"); err != nil {
+		log.Printf("failed to upload account statement PDF to storage: %v", err)
 		return appErr.ErrGeneratingAccountStatement
 	}
 
-	// call PDFShift API
-	payload, err := json.Marshal(map[string]any{
-		"source":           htmlBuf.String(),
-		"format":           "A4",
-		"margin":           "20mm",
+	return nil
+}
+
+// GenerateTransactionReceipt renders a branded receipt for txn through the
+// same template and PDFShift pipeline as statements, stores it in the
+// documents bucket and returns a presigned link to it.
+func (s *Service) GenerateTransactionReceipt(ctx context.Context, mobileUserID string, txn *transaction.Transaction, format transaction.ReceiptFormat) (string, time.Time, error) {
+	if s.PDFShiftAPIKey == "" {
+		return "", time.Time{}, errors.New("PDF generation is not configured")
+	}
+
+	account, err := s.Repo.GetAccountSummary(ctx, mobileUserID)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("fetch account summary: %w", err)
+	}
+
+	html, err := renderTemplate("templates/transaction_receipt.html", buildReceiptTemplateData(account, txn))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("render receipt template: %w", err)
+	}
+
+	file, err := s.convertHTML(ctx, html, string(format))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("convert receipt: %w", err)
+	}
+
+	contentType := "application/pdf"
+	if format == transaction.ReceiptFormatPNG {
+		contentType = "image/png"
+	}
+	key := fmt.Sprintf("receipts/%s/%s.%s", mobileUserID, txn.ID, format)
+	if err := s.B2.UploadDocument(ctx, key, bytes.NewReader(file), contentType); err != nil {
+		return "", time.Time{}, fmt.Errorf("upload receipt: %w", err)
+	}
+
+	expiry := 15 * time.Minute
+	url, err := s.B2.PresignURL(ctx, key, expiry)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("presign receipt: %w", err)
+	}
+	return url, time.Now().Add(expiry), nil
+}
+
+func renderTemplate(path string, data any) (string, error) {
+	tmpl, err := template.ParseFiles(path)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// convertHTML turns rendered HTML into a PDF or PNG through PDFShift.
+func (s *Service) convertHTML(ctx context.Context, html, format string) ([]byte, error) {
+	body := map[string]any{
+		"source":           html,
 		"wait_for_network": true,
-	})
+	}
+	if format == "pdf" {
+		body["format"] = "A4"
+		body["margin"] = "20mm"
+	}
+	payload, err := json.Marshal(body)
 	if err != nil {
-		log.Printf("failed to build PDF request: %v", err)
-		return appErr.ErrGeneratingAccountStatement
+		return nil, fmt.Errorf("build request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.pdfshift.io/v3/convert/pdf", bytes.NewReader(payload))
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.pdfshift.io/v3/convert/"+format, bytes.NewReader(payload))
 	if err != nil {
-		log.Printf("failed to create PDF request: %v", err)
-		return appErr.ErrGeneratingAccountStatement
+		return nil, fmt.Errorf("create request: %w", err)
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.SetBasicAuth("api", s.PDFShiftAPIKey)
 
 	resp, err := http.DefaultClient.Do(httpReq)
 	if err != nil {
-		log.Printf("failed to make PDF API request: %v", err)
-		return appErr.ErrGeneratingAccountStatement
+		return nil, fmt.Errorf("send request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		log.Printf("PDF API returned status %d: %s", resp.StatusCode, string(body))
-		return appErr.ErrGeneratingAccountStatement
+		return nil, fmt.Errorf("PDFShift returned status %d: %s", resp.StatusCode, string(body))
 	}
 
-	pdfBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Printf("failed to read PDF response: %v", err)
-		return appErr.ErrGeneratingAccountStatement
+	return io.ReadAll(resp.Body)
+}
+
+func buildReceiptTemplateData(account *AccountSummaryRow, txn *transaction.Transaction) receiptTemplateData {
+	formatAmount := func(kobo int64) string {
+		return fmt.Sprintf("%.2f", float64(kobo)/100)
 	}
 
-	log.Printf("generatePDF: size=%d bytes for key=%s", len(pdfBytes), key)
+	holderName := strings.TrimSpace(account.FirstName + " " + account.LastName)
+	holder := receiptParty{Name: holderName, Account: account.AccountNumber, Bank: account.BankName}
+	counterparty := receiptParty{Name: txn.CounterpartyName, Account: txn.CounterpartyAccount, Bank: txn.CounterpartyBank}
 
-	if err := s.B2.UploadDocument(ctx, key, bytes.NewReader(pdfBytes), "application/pdf"); err != nil {
-		log.Printf("failed to upload account statement PDF to storage: %v", err)
-		return appErr.ErrGeneratingAccountStatement
+	data := receiptTemplateData{
+		TodayDate:   time.Now().Format("02 Jan 2006"),
+		Amount:      formatAmount(txn.Amount),
+		Status:      strings.ToUpper(strings.ReplaceAll(string(txn.Status), "_", " ")),
+		Type:        strings.ToUpper(string(txn.Type)),
+		Category:    transaction.TransactionCategories[txn.Category],
+		Date:        txn.CreatedAt.Format("02 Jan 2006 15:04"),
+		Reference:   txn.Reference,
+		SessionID:   txn.SessionID,
+		Description: txn.Description,
+		Sender:      holder,
+		Beneficiary: counterparty,
 	}
-
-	return nil
+	if txn.Type == transaction.TransactionTypeCredit {
+		data.Sender, data.Beneficiary = counterparty, holder
+	}
+	if txn.Charges > 0 {
+		data.Charges = formatAmount(txn.Charges)
+	}
+	if txn.VAT > 0 {
+		data.VAT = formatAmount(txn.VAT)
+	}
+	if txn.Narration != nil {
+		data.Narration = strings.TrimSpace(*txn.Narration)
+	}
+	return data
 }
 
 func (s *Service) uploadProfilePicture(ctx context.Context, file multipart.File, header multipart.FileHeader, mobileUserID string) (string, error) {
@@ -597,55 +683,54 @@ func (s *Service) uploadProfilePicture(ctx context.Context, file multipart.File,
 	return url, nil
 }
 
+// AccountLimits reports the user's tier limits per channel alongside the
+// activation cap. Amounts are in kobo.
 func (s *Service) AccountLimits(ctx context.Context, mobileUserID string) (*AccountLimitResponse, error) {
 	user, err := s.Repo.GetUser(ctx, mobileUserID)
 	if err != nil {
 		return nil, appErr.ErrUnauthorized
 	}
 
-	now := time.Now().UTC()
-	capActive := user.ActivationCapExpiresAt != nil && now.Before(*user.ActivationCapExpiresAt)
-
-	if !capActive {
-		return &AccountLimitResponse{
-			ActivationCap: ActivationCap{Active: false},
-			Outflow:       Outflow{},
-			Inflow:        Inflow{Capped: false},
-		}, nil
+	summary, err := s.Limits.Summary(ctx, mobileUserID)
+	if err != nil {
+		return nil, err
 	}
 
-	capAmount := user.ActivationCapAmount
-	capStart := user.CreatedAt
-	capEnd := *user.ActivationCapExpiresAt
-
-	outflowSpent, _ := s.Repo.SumTransactionsInWindow(ctx, mobileUserID, transaction.TransactionTypeDebit, capStart, capEnd)
-	inflowSpent, _ := s.Repo.SumTransactionsInWindow(ctx, mobileUserID, transaction.TransactionTypeCredit, capStart, capEnd)
+	resp := &AccountLimitResponse{
+		Tier:       int(summary.Tier),
+		WalletTier: int(summary.WalletTier),
+		KYCLevel:   int(summary.KYCTier),
+		Channels:   summary.Channels,
+	}
 
-	outflowRemaining := capAmount - outflowSpent
-	if outflowRemaining < 0 {
-		outflowRemaining = 0
+	activation := summary.ActivationCap
+	if !activation.Active {
+		resp.ActivationCap = ActivationCap{Active: false}
+		resp.Inflow = Inflow{Capped: false}
+		return resp, nil
 	}
-	inflowRemaining := capAmount - inflowSpent
+
+	inflowSpent, _ := s.Repo.SumTransactionsInWindow(ctx, mobileUserID, transaction.TransactionTypeCredit, user.CreatedAt, activation.ExpiresAt)
+	inflowRemaining := activation.Limit - inflowSpent
 	if inflowRemaining < 0 {
 		inflowRemaining = 0
 	}
 
-	return &AccountLimitResponse{
-		ActivationCap: ActivationCap{
-			Active:    true,
-			ExpiresAt: capEnd,
-			CapAmount: capAmount,
-			Currency:  "NGN",
-		},
-		Outflow: Outflow{
-			Limit:     capAmount,
-			Spent:     outflowSpent,
-			Remaining: outflowRemaining,
-		},
-		Inflow: Inflow{
-			Capped:    true,
-			Limit:     capAmount,
-			Remaining: inflowRemaining,
-		},
-	}, nil
+	resp.ActivationCap = ActivationCap{
+		Active:    true,
+		ExpiresAt: activation.ExpiresAt,
+		CapAmount: activation.Limit,
+		Currency:  "NGN",
+	}
+	resp.Outflow = Outflow{
+		Limit:     activation.Limit,
+		Spent:     activation.Spent,
+		Remaining: activation.Remaining,
+	}
+	resp.Inflow = Inflow{
+		Capped:    true,
+		Limit:     activation.Limit,
+		Remaining: inflowRemaining,
+	}
+	return resp, nil
 }
@@ -85,3 +85,26 @@ type statementTemplateData struct {
 	ClosingBalance   string
 	Transactions     []statementTxRow
 }
+
+type receiptParty struct {
+	Name    string
+	Account string
+	Bank    string
+}
+
+type receiptTemplateData struct {
+	TodayDate   string
+	Amount      string
+	Status      string
+	Type        string
+	Category    string
+	Date        string
+	Reference   string
+	SessionID   string
+	Description string
+	Narration   string
+	Charges     string
+	VAT         string
+	Sender      receiptParty
+	Beneficiary receiptParty
+}
@@ -107,10 +107,82 @@ type VerifiedDeviceResponse struct {
 	RefreshToken        string `json:"refresh_token"`
 }
 
+type SessionResponse struct {
+	SessionID   string     `json:"session_id"`
+	DeviceID    string     `json:"device_id,omitempty"`
+	DeviceName  string     `json:"device_name,omitempty"`
+	DeviceModel string     `json:"device_model,omitempty"`
+	OS          string     `json:"os,omitempty"`
+	OSVersion   string     `json:"os_version,omitempty"`
+	AppVersion  string     `json:"app_version,omitempty"`
+	IP          string     `json:"ip,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	IsCurrent   bool       `json:"is_current"`
+}
+
+type DeviceResponse struct {
+	DeviceID    string    `json:"device_id"`
+	DeviceName  string    `json:"device_name"`
+	DeviceModel string    `json:"device_model"`
+	OS          string    `json:"os"`
+	OSVersion   string    `json:"os_version"`
+	AppVersion  string    `json:"app_version"`
+	IP          string    `json:"ip"`
+	IsTrusted   bool      `json:"is_trusted"`
+	IsCurrent   bool      `json:"is_current"`
+	LastUsedAt  time.Time `json:"last_used_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type RevokeSessionsResponse struct {
+	Revoked int64 `json:"revoked"`
+}
+
+type TOTPStatusResponse struct {
+	Enabled                bool       `json:"enabled"`
+	ConfirmedAt            *time.Time `json:"confirmed_at,omitempty"`
+	RecoveryCodesRemaining int64      `json:"recovery_codes_remaining"`
+}
+
+type BeginTOTPEnrollmentRequest struct {
+	TransactionPin string `json:"transaction_pin" binding:"required"`
+}
+
+// BeginTOTPEnrollmentResponse carries the secret for manual entry and the
+// otpauth:// URI the app renders as a QR code.
+type BeginTOTPEnrollmentResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type ConfirmTOTPEnrollmentRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+type TOTPRecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type RequestTOTPManagementOTPResponse struct {
+	OTPID   string `json:"otp_id"`
+	Channel string `json:"channel"`
+}
+
+// ManageTOTPRequest authorises regenerating recovery codes or disabling the
+// authenticator. OTPCode may be an authenticator code, a recovery code or an
+// SMS code, depending on the channel the OTP was requested on.
+type ManageTOTPRequest struct {
+	TransactionPin string `json:"transaction_pin" binding:"required"`
+	OTPID          string `json:"otp_id" binding:"required"`
+	OTPCode        string `json:"otp_code" binding:"required"`
+}
+
 type LoginInitResponse struct {
-	Status       string `json:"status"`
-	Challenge    string `json:"challenge,omitempty"`
-	SessionToken string `json:"session_token,omitempty"`
+	Status        string `json:"status"`
+	Challenge     string `json:"challenge,omitempty"`
+	SessionToken  string `json:"session_token,omitempty"`
+	TOTPAvailable bool   `json:"totp_available,omitempty"`
 }
 
 type VerifyDeviceRequest struct {
@@ -123,6 +195,7 @@ type ForgotTransactionPinResponse struct {
 	Status  string `json:"status"`
 	Message string `json:"message"`
 	OTPID   string `json:"otp_id"`
+	Channel string `json:"channel"`
 }
 
 type VerifyForgotTransactionPinOTPRequest struct {
@@ -141,7 +214,8 @@ type ResetTransactionPinRequest struct {
 }
 
 type RequestTransactionPinChangeResponse struct {
-	OTPID string `json:"otp_id"`
+	OTPID   string `json:"otp_id"`
+	Channel string `json:"channel"`
 }
 
 type VerifyTransactionPinChangeOTPRequest struct {
@@ -164,8 +238,15 @@ type ChangeTransactionPinRequest struct {
 	ConfirmNewPin  string `json:"confirm_new_pin" binding:"required"`
 }
 
+// OTPChannelRequest is the optional body of the OTP request endpoints. An
+// empty channel means SMS.
+type OTPChannelRequest struct {
+	Channel string `json:"channel" binding:"omitempty,oneof=sms totp"`
+}
+
 type RequestChangePasswordResponse struct {
-	OTPID string `json:"otp_id"`
+	OTPID   string `json:"otp_id"`
+	Channel string `json:"channel"`
 }
 
 type VerifyPasswordChangeOTPRequest struct {
@@ -231,6 +312,7 @@ type NINValidationResponse struct {
 type NewDeviceResquest struct {
 	SessionToken string              `json:"session_token" binding:"required"`
 	OTP          string              `json:"otp" binding:"required"`
+	Channel      string              `json:"channel" binding:"omitempty,oneof=sms totp"` // totp: OTP is from the authenticator app
 	Device       DeviceRegisteration `json:"device" binding:"required"`
 }
 
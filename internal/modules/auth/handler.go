@@ -1,9 +1,12 @@
 package auth
 
 import (
+	"errors"
+	"io"
 	"log"
 	appErr "neat_mobile_app_backend/internal/errors"
 	"neat_mobile_app_backend/internal/middleware"
+	authotp "neat_mobile_app_backend/internal/modules/auth/otp"
 	"neat_mobile_app_backend/internal/response"
 	"net/http"
 	"strings"
@@ -204,9 +207,10 @@ func (h *Handler) Login(c *gin.Context) {
 	}
 
 	resp := LoginInitResponse{
-		Challenge:    loginObj.Challenge,
-		SessionToken: loginObj.SessionToken,
-		Status:       loginObj.Status,
+		Challenge:     loginObj.Challenge,
+		SessionToken:  loginObj.SessionToken,
+		Status:        loginObj.Status,
+		TOTPAvailable: loginObj.TOTPAvailable,
 	}
 
 	c.JSON(http.StatusOK, response.APIResponse[LoginInitResponse]{
@@ -676,7 +680,19 @@ func (h *Handler) ForgotTransactionPin(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.service.ForgotTransactionPin(c.Request.Context(), mobileUserID)
+	var req OTPChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.APIResponse[any]{
+			Status: "error",
+			Error: &response.APIError{
+				Code:    string(ErrCodeInvalidRequestBody),
+				Message: "Invalid request body.",
+			},
+		})
+		return
+	}
+
+	resp, err := h.service.ForgotTransactionPin(c.Request.Context(), mobileUserID, authotp.Channel(req.Channel))
 	if err != nil {
 		mapped := response.MapError(err)
 		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
@@ -816,7 +832,19 @@ func (h *Handler) RequestPasswordChange(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.service.RequestPasswordChange(c.Request.Context(), mobileUserID)
+	var req OTPChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.APIResponse[any]{
+			Status: "error",
+			Error: &response.APIError{
+				Code:    string(ErrCodeInvalidRequestBody),
+				Message: "Invalid request body.",
+			},
+		})
+		return
+	}
+
+	resp, err := h.service.RequestPasswordChange(c.Request.Context(), mobileUserID, authotp.Channel(req.Channel))
 	if err != nil {
 		mapped := response.MapError(err)
 		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
@@ -995,7 +1023,19 @@ func (h *Handler) RequestTransactionPinChange(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.service.RequestTransactionPinChange(c.Request.Context(), mobileUserID)
+	var req OTPChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, response.APIResponse[any]{
+			Status: "error",
+			Error: &response.APIError{
+				Code:    string(ErrCodeInvalidRequestBody),
+				Message: "Invalid request body.",
+			},
+		})
+		return
+	}
+
+	resp, err := h.service.RequestTransactionPinChange(c.Request.Context(), mobileUserID, authotp.Channel(req.Channel))
 	if err != nil {
 		mapped := response.MapError(err)
 		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
@@ -1257,3 +1297,279 @@ func (h *Handler) ChallengeRequest(c *gin.Context) {
 		Data:    resp,
 	})
 }
+
+func (h *Handler) ListSessions(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrUnauthorized)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{Status: "error", Error: &mapped.Error})
+		return
+	}
+	sid := strings.TrimSpace(c.GetString(middleware.SessionIDContextKey))
+
+	sessions, err := h.service.ListSessions(c.Request.Context(), mobileUserID, sid)
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{Status: "error", Error: &mapped.Error})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[[]SessionResponse]{
+		Status:  "success",
+		Message: "Sessions fetched successfully.",
+		Data:    &sessions,
+	})
+}
+
+func (h *Handler) RevokeSession(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrUnauthorized)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{Status: "error", Error: &mapped.Error})
+		return
+	}
+
+	if err := h.service.RevokeSession(c.Request.Context(), mobileUserID, c.Param("sid")); err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{Status: "error", Error: &mapped.Error})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[any]{
+		Status:  "success",
+		Message: "Session revoked successfully.",
+	})
+}
+
+func (h *Handler) RevokeOtherSessions(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	sid := strings.TrimSpace(c.GetString(middleware.SessionIDContextKey))
+	if mobileUserID == "" || sid == "" {
+		mapped := response.MapError(appErr.ErrUnauthorized)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{Status: "error", Error: &mapped.Error})
+		return
+	}
+
+	resp, err := h.service.RevokeOtherSessions(c.Request.Context(), mobileUserID, sid)
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{Status: "error", Error: &mapped.Error})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[RevokeSessionsResponse]{
+		Status:  "success",
+		Message: "Other sessions revoked successfully.",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) ListDevices(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrUnauthorized)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{Status: "error", Error: &mapped.Error})
+		return
+	}
+	sid := strings.TrimSpace(c.GetString(middleware.SessionIDContextKey))
+
+	devices, err := h.service.ListDevices(c.Request.Context(), mobileUserID, sid)
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{Status: "error", Error: &mapped.Error})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[[]DeviceResponse]{
+		Status:  "success",
+		Message: "Devices fetched successfully.",
+		Data:    &devices,
+	})
+}
+
+func (h *Handler) UntrustDevice(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrUnauthorized)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{Status: "error", Error: &mapped.Error})
+		return
+	}
+
+	resp, err := h.service.UntrustDevice(c.Request.Context(), mobileUserID, c.Param("device_id"))
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{Status: "error", Error: &mapped.Error})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[RevokeSessionsResponse]{
+		Status:  "success",
+		Message: "Device removed successfully.",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) GetTOTPStatus(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrUnauthorized)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{Status: "error", Error: &mapped.Error})
+		return
+	}
+
+	resp, err := h.service.GetTOTPStatus(c.Request.Context(), mobileUserID)
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{Status: "error", Error: &mapped.Error})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[TOTPStatusResponse]{
+		Status:  "success",
+		Message: "Authenticator status fetched successfully.",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) BeginTOTPEnrollment(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrUnauthorized)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{Status: "error", Error: &mapped.Error})
+		return
+	}
+
+	var req BeginTOTPEnrollmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		mapped := response.MapError(appErr.ErrInvalidRequestBody)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{Status: "error", Error: &mapped.Error})
+		return
+	}
+
+	resp, err := h.service.BeginTOTPEnrollment(c.Request.Context(), mobileUserID, req)
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{Status: "error", Error: &mapped.Error})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[BeginTOTPEnrollmentResponse]{
+		Status:  "success",
+		Message: "Scan the QR code with your authenticator app, then confirm with a code from it.",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) ConfirmTOTPEnrollment(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrUnauthorized)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{Status: "error", Error: &mapped.Error})
+		return
+	}
+
+	var req ConfirmTOTPEnrollmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		mapped := response.MapError(appErr.ErrInvalidRequestBody)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{Status: "error", Error: &mapped.Error})
+		return
+	}
+
+	resp, err := h.service.ConfirmTOTPEnrollment(c.Request.Context(), mobileUserID, req)
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{Status: "error", Error: &mapped.Error})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[TOTPRecoveryCodesResponse]{
+		Status:  "success",
+		Message: "Authenticator app enabled. Store your recovery codes somewhere safe.",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) RequestTOTPManagementOTP(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrUnauthorized)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{Status: "error", Error: &mapped.Error})
+		return
+	}
+
+	var req OTPChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		mapped := response.MapError(appErr.ErrInvalidRequestBody)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{Status: "error", Error: &mapped.Error})
+		return
+	}
+
+	resp, err := h.service.RequestTOTPManagementOTP(c.Request.Context(), mobileUserID, authotp.Channel(req.Channel))
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{Status: "error", Error: &mapped.Error})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[RequestTOTPManagementOTPResponse]{
+		Status:  "success",
+		Message: "OTP has been sent.",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) RegenerateTOTPRecoveryCodes(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrUnauthorized)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{Status: "error", Error: &mapped.Error})
+		return
+	}
+
+	var req ManageTOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		mapped := response.MapError(appErr.ErrInvalidRequestBody)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{Status: "error", Error: &mapped.Error})
+		return
+	}
+
+	resp, err := h.service.RegenerateTOTPRecoveryCodes(c.Request.Context(), mobileUserID, req)
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{Status: "error", Error: &mapped.Error})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[TOTPRecoveryCodesResponse]{
+		Status:  "success",
+		Message: "Recovery codes regenerated. Your old codes no longer work.",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) DisableTOTP(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrUnauthorized)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{Status: "error", Error: &mapped.Error})
+		return
+	}
+
+	var req ManageTOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		mapped := response.MapError(appErr.ErrInvalidRequestBody)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{Status: "error", Error: &mapped.Error})
+		return
+	}
+
+	if err := h.service.DisableTOTP(c.Request.Context(), mobileUserID, req); err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{Status: "error", Error: &mapped.Error})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[any]{
+		Status:  "success",
+		Message: "Authenticator app disabled.",
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"fmt"
 	"math/big"
 	"neat_mobile_app_backend/internal/timeutil"
+	"neat_mobile_app_backend/models"
 	"regexp"
 	"strings"
 	"time"
@@ -277,3 +278,9 @@ func derefString(s *string) string {
 	}
 	return *s
 }
+
+// withinRefreshReuseGrace reports whether a rotated refresh token was rotated
+// recently enough that presenting it again is a client retry.
+func withinRefreshReuseGrace(token *models.RefreshToken, now time.Time) bool {
+	return token.RevokedAt != nil && token.ReplacedByJTI != nil && now.Sub(*token.RevokedAt) < refreshReuseGrace
+}
@@ -62,3 +62,18 @@ type DeviceVerifier interface {
 type OptimusKYCValidation interface {
 	VerifyOTPWithOptimus(ctx context.Context, phone, otpToken, email, referenceID string) error
 }
+
+// IPGeolocator resolves an IP address to a rough location for login risk
+// scoring.
+type IPGeolocator interface {
+	Locate(ctx context.Context, ip string) (*GeoLocation, error)
+}
+
+// LoginAttemptHistory reports recent failed logins seen by the rate limiter.
+type LoginAttemptHistory interface {
+	RecentLoginFailures(ip, phone string) (ipFailures, phoneFailures int)
+}
+
+type SecurityNotifier interface {
+	SendToUser(ctx context.Context, userID, title, typ, body string, data map[string]any) error
+}
@@ -0,0 +1,202 @@
+package auth
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+type LoginRiskLevel string
+
+const (
+	LoginRiskLow    LoginRiskLevel = "low"
+	LoginRiskMedium LoginRiskLevel = "medium"
+	LoginRiskHigh   LoginRiskLevel = "high"
+)
+
+type LoginRiskSignal string
+
+const (
+	LoginRiskSignalNewIP                LoginRiskSignal = "new_ip"
+	LoginRiskSignalNewCountry           LoginRiskSignal = "new_country"
+	LoginRiskSignalImpossibleTravel     LoginRiskSignal = "impossible_travel"
+	LoginRiskSignalUnusualHour          LoginRiskSignal = "unusual_hour"
+	LoginRiskSignalRecentPasswordChange LoginRiskSignal = "recent_password_change"
+	LoginRiskSignalFailedAttempts       LoginRiskSignal = "failed_attempts"
+)
+
+var loginRiskWeights = map[LoginRiskSignal]int{
+	LoginRiskSignalNewIP:                20,
+	LoginRiskSignalNewCountry:           30,
+	LoginRiskSignalImpossibleTravel:     60,
+	LoginRiskSignalUnusualHour:          15,
+	LoginRiskSignalRecentPasswordChange: 30,
+	LoginRiskSignalFailedAttempts:       25,
+}
+
+// LoginRiskDecision is what the login flow asked the user to do next.
+type LoginRiskDecision string
+
+const (
+	LoginRiskDecisionDeviceChallenge LoginRiskDecision = "device_challenge"
+	LoginRiskDecisionOTPStepUp       LoginRiskDecision = "otp_step_up"
+	LoginRiskDecisionNewDevice       LoginRiskDecision = "new_device_otp"
+)
+
+const (
+	loginRiskMediumScore = 25
+	loginRiskHighScore   = 50
+
+	// Two logins further apart than a commercial flight could carry someone
+	// are treated as impossible travel. Short hops are ignored because IP
+	// geolocation is only accurate to the city.
+	maxTravelSpeedKmh       = 900
+	minImpossibleTravelKm   = 150
+	recentPasswordChange    = 24 * time.Hour
+	minLoginsForHourProfile = 5
+	loginHourTolerance      = 1
+	loginHistoryLimit       = 30
+	phoneFailureThreshold   = 3
+	ipFailureThreshold      = 10
+)
+
+// loginRiskLocation is West Africa Time, where customers' usual hours are
+// judged.
+var loginRiskLocation = time.FixedZone("WAT", 60*60)
+
+// LoginRiskAssessment records the risk score and decision for one login.
+type LoginRiskAssessment struct {
+	ID        string            `gorm:"column:id;type:text;primaryKey"`
+	UserID    string            `gorm:"column:user_id;type:text;not null;index"`
+	DeviceID  string            `gorm:"column:device_id;type:text;not null"`
+	IP        string            `gorm:"column:ip;type:text"`
+	Country   string            `gorm:"column:country;type:text"`
+	Score     int               `gorm:"column:score;not null"`
+	Level     LoginRiskLevel    `gorm:"column:level;type:text;not null;index"`
+	Signals   string            `gorm:"column:signals;type:text"`
+	Decision  LoginRiskDecision `gorm:"column:decision;type:text;not null"`
+	CreatedAt time.Time         `gorm:"column:created_at;not null;index"`
+}
+
+func (LoginRiskAssessment) TableName() string {
+	return "wallet_login_risk_assessments"
+}
+
+type GeoLocation struct {
+	Country   string
+	Latitude  float64
+	Longitude float64
+}
+
+// loginRiskInput is everything known about a login attempt and the user's
+// history before it.
+type loginRiskInput struct {
+	now time.Time
+
+	hasHistory bool
+	knownIP    bool
+
+	location     *GeoLocation
+	lastLocation *GeoLocation
+	lastSeenAt   time.Time
+
+	recentLogins      []time.Time
+	passwordChangedAt *time.Time
+
+	phoneFailures int
+	ipFailures    int
+}
+
+func scoreLoginRisk(in loginRiskInput) (int, []LoginRiskSignal) {
+	var signals []LoginRiskSignal
+
+	// A first login has nothing to compare against.
+	if in.hasHistory && !in.knownIP {
+		signals = append(signals, LoginRiskSignalNewIP)
+	}
+
+	if in.location != nil && in.lastLocation != nil {
+		if in.location.Country != "" && in.lastLocation.Country != "" &&
+			!strings.EqualFold(in.location.Country, in.lastLocation.Country) {
+			signals = append(signals, LoginRiskSignalNewCountry)
+		}
+		if impossibleTravel(*in.lastLocation, *in.location, in.now.Sub(in.lastSeenAt)) {
+			signals = append(signals, LoginRiskSignalImpossibleTravel)
+		}
+	}
+
+	if unusualLoginHour(in.recentLogins, in.now) {
+		signals = append(signals, LoginRiskSignalUnusualHour)
+	}
+
+	if in.passwordChangedAt != nil && in.now.Sub(*in.passwordChangedAt) < recentPasswordChange {
+		signals = append(signals, LoginRiskSignalRecentPasswordChange)
+	}
+
+	if in.phoneFailures >= phoneFailureThreshold || in.ipFailures >= ipFailureThreshold {
+		signals = append(signals, LoginRiskSignalFailedAttempts)
+	}
+
+	score := 0
+	for _, signal := range signals {
+		score += loginRiskWeights[signal]
+	}
+	return score, signals
+}
+
+func loginRiskLevelFor(score int) LoginRiskLevel {
+	switch {
+	case score >= loginRiskHighScore:
+		return LoginRiskHigh
+	case score >= loginRiskMediumScore:
+		return LoginRiskMedium
+	default:
+		return LoginRiskLow
+	}
+}
+
+func impossibleTravel(from, to GeoLocation, elapsed time.Duration) bool {
+	distance := haversineKm(from.Latitude, from.Longitude, to.Latitude, to.Longitude)
+	if distance < minImpossibleTravelKm {
+		return false
+	}
+	hours := math.Max(elapsed.Hours(), time.Minute.Hours())
+	return distance/hours > maxTravelSpeedKmh
+}
+
+func haversineKm(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadiusKm = 6371.0
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// unusualLoginHour reports whether now falls outside every hour the user has
+// logged in at before. Users without enough history are never flagged.
+func unusualLoginHour(recent []time.Time, now time.Time) bool {
+	if len(recent) < minLoginsForHourProfile {
+		return false
+	}
+
+	hour := now.In(loginRiskLocation).Hour()
+	return !slices.ContainsFunc(recent, func(at time.Time) bool {
+		diff := hour - at.In(loginRiskLocation).Hour()
+		if diff < 0 {
+			diff = -diff
+		}
+		return min(diff, 24-diff) <= loginHourTolerance
+	})
+}
+
+func joinLoginRiskSignals(signals []LoginRiskSignal) string {
+	parts := make([]string, len(signals))
+	for i, signal := range signals {
+		parts[i] = string(signal)
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"context"
+	"neat_mobile_app_backend/internal/modules/device"
+	"neat_mobile_app_backend/models"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestScoreLoginRisk(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC) // 13:00 WAT
+	lagos := &GeoLocation{Country: "NG", Latitude: 6.52, Longitude: 3.38}
+	london := &GeoLocation{Country: "GB", Latitude: 51.51, Longitude: -0.13}
+	abuja := &GeoLocation{Country: "NG", Latitude: 9.08, Longitude: 7.40}
+
+	daytime := make([]time.Time, 6)
+	for i := range daytime {
+		daytime[i] = now.Add(-time.Duration(i+1) * 24 * time.Hour)
+	}
+	changedRecently := now.Add(-2 * time.Hour)
+
+	tests := []struct {
+		name    string
+		in      loginRiskInput
+		level   LoginRiskLevel
+		signals []LoginRiskSignal
+	}{
+		{
+			name:  "first login is not flagged",
+			in:    loginRiskInput{now: now},
+			level: LoginRiskLow,
+		},
+		{
+			name:    "new ip alone stays low",
+			in:      loginRiskInput{now: now, hasHistory: true},
+			level:   LoginRiskLow,
+			signals: []LoginRiskSignal{LoginRiskSignalNewIP},
+		},
+		{
+			name: "lagos to london in an hour is impossible travel",
+			in: loginRiskInput{
+				now: now, hasHistory: true, knownIP: true,
+				location: london, lastLocation: lagos, lastSeenAt: now.Add(-time.Hour),
+			},
+			level:   LoginRiskHigh,
+			signals: []LoginRiskSignal{LoginRiskSignalNewCountry, LoginRiskSignalImpossibleTravel},
+		},
+		{
+			name: "lagos to abuja a day later is fine",
+			in: loginRiskInput{
+				now: now, hasHistory: true, knownIP: true,
+				location: abuja, lastLocation: lagos, lastSeenAt: now.Add(-24 * time.Hour),
+			},
+			level: LoginRiskLow,
+		},
+		{
+			name: "3am login for a daytime user",
+			in: loginRiskInput{
+				now: now.Add(14 * time.Hour), hasHistory: true, knownIP: true, recentLogins: daytime,
+			},
+			level:   LoginRiskLow,
+			signals: []LoginRiskSignal{LoginRiskSignalUnusualHour},
+		},
+		{
+			name: "new ip after a password change and failed attempts",
+			in: loginRiskInput{
+				now: now, hasHistory: true, passwordChangedAt: &changedRecently, phoneFailures: 3,
+			},
+			level: LoginRiskHigh,
+			signals: []LoginRiskSignal{
+				LoginRiskSignalNewIP, LoginRiskSignalRecentPasswordChange, LoginRiskSignalFailedAttempts,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, signals := scoreLoginRisk(tt.in)
+			if !slices.Equal(signals, tt.signals) {
+				t.Fatalf("signals = %v, want %v", signals, tt.signals)
+			}
+			if level := loginRiskLevelFor(score); level != tt.level {
+				t.Fatalf("level = %s (score %d), want %s", level, score, tt.level)
+			}
+		})
+	}
+}
+
+type stubGeolocator map[string]*GeoLocation
+
+func (g stubGeolocator) Locate(_ context.Context, ip string) (*GeoLocation, error) {
+	return g[ip], nil
+}
+
+func TestAssessLoginRiskFlagsImpossibleTravel(t *testing.T) {
+	repo, mock, cleanup := newMockRepository(t)
+	defer cleanup()
+
+	lastSeenAt := time.Now().UTC().Add(-time.Hour)
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM wallet_auth_sessions WHERE user_id = \$1\)`).
+		WithArgs("user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(`SELECT EXISTS`).
+		WithArgs("user-1", "81.2.69.142", "user-1", "81.2.69.142").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(`SELECT "created_at" FROM "wallet_auth_sessions"`).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}))
+	mock.ExpectQuery(`SELECT \* FROM "wallet_user_devices"`).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "ip", "last_used_at"}).AddRow("user-1", "102.89.0.1", lastSeenAt))
+
+	s := &Service{
+		repo:       repo,
+		deviceRepo: device.NewRepository(repo.db),
+		geolocator: stubGeolocator{
+			"81.2.69.142": {Country: "GB", Latitude: 51.51, Longitude: -0.13},
+			"102.89.0.1":  {Country: "NG", Latitude: 6.52, Longitude: 3.38},
+		},
+	}
+
+	assessment := s.assessLoginRisk(context.Background(), &models.User{ID: "user-1"}, "device-1", "81.2.69.142")
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+	if assessment.Level != LoginRiskHigh {
+		t.Fatalf("level = %s (score %d), want %s", assessment.Level, assessment.Score, LoginRiskHigh)
+	}
+	if !strings.Contains(assessment.Signals, string(LoginRiskSignalImpossibleTravel)) {
+		t.Fatalf("signals = %q, want %s", assessment.Signals, LoginRiskSignalImpossibleTravel)
+	}
+	if assessment.Country != "GB" {
+		t.Fatalf("country = %q, want GB", assessment.Country)
+	}
+}
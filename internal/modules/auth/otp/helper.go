@@ -43,7 +43,7 @@ func NormalizeDestination(destination string, channel Channel) (string, error) {
 			return "", errors.New("invalid email")
 		}
 		return dst, nil
-	case ChannelSMS:
+	case ChannelSMS, ChannelTOTP:
 		return NormalizeNigerianNumber(destination)
 	default:
 		return "", errors.New("unsupported channel")
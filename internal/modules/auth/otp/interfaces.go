@@ -41,3 +41,26 @@ type VerifyOTPResult struct {
 	VerifiedAt     time.Time
 	VerificationID string // ID of the verification record that was created
 }
+
+// TOTPManager manages authenticator app enrollment. Codes from an enrolled
+// app are also accepted by OTPManager through ChannelTOTP.
+type TOTPManager interface {
+	TOTPStatus(ctx context.Context, userID string) (*TOTPStatus, error)
+	TOTPEnabled(ctx context.Context, userID string) (bool, error)
+	BeginTOTPEnrollment(ctx context.Context, userID, accountName string) (*TOTPSetup, error)
+	ConfirmTOTPEnrollment(ctx context.Context, userID, code string) ([]string, error)
+	RegenerateTOTPRecoveryCodes(ctx context.Context, userID string) ([]string, error)
+	DisableTOTP(ctx context.Context, userID string) error
+	VerifyTOTP(ctx context.Context, userID, code string) error
+}
+
+type TOTPStatus struct {
+	Enabled                bool
+	ConfirmedAt            *time.Time
+	RecoveryCodesRemaining int64
+}
+
+type TOTPSetup struct {
+	Secret          string
+	ProvisioningURI string
+}
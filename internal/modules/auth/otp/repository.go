@@ -22,11 +22,15 @@ func (r *Repository) CreateOTP(ctx context.Context, otp *OTPModel) error {
 	return r.db.WithContext(ctx).Create(otp).Error
 }
 
+// GetActiveOTP returns the open sent OTP for destination and purpose. TOTP
+// rows share the user's phone as their destination but are never returned
+// here, so an authenticator challenge can't be resent over SMS or used to
+// dodge the SMS resend limits.
 func (r *Repository) GetActiveOTP(ctx context.Context, destination string, purpose Purpose) (*OTPModel, error) {
 	var otp OTPModel
 	result := r.db.WithContext(ctx).
 		Clauses(clause.Locking{Strength: "UPDATE"}).
-		Where("destination = ? AND purpose = ? AND consumed_at IS NULL AND expires_at > ?", destination, purpose, time.Now().UTC()).
+		Where("destination = ? AND purpose = ? AND (channel IS NULL OR channel <> ?) AND consumed_at IS NULL AND expires_at > ?", destination, purpose, ChannelTOTP, time.Now().UTC()).
 		Limit(1).
 		Find(&otp)
 	if result.Error != nil {
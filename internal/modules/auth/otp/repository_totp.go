@@ -0,0 +1,134 @@
+package otp
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetActiveTOTPChallenge returns the open TOTP-channel OTP row for userID and
+// purpose, if any.
+func (r *Repository) GetActiveTOTPChallenge(ctx context.Context, userID string, purpose Purpose) (*OTPModel, error) {
+	var otp OTPModel
+	result := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND purpose = ? AND channel = ? AND consumed_at IS NULL AND expires_at > ?", userID, purpose, ChannelTOTP, time.Now().UTC()).
+		Limit(1).
+		Find(&otp)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &otp, nil
+}
+
+// GetTOTPEnrollment locks and returns userID's enrollment, or nil when there
+// is none.
+func (r *Repository) GetTOTPEnrollment(ctx context.Context, userID string) (*TOTPEnrollment, error) {
+	var enrollment TOTPEnrollment
+	result := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ?", userID).
+		Limit(1).
+		Find(&enrollment)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &enrollment, nil
+}
+
+// SaveTOTPEnrollment stores a new, unconfirmed secret, replacing any
+// enrollment that was started and never confirmed.
+func (r *Repository) SaveTOTPEnrollment(ctx context.Context, enrollment *TOTPEnrollment) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"secret_enc", "confirmed_at", "last_used_step", "created_at", "updated_at"}),
+			Where:     clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "wallet_totp_enrollments.confirmed_at IS NULL"}}},
+		}).
+		Create(enrollment).Error
+}
+
+func (r *Repository) ConfirmTOTPEnrollment(ctx context.Context, userID string, step int64, now time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&TOTPEnrollment{}).
+		Where("user_id = ? AND confirmed_at IS NULL", userID).
+		Updates(map[string]any{
+			"confirmed_at":   now,
+			"last_used_step": step,
+			"updated_at":     now,
+		}).Error
+}
+
+// AdvanceTOTPStep records step as the last accepted one. It reports false
+// when a code for the same or a later step was accepted first.
+func (r *Repository) AdvanceTOTPStep(ctx context.Context, userID string, step int64, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&TOTPEnrollment{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Updates(map[string]any{
+			"last_used_step": step,
+			"updated_at":     now,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// SetTOTPFailures records the consecutive failed code count and any lockout
+// it triggered.
+func (r *Repository) SetTOTPFailures(ctx context.Context, userID string, failures int, lockedUntil *time.Time, now time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&TOTPEnrollment{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]any{
+			"failed_attempts": failures,
+			"locked_until":    lockedUntil,
+			"updated_at":      now,
+		}).Error
+}
+
+// DeleteTOTPEnrollment removes userID's secret and recovery codes.
+func (r *Repository) DeleteTOTPEnrollment(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&TOTPRecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&TOTPEnrollment{}).Error
+	})
+}
+
+// ReplaceTOTPRecoveryCodes invalidates userID's existing recovery codes and
+// stores codes in their place.
+func (r *Repository) ReplaceTOTPRecoveryCodes(ctx context.Context, userID string, codes []TOTPRecoveryCode) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&TOTPRecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// ConsumeTOTPRecoveryCode marks the unused code with codeHash as used. It
+// reports false when no such code exists.
+func (r *Repository) ConsumeTOTPRecoveryCode(ctx context.Context, userID, codeHash string, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&TOTPRecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", now)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *Repository) CountUnusedTOTPRecoveryCodes(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&TOTPRecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
@@ -40,6 +40,10 @@ func (s *Service) Issue(ctx context.Context, in IssueOTPInput) (*IssueOTPResult,
 	log.Printf("[otp.Issue] start: purpose=%s channel=%s verificationID=%q hasDestination=%v", in.Purpose, in.Channel, in.VerificationID, in.Destination != "")
 	now := time.Now().UTC()
 
+	if in.Channel == ChannelTOTP {
+		return s.issueTOTPChallenge(ctx, in, now)
+	}
+
 	var normalizeDestination string
 	var err error
 
@@ -314,13 +318,31 @@ func (s *Service) Verify(ctx context.Context, in VerifyOTPInput) (*VerifyOTPResu
 			return appErr.ErrInvalidOTP
 		}
 
-		hashed, err := HashOTP(s.pepper, in.Purpose, active.Destination, strings.TrimSpace(in.Code))
-		if err != nil {
-			log.Printf("[otp.Verify] failed to hash submitted code: otpID=%s err=%v", active.ID, err)
-			return appErr.ErrInvalidOTP
+		var matched bool
+		if active.Channel == ChannelTOTP {
+			enrollment, err := r.GetTOTPEnrollment(ctx, active.UserID)
+			if err != nil {
+				log.Printf("[otp.Verify] failed to fetch totp enrollment: otpID=%s err=%v", active.ID, err)
+				return err
+			}
+			if enrollment == nil || enrollment.ConfirmedAt == nil {
+				log.Printf("[otp.Verify] totp disabled since challenge was issued: otpID=%s", active.ID)
+				return appErr.ErrTOTPNotEnabled
+			}
+			if matched, err = s.attemptTOTP(ctx, r, enrollment, in.Code, now); err != nil {
+				log.Printf("[otp.Verify] failed to check totp code: otpID=%s err=%v", active.ID, err)
+				return err
+			}
+		} else {
+			hashed, err := HashOTP(s.pepper, in.Purpose, active.Destination, strings.TrimSpace(in.Code))
+			if err != nil {
+				log.Printf("[otp.Verify] failed to hash submitted code: otpID=%s err=%v", active.ID, err)
+				return appErr.ErrInvalidOTP
+			}
+			matched = HashEqualHex(hashed, active.OTPHash)
 		}
 
-		if !HashEqualHex(hashed, active.OTPHash) {
+		if !matched {
 			if err = r.IncrementAttempt(ctx, active.ID); err != nil {
 				log.Printf("[otp.Verify] failed to increment attempt count: otpID=%s err=%v", active.ID, err)
 				return err
@@ -594,7 +616,7 @@ func newVerifiedVerificationRecord(channel Channel, destination string, verified
 	switch channel {
 	case ChannelEmail:
 		record.VerifiedEmail = &destination
-	case ChannelSMS:
+	case ChannelSMS, ChannelTOTP:
 		record.VerifiedPhone = &destination
 	}
 
@@ -617,6 +639,8 @@ func newVerificationRecord(channel Channel, destination string) (*models.Verific
 	case ChannelSMS:
 		record.Type = models.VerificationTypePhone
 		record.Provider = string(ProviderTermii)
+	case ChannelTOTP:
+		record.Type = models.VerificationTypeTOTP
 	default:
 		return nil, appErr.ErrInvalidChannel
 	}
@@ -0,0 +1,336 @@
+package otp
+
+import (
+	"context"
+	"log"
+	appErr "neat_mobile_app_backend/internal/errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (s *Service) TOTPStatus(ctx context.Context, userID string) (*TOTPStatus, error) {
+	enrollment, err := s.repo.GetTOTPEnrollment(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if enrollment == nil || enrollment.ConfirmedAt == nil {
+		return &TOTPStatus{}, nil
+	}
+
+	remaining, err := s.repo.CountUnusedTOTPRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TOTPStatus{
+		Enabled:                true,
+		ConfirmedAt:            enrollment.ConfirmedAt,
+		RecoveryCodesRemaining: remaining,
+	}, nil
+}
+
+func (s *Service) TOTPEnabled(ctx context.Context, userID string) (bool, error) {
+	enrollment, err := s.repo.GetTOTPEnrollment(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return enrollment != nil && enrollment.ConfirmedAt != nil, nil
+}
+
+// BeginTOTPEnrollment generates a new secret for userID. It doesn't protect
+// anything until ConfirmTOTPEnrollment proves the app was set up with it.
+func (s *Service) BeginTOTPEnrollment(ctx context.Context, userID, accountName string) (*TOTPSetup, error) {
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := sealTOTPSecret(totpSecretKey(s.pepper), secret)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	err = s.repo.WithTx(ctx, func(r *Repository) error {
+		existing, err := r.GetTOTPEnrollment(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if existing != nil && existing.ConfirmedAt != nil {
+			return appErr.ErrTOTPAlreadyEnabled
+		}
+
+		return r.SaveTOTPEnrollment(ctx, &TOTPEnrollment{
+			UserID:    userID,
+			SecretEnc: sealed,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[otp.BeginTOTPEnrollment] enrollment started: userID=%s", userID)
+	return &TOTPSetup{
+		Secret:          totpEncoding.EncodeToString(secret),
+		ProvisioningURI: totpProvisioningURI(s.appName, accountName, secret),
+	}, nil
+}
+
+// ConfirmTOTPEnrollment enables the pending enrollment once code matches it
+// and returns the user's first set of recovery codes.
+func (s *Service) ConfirmTOTPEnrollment(ctx context.Context, userID, code string) ([]string, error) {
+	code = strings.TrimSpace(code)
+	now := time.Now().UTC()
+
+	var codes []string
+	err := s.repo.WithTx(ctx, func(r *Repository) error {
+		enrollment, err := r.GetTOTPEnrollment(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if enrollment == nil {
+			return appErr.ErrTOTPEnrollmentNotFound
+		}
+		if enrollment.ConfirmedAt != nil {
+			return appErr.ErrTOTPAlreadyEnabled
+		}
+
+		secret, err := openTOTPSecret(totpSecretKey(s.pepper), enrollment.SecretEnc)
+		if err != nil {
+			return err
+		}
+		step, ok := matchTOTP(secret, code, now, enrollment.LastUsedStep)
+		if !ok {
+			return appErr.ErrInvalidTOTPCode
+		}
+
+		if err := r.ConfirmTOTPEnrollment(ctx, userID, step, now); err != nil {
+			return err
+		}
+		codes, err = s.replaceRecoveryCodes(ctx, r, userID, now)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[otp.ConfirmTOTPEnrollment] authenticator enabled: userID=%s", userID)
+	return codes, nil
+}
+
+// RegenerateTOTPRecoveryCodes replaces every recovery code, used or not.
+func (s *Service) RegenerateTOTPRecoveryCodes(ctx context.Context, userID string) ([]string, error) {
+	now := time.Now().UTC()
+
+	var codes []string
+	err := s.repo.WithTx(ctx, func(r *Repository) error {
+		enrollment, err := r.GetTOTPEnrollment(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if enrollment == nil || enrollment.ConfirmedAt == nil {
+			return appErr.ErrTOTPNotEnabled
+		}
+
+		codes, err = s.replaceRecoveryCodes(ctx, r, userID, now)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[otp.RegenerateTOTPRecoveryCodes] recovery codes replaced: userID=%s", userID)
+	return codes, nil
+}
+
+func (s *Service) DisableTOTP(ctx context.Context, userID string) error {
+	err := s.repo.WithTx(ctx, func(r *Repository) error {
+		enrollment, err := r.GetTOTPEnrollment(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if enrollment == nil || enrollment.ConfirmedAt == nil {
+			return appErr.ErrTOTPNotEnabled
+		}
+		return r.DeleteTOTPEnrollment(ctx, userID)
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[otp.DisableTOTP] authenticator disabled: userID=%s", userID)
+	return nil
+}
+
+// VerifyTOTP checks a code from userID's authenticator app, or one of their
+// recovery codes, outside of any OTP challenge.
+func (s *Service) VerifyTOTP(ctx context.Context, userID, code string) error {
+	var matched bool
+	err := s.repo.WithTx(ctx, func(r *Repository) error {
+		enrollment, err := r.GetTOTPEnrollment(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if enrollment == nil || enrollment.ConfirmedAt == nil {
+			return appErr.ErrTOTPNotEnabled
+		}
+
+		matched, err = s.attemptTOTP(ctx, r, enrollment, code, time.Now().UTC())
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if !matched {
+		return appErr.ErrInvalidTOTPCode
+	}
+	return nil
+}
+
+// attemptTOTP runs checkTOTP under the enrollment's own failure limit, which
+// applies however many OTP challenges or transfers the guesses are spread
+// across.
+func (s *Service) attemptTOTP(ctx context.Context, r *Repository, enrollment *TOTPEnrollment, code string, now time.Time) (bool, error) {
+	if enrollment.LockedUntil != nil && now.Before(*enrollment.LockedUntil) {
+		log.Printf("[otp.attemptTOTP] authenticator locked: userID=%s lockedUntil=%s", enrollment.UserID, enrollment.LockedUntil.Format(time.RFC3339))
+		return false, appErr.ErrTooManyRequests
+	}
+
+	matched, err := s.checkTOTP(ctx, r, enrollment, code, now)
+	if err != nil {
+		return false, err
+	}
+
+	if matched {
+		if enrollment.FailedAttempts == 0 && enrollment.LockedUntil == nil {
+			return true, nil
+		}
+		return true, r.SetTOTPFailures(ctx, enrollment.UserID, 0, nil, now)
+	}
+
+	failures := enrollment.FailedAttempts + 1
+	var lockedUntil *time.Time
+	if failures >= maxTOTPFailures {
+		until := now.Add(totpLockDuration)
+		lockedUntil = &until
+		failures = 0
+		log.Printf("[otp.attemptTOTP] too many failed codes, locking authenticator: userID=%s", enrollment.UserID)
+	}
+	return false, r.SetTOTPFailures(ctx, enrollment.UserID, failures, lockedUntil, now)
+}
+
+// checkTOTP reports whether code is a current authenticator code or an unused
+// recovery code for enrollment, spending it if so.
+func (s *Service) checkTOTP(ctx context.Context, r *Repository, enrollment *TOTPEnrollment, code string, now time.Time) (bool, error) {
+	code = strings.TrimSpace(code)
+
+	if !isTOTPCode(code) {
+		used, err := r.ConsumeTOTPRecoveryCode(ctx, enrollment.UserID, hashRecoveryCode(s.pepper, enrollment.UserID, code), now)
+		if used {
+			log.Printf("[otp.checkTOTP] recovery code used: userID=%s", enrollment.UserID)
+		}
+		return used, err
+	}
+
+	secret, err := openTOTPSecret(totpSecretKey(s.pepper), enrollment.SecretEnc)
+	if err != nil {
+		return false, err
+	}
+	step, ok := matchTOTP(secret, code, now, enrollment.LastUsedStep)
+	if !ok {
+		return false, nil
+	}
+	return r.AdvanceTOTPStep(ctx, enrollment.UserID, step, now)
+}
+
+func (s *Service) replaceRecoveryCodes(ctx context.Context, r *Repository, userID string, now time.Time) ([]string, error) {
+	codes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]TOTPRecoveryCode, len(codes))
+	for i, code := range codes {
+		rows[i] = TOTPRecoveryCode{
+			ID:        uuid.NewString(),
+			UserID:    userID,
+			CodeHash:  hashRecoveryCode(s.pepper, userID, code),
+			CreatedAt: now,
+		}
+	}
+	if err := r.ReplaceTOTPRecoveryCodes(ctx, userID, rows); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// issueTOTPChallenge opens an OTP row answered from the user's authenticator
+// app instead of a sent code. Asking again returns the challenge still open.
+func (s *Service) issueTOTPChallenge(ctx context.Context, in IssueOTPInput, now time.Time) (*IssueOTPResult, error) {
+	userID := strings.TrimSpace(in.UserID)
+	if userID == "" {
+		log.Printf("[otp.Issue] totp channel requires a user: purpose=%s", in.Purpose)
+		return nil, appErr.ErrInvalidChannel
+	}
+
+	destination, err := NormalizeDestination(in.Destination, ChannelTOTP)
+	if err != nil {
+		log.Printf("[otp.Issue] failed to normalize destination: channel=%s err=%v", ChannelTOTP, err)
+		return nil, err
+	}
+
+	ttl := in.TTL
+	if ttl <= 0 {
+		ttl = 10 * time.Minute
+	}
+	maxAttempts := in.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 5
+	}
+
+	var result IssueOTPResult
+	err = s.repo.WithTx(ctx, func(r *Repository) error {
+		enrollment, err := r.GetTOTPEnrollment(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if enrollment == nil || enrollment.ConfirmedAt == nil {
+			return appErr.ErrTOTPNotEnabled
+		}
+
+		active, err := r.GetActiveTOTPChallenge(ctx, userID, in.Purpose)
+		if err != nil {
+			return err
+		}
+		if active != nil {
+			result = IssueOTPResult{OTPID: active.ID, ExpiresAt: active.ExpiresAt}
+			return nil
+		}
+
+		row := &OTPModel{
+			ID:          uuid.NewString(),
+			UserID:      userID,
+			Purpose:     in.Purpose,
+			Channel:     ChannelTOTP,
+			Destination: destination,
+			ExpiresAt:   now.Add(ttl),
+			MaxAttempts: maxAttempts,
+			IssuedAt:    now,
+		}
+		if err := r.CreateOTP(ctx, row); err != nil {
+			return err
+		}
+		result = IssueOTPResult{OTPID: row.ID, ExpiresAt: row.ExpiresAt}
+		return nil
+	})
+	if err != nil {
+		log.Printf("[otp.Issue] totp challenge failed: purpose=%s err=%v", in.Purpose, err)
+		return nil, err
+	}
+
+	log.Printf("[otp.Issue] totp challenge opened: otpID=%s purpose=%s", result.OTPID, in.Purpose)
+	return &result, nil
+}
@@ -0,0 +1,35 @@
+package otp
+
+import "time"
+
+// TOTPEnrollment holds a user's authenticator app secret, sealed with a key
+// derived from the OTP pepper. It only counts as a second factor once
+// ConfirmedAt is set.
+type TOTPEnrollment struct {
+	UserID         string     `gorm:"column:user_id;type:text;primaryKey"`
+	SecretEnc      string     `gorm:"column:secret_enc;type:text;not null"`
+	ConfirmedAt    *time.Time `gorm:"column:confirmed_at"`
+	LastUsedStep   int64      `gorm:"column:last_used_step;not null;default:0"`
+	FailedAttempts int        `gorm:"column:failed_attempts;not null;default:0"`
+	LockedUntil    *time.Time `gorm:"column:locked_until"`
+	CreatedAt      time.Time  `gorm:"column:created_at;not null"`
+	UpdatedAt      time.Time  `gorm:"column:updated_at;not null"`
+}
+
+func (TOTPEnrollment) TableName() string {
+	return "wallet_totp_enrollments"
+}
+
+// TOTPRecoveryCode is a single-use code that stands in for an authenticator
+// code when the user no longer has the app.
+type TOTPRecoveryCode struct {
+	ID        string     `gorm:"column:id;type:text;primaryKey"`
+	UserID    string     `gorm:"column:user_id;type:text;not null;index"`
+	CodeHash  string     `gorm:"column:code_hash;type:text;not null;uniqueIndex"`
+	UsedAt    *time.Time `gorm:"column:used_at"`
+	CreatedAt time.Time  `gorm:"column:created_at;not null"`
+}
+
+func (TOTPRecoveryCode) TableName() string {
+	return "wallet_totp_recovery_codes"
+}
@@ -0,0 +1,106 @@
+package otp
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// RFC 6238 appendix B vectors for SHA-1, truncated to six digits.
+func TestTOTPCodeMatchesRFC6238Vectors(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+		{unix: 20000000000, want: "353130"},
+	}
+
+	for _, tc := range tests {
+		got := totpCode(secret, totpStep(time.Unix(tc.unix, 0)))
+		if got != tc.want {
+			t.Fatalf("totpCode at %d = %q, want %q", tc.unix, got, tc.want)
+		}
+	}
+}
+
+func TestMatchTOTPAllowsOneStepOfDriftAndRefusesReplay(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	now := time.Unix(1234567890, 0)
+	current := totpStep(now)
+
+	for _, step := range []int64{current - 1, current, current + 1} {
+		got, ok := matchTOTP(secret, totpCode(secret, step), now, 0)
+		if !ok || got != step {
+			t.Fatalf("matchTOTP for step %d = (%d, %v), want (%d, true)", step, got, ok, step)
+		}
+	}
+
+	if _, ok := matchTOTP(secret, totpCode(secret, current-2), now, 0); ok {
+		t.Fatal("expected a code two steps old to be rejected")
+	}
+	if _, ok := matchTOTP(secret, totpCode(secret, current), now, current); ok {
+		t.Fatal("expected an already used step to be rejected")
+	}
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	raw := totpProvisioningURI("NeatPay", "2348012345678", secret)
+
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("parse provisioning uri: %v", err)
+	}
+	if parsed.Scheme != "otpauth" || parsed.Host != "totp" {
+		t.Fatalf("unexpected uri %q", raw)
+	}
+	if parsed.Path != "/NeatPay:2348012345678" {
+		t.Fatalf("label = %q", parsed.Path)
+	}
+	query := parsed.Query()
+	if query.Get("secret") != "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ" || query.Get("issuer") != "NeatPay" {
+		t.Fatalf("unexpected query %q", parsed.RawQuery)
+	}
+}
+
+func TestSealedTOTPSecretRoundTrips(t *testing.T) {
+	key := totpSecretKey("pepper")
+	sealed, err := sealTOTPSecret(key, []byte("secret"))
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+
+	opened, err := openTOTPSecret(key, sealed)
+	if err != nil || string(opened) != "secret" {
+		t.Fatalf("open = (%q, %v)", opened, err)
+	}
+	if _, err := openTOTPSecret(totpSecretKey("other"), sealed); err == nil {
+		t.Fatal("expected a different pepper to fail to open the secret")
+	}
+}
+
+func TestRecoveryCodesHashTheSameHoweverTheyAreTyped(t *testing.T) {
+	codes, err := generateRecoveryCodes()
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if len(codes) != recoveryCodeCount {
+		t.Fatalf("got %d codes, want %d", len(codes), recoveryCodeCount)
+	}
+
+	code := codes[0]
+	typed := strings.ToLower(strings.ReplaceAll(code, "-", " "))
+	if hashRecoveryCode("pepper", "user-1", typed) != hashRecoveryCode("pepper", "user-1", code) {
+		t.Fatal("expected recovery code hashing to ignore case and separators")
+	}
+	if hashRecoveryCode("pepper", "user-2", code) == hashRecoveryCode("pepper", "user-1", code) {
+		t.Fatal("expected recovery code hashes to be bound to the user")
+	}
+}
@@ -11,11 +11,16 @@ const (
 	PurposePasswordChange Purpose = "password_change"
 	PurposePinReset       Purpose = "pin_reset"
 	PurposePinChange      Purpose = "pin_change"
+	PurposeTOTPManage     Purpose = "totp_manage"
 )
 
 const (
 	ChannelSMS   Channel = "sms"
 	ChannelEmail Channel = "email"
+	// ChannelTOTP codes come from the user's authenticator app, so nothing is
+	// sent. The OTP row is still bound to the user's phone so the verification
+	// record it produces satisfies the same checks as an SMS one.
+	ChannelTOTP Channel = "totp"
 )
 
 const (
@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 	"errors"
+	"log"
 	"neat_mobile_app_backend/internal/modules/device"
 	"neat_mobile_app_backend/models"
 	"strings"
@@ -36,7 +37,7 @@ func (r *Repository) GetUserByEmail(ctx context.Context, email string) (*models.
 
 func (r *Repository) GetUserByPhone(ctx context.Context, phone string) (*models.User, error) {
 	var u models.User
-	err := r.db.WithContext(ctx).Table("wallet_users").Select("id,phone,password_hash,created_at").Where("phone = ?", phone).First(&u).Error
+	err := r.db.WithContext(ctx).Table("wallet_users").Select("id,phone,password_hash,password_changed_at,created_at").Where("phone = ?", phone).First(&u).Error
 	if err != nil {
 		return nil, err
 	}
@@ -111,6 +112,9 @@ func (r *Repository) RotateRefreshToken(ctx context.Context, oldJTI string, newT
 		}
 
 		if oldToken.RevokedAt != nil {
+			if oldToken.ReplacedByJTI != nil {
+				return errRefreshTokenReused
+			}
 			return errors.New("refresh token already revoked")
 		}
 
@@ -130,6 +134,8 @@ func (r *Repository) RotateRefreshToken(ctx context.Context, oldJTI string, newT
 			return err
 		}
 
+		newToken.FamilyID = refreshTokenFamily(&oldToken)
+		newToken.ParentJTI = &oldToken.JTI
 		if err := tx.Create(newToken).Error; err != nil {
 			return err
 		}
@@ -138,6 +144,50 @@ func (r *Repository) RotateRefreshToken(ctx context.Context, oldJTI string, newT
 	})
 }
 
+// RevokeRefreshTokenFamily revokes every token descended from the same login
+// as token, together with the sessions those tokens belong to. It returns the
+// number of sessions that were still live.
+func (r *Repository) RevokeRefreshTokenFamily(ctx context.Context, token *models.RefreshToken, now time.Time) (int64, error) {
+	family := refreshTokenFamily(token)
+
+	var revoked int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var sids []string
+		if err := tx.Model(&models.RefreshToken{}).
+			Where("family_id = ? OR jti = ?", family, family).
+			Distinct().
+			Pluck("sid", &sids).Error; err != nil {
+			return err
+		}
+		sids = append(sids, token.SessionID)
+
+		if err := tx.Model(&models.RefreshToken{}).
+			Where("(family_id = ? OR jti = ? OR sid IN ?) AND revoked_at IS NULL", family, family, sids).
+			Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.AuthSession{}).
+			Where("sid IN ? AND revoked_at IS NULL", sids).
+			Update("revoked_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		revoked = result.RowsAffected
+		return nil
+	})
+	return revoked, err
+}
+
+// refreshTokenFamily falls back to the session for tokens issued before
+// families were recorded, since a session never changes across rotations.
+func refreshTokenFamily(token *models.RefreshToken) string {
+	if token.FamilyID != "" {
+		return token.FamilyID
+	}
+	return token.SessionID
+}
+
 func (r *Repository) GetValidationRow(ctx context.Context, verificationID string) (*models.VerificationRecord, error) {
 	var record models.VerificationRecord
 	err := r.db.WithContext(ctx).Table("wallet_verification_records").
@@ -263,7 +313,10 @@ func (r *Repository) UpdateUserPin(ctx context.Context, userID, newPinHash strin
 }
 
 func (r *Repository) UpdateUserPassword(ctx context.Context, userID, newPasswordHash string) error {
-	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ? AND password_hash IS NOT NULL", userID).Update("password_hash", newPasswordHash).Error
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ? AND password_hash IS NOT NULL", userID).Updates(map[string]any{
+		"password_hash":       newPasswordHash,
+		"password_changed_at": time.Now().UTC(),
+	}).Error
 }
 
 func (r *Repository) UpdateCoreCustomerID(ctx context.Context, userID, coreCustomerID string) error {
@@ -309,17 +362,93 @@ func (r *Repository) DeactiveOlderDevices(ctx context.Context, mobileUserID, new
 		Update("is_active", false).Error
 }
 
+// CheckSession reports whether sid is a live session of mobileUserID on
+// deviceID, and records that the session was just used.
 func (r *Repository) CheckSession(ctx context.Context, sid, mobileUserID, deviceID string) (bool, error) {
-	err := r.db.WithContext(ctx).Where("sid = ? AND user_id = ? AND revoked_at IS NULL AND device_id = ?", sid, mobileUserID, deviceID).Error
+	var session models.AuthSession
+	err := r.db.WithContext(ctx).
+		Where("sid = ? AND user_id = ? AND revoked_at IS NULL AND device_id = ?", sid, mobileUserID, deviceID).
+		Take(&session).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return false, nil
 		}
 		return false, err
 	}
+
+	now := time.Now().UTC()
+	if session.LastSeenAt == nil || now.Sub(*session.LastSeenAt) >= sessionSeenInterval {
+		if err := r.db.WithContext(ctx).
+			Model(&models.AuthSession{}).
+			Where("sid = ?", sid).
+			Update("last_seen_at", now).Error; err != nil {
+			log.Printf("auth repository: failed to record session %s as seen: %v", sid, err)
+		}
+	}
 	return true, nil
 }
 
+// ListActiveSessions returns the user's sessions that are not revoked and
+// still hold a usable refresh token, most recently used first.
+func (r *Repository) ListActiveSessions(ctx context.Context, userID string, now time.Time) ([]models.AuthSession, error) {
+	var sessions []models.AuthSession
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Where(`EXISTS (
+			SELECT 1 FROM wallet_refresh_tokens rt
+			WHERE rt.sid = wallet_auth_sessions.sid
+				AND rt.revoked_at IS NULL
+				AND rt.expires_at > ?
+		)`, now).
+		Order("last_seen_at DESC NULLS LAST, created_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// RevokeSession revokes one of the user's sessions and its refresh tokens.
+func (r *Repository) RevokeSession(ctx context.Context, userID, sid string, now time.Time) (int64, error) {
+	return r.revokeSessions(ctx, now, "user_id = ? AND sid = ?", userID, sid)
+}
+
+// RevokeOtherSessions revokes every session of the user except keepSID.
+func (r *Repository) RevokeOtherSessions(ctx context.Context, userID, keepSID string, now time.Time) (int64, error) {
+	return r.revokeSessions(ctx, now, "user_id = ? AND sid <> ?", userID, keepSID)
+}
+
+// RevokeDeviceSessions revokes every session the user holds on deviceID.
+func (r *Repository) RevokeDeviceSessions(ctx context.Context, userID, deviceID string, now time.Time) (int64, error) {
+	return r.revokeSessions(ctx, now, "user_id = ? AND device_id = ?", userID, deviceID)
+}
+
+func (r *Repository) revokeSessions(ctx context.Context, now time.Time, query string, args ...any) (int64, error) {
+	var revoked int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var sids []string
+		if err := tx.Model(&models.AuthSession{}).
+			Where(query, args...).
+			Where("revoked_at IS NULL").
+			Pluck("sid", &sids).Error; err != nil {
+			return err
+		}
+		if len(sids) == 0 {
+			return nil
+		}
+
+		result := tx.Model(&models.AuthSession{}).
+			Where("sid IN ? AND revoked_at IS NULL", sids).
+			Update("revoked_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		revoked = result.RowsAffected
+
+		return tx.Model(&models.RefreshToken{}).
+			Where("sid IN ? AND revoked_at IS NULL", sids).
+			Update("revoked_at", now).Error
+	})
+	return revoked, err
+}
+
 func (r *Repository) CreateFaceCheckRecord(ctx context.Context, record *models.FaceCheckRecord) error {
 	return r.db.WithContext(ctx).Create(record).Error
 }
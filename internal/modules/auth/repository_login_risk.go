@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"context"
+	"neat_mobile_app_backend/internal/modules/device"
+	"neat_mobile_app_backend/models"
+	"time"
+)
+
+// HasLoginHistory reports whether the user has ever been issued a session.
+func (r *Repository) HasLoginHistory(ctx context.Context, userID string) (bool, error) {
+	var seen bool
+	err := r.db.WithContext(ctx).
+		Raw(`SELECT EXISTS (SELECT 1 FROM `+models.AuthSession{}.TableName()+` WHERE user_id = ?)`, userID).
+		Scan(&seen).Error
+	return seen, err
+}
+
+// HasSeenLoginIP reports whether the user has signed in from, or registered a
+// device on, ip before.
+func (r *Repository) HasSeenLoginIP(ctx context.Context, userID, ip string) (bool, error) {
+	var seen bool
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT EXISTS (SELECT 1 FROM `+models.AuthSession{}.TableName()+` WHERE user_id = ? AND ip = ?)
+			OR EXISTS (SELECT 1 FROM `+device.UserDevice{}.TableName()+` WHERE user_id = ? AND ip = ?)
+	`, userID, ip, userID, ip).Scan(&seen).Error
+	return seen, err
+}
+
+// RecentLoginTimes returns when the user's latest sessions were started.
+func (r *Repository) RecentLoginTimes(ctx context.Context, userID string, limit int) ([]time.Time, error) {
+	var times []time.Time
+	err := r.db.WithContext(ctx).
+		Model(&models.AuthSession{}).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Pluck("created_at", &times).Error
+	return times, err
+}
+
+func (r *Repository) CreateLoginRiskAssessment(ctx context.Context, assessment *LoginRiskAssessment) error {
+	return r.db.WithContext(ctx).Create(assessment).Error
+}
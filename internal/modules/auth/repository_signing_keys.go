@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"context"
+	"neat_mobile_app_backend/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ListSigningKeys returns the JWT signing keys that are still valid at now,
+// oldest activation first.
+func (r *Repository) ListSigningKeys(ctx context.Context, now time.Time) ([]models.JWTSigningKey, error) {
+	var keys []models.JWTSigningKey
+	err := r.db.WithContext(ctx).
+		Where("expires_at IS NULL OR expires_at > ?", now).
+		Order("activates_at ASC").
+		Find(&keys).Error
+	return keys, err
+}
+
+// RotateSigningKey stores next as the successor signing key. The keys it
+// replaces are scheduled to expire at supersededExpiresAt, and keys that have
+// already expired are dropped. Nothing is stored when a successor is already
+// waiting to activate, which keeps concurrent rotations from piling up keys.
+func (r *Repository) RotateSigningKey(ctx context.Context, next *models.JWTSigningKey, supersededExpiresAt, now time.Time) (bool, error) {
+	stored := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current []models.JWTSigningKey
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("expires_at IS NULL").
+			Find(&current).Error; err != nil {
+			return err
+		}
+		for _, key := range current {
+			if key.ActivatesAt.After(now) {
+				return nil
+			}
+		}
+
+		if err := tx.Model(&models.JWTSigningKey{}).
+			Where("expires_at IS NULL").
+			Update("expires_at", supersededExpiresAt).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("expires_at <= ?", now).Delete(&models.JWTSigningKey{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+		stored = true
+		return nil
+	})
+	return stored, err
+}
@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 	"errors"
+	"neat_mobile_app_backend/models"
 	"regexp"
 	"testing"
 	"time"
@@ -171,3 +172,70 @@ func TestRepository_LinkBVNRecordToUser_RejectsDifferentUser(t *testing.T) {
 		t.Fatalf("unmet sqlmock expectations: %v", err)
 	}
 }
+
+func checkSessionQueryPattern() string {
+	return regexp.QuoteMeta(`SELECT * FROM "wallet_auth_sessions" WHERE sid = $1 AND user_id = $2 AND revoked_at IS NULL AND device_id = $3 LIMIT $4`)
+}
+
+func TestRepository_CheckSession_RevokedSessionIsInactive(t *testing.T) {
+	repo, mock, cleanup := newMockRepository(t)
+	defer cleanup()
+
+	mock.ExpectQuery(checkSessionQueryPattern()).
+		WillReturnRows(sqlmock.NewRows([]string{"sid", "user_id", "device_id"}))
+
+	ok, err := repo.CheckSession(context.Background(), "sid-1", "user-1", "device-1")
+	if err != nil {
+		t.Fatalf("CheckSession returned error: %v", err)
+	}
+	if ok {
+		t.Fatal("expected revoked session to be inactive")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sqlmock expectations: %v", err)
+	}
+}
+
+func TestRepository_CheckSession_RecentlySeenSessionSkipsUpdate(t *testing.T) {
+	repo, mock, cleanup := newMockRepository(t)
+	defer cleanup()
+
+	lastSeen := time.Now().UTC()
+	mock.ExpectQuery(checkSessionQueryPattern()).
+		WillReturnRows(sqlmock.NewRows([]string{"sid", "user_id", "device_id", "last_seen_at"}).
+			AddRow("sid-1", "user-1", "device-1", lastSeen))
+
+	ok, err := repo.CheckSession(context.Background(), "sid-1", "user-1", "device-1")
+	if err != nil {
+		t.Fatalf("CheckSession returned error: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected session to be active")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sqlmock expectations: %v", err)
+	}
+}
+
+func TestRepository_RotateRefreshToken_RotatedTokenIsReuse(t *testing.T) {
+	repo, mock, cleanup := newMockRepository(t)
+	defer cleanup()
+
+	revokedAt := time.Now().UTC().Add(-time.Minute)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "wallet_refresh_tokens" WHERE jti = $1 ORDER BY "wallet_refresh_tokens"."jti" LIMIT $2 FOR UPDATE`)).
+		WillReturnRows(sqlmock.NewRows([]string{"jti", "sid", "user_id", "revoked_at", "replaced_by_jti", "family_id"}).
+			AddRow("jti-1", "sid-1", "user-1", revokedAt, "jti-2", "jti-1"))
+	mock.ExpectRollback()
+
+	err := repo.RotateRefreshToken(context.Background(), "jti-1", &models.RefreshToken{JTI: "jti-3"})
+	if !errors.Is(err, errRefreshTokenReused) {
+		t.Fatalf("expected errRefreshTokenReused, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sqlmock expectations: %v", err)
+	}
+}
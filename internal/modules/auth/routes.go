@@ -44,6 +44,17 @@ func RegisterRoutes(rg *gin.RouterGroup, handler *Handler, authGuard, deviceVali
 		auth.POST("/password/change/verify", authGuard, deviceValidator, handler.VerifyPasswordChangeOTP)
 		auth.PATCH("/password/change", authGuard, deviceValidator, handler.ChangePassword)
 		auth.PATCH("/biometrics/toggle", authGuard, deviceValidator, handler.ToggleBiometrics)
+		auth.GET("/sessions", authGuard, deviceValidator, handler.ListSessions)
+		auth.DELETE("/sessions/:sid", authGuard, deviceValidator, handler.RevokeSession)
+		auth.POST("/sessions/revoke-others", authGuard, deviceValidator, handler.RevokeOtherSessions)
+		auth.GET("/devices", authGuard, deviceValidator, handler.ListDevices)
+		auth.DELETE("/devices/:device_id", authGuard, deviceValidator, handler.UntrustDevice)
+		auth.GET("/totp", authGuard, deviceValidator, handler.GetTOTPStatus)
+		auth.POST("/totp/enroll", authGuard, deviceValidator, handler.BeginTOTPEnrollment)
+		auth.POST("/totp/enroll/confirm", authGuard, deviceValidator, handler.ConfirmTOTPEnrollment)
+		auth.POST("/totp/otp/request", authGuard, deviceValidator, handler.RequestTOTPManagementOTP)
+		auth.POST("/totp/recovery-codes/regenerate", authGuard, deviceValidator, handler.RegenerateTOTPRecoveryCodes)
+		auth.POST("/totp/disable", authGuard, deviceValidator, handler.DisableTOTP)
 		auth.POST("/challenge/request", handler.ChallengeRequest)
 	}
 }
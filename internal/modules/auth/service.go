@@ -66,6 +66,10 @@ type Service struct {
 	productID            string
 	optimusKYC           OptimusKYCValidation
 	activationCapKobo    int64
+	notifier             SecurityNotifier
+	geolocator           IPGeolocator
+	loginAttempts        LoginAttemptHistory
+	totp                 authotp.TOTPManager
 }
 
 func NewService(
@@ -123,6 +127,19 @@ func (s *Service) ConfigureOptimusKYC(kyc OptimusKYCValidation) {
 	s.optimusKYC = kyc
 }
 
+func (s *Service) ConfigureNotifier(notifier SecurityNotifier) {
+	s.notifier = notifier
+}
+
+func (s *Service) ConfigureLoginRisk(geolocator IPGeolocator, attempts LoginAttemptHistory) {
+	s.geolocator = geolocator
+	s.loginAttempts = attempts
+}
+
+func (s *Service) ConfigureTOTP(totp authotp.TOTPManager) {
+	s.totp = totp
+}
+
 func (s *Service) VerifyTransactionPin(ctx context.Context, mobileUserID, pin string) error {
 	user, err := s.repo.GetUserByID(ctx, mobileUserID)
 	if err != nil {
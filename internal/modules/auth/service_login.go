@@ -49,17 +49,29 @@ func (s *Service) Login(ctx context.Context, deviceID, ip, phone, password strin
 		return nil, errors.New("device repository not configured")
 	}
 
+	risk := s.assessLoginRisk(ctx, user, deviceID, ip)
+
 	deviceRecord, err := s.deviceRepo.FindDevice(ctx, user.ID, deviceID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return s.startNewDeviceFlow(ctx, user.ID, user.Phone, deviceID, ip)
+			s.recordLoginRisk(ctx, risk, LoginRiskDecisionNewDevice)
+			return s.startNewDeviceFlow(ctx, user.ID, user.Phone, deviceID, ip, LoginStatusNewDeviceDetected)
 		}
 		return nil, err
 	}
 
 	if !deviceRecord.IsActive || !deviceRecord.IsTrusted {
-		return s.startNewDeviceFlow(ctx, user.ID, user.Phone, deviceID, ip)
+		s.recordLoginRisk(ctx, risk, LoginRiskDecisionNewDevice)
+		return s.startNewDeviceFlow(ctx, user.ID, user.Phone, deviceID, ip, LoginStatusNewDeviceDetected)
+	}
+
+	// A trusted device normally only signs a challenge. Risky logins must also
+	// prove control of the phone number.
+	if risk.Level == LoginRiskHigh {
+		s.recordLoginRisk(ctx, risk, LoginRiskDecisionOTPStepUp)
+		return s.startNewDeviceFlow(ctx, user.ID, user.Phone, deviceID, ip, LoginStatusStepUpRequired)
 	}
+	s.recordLoginRisk(ctx, risk, LoginRiskDecisionDeviceChallenge)
 
 	deviceService := device.NewService(*s.deviceRepo)
 	challenge, err := deviceService.CreateChallenge(ctx, user.ID, deviceID, 0)
@@ -186,8 +198,11 @@ func (s *Service) VerifyNewDevice(ctx context.Context, ip string, req NewDeviceR
 			return appErr.ErrInvalidOTP
 		}
 
-		hashedOTP, err := authotp.HashOTP(s.otpPepper, loginOTPPurpose, activeOTP.Destination, otp)
-		if err != nil || !authotp.HashEqualHex(hashedOTP, activeOTP.OTPHash) {
+		matched, err := s.matchNewDeviceOTP(ctx, activeOTP, pendingSession.UserID, authotp.Channel(req.Channel), otp)
+		if err != nil {
+			return err
+		}
+		if !matched {
 			if updateErr := otpRepo.IncrementAttempt(ctx, activeOTP.ID); updateErr != nil {
 				return updateErr
 			}
@@ -247,7 +262,10 @@ func (s *Service) VerifyNewDevice(ctx context.Context, ip string, req NewDeviceR
 	return authObj, nil
 }
 
-func (s *Service) startNewDeviceFlow(ctx context.Context, userID, phone, deviceID, ip string) (*LoginInitObject, error) {
+// startNewDeviceFlow sends a login OTP and opens a pending device session
+// that VerifyNewDevice completes. status tells the client why the OTP is
+// needed; other devices are only signed out when a new device is taking over.
+func (s *Service) startNewDeviceFlow(ctx context.Context, userID, phone, deviceID, ip, status string) (*LoginInitObject, error) {
 	if s.tx == nil {
 		return nil, errors.New("transaction manager not configured")
 	}
@@ -285,6 +303,10 @@ func (s *Service) startNewDeviceFlow(ctx context.Context, userID, phone, deviceI
 		}
 		sessionToken = token
 
+		if status != LoginStatusNewDeviceDetected {
+			return nil
+		}
+
 		authRepo := NewRespository(txDB)
 		if err := authRepo.DeactiveOlderDevices(ctx, userID, deviceID); err != nil {
 			return err
@@ -297,11 +319,31 @@ func (s *Service) startNewDeviceFlow(ctx context.Context, userID, phone, deviceI
 	}
 
 	return &LoginInitObject{
-		Status:       LoginStatusNewDeviceDetected,
-		SessionToken: sessionToken,
+		Status:        status,
+		SessionToken:  sessionToken,
+		TOTPAvailable: s.totpEnabled(ctx, userID),
 	}, nil
 }
 
+// matchNewDeviceOTP checks the code sent for a pending device session, or a
+// code from the user's authenticator app when channel is TOTP. Either way the
+// attempt counts against the session's OTP.
+func (s *Service) matchNewDeviceOTP(ctx context.Context, activeOTP *authotp.OTPModel, userID string, channel authotp.Channel, code string) (bool, error) {
+	if channel != authotp.ChannelTOTP {
+		hashedOTP, err := authotp.HashOTP(s.otpPepper, loginOTPPurpose, activeOTP.Destination, code)
+		return err == nil && authotp.HashEqualHex(hashedOTP, activeOTP.OTPHash), nil
+	}
+
+	if s.totp == nil {
+		return false, appErr.ErrTOTPNotEnabled
+	}
+	err := s.totp.VerifyTOTP(ctx, userID, code)
+	if errors.Is(err, appErr.ErrInvalidTOTPCode) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
 func (s *Service) createPendingDeviceSession(ctx context.Context, deviceRepo *device.Repository, userID, deviceID, ip, otpRef string) (string, error) {
 	repo := deviceRepo
 	if repo == nil {
@@ -532,6 +574,7 @@ func (s *Service) issueSessionTokensWithRepo(ctx context.Context, repo *Reposito
 		TokenHash: hex.EncodeToString(hashedRefreshToken[:]),
 		IssuedAt:  now,
 		ExpiresAt: refreshExpiresAt,
+		FamilyID:  jti,
 	}
 
 	if err := repo.AddRefreshToken(ctx, refreshTokenObj); err != nil {
@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"context"
+	"log"
+	"neat_mobile_app_backend/models"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// assessLoginRisk scores a login whose password has already been checked.
+// Signals that can't be gathered are skipped rather than failing the login.
+func (s *Service) assessLoginRisk(ctx context.Context, user *models.User, deviceID, ip string) *LoginRiskAssessment {
+	ip = strings.TrimSpace(ip)
+	in := loginRiskInput{
+		now:               time.Now().UTC(),
+		knownIP:           true,
+		passwordChangedAt: user.PasswordChangedAt,
+	}
+
+	hasHistory, err := s.repo.HasLoginHistory(ctx, user.ID)
+	if err != nil {
+		log.Printf("login risk: failed to load login history for user %s: %v", user.ID, err)
+	}
+	in.hasHistory = hasHistory
+
+	if hasHistory && ip != "" {
+		if in.knownIP, err = s.repo.HasSeenLoginIP(ctx, user.ID, ip); err != nil {
+			log.Printf("login risk: failed to check ip history for user %s: %v", user.ID, err)
+			in.knownIP = true
+		}
+	}
+
+	if in.recentLogins, err = s.repo.RecentLoginTimes(ctx, user.ID, loginHistoryLimit); err != nil {
+		log.Printf("login risk: failed to load login times for user %s: %v", user.ID, err)
+	}
+
+	if s.loginAttempts != nil {
+		in.ipFailures, in.phoneFailures = s.loginAttempts.RecentLoginFailures(ip, user.Phone)
+	}
+
+	if s.geolocator != nil && ip != "" {
+		in.location = s.locate(ctx, ip)
+		if in.location != nil && s.deviceRepo != nil {
+			if last, err := s.deviceRepo.LastSeenDevice(ctx, user.ID); err == nil && strings.TrimSpace(last.IP) != ip {
+				in.lastLocation = s.locate(ctx, strings.TrimSpace(last.IP))
+				in.lastSeenAt = last.LastUsedAt
+			}
+		}
+	}
+
+	score, signals := scoreLoginRisk(in)
+	assessment := &LoginRiskAssessment{
+		UserID:    user.ID,
+		DeviceID:  deviceID,
+		IP:        ip,
+		Score:     score,
+		Level:     loginRiskLevelFor(score),
+		Signals:   joinLoginRiskSignals(signals),
+		CreatedAt: in.now,
+	}
+	if in.location != nil {
+		assessment.Country = in.location.Country
+	}
+	return assessment
+}
+
+func (s *Service) locate(ctx context.Context, ip string) *GeoLocation {
+	location, err := s.geolocator.Locate(ctx, ip)
+	if err != nil {
+		log.Printf("login risk: failed to locate ip %s: %v", ip, err)
+		return nil
+	}
+	return location
+}
+
+// recordLoginRisk stores the assessment with the decision taken on it. Audit
+// failures are logged and never block the login.
+func (s *Service) recordLoginRisk(ctx context.Context, assessment *LoginRiskAssessment, decision LoginRiskDecision) {
+	assessment.ID = uuid.NewString()
+	assessment.Decision = decision
+
+	if assessment.Level != LoginRiskLow {
+		log.Printf("login risk: user %s scored %d (%s) with signals [%s]; decision %s",
+			assessment.UserID, assessment.Score, assessment.Level, assessment.Signals, decision)
+	}
+	if err := s.repo.CreateLoginRiskAssessment(ctx, assessment); err != nil {
+		log.Printf("login risk: failed to record assessment for user %s: %v", assessment.UserID, err)
+	}
+}
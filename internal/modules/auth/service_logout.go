@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
+	"log"
 	appErr "neat_mobile_app_backend/internal/errors"
 	"neat_mobile_app_backend/models"
 	"strings"
@@ -91,6 +92,13 @@ func (s *Service) RefreshAccessToken(ctx context.Context, deviceID, refreshToken
 		}
 	}
 
+	if refreshTokenObj.RevokedAt != nil && refreshTokenObj.ReplacedByJTI != nil {
+		if !withinRefreshReuseGrace(refreshTokenObj, time.Now().UTC()) {
+			s.handleRefreshTokenReuse(ctx, refreshTokenObj)
+		}
+		return nil, appErr.ErrUnauthorized
+	}
+
 	if _, err := s.deviceVerifier.VerifyUserDevice(ctx, refreshTokenObj.UserID, deviceID); err != nil {
 		return nil, err
 	}
@@ -138,6 +146,11 @@ func (s *Service) RefreshAccessToken(ctx context.Context, deviceID, refreshToken
 	}
 
 	if err := s.repo.RotateRefreshToken(ctx, oldJTI, newRefreshTokenRow); err != nil {
+		if errors.Is(err, errRefreshTokenReused) {
+			// Lost a race with another refresh using the same token, which
+			// rotated it moments ago: a client retry, not a replay.
+			return nil, appErr.ErrUnauthorized
+		}
 		return nil, err
 	}
 
@@ -145,6 +158,27 @@ func (s *Service) RefreshAccessToken(ctx context.Context, deviceID, refreshToken
 
 }
 
+// handleRefreshTokenReuse treats a replayed refresh token as stolen: the whole
+// token family and its sessions are revoked and the user is alerted.
+func (s *Service) handleRefreshTokenReuse(ctx context.Context, token *models.RefreshToken) {
+	log.Printf("auth service: refresh token reuse detected for user %s session %s jti %s", token.UserID, token.SessionID, token.JTI)
+
+	revoked, err := s.repo.RevokeRefreshTokenFamily(ctx, token, time.Now().UTC())
+	if err != nil {
+		log.Printf("auth service: failed to revoke token family for session %s: %v", token.SessionID, err)
+		return
+	}
+
+	if s.notifier == nil {
+		return
+	}
+	if err := s.notifier.SendToUser(ctx, token.UserID, "Security alert", "security",
+		"We noticed an old sign-in token being reused and signed you out to protect your account. If this wasn't you, change your password.",
+		map[string]any{"event": "refresh_token_reuse", "sessions_revoked": revoked}); err != nil {
+		log.Printf("auth service: failed to send token reuse alert to user %s: %v", token.UserID, err)
+	}
+}
+
 func (s *Service) IsSessionActive(ctx context.Context, sid, mobileUserID, deviceID string) (bool, error) {
 	return s.repo.CheckSession(ctx, sid, mobileUserID, deviceID)
 }
@@ -16,7 +16,7 @@ import (
 	"gorm.io/gorm"
 )
 
-func (s *Service) RequestPasswordChange(ctx context.Context, mobileUserID string) (*RequestChangePasswordResponse, error) {
+func (s *Service) RequestPasswordChange(ctx context.Context, mobileUserID string, channel authotp.Channel) (*RequestChangePasswordResponse, error) {
 	if strings.TrimSpace(mobileUserID) == "" {
 		return nil, errors.New("mobile user id is required")
 	}
@@ -37,9 +37,10 @@ func (s *Service) RequestPasswordChange(ctx context.Context, mobileUserID string
 	if err != nil {
 		return nil, appErr.ErrInvalidPhone
 	}
+	channel = stepUpChannel(channel)
 	result, err := s.otpManager.Issue(ctx, authotp.IssueOTPInput{
 		Purpose:     authotp.PurposePasswordChange,
-		Channel:     authotp.ChannelSMS,
+		Channel:     channel,
 		Destination: phone,
 		UserID:      mobileUserID,
 		TTL:         10 * time.Minute,
@@ -52,7 +53,8 @@ func (s *Service) RequestPasswordChange(ctx context.Context, mobileUserID string
 	}
 
 	return &RequestChangePasswordResponse{
-		OTPID: result.OTPID,
+		OTPID:   result.OTPID,
+		Channel: string(channel),
 	}, nil
 }
 
@@ -17,7 +17,7 @@ import (
 	"gorm.io/gorm"
 )
 
-func (s *Service) ForgotTransactionPin(ctx context.Context, mobileUserID string) (*ForgotTransactionPinResponse, error) {
+func (s *Service) ForgotTransactionPin(ctx context.Context, mobileUserID string, channel authotp.Channel) (*ForgotTransactionPinResponse, error) {
 	if s.otpManager == nil {
 		return nil, errors.New("otp manager not configured")
 	}
@@ -32,9 +32,10 @@ func (s *Service) ForgotTransactionPin(ctx context.Context, mobileUserID string)
 		return nil, appErr.ErrInvalidPhone
 	}
 
+	channel = stepUpChannel(channel)
 	result, err := s.otpManager.Issue(ctx, authotp.IssueOTPInput{
 		Purpose:     authotp.PurposePinReset,
-		Channel:     authotp.ChannelSMS,
+		Channel:     channel,
 		Destination: phone,
 		UserID:      mobileUserID,
 		TTL:         10 * time.Minute,
@@ -46,10 +47,16 @@ func (s *Service) ForgotTransactionPin(ctx context.Context, mobileUserID string)
 		return nil, err
 	}
 
+	message := "OTP has been sent to your phone"
+	if channel == authotp.ChannelTOTP {
+		message = "Enter the code from your authenticator app"
+	}
+
 	return &ForgotTransactionPinResponse{
 		Status:  "success",
-		Message: "OTP has been sent to your phone",
+		Message: message,
 		OTPID:   result.OTPID,
+		Channel: string(channel),
 	}, err
 }
 
@@ -191,7 +198,7 @@ func (s *Service) ResendForgotTransactionPinOTP(ctx context.Context, mobileUserI
 	return nil
 }
 
-func (s *Service) RequestTransactionPinChange(ctx context.Context, mobileUserID string) (*RequestTransactionPinChangeResponse, error) {
+func (s *Service) RequestTransactionPinChange(ctx context.Context, mobileUserID string, channel authotp.Channel) (*RequestTransactionPinChangeResponse, error) {
 	if strings.TrimSpace(mobileUserID) == "" {
 		return nil, errors.New("mobile user id is required")
 	}
@@ -210,9 +217,10 @@ func (s *Service) RequestTransactionPinChange(ctx context.Context, mobileUserID
 		return nil, appErr.ErrInvalidPhone
 	}
 
+	channel = stepUpChannel(channel)
 	result, err := s.otpManager.Issue(ctx, authotp.IssueOTPInput{
 		Purpose:     authotp.PurposePinChange,
-		Channel:     authotp.ChannelSMS,
+		Channel:     channel,
 		Destination: phone,
 		UserID:      mobileUserID,
 		TTL:         10 * time.Minute,
@@ -224,7 +232,8 @@ func (s *Service) RequestTransactionPinChange(ctx context.Context, mobileUserID
 	}
 
 	return &RequestTransactionPinChangeResponse{
-		OTPID: result.OTPID,
+		OTPID:   result.OTPID,
+		Channel: string(channel),
 	}, nil
 }
 
@@ -0,0 +1,165 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log"
+	appErr "neat_mobile_app_backend/internal/errors"
+	"neat_mobile_app_backend/internal/modules/device"
+	"neat_mobile_app_backend/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ListSessions returns the user's active sessions, flagging the one the
+// request was made with.
+func (s *Service) ListSessions(ctx context.Context, userID, currentSID string) ([]SessionResponse, error) {
+	sessions, err := s.repo.ListActiveSessions(ctx, userID, time.Now().UTC())
+	if err != nil {
+		log.Printf("auth service: failed to list sessions for user %s: %v", userID, err)
+		return nil, appErr.ErrGettingData
+	}
+
+	devices := map[string]device.UserDevice{}
+	if s.deviceRepo != nil {
+		userDevices, err := s.deviceRepo.ListUserDevices(ctx, userID)
+		if err != nil {
+			log.Printf("auth service: failed to list devices for user %s: %v", userID, err)
+		}
+		for _, d := range userDevices {
+			devices[d.DeviceID] = d
+		}
+	}
+
+	out := make([]SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		out = append(out, toSessionResponse(session, devices, currentSID))
+	}
+	return out, nil
+}
+
+// RevokeSession ends one of the user's sessions. The access token tied to it
+// stops working on its next request.
+func (s *Service) RevokeSession(ctx context.Context, userID, sid string) error {
+	sid = strings.TrimSpace(sid)
+	if sid == "" {
+		return appErr.ErrSessionNotFound
+	}
+
+	revoked, err := s.repo.RevokeSession(ctx, userID, sid, time.Now().UTC())
+	if err != nil {
+		log.Printf("auth service: failed to revoke session %s: %v", sid, err)
+		return appErr.ErrRevokingSession
+	}
+	if revoked == 0 {
+		return appErr.ErrSessionNotFound
+	}
+	return nil
+}
+
+// RevokeOtherSessions ends every session of the user except the current one.
+func (s *Service) RevokeOtherSessions(ctx context.Context, userID, currentSID string) (*RevokeSessionsResponse, error) {
+	revoked, err := s.repo.RevokeOtherSessions(ctx, userID, currentSID, time.Now().UTC())
+	if err != nil {
+		log.Printf("auth service: failed to revoke other sessions for user %s: %v", userID, err)
+		return nil, appErr.ErrRevokingSession
+	}
+	return &RevokeSessionsResponse{Revoked: revoked}, nil
+}
+
+// ListDevices returns the devices the user is signed in on.
+func (s *Service) ListDevices(ctx context.Context, userID, currentSID string) ([]DeviceResponse, error) {
+	if s.deviceRepo == nil {
+		return []DeviceResponse{}, nil
+	}
+
+	devices, err := s.deviceRepo.ListUserDevices(ctx, userID)
+	if err != nil {
+		log.Printf("auth service: failed to list devices for user %s: %v", userID, err)
+		return nil, appErr.ErrGettingData
+	}
+
+	currentDeviceID := s.sessionDeviceID(ctx, currentSID)
+	out := make([]DeviceResponse, 0, len(devices))
+	for _, d := range devices {
+		out = append(out, DeviceResponse{
+			DeviceID:    d.DeviceID,
+			DeviceName:  d.DeviceName,
+			DeviceModel: d.DeviceModel,
+			OS:          d.OS,
+			OSVersion:   d.OSVersion,
+			AppVersion:  d.AppVersion,
+			IP:          d.IP,
+			IsTrusted:   d.IsTrusted,
+			IsCurrent:   currentDeviceID != "" && d.DeviceID == currentDeviceID,
+			LastUsedAt:  d.LastUsedAt,
+			CreatedAt:   d.CreatedAt,
+		})
+	}
+	return out, nil
+}
+
+// UntrustDevice removes trust from a device and signs it out, so the next
+// login from it needs a fresh device verification.
+func (s *Service) UntrustDevice(ctx context.Context, userID, deviceID string) (*RevokeSessionsResponse, error) {
+	deviceID = strings.TrimSpace(deviceID)
+	if deviceID == "" || s.deviceRepo == nil {
+		return nil, appErr.ErrDeviceNotFound
+	}
+
+	if err := s.deviceRepo.UntrustDevice(ctx, userID, deviceID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErr.ErrDeviceNotFound
+		}
+		log.Printf("auth service: failed to untrust device %s: %v", deviceID, err)
+		return nil, appErr.ErrRevokingSession
+	}
+
+	revoked, err := s.repo.RevokeDeviceSessions(ctx, userID, deviceID, time.Now().UTC())
+	if err != nil {
+		log.Printf("auth service: failed to revoke sessions on device %s: %v", deviceID, err)
+		return nil, appErr.ErrRevokingSession
+	}
+	return &RevokeSessionsResponse{Revoked: revoked}, nil
+}
+
+func (s *Service) sessionDeviceID(ctx context.Context, sid string) string {
+	if strings.TrimSpace(sid) == "" {
+		return ""
+	}
+	session, err := s.repo.GetAccessTokenWithSID(ctx, sid)
+	if err != nil || session.DeviceID == nil {
+		return ""
+	}
+	return strings.TrimSpace(*session.DeviceID)
+}
+
+func toSessionResponse(session models.AuthSession, devices map[string]device.UserDevice, currentSID string) SessionResponse {
+	res := SessionResponse{
+		SessionID:  session.SID,
+		CreatedAt:  session.CreatedAt,
+		LastUsedAt: session.LastSeenAt,
+		IsCurrent:  session.SID == currentSID,
+	}
+	if session.IP != nil {
+		res.IP = *session.IP
+	}
+	if session.DeviceID == nil {
+		return res
+	}
+
+	res.DeviceID = *session.DeviceID
+	if d, ok := devices[res.DeviceID]; ok {
+		res.DeviceName = d.DeviceName
+		res.DeviceModel = d.DeviceModel
+		res.OS = d.OS
+		res.OSVersion = d.OSVersion
+		res.AppVersion = d.AppVersion
+		if res.IP == "" {
+			res.IP = d.IP
+		}
+	}
+	return res
+}
@@ -3,8 +3,10 @@ package auth
 import (
 	"context"
 	"errors"
+	"neat_mobile_app_backend/models"
 	"neat_mobile_app_backend/providers/bvn"
 	"testing"
+	"time"
 )
 
 type stubProviderSource struct {
@@ -170,3 +172,20 @@ func TestService_ValidateBVN_RoutesToPrembly(t *testing.T) {
 		t.Fatal("did not expect tendar validator to be called")
 	}
 }
+
+func TestWithinRefreshReuseGrace(t *testing.T) {
+	now := time.Now().UTC()
+	replacedBy := "jti-2"
+	rotatedAt := now.Add(-2 * time.Second)
+	token := &models.RefreshToken{JTI: "jti-1", RevokedAt: &rotatedAt, ReplacedByJTI: &replacedBy}
+
+	if !withinRefreshReuseGrace(token, now) {
+		t.Fatal("a replay seconds after rotation should be within the grace window")
+	}
+	if withinRefreshReuseGrace(token, now.Add(refreshReuseGrace)) {
+		t.Fatal("a replay after the grace window should be treated as reuse")
+	}
+	if withinRefreshReuseGrace(&models.RefreshToken{JTI: "jti-1", RevokedAt: &rotatedAt}, now) {
+		t.Fatal("a token revoked without a successor was not rotated")
+	}
+}
@@ -0,0 +1,208 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log"
+	appErr "neat_mobile_app_backend/internal/errors"
+	authotp "neat_mobile_app_backend/internal/modules/auth/otp"
+	phoneutil "neat_mobile_app_backend/internal/phone"
+	"strings"
+	"time"
+)
+
+// stepUpChannel is the channel a step-up OTP is answered on. Clients that
+// don't ask for one keep getting SMS.
+func stepUpChannel(channel authotp.Channel) authotp.Channel {
+	if channel == "" {
+		return authotp.ChannelSMS
+	}
+	return channel
+}
+
+// totpEnabled reports whether userID can answer OTPs from an authenticator
+// app. Lookup failures are treated as not enabled since SMS still works.
+func (s *Service) totpEnabled(ctx context.Context, userID string) bool {
+	if s.totp == nil {
+		return false
+	}
+	enabled, err := s.totp.TOTPEnabled(ctx, userID)
+	if err != nil {
+		log.Printf("auth service: failed to check totp enrollment for user %s: %v", userID, err)
+		return false
+	}
+	return enabled
+}
+
+func (s *Service) GetTOTPStatus(ctx context.Context, mobileUserID string) (*TOTPStatusResponse, error) {
+	if s.totp == nil {
+		return nil, errors.New("totp manager not configured")
+	}
+
+	status, err := s.totp.TOTPStatus(ctx, mobileUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TOTPStatusResponse{
+		Enabled:                status.Enabled,
+		ConfirmedAt:            status.ConfirmedAt,
+		RecoveryCodesRemaining: status.RecoveryCodesRemaining,
+	}, nil
+}
+
+// BeginTOTPEnrollment returns a fresh authenticator secret once the user has
+// proved their transaction PIN.
+func (s *Service) BeginTOTPEnrollment(ctx context.Context, mobileUserID string, req BeginTOTPEnrollmentRequest) (*BeginTOTPEnrollmentResponse, error) {
+	if s.totp == nil {
+		return nil, errors.New("totp manager not configured")
+	}
+
+	if err := s.VerifyTransactionPin(ctx, mobileUserID, req.TransactionPin); err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.GetUserByID(ctx, mobileUserID)
+	if err != nil {
+		return nil, appErr.ErrUnauthorized
+	}
+	phone, err := phoneutil.NormalizeNigerianNumber(strings.TrimSpace(user.Phone))
+	if err != nil {
+		return nil, appErr.ErrInvalidPhone
+	}
+
+	setup, err := s.totp.BeginTOTPEnrollment(ctx, mobileUserID, phone)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BeginTOTPEnrollmentResponse{
+		Secret:          setup.Secret,
+		ProvisioningURI: setup.ProvisioningURI,
+	}, nil
+}
+
+func (s *Service) ConfirmTOTPEnrollment(ctx context.Context, mobileUserID string, req ConfirmTOTPEnrollmentRequest) (*TOTPRecoveryCodesResponse, error) {
+	if s.totp == nil {
+		return nil, errors.New("totp manager not configured")
+	}
+
+	codes, err := s.totp.ConfirmTOTPEnrollment(ctx, mobileUserID, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	s.sendTOTPAlert(ctx, mobileUserID, "totp_enabled", "An authenticator app was added to your account. If this wasn't you, contact support immediately.")
+	return &TOTPRecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// RequestTOTPManagementOTP issues the OTP that authorises regenerating
+// recovery codes or disabling the authenticator. SMS stays available so a
+// user who lost the app can still turn it off.
+func (s *Service) RequestTOTPManagementOTP(ctx context.Context, mobileUserID string, channel authotp.Channel) (*RequestTOTPManagementOTPResponse, error) {
+	if s.otpManager == nil {
+		return nil, errors.New("otp manager not configured")
+	}
+
+	user, err := s.repo.GetUserByID(ctx, mobileUserID)
+	if err != nil {
+		return nil, appErr.ErrUnauthorized
+	}
+	phone, err := phoneutil.NormalizeNigerianNumber(strings.TrimSpace(user.Phone))
+	if err != nil {
+		return nil, appErr.ErrInvalidPhone
+	}
+
+	channel = stepUpChannel(channel)
+	result, err := s.otpManager.Issue(ctx, authotp.IssueOTPInput{
+		Purpose:     authotp.PurposeTOTPManage,
+		Channel:     channel,
+		Destination: phone,
+		UserID:      mobileUserID,
+		TTL:         10 * time.Minute,
+		MaxAttempts: 5,
+		MaxResends:  3,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &RequestTOTPManagementOTPResponse{
+		OTPID:   result.OTPID,
+		Channel: string(channel),
+	}, nil
+}
+
+func (s *Service) RegenerateTOTPRecoveryCodes(ctx context.Context, mobileUserID string, req ManageTOTPRequest) (*TOTPRecoveryCodesResponse, error) {
+	if s.totp == nil {
+		return nil, errors.New("totp manager not configured")
+	}
+
+	if err := s.authorizeTOTPChange(ctx, mobileUserID, req); err != nil {
+		return nil, err
+	}
+
+	codes, err := s.totp.RegenerateTOTPRecoveryCodes(ctx, mobileUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TOTPRecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (s *Service) DisableTOTP(ctx context.Context, mobileUserID string, req ManageTOTPRequest) error {
+	if s.totp == nil {
+		return errors.New("totp manager not configured")
+	}
+
+	if err := s.authorizeTOTPChange(ctx, mobileUserID, req); err != nil {
+		return err
+	}
+
+	if err := s.totp.DisableTOTP(ctx, mobileUserID); err != nil {
+		return err
+	}
+
+	s.sendTOTPAlert(ctx, mobileUserID, "totp_disabled", "The authenticator app was removed from your account. If this wasn't you, contact support immediately.")
+	return nil
+}
+
+// authorizeTOTPChange requires the transaction PIN and an OTP issued by
+// RequestTOTPManagementOTP. The verification the OTP produces is spent
+// straight away so it can't be reused to reset the PIN or password.
+func (s *Service) authorizeTOTPChange(ctx context.Context, mobileUserID string, req ManageTOTPRequest) error {
+	if s.otpManager == nil {
+		return errors.New("otp manager not configured")
+	}
+
+	if err := s.VerifyTransactionPin(ctx, mobileUserID, req.TransactionPin); err != nil {
+		return err
+	}
+
+	result, err := s.otpManager.Verify(ctx, authotp.VerifyOTPInput{
+		Purpose: authotp.PurposeTOTPManage,
+		OTPID:   strings.TrimSpace(req.OTPID),
+		Code:    strings.TrimSpace(req.OTPCode),
+	})
+	if err != nil {
+		return err
+	}
+	if result == nil || result.UserID != mobileUserID {
+		return appErr.ErrInvalidOTP
+	}
+
+	if err := s.verification.MarkVerificationUsed(ctx, result.VerificationID, time.Now().UTC()); err != nil {
+		log.Printf("auth service: failed to spend totp management verification %s: %v", result.VerificationID, err)
+		return appErr.ErrUnauthorized
+	}
+	return nil
+}
+
+func (s *Service) sendTOTPAlert(ctx context.Context, mobileUserID, event, body string) {
+	if s.notifier == nil {
+		return
+	}
+	if err := s.notifier.SendToUser(ctx, mobileUserID, "Security alert", "security", body, map[string]any{"event": event}); err != nil {
+		log.Printf("auth service: failed to send %s alert to user %s: %v", event, mobileUserID, err)
+	}
+}
@@ -1,5 +1,23 @@
 package auth
 
+import (
+	"errors"
+	"time"
+)
+
+// sessionSeenInterval throttles how often a session's last_seen_at is
+// refreshed while it is being used.
+const sessionSeenInterval = time.Minute
+
+// refreshReuseGrace is how long after a refresh token is rotated a replay of
+// it is taken for a client retry, such as a lost response or two tabs
+// refreshing at once, rather than theft.
+const refreshReuseGrace = 10 * time.Second
+
+// errRefreshTokenReused is returned when a refresh token that was already
+// rotated away is presented again.
+var errRefreshTokenReused = errors.New("refresh token reuse detected")
+
 type TokenType string
 
 type ErrCode string
@@ -20,11 +38,15 @@ type LoginInitObject struct {
 	Status       string
 	Challenge    string
 	SessionToken string
+	// TOTPAvailable tells the client the OTP step can be answered from an
+	// authenticator app instead of the SMS.
+	TOTPAvailable bool
 }
 
 const (
 	LoginStatusChallengeRequired = "challenge_required"
 	LoginStatusNewDeviceDetected = "new_device_detected"
+	LoginStatusStepUpRequired    = "step_up_required"
 )
 
 type Provider string
@@ -2,6 +2,7 @@ package autorepayment
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"math"
@@ -9,12 +10,15 @@ import (
 	"strings"
 	"time"
 
+	appErr "neat_mobile_app_backend/internal/errors"
+	"neat_mobile_app_backend/internal/modules/limits"
 	"neat_mobile_app_backend/internal/modules/loanproduct"
 	"neat_mobile_app_backend/internal/modules/notification"
 	"neat_mobile_app_backend/internal/modules/transaction"
 	"neat_mobile_app_backend/internal/modules/wallet"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type Service struct {
@@ -24,6 +28,7 @@ type Service struct {
 	repayer             loanproduct.ManualRepayer
 	notificationService *notification.Service
 	settlementAccount   wallet.SettlementAccount
+	limits              wallet.LimitChecker
 }
 
 func NewService(
@@ -33,6 +38,7 @@ func NewService(
 	repayer loanproduct.ManualRepayer,
 	notificationService *notification.Service,
 	settlementAccount wallet.SettlementAccount,
+	limitChecker wallet.LimitChecker,
 ) *Service {
 	return &Service{
 		repository:          repository,
@@ -41,6 +47,7 @@ func NewService(
 		repayer:             repayer,
 		notificationService: notificationService,
 		settlementAccount:   settlementAccount,
+		limits:              limitChecker,
 	}
 }
 
@@ -97,6 +104,11 @@ func (s *Service) processSingle(ctx context.Context, row DueRepaymentRow) {
 		return
 	}
 
+	if err := wallet.DebitAllowed(w.Status); err != nil {
+		_ = s.repository.UpdateAttemptStatus(ctx, attemptID, AutoRepaymentAttemptStatusSkipped, "wallet restricted: "+string(wallet.NormalizeWalletStatus(w.Status)), "")
+		return
+	}
+
 	amountKobo := row.Amount * 100
 	if w.AvailableBalance < amountKobo {
 		_ = s.repository.UpdateAttemptStatus(ctx, attemptID, AutoRepaymentAttemptStatusSkipped, "insufficient balance", "")
@@ -110,7 +122,8 @@ func (s *Service) processSingle(ctx context.Context, row DueRepaymentRow) {
 	narration := "Loan auto-repayment"
 	accountName := s.settlementAccount.AccountName
 	txID := uuid.NewString()
-	if err := s.walletRepository.AddTransaction(ctx, &transaction.Transaction{
+	reference := uuid.NewString()
+	if err := s.walletRepository.AddDebitTransaction(ctx, &transaction.Transaction{
 		ID:                  txID,
 		MobileUserID:        row.MobileUserID,
 		WalletID:            walletUser.WalletID,
@@ -118,13 +131,25 @@ func (s *Service) processSingle(ctx context.Context, row DueRepaymentRow) {
 		Category:            transaction.TransactionCategoryLoanRepayment,
 		Source:              transaction.TransactionSourceAutoRepayment,
 		Amount:              amountKobo,
-		Reference:           uuid.NewString(),
+		Reference:           reference,
 		Narration:           &narration,
 		CounterpartyAccount: s.settlementAccount.AccountNumber,
 		CounterpartyName:    accountName,
 		CounterpartyBank:    s.settlementAccount.BankCode,
 		Status:              transaction.TransactionStatusPending,
-	}); err != nil {
+	}, wallet.TransferChargeKobo(amountKobo), s.limitCheck(ctx, row.MobileUserID, amountKobo)); err != nil {
+		if errors.Is(err, wallet.ErrInsufficientFunds) {
+			_ = s.repository.UpdateAttemptStatus(ctx, attemptID, AutoRepaymentAttemptStatusSkipped, "insufficient balance", "")
+			return
+		}
+		if limits.IsLimitError(err) {
+			_ = s.repository.UpdateAttemptStatus(ctx, attemptID, AutoRepaymentAttemptStatusSkipped, "limit: "+err.Error(), "")
+			return
+		}
+		if wallet.IsWalletRestricted(err) {
+			_ = s.repository.UpdateAttemptStatus(ctx, attemptID, AutoRepaymentAttemptStatusSkipped, "wallet restricted: "+err.Error(), "")
+			return
+		}
 		log.Printf("auto-repayment: failed to create transaction for repayment %d: %v", row.RepaymentID, err)
 		_ = s.repository.UpdateAttemptStatus(ctx, attemptID, AutoRepaymentAttemptStatusFailed, err.Error(), "")
 		return
@@ -136,6 +161,7 @@ func (s *Service) processSingle(ctx context.Context, row DueRepaymentRow) {
 		AccountNumber: s.settlementAccount.AccountNumber,
 		AccountName:   &accountName,
 		Narration:     &narration,
+		Reference:     reference,
 	})
 	if err != nil || resp == nil || !resp.Status {
 		reason := "provider transfer failed"
@@ -144,7 +170,11 @@ func (s *Service) processSingle(ctx context.Context, row DueRepaymentRow) {
 		} else if strings.TrimSpace(resp.Message) != "" {
 			reason = resp.Message
 		}
-		_ = s.walletRepository.UpdateTransactionStatus(ctx, txID, transaction.TransactionStatusFailed)
+		if errors.Is(err, appErr.ErrTransferAmbiguous) {
+			_ = s.walletRepository.MarkTransactionAmbiguous(ctx, txID, transaction.TransactionStatusFailed)
+		} else {
+			_ = s.walletRepository.UpdateTransactionStatus(ctx, txID, transaction.TransactionStatusFailed)
+		}
 		_ = s.repository.UpdateAttemptStatus(ctx, attemptID, AutoRepaymentAttemptStatusFailed, reason, "")
 		_ = s.notificationService.SendToUser(ctx, row.MobileUserID,
 			"Auto-repayment failed", "loan",
@@ -152,9 +182,10 @@ func (s *Service) processSingle(ctx context.Context, row DueRepaymentRow) {
 		return
 	}
 
-	totalDebit := amountKobo + int64(math.Round(resp.Transfer.Charges*100)) + int64(math.Round(resp.Transfer.Vat*100))
+	charges := int64(math.Round(resp.Transfer.Charges * 100))
+	vat := int64(math.Round(resp.Transfer.Vat * 100))
 	if err := s.walletRepository.CompleteDebitTransaction(ctx, txID, resp.Transfer.TransactionReference,
-		transaction.TransactionStatusSuccessful, walletUser.WalletID, totalDebit); err != nil {
+		transaction.TransactionStatusSuccessful, walletUser.WalletID, amountKobo, charges, vat); err != nil {
 		log.Printf("auto-repayment: failed to complete debit for repayment %d: %v", row.RepaymentID, err)
 		_ = s.repository.UpdateAttemptStatus(ctx, attemptID, AutoRepaymentAttemptStatusFailed, err.Error(), resp.Transfer.TransactionReference)
 		return
@@ -182,3 +213,14 @@ func (s *Service) processSingle(ctx context.Context, row DueRepaymentRow) {
 		fmt.Sprintf("Your loan auto-repayment of ₦%d was successful.", row.Amount),
 		nil)
 }
+
+// limitCheck holds a repayment debit (kobo) against the user's transfer limits
+// inside the database transaction that books it.
+func (s *Service) limitCheck(ctx context.Context, mobileUserID string, amount int64) wallet.DebitCheck {
+	if s.limits == nil {
+		return nil
+	}
+	return func(tx *gorm.DB) error {
+		return s.limits.CheckTx(ctx, tx, mobileUserID, limits.ChannelTransfer, amount)
+	}
+}
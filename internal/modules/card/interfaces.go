@@ -3,6 +3,7 @@ package card
 import (
 	"context"
 	"neat_mobile_app_backend/internal/modules/device"
+	"neat_mobile_app_backend/internal/modules/limits"
 	"neat_mobile_app_backend/providers/card"
 )
 
@@ -13,3 +14,7 @@ type DeviceVerifier interface {
 type CardService interface {
 	RequestCard(ctx context.Context, requestInfo *card.OptimusCardRequest) error
 }
+
+type LimitChecker interface {
+	Check(ctx context.Context, mobileUserID string, channel limits.Channel, amounts ...int64) error
+}
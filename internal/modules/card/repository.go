@@ -2,6 +2,8 @@ package card
 
 import (
 	"context"
+	"errors"
+	appErr "neat_mobile_app_backend/internal/errors"
 	"neat_mobile_app_backend/internal/modules/wallet"
 
 	"gorm.io/gorm"
@@ -18,7 +20,7 @@ func NewRepository(db *gorm.DB) *Repository {
 func (r *Repository) FindWalletWithMobileUserID(ctx context.Context, mobileUserID string) (*wallet.CustomerWallet, error) {
 	var wallet wallet.CustomerWallet
 	err := r.db.WithContext(ctx).
-		Select("account_number, account_name, phone_number, internal_wallet_id, available_balance, address, bvn").
+		Select("account_number, account_name, phone_number, internal_wallet_id, available_balance, address, bvn, status").
 		Where("mobileUserID = ?", mobileUserID).
 		First(&wallet).Error
 
@@ -28,3 +30,18 @@ func (r *Repository) FindWalletWithMobileUserID(ctx context.Context, mobileUserI
 
 	return &wallet, nil
 }
+
+// ChargeWallet runs request while holding the wallet row, once the wallet is
+// confirmed debitable with at least charge kobo available, so a freeze can't
+// land between the check and the provider taking its fee.
+func (r *Repository) ChargeWallet(ctx context.Context, internalWalletID string, charge int64, request func() error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := wallet.LockWalletForDebit(tx, internalWalletID, charge); err != nil {
+			if errors.Is(err, wallet.ErrInsufficientFunds) {
+				return appErr.ErrInsufficientBalance
+			}
+			return err
+		}
+		return request()
+	})
+}
@@ -3,6 +3,8 @@ package card
 import (
 	"context"
 	appErr "neat_mobile_app_backend/internal/errors"
+	"neat_mobile_app_backend/internal/modules/limits"
+	walletmodule "neat_mobile_app_backend/internal/modules/wallet"
 	"neat_mobile_app_backend/providers/card"
 
 	"github.com/google/uuid"
@@ -12,10 +14,13 @@ type Service struct {
 	repo           *Repository
 	deviceVerifier DeviceVerifier
 	cardService    CardService
+	limits         LimitChecker
+	// issuanceFee is what the card provider charges for a new card, in naira.
+	issuanceFee int64
 }
 
-func NewService(repo *Repository, deviceVerifier DeviceVerifier, cardService CardService) *Service {
-	return &Service{repo: repo, deviceVerifier: deviceVerifier, cardService: cardService}
+func NewService(repo *Repository, deviceVerifier DeviceVerifier, cardService CardService, limitChecker LimitChecker, issuanceFee int64) *Service {
+	return &Service{repo: repo, deviceVerifier: deviceVerifier, cardService: cardService, limits: limitChecker, issuanceFee: issuanceFee}
 }
 
 func (s *Service) RequestForCard(ctx context.Context, mobileUserID, deviceID string, payload RequestForCardRequest) error {
@@ -27,13 +32,21 @@ func (s *Service) RequestForCard(ctx context.Context, mobileUserID, deviceID str
 	if err != nil {
 		return appErr.ErrRequestingForCard
 	}
+	if err := walletmodule.DebitAllowed(wallet.Status); err != nil {
+		return err
+	}
 
 	if payload.DeliveryFee < 0 {
 		return appErr.ErrInvalidTransferAmount
 	}
 
-	if payload.DeliveryFee > 0 && payload.DeliveryFee < wallet.AvailableBalance {
-		return appErr.ErrInsufficientBalance
+	// The provider debits the issuance and delivery fees from the wallet
+	// itself, so the whole charge is held against the card limits here.
+	charge := (s.issuanceFee + payload.DeliveryFee) * 100
+	if charge > 0 {
+		if err := s.limits.Check(ctx, mobileUserID, limits.ChannelCard, charge); err != nil {
+			return err
+		}
 	}
 
 	referenceID := uuid.NewString()
@@ -55,5 +68,7 @@ func (s *Service) RequestForCard(ctx context.Context, mobileUserID, deviceID str
 		BranchPickupLocation: payload.BranchPickupLocation,
 	}
 
-	return s.cardService.RequestCard(ctx, &cSPayload)
+	return s.repo.ChargeWallet(ctx, wallet.InternalWalletID, charge, func() error {
+		return s.cardService.RequestCard(ctx, &cSPayload)
+	})
 }
@@ -194,6 +194,47 @@ func (r *Repository) DeactivateDevice(ctx context.Context, userID, deviceID stri
 	return nil
 }
 
+// LastSeenDevice returns the user's most recently used device that has an IP
+// on record.
+func (r *Repository) LastSeenDevice(ctx context.Context, userID string) (*UserDevice, error) {
+	var device UserDevice
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND ip <> ''", userID).
+		Order("last_used_at DESC").
+		First(&device).Error; err != nil {
+		return nil, err
+	}
+	return &device, nil
+}
+
+func (r *Repository) ListUserDevices(ctx context.Context, userID string) ([]UserDevice, error) {
+	var devices []UserDevice
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND is_active = ?", userID, true).
+		Order("last_used_at DESC").
+		Find(&devices).Error
+	return devices, err
+}
+
+// UntrustDevice drops the trust on a device so the next login from it has to
+// go through device verification again.
+func (r *Repository) UntrustDevice(ctx context.Context, userID, deviceID string) error {
+	result := r.db.WithContext(ctx).
+		Model(&UserDevice{}).
+		Where("user_id = ? AND device_id = ?", userID, deviceID).
+		Updates(map[string]any{
+			"is_trusted": false,
+			"is_active":  false,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
 func (r *Repository) RefreshPendingSession(ctx context.Context, id, otpRef string, expiresAt, now time.Time) error {
 	return r.db.WithContext(ctx).
 		Model(&models.PendingDeviceSession{}).
@@ -0,0 +1,98 @@
+package dispute
+
+import (
+	"io"
+	"time"
+)
+
+// RaiseDisputeRequest is the form part of the multipart upload; evidence
+// comes in as up to three "attachments" files.
+type RaiseDisputeRequest struct {
+	TransactionID string        `form:"transaction_id" binding:"required"`
+	Reason        DisputeReason `form:"reason" binding:"required,oneof=not_received wrong_amount duplicate_debit unauthorized value_not_given other"`
+	Description   string        `form:"description" binding:"required,max=1000"`
+}
+
+type Attachment struct {
+	Name        string
+	Body        io.ReadSeeker
+	ContentType string
+	Size        int64
+}
+
+type RaiseDisputeInput struct {
+	RaiseDisputeRequest
+	Attachments []*Attachment
+}
+
+type DisputeResponse struct {
+	ID                    string                 `json:"id"`
+	Reference             string                 `json:"reference"`
+	TransactionID         string                 `json:"transaction_id"`
+	TransactionReference  string                 `json:"transaction_reference"`
+	Amount                float64                `json:"amount"`
+	Reason                DisputeReason          `json:"reason"`
+	Description           string                 `json:"description"`
+	Status                DisputeStatus          `json:"status"`
+	ReversalTransactionID string                 `json:"reversal_transaction_id,omitempty"`
+	ResolvedAt            *time.Time             `json:"resolved_at,omitempty"`
+	CreatedAt             time.Time              `json:"created_at"`
+	Timeline              []DisputeEventResponse `json:"timeline,omitempty"`
+}
+
+type DisputeEventResponse struct {
+	Status    DisputeStatus `json:"status,omitempty"`
+	Note      string        `json:"note,omitempty"`
+	Actor     string        `json:"actor,omitempty"`
+	Internal  bool          `json:"internal,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// DisputeReview is support's view of a dispute, with internal notes and
+// short-lived links to the attachments.
+type DisputeReview struct {
+	DisputeResponse
+	MobileUserID string               `json:"mobile_user_id"`
+	Attachments  []AttachmentResponse `json:"attachments"`
+}
+
+type AttachmentResponse struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	URL         string `json:"url"`
+}
+
+type ListDisputesQuery struct {
+	Status DisputeStatus `form:"status" binding:"omitempty,oneof=open in_review awaiting_customer resolved rejected"`
+	Page   int           `form:"page"`
+	Limit  int           `form:"limit"`
+}
+
+type ListDisputesResponse struct {
+	Items []DisputeResponseItem `json:"items"`
+	Page  int                   `json:"page"`
+	Limit int                   `json:"limit"`
+	Total int64                 `json:"total"`
+}
+
+type DisputeResponseItem struct {
+	DisputeResponse
+	MobileUserID string `json:"mobile_user_id,omitempty"`
+}
+
+type UpdateDisputeStatusRequest struct {
+	Actor  string        `json:"actor" binding:"required"`
+	Status DisputeStatus `json:"status" binding:"required,oneof=in_review awaiting_customer resolved rejected"`
+	Note   string        `json:"note" binding:"omitempty,max=1000"`
+}
+
+type AddDisputeNoteRequest struct {
+	Actor         string `json:"actor" binding:"required"`
+	Note          string `json:"note" binding:"required,max=1000"`
+	VisibleToUser bool   `json:"visible_to_user"`
+}
+
+type ReverseDisputeRequest struct {
+	Actor  string `json:"actor" binding:"required"`
+	Reason string `json:"reason" binding:"omitempty,max=255"`
+}
@@ -0,0 +1,287 @@
+package dispute
+
+import (
+	"mime/multipart"
+	appErr "neat_mobile_app_backend/internal/errors"
+	"neat_mobile_app_backend/internal/middleware"
+	"neat_mobile_app_backend/internal/response"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) RaiseDispute(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrMissingUserID)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	var req RaiseDisputeRequest
+	if err := c.ShouldBind(&req); err != nil {
+		mapped := response.MapError(appErr.ErrInvalidRequestBody)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	input := &RaiseDisputeInput{RaiseDisputeRequest: req}
+	if form, err := c.MultipartForm(); err == nil {
+		files := form.File["attachments"]
+		if len(files) > maxAttachments {
+			mapped := response.MapError(appErr.ErrInvalidDisputeAttachment)
+			c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+				Status: "error",
+				Error:  &mapped.Error,
+			})
+			return
+		}
+		for _, fileHeader := range files {
+			file, err := fileHeader.Open()
+			if err != nil {
+				mapped := response.MapError(appErr.ErrInvalidDisputeAttachment)
+				c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+					Status: "error",
+					Error:  &mapped.Error,
+				})
+				return
+			}
+			defer file.Close()
+			input.Attachments = append(input.Attachments, toAttachment(file, fileHeader))
+		}
+	}
+
+	resp, err := h.service.RaiseDispute(c.Request.Context(), mobileUserID, input)
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[DisputeResponse]{
+		Status:  "success",
+		Message: "Dispute submitted successfully",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) ListUserDisputes(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrMissingUserID)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	var query ListDisputesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		mapped := response.MapError(appErr.ErrInvalidQueryParameter)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	resp, err := h.service.ListUserDisputes(c.Request.Context(), mobileUserID, query)
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[ListDisputesResponse]{
+		Status:  "success",
+		Message: "Disputes fetched successfully",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) GetUserDispute(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrMissingUserID)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	resp, err := h.service.GetUserDispute(c.Request.Context(), mobileUserID, c.Param("id"))
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[DisputeResponse]{
+		Status:  "success",
+		Message: "Dispute fetched successfully",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) ListDisputes(c *gin.Context) {
+	var query ListDisputesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		mapped := response.MapError(appErr.ErrInvalidQueryParameter)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	resp, err := h.service.ListDisputes(c.Request.Context(), query)
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[ListDisputesResponse]{
+		Status:  "success",
+		Message: "Disputes fetched successfully",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) GetDisputeForReview(c *gin.Context) {
+	resp, err := h.service.GetDisputeForReview(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[DisputeReview]{
+		Status:  "success",
+		Message: "Dispute fetched successfully",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) UpdateDisputeStatus(c *gin.Context) {
+	var req UpdateDisputeStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		mapped := response.MapError(appErr.ErrInvalidRequestBody)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	resp, err := h.service.UpdateStatus(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[DisputeResponse]{
+		Status:  "success",
+		Message: "Dispute status updated",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) AddDisputeNote(c *gin.Context) {
+	var req AddDisputeNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		mapped := response.MapError(appErr.ErrInvalidRequestBody)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	if err := h.service.AddNote(c.Request.Context(), c.Param("id"), req); err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[any]{
+		Status:  "success",
+		Message: "Dispute note added",
+	})
+}
+
+func (h *Handler) ReverseDisputedTransaction(c *gin.Context) {
+	var req ReverseDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		mapped := response.MapError(appErr.ErrInvalidRequestBody)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	resp, err := h.service.ReverseDisputedTransaction(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[DisputeResponse]{
+		Status:  "success",
+		Message: "Disputed transaction reversed",
+		Data:    resp,
+	})
+}
+
+func toAttachment(file multipart.File, header *multipart.FileHeader) *Attachment {
+	return &Attachment{
+		Name:        header.Filename,
+		Body:        file,
+		ContentType: header.Header.Get("Content-Type"),
+		Size:        header.Size,
+	}
+}
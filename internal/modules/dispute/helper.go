@@ -0,0 +1,112 @@
+package dispute
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strings"
+)
+
+// referenceAlphabet leaves out characters that are easy to misread.
+const referenceAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+func newDisputeReference() (string, error) {
+	buf := make([]byte, disputeReferenceLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	for i, b := range buf {
+		buf[i] = referenceAlphabet[int(b)%len(referenceAlphabet)]
+	}
+	return disputeReferencePrefix + string(buf), nil
+}
+
+func canTransition(from, to DisputeStatus) bool {
+	for _, next := range disputeTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+func isClosed(status DisputeStatus) bool {
+	return status == DisputeStatusResolved || status == DisputeStatusRejected
+}
+
+func attachmentKey(mobileUserID, disputeID string, index int, contentType string) string {
+	return fmt.Sprintf("disputes/%s/%s/attachment-%d%s", mobileUserID, disputeID, index+1, attachmentExtensions[contentType])
+}
+
+func validAttachment(attachment *Attachment) bool {
+	if attachment == nil || attachment.Size <= 0 || attachment.Size > maxAttachmentSize {
+		return false
+	}
+	_, ok := attachmentExtensions[strings.ToLower(strings.TrimSpace(attachment.ContentType))]
+	return ok
+}
+
+// statusMessage is the push sent to the user when their dispute moves to
+// status. The note, when given, is passed on as support wrote it.
+func statusMessage(dispute *Dispute, note string) (string, string) {
+	var title, body string
+	switch dispute.Status {
+	case DisputeStatusOpen:
+		title = "Dispute received"
+		body = fmt.Sprintf("We have received your dispute %s and will look into it shortly.", dispute.Reference)
+	case DisputeStatusInReview:
+		title = "Dispute under review"
+		body = fmt.Sprintf("Our support team is reviewing your dispute %s.", dispute.Reference)
+	case DisputeStatusAwaitingCustomer:
+		title = "More information needed"
+		body = fmt.Sprintf("We need more information from you to continue with dispute %s.", dispute.Reference)
+	case DisputeStatusResolved:
+		title = "Dispute resolved"
+		body = fmt.Sprintf("Your dispute %s has been resolved.", dispute.Reference)
+		if dispute.ReversalTransactionID != "" {
+			body = fmt.Sprintf("Your dispute %s has been resolved and NGN %.2f refunded to your wallet.", dispute.Reference, float64(dispute.Amount)/100)
+		}
+	case DisputeStatusRejected:
+		title = "Dispute closed"
+		body = fmt.Sprintf("We could not uphold your dispute %s.", dispute.Reference)
+	}
+	if note = strings.TrimSpace(note); note != "" {
+		body += " " + note
+	}
+	return title, body
+}
+
+func toDisputeResponse(dispute *Dispute) DisputeResponse {
+	return DisputeResponse{
+		ID:                    dispute.ID,
+		Reference:             dispute.Reference,
+		TransactionID:         dispute.TransactionID,
+		TransactionReference:  dispute.TransactionReference,
+		Amount:                float64(dispute.Amount) / 100,
+		Reason:                dispute.Reason,
+		Description:           dispute.Description,
+		Status:                dispute.Status,
+		ReversalTransactionID: dispute.ReversalTransactionID,
+		ResolvedAt:            dispute.ResolvedAt,
+		CreatedAt:             dispute.CreatedAt,
+	}
+}
+
+// toTimeline converts events for display. Users see who acted only as the
+// customer or support.
+func toTimeline(events []DisputeEvent, forSupport bool) []DisputeEventResponse {
+	timeline := make([]DisputeEventResponse, 0, len(events))
+	for _, event := range events {
+		actor := event.Actor
+		if !forSupport && actor != userActor {
+			actor = "support"
+		}
+		timeline = append(timeline, DisputeEventResponse{
+			Status:    event.Status,
+			Note:      event.Note,
+			Actor:     actor,
+			Internal:  event.Internal,
+			CreatedAt: event.CreatedAt,
+		})
+	}
+	return timeline
+}
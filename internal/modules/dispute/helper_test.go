@@ -0,0 +1,76 @@
+package dispute
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to DisputeStatus
+		want     bool
+	}{
+		{from: DisputeStatusOpen, to: DisputeStatusInReview, want: true},
+		{from: DisputeStatusOpen, to: DisputeStatusResolved, want: true},
+		{from: DisputeStatusInReview, to: DisputeStatusAwaitingCustomer, want: true},
+		{from: DisputeStatusAwaitingCustomer, to: DisputeStatusInReview, want: true},
+		{from: DisputeStatusInReview, to: DisputeStatusOpen, want: false},
+		{from: DisputeStatusResolved, to: DisputeStatusInReview, want: false},
+		{from: DisputeStatusRejected, to: DisputeStatusResolved, want: false},
+	}
+
+	for _, tc := range tests {
+		if got := canTransition(tc.from, tc.to); got != tc.want {
+			t.Fatalf("canTransition(%s, %s) = %v, want %v", tc.from, tc.to, got, tc.want)
+		}
+	}
+}
+
+func TestValidAttachment(t *testing.T) {
+	tests := []struct {
+		name       string
+		attachment *Attachment
+		want       bool
+	}{
+		{name: "nil", attachment: nil, want: false},
+		{name: "png", attachment: &Attachment{ContentType: "image/png", Size: 1024}, want: true},
+		{name: "empty", attachment: &Attachment{ContentType: "image/png", Size: 0}, want: false},
+		{name: "too large", attachment: &Attachment{ContentType: "application/pdf", Size: maxAttachmentSize + 1}, want: false},
+		{name: "unsupported type", attachment: &Attachment{ContentType: "text/plain", Size: 10}, want: false},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if got := validAttachment(tc.attachment); got != tc.want {
+				t.Fatalf("validAttachment() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNewDisputeReference(t *testing.T) {
+	ref, err := newDisputeReference()
+	if err != nil {
+		t.Fatalf("newDisputeReference() err = %v", err)
+	}
+	if !strings.HasPrefix(ref, disputeReferencePrefix) || len(ref) != len(disputeReferencePrefix)+disputeReferenceLength {
+		t.Fatalf("reference = %q", ref)
+	}
+}
+
+func TestToTimelineHidesSupportNames(t *testing.T) {
+	events := []DisputeEvent{
+		{Status: DisputeStatusOpen, Actor: userActor},
+		{Status: DisputeStatusInReview, Actor: "ada@neatpay.com"},
+	}
+
+	user := toTimeline(events, false)
+	if user[0].Actor != userActor || user[1].Actor != "support" {
+		t.Fatalf("user timeline = %+v", user)
+	}
+	support := toTimeline(events, true)
+	if support[1].Actor != "ada@neatpay.com" {
+		t.Fatalf("support timeline = %+v", support)
+	}
+}
@@ -0,0 +1,18 @@
+package dispute
+
+import (
+	"context"
+	"io"
+	"neat_mobile_app_backend/internal/modules/wallet"
+	"time"
+)
+
+type DocumentStorage interface {
+	UploadDocument(ctx context.Context, key string, body io.ReadSeeker, contentType string) error
+	PresignURL(ctx context.Context, filePath string, ttl time.Duration) (string, error)
+}
+
+// Reverser refunds a disputed transaction.
+type Reverser interface {
+	ReverseTransaction(ctx context.Context, txID, reason string) (*wallet.ReversalResponse, error)
+}
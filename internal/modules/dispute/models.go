@@ -0,0 +1,59 @@
+package dispute
+
+import "time"
+
+// Dispute is a complaint a user raised against one of their wallet
+// transactions. Amount and TransactionReference are copied from the
+// transaction when the dispute is raised.
+type Dispute struct {
+	ID                    string        `gorm:"column:id;type:text;primaryKey"`
+	Reference             string        `gorm:"column:reference;type:text;not null;uniqueIndex"`
+	MobileUserID          string        `gorm:"column:mobile_user_id;type:text;not null;index"`
+	TransactionID         string        `gorm:"column:transaction_id;type:text;not null;index"`
+	TransactionReference  string        `gorm:"column:transaction_reference;type:text;not null"`
+	Amount                int64         `gorm:"column:amount;type:bigint;not null"`
+	Reason                DisputeReason `gorm:"column:reason;type:text;not null"`
+	Description           string        `gorm:"column:description;type:text;not null"`
+	Status                DisputeStatus `gorm:"column:status;type:text;not null;index"`
+	ReversalTransactionID string        `gorm:"column:reversal_transaction_id;type:text"`
+	ResolvedAt            *time.Time    `gorm:"column:resolved_at;type:timestamptz"`
+	CreatedAt             time.Time     `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime"`
+	UpdatedAt             *time.Time    `gorm:"column:updated_at;type:timestamptz;autoUpdateTime"`
+}
+
+func (Dispute) TableName() string {
+	return "wallet_disputes"
+}
+
+// DisputeAttachment is a file the user uploaded with a dispute. The file lives
+// in the documents bucket under Key.
+type DisputeAttachment struct {
+	ID          string    `gorm:"column:id;type:text;primaryKey"`
+	DisputeID   string    `gorm:"column:dispute_id;type:text;not null;index"`
+	Name        string    `gorm:"column:name;type:text;not null"`
+	Key         string    `gorm:"column:key;type:text;not null"`
+	ContentType string    `gorm:"column:content_type;type:text;not null"`
+	Size        int64     `gorm:"column:size;type:bigint;not null"`
+	CreatedAt   time.Time `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime"`
+}
+
+func (DisputeAttachment) TableName() string {
+	return "wallet_dispute_attachments"
+}
+
+// DisputeEvent is one entry on a dispute's timeline. Status is set when the
+// event moved the dispute; a bare note leaves it empty. Internal events are
+// only shown to support.
+type DisputeEvent struct {
+	ID        string        `gorm:"column:id;type:text;primaryKey"`
+	DisputeID string        `gorm:"column:dispute_id;type:text;not null;index"`
+	Status    DisputeStatus `gorm:"column:status;type:text"`
+	Note      string        `gorm:"column:note;type:text"`
+	Actor     string        `gorm:"column:actor;type:text;not null"`
+	Internal  bool          `gorm:"column:internal;not null;default:false"`
+	CreatedAt time.Time     `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime"`
+}
+
+func (DisputeEvent) TableName() string {
+	return "wallet_dispute_events"
+}
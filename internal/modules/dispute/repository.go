@@ -0,0 +1,173 @@
+package dispute
+
+import (
+	"context"
+	"errors"
+	"neat_mobile_app_backend/internal/modules/transaction"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) GetUserTransaction(ctx context.Context, mobileUserID, txID string) (*transaction.Transaction, error) {
+	var txn transaction.Transaction
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND mobile_user_id = ?", txID, mobileUserID).
+		First(&txn).Error
+	if err != nil {
+		return nil, err
+	}
+	return &txn, nil
+}
+
+// HasActiveDispute reports whether the transaction already has a dispute
+// that is not yet resolved or rejected.
+func (r *Repository) HasActiveDispute(ctx context.Context, txID string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&Dispute{}).
+		Where("transaction_id = ? AND status NOT IN ?", txID, []DisputeStatus{DisputeStatusResolved, DisputeStatusRejected}).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// CreateDispute saves a new dispute with its attachments and the opening
+// timeline entry.
+func (r *Repository) CreateDispute(ctx context.Context, dispute *Dispute, attachments []DisputeAttachment, event *DisputeEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(dispute).Error; err != nil {
+			return err
+		}
+		if len(attachments) > 0 {
+			if err := tx.Create(&attachments).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(event).Error
+	})
+}
+
+func (r *Repository) GetDispute(ctx context.Context, id string) (*Dispute, error) {
+	var dispute Dispute
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&dispute).Error; err != nil {
+		return nil, err
+	}
+	return &dispute, nil
+}
+
+func (r *Repository) GetUserDispute(ctx context.Context, mobileUserID, id string) (*Dispute, error) {
+	var dispute Dispute
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND mobile_user_id = ?", id, mobileUserID).
+		First(&dispute).Error
+	if err != nil {
+		return nil, err
+	}
+	return &dispute, nil
+}
+
+// ListDisputes pages through disputes. An empty mobileUserID lists every
+// user's, oldest first so support works the queue in order; a user's own
+// list is newest first.
+func (r *Repository) ListDisputes(ctx context.Context, mobileUserID string, status DisputeStatus, limit, offset int) ([]Dispute, int64, error) {
+	query := r.db.WithContext(ctx).Model(&Dispute{})
+	order := "created_at ASC"
+	if mobileUserID != "" {
+		query = query.Where("mobile_user_id = ?", mobileUserID)
+		order = "created_at DESC"
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var disputes []Dispute
+	err := query.Order(order).Limit(limit).Offset(offset).Find(&disputes).Error
+	return disputes, total, err
+}
+
+func (r *Repository) ListEvents(ctx context.Context, disputeID string, includeInternal bool) ([]DisputeEvent, error) {
+	query := r.db.WithContext(ctx).Where("dispute_id = ?", disputeID)
+	if !includeInternal {
+		query = query.Where("internal = ?", false)
+	}
+
+	var events []DisputeEvent
+	err := query.Order("created_at ASC").Find(&events).Error
+	return events, err
+}
+
+func (r *Repository) ListAttachments(ctx context.Context, disputeID string) ([]DisputeAttachment, error) {
+	var attachments []DisputeAttachment
+	err := r.db.WithContext(ctx).
+		Where("dispute_id = ?", disputeID).
+		Order("created_at ASC").
+		Find(&attachments).Error
+	return attachments, err
+}
+
+func (r *Repository) AddEvent(ctx context.Context, event *DisputeEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+// UpdateStatus moves a dispute to status and records it on the timeline in
+// one transaction. A reversal transaction ID, when given, is stored with it.
+func (r *Repository) UpdateStatus(ctx context.Context, id string, status DisputeStatus, actor, note, reversalTxID string, now time.Time) (*Dispute, error) {
+	var dispute Dispute
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&dispute).Error; err != nil {
+			return err
+		}
+		if isClosed(dispute.Status) {
+			return ErrDisputeClosed
+		}
+		if !canTransition(dispute.Status, status) {
+			return ErrInvalidTransition
+		}
+
+		updates := map[string]any{"status": status}
+		dispute.Status = status
+		if isClosed(status) {
+			updates["resolved_at"] = now
+			dispute.ResolvedAt = &now
+		}
+		if reversalTxID != "" {
+			updates["reversal_transaction_id"] = reversalTxID
+			dispute.ReversalTransactionID = reversalTxID
+		}
+		if err := tx.Model(&Dispute{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&DisputeEvent{
+			ID:        uuid.NewString(),
+			DisputeID: id,
+			Status:    status,
+			Note:      note,
+			Actor:     actor,
+			CreatedAt: now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dispute, nil
+}
+
+func isNotFound(err error) bool {
+	return errors.Is(err, gorm.ErrRecordNotFound)
+}
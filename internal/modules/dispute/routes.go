@@ -0,0 +1,25 @@
+package dispute
+
+import "github.com/gin-gonic/gin"
+
+func RegisterRoutes(rg *gin.RouterGroup, handler *Handler, authGuard, deviceValidator gin.HandlerFunc) {
+	disputes := rg.Group("/disputes", authGuard, deviceValidator)
+	{
+		disputes.POST("", handler.RaiseDispute)
+		disputes.GET("", handler.ListUserDisputes)
+		disputes.GET("/:id", handler.GetUserDispute)
+	}
+}
+
+func RegisterInternalRoutes(rg *gin.RouterGroup, handler *Handler, internalAuth gin.HandlerFunc) {
+	disputes := rg.Group("/disputes")
+	disputes.Use(internalAuth)
+
+	{
+		disputes.GET("", handler.ListDisputes)
+		disputes.GET("/:id", handler.GetDisputeForReview)
+		disputes.POST("/:id/status", handler.UpdateDisputeStatus)
+		disputes.POST("/:id/notes", handler.AddDisputeNote)
+		disputes.POST("/:id/reverse", handler.ReverseDisputedTransaction)
+	}
+}
@@ -0,0 +1,322 @@
+package dispute
+
+import (
+	"context"
+	"errors"
+	"log"
+	appErr "neat_mobile_app_backend/internal/errors"
+	"neat_mobile_app_backend/internal/modules/notification"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Service struct {
+	repo     *Repository
+	storage  DocumentStorage
+	notifier *notification.Service
+	reverser Reverser
+}
+
+func NewService(repo *Repository, storage DocumentStorage, notifier *notification.Service, reverser Reverser) *Service {
+	return &Service{repo: repo, storage: storage, notifier: notifier, reverser: reverser}
+}
+
+// RaiseDispute opens a dispute against one of the user's transactions and
+// uploads any evidence with it. A transaction can only have one dispute open
+// at a time.
+func (s *Service) RaiseDispute(ctx context.Context, mobileUserID string, input *RaiseDisputeInput) (*DisputeResponse, error) {
+	txn, err := s.repo.GetUserTransaction(ctx, mobileUserID, strings.TrimSpace(input.TransactionID))
+	if err != nil {
+		if isNotFound(err) {
+			return nil, appErr.ErrTransactionNotFound
+		}
+		log.Printf("dispute service: failed to get transaction: %v", err)
+		return nil, appErr.ErrDispute
+	}
+
+	active, err := s.repo.HasActiveDispute(ctx, txn.ID)
+	if err != nil {
+		log.Printf("dispute service: failed to check active disputes: %v", err)
+		return nil, appErr.ErrDispute
+	}
+	if active {
+		return nil, appErr.ErrDisputeAlreadyOpen
+	}
+
+	if len(input.Attachments) > maxAttachments {
+		return nil, appErr.ErrInvalidDisputeAttachment
+	}
+	for _, attachment := range input.Attachments {
+		if !validAttachment(attachment) {
+			return nil, appErr.ErrInvalidDisputeAttachment
+		}
+	}
+
+	reference, err := newDisputeReference()
+	if err != nil {
+		log.Printf("dispute service: failed to generate reference: %v", err)
+		return nil, appErr.ErrDispute
+	}
+
+	disputeID := uuid.NewString()
+	attachments := make([]DisputeAttachment, 0, len(input.Attachments))
+	for i, attachment := range input.Attachments {
+		contentType := strings.ToLower(strings.TrimSpace(attachment.ContentType))
+		key := attachmentKey(mobileUserID, disputeID, i, contentType)
+		if err := s.storage.UploadDocument(ctx, key, attachment.Body, contentType); err != nil {
+			log.Printf("dispute service: failed to upload attachment: %v", err)
+			return nil, appErr.ErrDispute
+		}
+		attachments = append(attachments, DisputeAttachment{
+			ID:          uuid.NewString(),
+			DisputeID:   disputeID,
+			Name:        attachment.Name,
+			Key:         key,
+			ContentType: contentType,
+			Size:        attachment.Size,
+		})
+	}
+
+	dispute := &Dispute{
+		ID:                   disputeID,
+		Reference:            reference,
+		MobileUserID:         mobileUserID,
+		TransactionID:        txn.ID,
+		TransactionReference: txn.Reference,
+		Amount:               txn.Amount,
+		Reason:               input.Reason,
+		Description:          strings.TrimSpace(input.Description),
+		Status:               DisputeStatusOpen,
+	}
+	event := &DisputeEvent{
+		ID:        uuid.NewString(),
+		DisputeID: disputeID,
+		Status:    DisputeStatusOpen,
+		Actor:     userActor,
+	}
+	if err := s.repo.CreateDispute(ctx, dispute, attachments, event); err != nil {
+		log.Printf("dispute service: failed to save dispute: %v", err)
+		return nil, appErr.ErrDispute
+	}
+
+	s.notify(ctx, dispute, "")
+
+	resp := toDisputeResponse(dispute)
+	resp.Timeline = toTimeline([]DisputeEvent{*event}, false)
+	return &resp, nil
+}
+
+func (s *Service) ListUserDisputes(ctx context.Context, mobileUserID string, query ListDisputesQuery) (*ListDisputesResponse, error) {
+	return s.listDisputes(ctx, mobileUserID, query)
+}
+
+// GetUserDispute returns one of the user's disputes with the timeline they
+// are allowed to see.
+func (s *Service) GetUserDispute(ctx context.Context, mobileUserID, id string) (*DisputeResponse, error) {
+	dispute, err := s.repo.GetUserDispute(ctx, mobileUserID, id)
+	if err != nil {
+		return nil, lookupError(err)
+	}
+
+	events, err := s.repo.ListEvents(ctx, dispute.ID, false)
+	if err != nil {
+		log.Printf("dispute service: failed to list dispute events: %v", err)
+		return nil, appErr.ErrDispute
+	}
+
+	resp := toDisputeResponse(dispute)
+	resp.Timeline = toTimeline(events, false)
+	return &resp, nil
+}
+
+func (s *Service) ListDisputes(ctx context.Context, query ListDisputesQuery) (*ListDisputesResponse, error) {
+	return s.listDisputes(ctx, "", query)
+}
+
+// GetDisputeForReview returns a dispute with its full timeline and presigned
+// links to the attachments.
+func (s *Service) GetDisputeForReview(ctx context.Context, id string) (*DisputeReview, error) {
+	dispute, err := s.repo.GetDispute(ctx, id)
+	if err != nil {
+		return nil, lookupError(err)
+	}
+
+	events, err := s.repo.ListEvents(ctx, dispute.ID, true)
+	if err != nil {
+		log.Printf("dispute service: failed to list dispute events: %v", err)
+		return nil, appErr.ErrDispute
+	}
+	attachments, err := s.repo.ListAttachments(ctx, dispute.ID)
+	if err != nil {
+		log.Printf("dispute service: failed to list dispute attachments: %v", err)
+		return nil, appErr.ErrDispute
+	}
+
+	review := &DisputeReview{
+		DisputeResponse: toDisputeResponse(dispute),
+		MobileUserID:    dispute.MobileUserID,
+		Attachments:     make([]AttachmentResponse, 0, len(attachments)),
+	}
+	review.Timeline = toTimeline(events, true)
+	for _, attachment := range attachments {
+		url, err := s.storage.PresignURL(ctx, attachment.Key, attachmentURLTTL)
+		if err != nil {
+			log.Printf("dispute service: failed to presign attachment %s: %v", attachment.ID, err)
+			return nil, appErr.ErrDispute
+		}
+		review.Attachments = append(review.Attachments, AttachmentResponse{
+			Name:        attachment.Name,
+			ContentType: attachment.ContentType,
+			URL:         url,
+		})
+	}
+	return review, nil
+}
+
+func (s *Service) UpdateStatus(ctx context.Context, id string, req UpdateDisputeStatusRequest) (*DisputeResponse, error) {
+	note := strings.TrimSpace(req.Note)
+	dispute, err := s.repo.UpdateStatus(ctx, id, req.Status, strings.TrimSpace(req.Actor), note, "", time.Now().UTC())
+	if err != nil {
+		return nil, updateError(err)
+	}
+
+	s.notify(ctx, dispute, note)
+
+	resp := toDisputeResponse(dispute)
+	return &resp, nil
+}
+
+// AddNote records a support note. Notes visible to the user are pushed to
+// them; internal ones are not.
+func (s *Service) AddNote(ctx context.Context, id string, req AddDisputeNoteRequest) error {
+	dispute, err := s.repo.GetDispute(ctx, id)
+	if err != nil {
+		return lookupError(err)
+	}
+
+	note := strings.TrimSpace(req.Note)
+	if note == "" {
+		return appErr.ErrInvalidRequestBody
+	}
+	if err := s.repo.AddEvent(ctx, &DisputeEvent{
+		ID:        uuid.NewString(),
+		DisputeID: dispute.ID,
+		Note:      note,
+		Actor:     strings.TrimSpace(req.Actor),
+		Internal:  !req.VisibleToUser,
+	}); err != nil {
+		log.Printf("dispute service: failed to add note to dispute %s: %v", dispute.ID, err)
+		return appErr.ErrDispute
+	}
+
+	if req.VisibleToUser && s.notifier != nil {
+		if err := s.notifier.SendToUser(ctx, dispute.MobileUserID, "Update on your dispute", "transaction", note,
+			map[string]any{"dispute_id": dispute.ID, "status": string(dispute.Status)}); err != nil {
+			log.Printf("dispute service: failed to send dispute note notification: %v", err)
+		}
+	}
+	return nil
+}
+
+// ReverseDisputedTransaction refunds the disputed transaction through the
+// wallet reversal flow, which refunds a settled debit at the provider before
+// crediting the wallet locally, and resolves the dispute.
+func (s *Service) ReverseDisputedTransaction(ctx context.Context, id string, req ReverseDisputeRequest) (*DisputeResponse, error) {
+	dispute, err := s.repo.GetDispute(ctx, id)
+	if err != nil {
+		return nil, lookupError(err)
+	}
+	if isClosed(dispute.Status) {
+		return nil, appErr.ErrDisputeClosed
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		reason = "dispute " + dispute.Reference + " upheld"
+	}
+	reversal, err := s.reverser.ReverseTransaction(ctx, dispute.TransactionID, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	note := "Transaction reversed: " + reason
+	dispute, err = s.repo.UpdateStatus(ctx, id, DisputeStatusResolved, strings.TrimSpace(req.Actor), note, reversal.ReversalTransactionID, time.Now().UTC())
+	if err != nil {
+		log.Printf("dispute service: reversed %s but failed to resolve dispute %s: %v", reversal.TransactionID, id, err)
+		return nil, updateError(err)
+	}
+
+	s.notify(ctx, dispute, "")
+
+	resp := toDisputeResponse(dispute)
+	return &resp, nil
+}
+
+func (s *Service) listDisputes(ctx context.Context, mobileUserID string, query ListDisputesQuery) (*ListDisputesResponse, error) {
+	page := query.Page
+	if page < 1 {
+		page = defaultPage
+	}
+	limit := query.Limit
+	if limit < 1 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	disputes, total, err := s.repo.ListDisputes(ctx, mobileUserID, query.Status, limit, (page-1)*limit)
+	if err != nil {
+		log.Printf("dispute service: failed to list disputes: %v", err)
+		return nil, appErr.ErrDispute
+	}
+
+	items := make([]DisputeResponseItem, 0, len(disputes))
+	for i := range disputes {
+		item := DisputeResponseItem{DisputeResponse: toDisputeResponse(&disputes[i])}
+		if mobileUserID == "" {
+			item.MobileUserID = disputes[i].MobileUserID
+		}
+		items = append(items, item)
+	}
+
+	return &ListDisputesResponse{Items: items, Page: page, Limit: limit, Total: total}, nil
+}
+
+func (s *Service) notify(ctx context.Context, dispute *Dispute, note string) {
+	if s.notifier == nil {
+		return
+	}
+	title, body := statusMessage(dispute, note)
+	if err := s.notifier.SendToUser(ctx, dispute.MobileUserID, title, "transaction", body, map[string]any{
+		"dispute_id": dispute.ID,
+		"reference":  dispute.Reference,
+		"status":     string(dispute.Status),
+	}); err != nil {
+		log.Printf("dispute service: failed to send dispute notification: %v", err)
+	}
+}
+
+func lookupError(err error) error {
+	if isNotFound(err) {
+		return appErr.ErrDisputeNotFound
+	}
+	log.Printf("dispute service: failed to get dispute: %v", err)
+	return appErr.ErrDispute
+}
+
+func updateError(err error) error {
+	switch {
+	case isNotFound(err):
+		return appErr.ErrDisputeNotFound
+	case errors.Is(err, ErrDisputeClosed):
+		return appErr.ErrDisputeClosed
+	case errors.Is(err, ErrInvalidTransition):
+		return appErr.ErrInvalidDisputeTransition
+	default:
+		log.Printf("dispute service: failed to update dispute: %v", err)
+		return appErr.ErrDispute
+	}
+}
@@ -0,0 +1,66 @@
+package dispute
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidTransition = errors.New("dispute cannot move to that status")
+	ErrDisputeClosed     = errors.New("dispute is closed")
+)
+
+type DisputeStatus string
+
+const (
+	DisputeStatusOpen             DisputeStatus = "open"
+	DisputeStatusInReview         DisputeStatus = "in_review"
+	DisputeStatusAwaitingCustomer DisputeStatus = "awaiting_customer"
+	DisputeStatusResolved         DisputeStatus = "resolved"
+	DisputeStatusRejected         DisputeStatus = "rejected"
+)
+
+type DisputeReason string
+
+const (
+	DisputeReasonNotReceived    DisputeReason = "not_received"
+	DisputeReasonWrongAmount    DisputeReason = "wrong_amount"
+	DisputeReasonDuplicateDebit DisputeReason = "duplicate_debit"
+	DisputeReasonUnauthorized   DisputeReason = "unauthorized"
+	DisputeReasonValueNotGiven  DisputeReason = "value_not_given"
+	DisputeReasonOther          DisputeReason = "other"
+)
+
+// disputeTransitions lists where each open status may move. Resolved and
+// rejected disputes are closed.
+var disputeTransitions = map[DisputeStatus][]DisputeStatus{
+	DisputeStatusOpen:             {DisputeStatusInReview, DisputeStatusAwaitingCustomer, DisputeStatusResolved, DisputeStatusRejected},
+	DisputeStatusInReview:         {DisputeStatusAwaitingCustomer, DisputeStatusResolved, DisputeStatusRejected},
+	DisputeStatusAwaitingCustomer: {DisputeStatusInReview, DisputeStatusResolved, DisputeStatusRejected},
+}
+
+const (
+	// userActor is recorded on events the customer caused.
+	userActor = "customer"
+
+	maxAttachments    = 3
+	maxAttachmentSize = 8 << 20
+	// attachmentURLTTL is how long support's presigned attachment links last.
+	attachmentURLTTL = 15 * time.Minute
+
+	disputeReferencePrefix = "DSP-"
+	disputeReferenceLength = 10
+
+	defaultPage  = 1
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// attachmentExtensions lists the upload types we accept and the extension
+// each is stored under.
+var attachmentExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
@@ -0,0 +1,74 @@
+package kyc
+
+import (
+	"io"
+	"time"
+)
+
+// SubmitTierUpgradeRequest is the form part of the multipart upload; the
+// address proof and selfie come in as files. RequestedTier defaults to the
+// next tier up.
+type SubmitTierUpgradeRequest struct {
+	RequestedTier int    `form:"requested_tier" binding:"omitempty,min=2,max=3"`
+	NIN           string `form:"nin" binding:"omitempty,len=11,numeric"`
+	Address       string `form:"address" binding:"required,max=255"`
+}
+
+type Document struct {
+	Body        io.ReadSeeker
+	ContentType string
+	Size        int64
+}
+
+type TierUpgradeInput struct {
+	SubmitTierUpgradeRequest
+	AddressProof *Document
+	Selfie       *Document
+}
+
+type TierUpgradeResponse struct {
+	ID              string            `json:"id"`
+	CurrentTier     int               `json:"current_tier"`
+	RequestedTier   int               `json:"requested_tier"`
+	Status          TierUpgradeStatus `json:"status"`
+	RejectionReason string            `json:"rejection_reason,omitempty"`
+	ReviewedAt      *time.Time        `json:"reviewed_at,omitempty"`
+	CreatedAt       time.Time         `json:"created_at"`
+}
+
+// TierUpgradeReview is the reviewer's view of a submission, with short-lived
+// links to the documents.
+type TierUpgradeReview struct {
+	TierUpgradeResponse
+	MobileUserID    string `json:"mobile_user_id"`
+	FullName        string `json:"full_name"`
+	NIN             string `json:"nin,omitempty"`
+	NINVerified     bool   `json:"nin_verified"`
+	Address         string `json:"address"`
+	AddressProofURL string `json:"address_proof_url"`
+	SelfieURL       string `json:"selfie_url"`
+	ReviewedBy      string `json:"reviewed_by,omitempty"`
+}
+
+type ListTierUpgradesQuery struct {
+	Status TierUpgradeStatus `form:"status" binding:"omitempty,oneof=pending approved rejected"`
+	Page   int               `form:"page"`
+	Limit  int               `form:"limit"`
+}
+
+type ListTierUpgradesResponse struct {
+	Items []TierUpgradeResponseItem `json:"items"`
+	Page  int                       `json:"page"`
+	Limit int                       `json:"limit"`
+	Total int64                     `json:"total"`
+}
+
+type TierUpgradeResponseItem struct {
+	TierUpgradeResponse
+	MobileUserID string `json:"mobile_user_id"`
+}
+
+type ReviewTierUpgradeRequest struct {
+	Reviewer string `json:"reviewer" binding:"required"`
+	Reason   string `json:"reason" binding:"omitempty,max=500"`
+}
@@ -0,0 +1,221 @@
+package kyc
+
+import (
+	"mime/multipart"
+	appErr "neat_mobile_app_backend/internal/errors"
+	"neat_mobile_app_backend/internal/middleware"
+	"neat_mobile_app_backend/internal/response"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) SubmitTierUpgrade(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrMissingUserID)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	var req SubmitTierUpgradeRequest
+	if err := c.ShouldBind(&req); err != nil {
+		mapped := response.MapError(appErr.ErrInvalidRequestBody)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	input := &TierUpgradeInput{SubmitTierUpgradeRequest: req}
+	for _, doc := range []struct {
+		field string
+		dest  **Document
+	}{
+		{field: "address_proof", dest: &input.AddressProof},
+		{field: "selfie", dest: &input.Selfie},
+	} {
+		fileHeader, err := c.FormFile(doc.field)
+		if err != nil {
+			continue
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			mapped := response.MapError(appErr.ErrInvalidKYCDocument)
+			c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+				Status: "error",
+				Error:  &mapped.Error,
+			})
+			return
+		}
+		defer file.Close()
+		*doc.dest = toDocument(file, fileHeader)
+	}
+
+	resp, err := h.service.SubmitTierUpgrade(c.Request.Context(), mobileUserID, input)
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[TierUpgradeResponse]{
+		Status:  "success",
+		Message: "Tier upgrade submitted for review",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) GetLatestTierUpgrade(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrMissingUserID)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	resp, err := h.service.GetLatestTierUpgrade(c.Request.Context(), mobileUserID)
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[TierUpgradeResponse]{
+		Status:  "success",
+		Message: "Tier upgrade fetched successfully",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) ListTierUpgrades(c *gin.Context) {
+	var query ListTierUpgradesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		mapped := response.MapError(appErr.ErrInvalidRequestBody)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	resp, err := h.service.ListTierUpgrades(c.Request.Context(), query)
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[ListTierUpgradesResponse]{
+		Status:  "success",
+		Message: "Tier upgrades fetched successfully",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) GetTierUpgradeForReview(c *gin.Context) {
+	resp, err := h.service.GetTierUpgradeForReview(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[TierUpgradeReview]{
+		Status:  "success",
+		Message: "Tier upgrade fetched successfully",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) ApproveTierUpgrade(c *gin.Context) {
+	var req ReviewTierUpgradeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		mapped := response.MapError(appErr.ErrInvalidRequestBody)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	resp, err := h.service.ApproveTierUpgrade(c.Request.Context(), c.Param("id"), req.Reviewer)
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[TierUpgradeResponse]{
+		Status:  "success",
+		Message: "Tier upgrade approved",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) RejectTierUpgrade(c *gin.Context) {
+	var req ReviewTierUpgradeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		mapped := response.MapError(appErr.ErrInvalidRequestBody)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	resp, err := h.service.RejectTierUpgrade(c.Request.Context(), c.Param("id"), req.Reviewer, req.Reason)
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[TierUpgradeResponse]{
+		Status:  "success",
+		Message: "Tier upgrade rejected",
+		Data:    resp,
+	})
+}
+
+func toDocument(file multipart.File, header *multipart.FileHeader) *Document {
+	return &Document{
+		Body:        file,
+		ContentType: header.Header.Get("Content-Type"),
+		Size:        header.Size,
+	}
+}
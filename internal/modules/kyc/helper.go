@@ -0,0 +1,81 @@
+package kyc
+
+import (
+	"neat_mobile_app_backend/models"
+	"neat_mobile_app_backend/providers/nin"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// tierLabel writes tier in the same shape as the provider's existing label,
+// so "TIER_1" becomes "TIER_2" and "1" becomes "2".
+func tierLabel(current string, tier int) string {
+	for i, r := range current {
+		if unicode.IsDigit(r) {
+			return current[:i] + strconv.Itoa(tier) + current[i+1:]
+		}
+	}
+	return strconv.Itoa(tier)
+}
+
+func documentKey(mobileUserID, requestID, name, contentType string) string {
+	return "kyc/tier-upgrades/" + mobileUserID + "/" + requestID + "/" + name + documentExtensions[contentType]
+}
+
+func validDocument(doc *Document) bool {
+	if doc == nil || doc.Size <= 0 || doc.Size > maxDocumentSize {
+		return false
+	}
+	_, ok := documentExtensions[strings.ToLower(strings.TrimSpace(doc.ContentType))]
+	return ok
+}
+
+// validNIN reports whether value has the shape of a NIN: eleven digits.
+func validNIN(value string) bool {
+	if len(value) != 11 {
+		return false
+	}
+	for _, r := range value {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// ninMatchesUser reports whether the identity the provider returned for a NIN
+// is the user's: the same first name, surname and date of birth.
+func ninMatchesUser(user *models.User, data nin.PremblyNINValidationData) bool {
+	if !strings.EqualFold(strings.TrimSpace(data.FirstName), strings.TrimSpace(user.FirstName)) ||
+		!strings.EqualFold(strings.TrimSpace(data.Surname), strings.TrimSpace(user.LastName)) {
+		return false
+	}
+	for _, layout := range ninDOBLayouts {
+		dob, err := time.Parse(layout, strings.TrimSpace(data.BirthDate))
+		if err == nil {
+			return dob.Format(time.DateOnly) == user.DOB.Format(time.DateOnly)
+		}
+	}
+	return false
+}
+
+func maskNIN(nin string) string {
+	if len(nin) <= 4 {
+		return nin
+	}
+	return strings.Repeat("*", len(nin)-4) + nin[len(nin)-4:]
+}
+
+func toTierUpgradeResponse(request *TierUpgradeRequest) TierUpgradeResponse {
+	return TierUpgradeResponse{
+		ID:              request.ID,
+		CurrentTier:     request.CurrentTier,
+		RequestedTier:   request.RequestedTier,
+		Status:          request.Status,
+		RejectionReason: request.RejectionReason,
+		ReviewedAt:      request.ReviewedAt,
+		CreatedAt:       request.CreatedAt,
+	}
+}
@@ -0,0 +1,87 @@
+package kyc
+
+import (
+	"neat_mobile_app_backend/models"
+	"neat_mobile_app_backend/providers/nin"
+	"testing"
+	"time"
+)
+
+func TestTierLabel(t *testing.T) {
+	tests := []struct {
+		current string
+		tier    int
+		want    string
+	}{
+		{current: "1", tier: 2, want: "2"},
+		{current: "TIER_1", tier: 3, want: "TIER_3"},
+		{current: "Tier 2", tier: 3, want: "Tier 3"},
+		{current: "", tier: 2, want: "2"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.current, func(t *testing.T) {
+			if got := tierLabel(tc.current, tc.tier); got != tc.want {
+				t.Fatalf("tierLabel(%q, %d) = %q, want %q", tc.current, tc.tier, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidDocument(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  *Document
+		want bool
+	}{
+		{name: "missing", doc: nil, want: false},
+		{name: "pdf", doc: &Document{ContentType: "application/pdf", Size: 1024}, want: true},
+		{name: "too large", doc: &Document{ContentType: "image/png", Size: maxDocumentSize + 1}, want: false},
+		{name: "wrong type", doc: &Document{ContentType: "text/plain", Size: 10}, want: false},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if got := validDocument(tc.doc); got != tc.want {
+				t.Fatalf("validDocument() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNINMatchesUser(t *testing.T) {
+	user := &models.User{FirstName: "Ada", LastName: "Obi", DOB: time.Date(1990, 1, 15, 0, 0, 0, 0, time.UTC)}
+	tests := []struct {
+		name string
+		data nin.PremblyNINValidationData
+		want bool
+	}{
+		{name: "match", data: nin.PremblyNINValidationData{FirstName: "ADA", Surname: "OBI", BirthDate: "1990-01-15"}, want: true},
+		{name: "day first date", data: nin.PremblyNINValidationData{FirstName: "Ada", Surname: "Obi", BirthDate: "15-01-1990"}, want: true},
+		{name: "other person", data: nin.PremblyNINValidationData{FirstName: "Chidi", Surname: "Obi", BirthDate: "1990-01-15"}, want: false},
+		{name: "other birthday", data: nin.PremblyNINValidationData{FirstName: "Ada", Surname: "Obi", BirthDate: "1991-01-15"}, want: false},
+		{name: "unreadable date", data: nin.PremblyNINValidationData{FirstName: "Ada", Surname: "Obi", BirthDate: "soon"}, want: false},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ninMatchesUser(user, tc.data); got != tc.want {
+				t.Fatalf("ninMatchesUser() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidNIN(t *testing.T) {
+	if !validNIN("12345678901") {
+		t.Fatal("eleven digits should be a valid NIN")
+	}
+	for _, value := range []string{"", "1234567890", "123456789012", "1234567890a"} {
+		if validNIN(value) {
+			t.Fatalf("validNIN(%q) = true, want false", value)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package kyc
+
+import (
+	"context"
+	"io"
+	"neat_mobile_app_backend/providers/nin"
+	"time"
+)
+
+type DocumentStorage interface {
+	UploadDocument(ctx context.Context, key string, body io.ReadSeeker, contentType string) error
+	PresignURL(ctx context.Context, filePath string, ttl time.Duration) (string, error)
+}
+
+// WalletTierUpgrader raises a wallet's tier at the wallet provider. tier is in
+// the provider's own label format.
+type WalletTierUpgrader interface {
+	UpgradeWalletTier(ctx context.Context, walletCustomerID, tier string) error
+}
+
+// NINValidator looks a NIN up with the identity provider.
+type NINValidator interface {
+	ValidateNIN(ctx context.Context, nin string) (*nin.PremblyNINValidationSuccessResponse, error)
+}
@@ -0,0 +1,27 @@
+package kyc
+
+import "time"
+
+// TierUpgradeRequest is a user's submission for a higher wallet tier. The
+// documents live in the documents bucket under the stored keys.
+type TierUpgradeRequest struct {
+	ID              string            `gorm:"column:id;type:text;primaryKey"`
+	MobileUserID    string            `gorm:"column:mobile_user_id;type:text;not null;index"`
+	CurrentTier     int               `gorm:"column:current_tier;not null"`
+	RequestedTier   int               `gorm:"column:requested_tier;not null"`
+	NIN             string            `gorm:"column:nin;type:text"`
+	NINVerified     bool              `gorm:"column:nin_verified;not null;default:false"`
+	Address         string            `gorm:"column:address;type:text;not null"`
+	AddressProofKey string            `gorm:"column:address_proof_key;type:text;not null"`
+	SelfieKey       string            `gorm:"column:selfie_key;type:text;not null"`
+	Status          TierUpgradeStatus `gorm:"column:status;type:text;not null;index"`
+	ReviewedBy      string            `gorm:"column:reviewed_by;type:text"`
+	RejectionReason string            `gorm:"column:rejection_reason;type:text"`
+	ReviewedAt      *time.Time        `gorm:"column:reviewed_at;type:timestamptz"`
+	CreatedAt       time.Time         `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime"`
+	UpdatedAt       *time.Time        `gorm:"column:updated_at;type:timestamptz;autoUpdateTime"`
+}
+
+func (TierUpgradeRequest) TableName() string {
+	return "wallet_tier_upgrade_requests"
+}
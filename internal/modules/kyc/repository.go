@@ -0,0 +1,178 @@
+package kyc
+
+import (
+	"context"
+	"errors"
+	"neat_mobile_app_backend/internal/modules/wallet"
+	"neat_mobile_app_backend/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) GetUser(ctx context.Context, mobileUserID string) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).Where("id = ?", mobileUserID).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *Repository) GetWallet(ctx context.Context, mobileUserID string) (*wallet.CustomerWallet, error) {
+	var w wallet.CustomerWallet
+	if err := r.db.WithContext(ctx).Where("mobile_user_id = ?", mobileUserID).First(&w).Error; err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func (r *Repository) HasPendingRequest(ctx context.Context, mobileUserID string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&TierUpgradeRequest{}).
+		Where("mobile_user_id = ? AND status = ?", mobileUserID, TierUpgradeStatusPending).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *Repository) CreateRequest(ctx context.Context, request *TierUpgradeRequest) error {
+	return r.db.WithContext(ctx).Create(request).Error
+}
+
+func (r *Repository) GetLatestRequest(ctx context.Context, mobileUserID string) (*TierUpgradeRequest, error) {
+	var request TierUpgradeRequest
+	err := r.db.WithContext(ctx).
+		Where("mobile_user_id = ?", mobileUserID).
+		Order("created_at DESC").
+		First(&request).Error
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (r *Repository) GetRequest(ctx context.Context, id string) (*TierUpgradeRequest, error) {
+	var request TierUpgradeRequest
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&request).Error; err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// ListRequests pages through submissions oldest first, so reviewers work the
+// queue in the order it was filled.
+func (r *Repository) ListRequests(ctx context.Context, status TierUpgradeStatus, limit, offset int) ([]TierUpgradeRequest, int64, error) {
+	query := r.db.WithContext(ctx).Model(&TierUpgradeRequest{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var requests []TierUpgradeRequest
+	err := query.Order("created_at ASC").Limit(limit).Offset(offset).Find(&requests).Error
+	return requests, total, err
+}
+
+// ApproveRequest marks a pending request approved and records the wallet's new
+// tier label in the same transaction, once the provider has raised it. A NIN submitted with the request is recorded on
+// the user as verified when the user did not already have one.
+func (r *Repository) ApproveRequest(ctx context.Context, id, reviewer string, now time.Time) (*TierUpgradeRequest, error) {
+	var request TierUpgradeRequest
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockPendingRequest(tx, id, &request); err != nil {
+			return err
+		}
+
+		var w wallet.CustomerWallet
+		if err := tx.Where("mobile_user_id = ?", request.MobileUserID).First(&w).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&wallet.CustomerWallet{}).
+			Where("id = ?", w.ID).
+			Update("tier", tierLabel(w.Tier, request.RequestedTier)).Error; err != nil {
+			return err
+		}
+
+		// only a NIN the provider matched to the user at submission counts as
+		// verified; a reviewer's approval never does
+		userUpdates := map[string]any{"address": request.Address}
+		if request.NINVerified && request.NIN != "" {
+			var user models.User
+			if err := tx.Where("id = ?", request.MobileUserID).First(&user).Error; err != nil {
+				return err
+			}
+			if !user.IsNinVerified || strings.TrimSpace(user.NIN) == "" {
+				userUpdates["nin"] = request.NIN
+				userUpdates["is_nin_verified"] = true
+			}
+		}
+		if err := tx.Model(&models.User{}).Where("id = ?", request.MobileUserID).Updates(userUpdates).Error; err != nil {
+			return err
+		}
+
+		request.Status = TierUpgradeStatusApproved
+		request.ReviewedBy = reviewer
+		request.ReviewedAt = &now
+		return tx.Model(&TierUpgradeRequest{}).Where("id = ?", id).Updates(map[string]any{
+			"status":      request.Status,
+			"reviewed_by": reviewer,
+			"reviewed_at": now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (r *Repository) RejectRequest(ctx context.Context, id, reviewer, reason string, now time.Time) (*TierUpgradeRequest, error) {
+	var request TierUpgradeRequest
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockPendingRequest(tx, id, &request); err != nil {
+			return err
+		}
+
+		request.Status = TierUpgradeStatusRejected
+		request.ReviewedBy = reviewer
+		request.RejectionReason = reason
+		request.ReviewedAt = &now
+		return tx.Model(&TierUpgradeRequest{}).Where("id = ?", id).Updates(map[string]any{
+			"status":           request.Status,
+			"reviewed_by":      reviewer,
+			"rejection_reason": reason,
+			"reviewed_at":      now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+func lockPendingRequest(tx *gorm.DB, id string, request *TierUpgradeRequest) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(request).Error; err != nil {
+		return err
+	}
+	if request.Status != TierUpgradeStatusPending {
+		return ErrNotPending
+	}
+	return nil
+}
+
+func isNotFound(err error) bool {
+	return errors.Is(err, gorm.ErrRecordNotFound)
+}
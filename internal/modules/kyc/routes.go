@@ -0,0 +1,23 @@
+package kyc
+
+import "github.com/gin-gonic/gin"
+
+func RegisterRoutes(rg *gin.RouterGroup, handler *Handler, authGuard, deviceValidator gin.HandlerFunc) {
+	kyc := rg.Group("/kyc", authGuard, deviceValidator)
+	{
+		kyc.POST("/tier-upgrade", handler.SubmitTierUpgrade)
+		kyc.GET("/tier-upgrade", handler.GetLatestTierUpgrade)
+	}
+}
+
+func RegisterInternalRoutes(rg *gin.RouterGroup, handler *Handler, internalAuth gin.HandlerFunc) {
+	kyc := rg.Group("/kyc")
+	kyc.Use(internalAuth)
+
+	{
+		kyc.GET("/tier-upgrades", handler.ListTierUpgrades)
+		kyc.GET("/tier-upgrades/:id", handler.GetTierUpgradeForReview)
+		kyc.POST("/tier-upgrades/:id/approve", handler.ApproveTierUpgrade)
+		kyc.POST("/tier-upgrades/:id/reject", handler.RejectTierUpgrade)
+	}
+}
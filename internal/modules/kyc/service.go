@@ -0,0 +1,325 @@
+package kyc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	appErr "neat_mobile_app_backend/internal/errors"
+	"neat_mobile_app_backend/internal/modules/limits"
+	"neat_mobile_app_backend/internal/modules/notification"
+	"neat_mobile_app_backend/models"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Service struct {
+	repo     *Repository
+	storage  DocumentStorage
+	notifier *notification.Service
+	nin      NINValidator
+	tiers    WalletTierUpgrader
+}
+
+func NewService(repo *Repository, storage DocumentStorage, notifier *notification.Service, ninValidator NINValidator, tierUpgrader WalletTierUpgrader) *Service {
+	return &Service{repo: repo, storage: storage, notifier: notifier, nin: ninValidator, tiers: tierUpgrader}
+}
+
+// SubmitTierUpgrade uploads the user's documents and queues the request for
+// review. Only one pending request is allowed per user.
+func (s *Service) SubmitTierUpgrade(ctx context.Context, mobileUserID string, input *TierUpgradeInput) (*TierUpgradeResponse, error) {
+	user, err := s.repo.GetUser(ctx, mobileUserID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, appErr.ErrUnauthorized
+		}
+		log.Printf("kyc service: failed to get user: %v", err)
+		return nil, appErr.ErrTierUpgrade
+	}
+
+	w, err := s.repo.GetWallet(ctx, mobileUserID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, appErr.ErrMissingUserWallet
+		}
+		log.Printf("kyc service: failed to get wallet: %v", err)
+		return nil, appErr.ErrTierUpgrade
+	}
+
+	currentTier := int(limits.ParseTier(w.Tier))
+	requestedTier := input.RequestedTier
+	if requestedTier == 0 {
+		requestedTier = currentTier + 1
+	}
+	if requestedTier <= currentTier || requestedTier > int(limits.Tier3) {
+		return nil, appErr.ErrInvalidTierUpgrade
+	}
+
+	pending, err := s.repo.HasPendingRequest(ctx, mobileUserID)
+	if err != nil {
+		log.Printf("kyc service: failed to check pending upgrades: %v", err)
+		return nil, appErr.ErrTierUpgrade
+	}
+	if pending {
+		return nil, appErr.ErrTierUpgradePending
+	}
+
+	nin := strings.TrimSpace(input.NIN)
+	if !user.IsNinVerified && nin == "" {
+		return nil, appErr.ErrNINRequired
+	}
+	if user.IsNinVerified {
+		nin = ""
+	}
+	if nin != "" {
+		if err := s.verifyNIN(ctx, user, nin); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.AddressProof == nil || input.Selfie == nil {
+		return nil, appErr.ErrMissingKYCDocument
+	}
+	if !validDocument(input.AddressProof) || !validDocument(input.Selfie) {
+		return nil, appErr.ErrInvalidKYCDocument
+	}
+
+	requestID := uuid.NewString()
+	addressProofKey := documentKey(mobileUserID, requestID, "address-proof", input.AddressProof.ContentType)
+	selfieKey := documentKey(mobileUserID, requestID, "selfie", input.Selfie.ContentType)
+
+	if err := s.storage.UploadDocument(ctx, addressProofKey, input.AddressProof.Body, input.AddressProof.ContentType); err != nil {
+		log.Printf("kyc service: failed to upload address proof: %v", err)
+		return nil, appErr.ErrTierUpgrade
+	}
+	if err := s.storage.UploadDocument(ctx, selfieKey, input.Selfie.Body, input.Selfie.ContentType); err != nil {
+		log.Printf("kyc service: failed to upload selfie: %v", err)
+		return nil, appErr.ErrTierUpgrade
+	}
+
+	request := &TierUpgradeRequest{
+		ID:              requestID,
+		MobileUserID:    mobileUserID,
+		CurrentTier:     currentTier,
+		RequestedTier:   requestedTier,
+		NIN:             nin,
+		NINVerified:     nin != "",
+		Address:         strings.TrimSpace(input.Address),
+		AddressProofKey: addressProofKey,
+		SelfieKey:       selfieKey,
+		Status:          TierUpgradeStatusPending,
+	}
+	if err := s.repo.CreateRequest(ctx, request); err != nil {
+		log.Printf("kyc service: failed to save tier upgrade request: %v", err)
+		return nil, appErr.ErrTierUpgrade
+	}
+
+	resp := toTierUpgradeResponse(request)
+	return &resp, nil
+}
+
+// verifyNIN checks with the identity provider that nin belongs to the user.
+// A request only carries a NIN that passed this check, and approval trusts
+// nothing else.
+func (s *Service) verifyNIN(ctx context.Context, user *models.User, nin string) error {
+	if !validNIN(nin) {
+		return appErr.ErrInvalidNIN
+	}
+	if s.nin == nil {
+		log.Printf("kyc service: nin validator is not configured")
+		return appErr.ErrTierUpgrade
+	}
+
+	resp, err := s.nin.ValidateNIN(ctx, nin)
+	if err != nil {
+		log.Printf("kyc service: nin lookup failed: %v", err)
+		return appErr.ErrTierUpgrade
+	}
+	if !resp.Status || !ninMatchesUser(user, resp.Data) {
+		return appErr.ErrInvalidNIN
+	}
+	return nil
+}
+
+func (s *Service) GetLatestTierUpgrade(ctx context.Context, mobileUserID string) (*TierUpgradeResponse, error) {
+	request, err := s.repo.GetLatestRequest(ctx, mobileUserID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, appErr.ErrTierUpgradeNotFound
+		}
+		log.Printf("kyc service: failed to get latest tier upgrade: %v", err)
+		return nil, appErr.ErrTierUpgrade
+	}
+
+	resp := toTierUpgradeResponse(request)
+	return &resp, nil
+}
+
+func (s *Service) ListTierUpgrades(ctx context.Context, query ListTierUpgradesQuery) (*ListTierUpgradesResponse, error) {
+	page := query.Page
+	if page < 1 {
+		page = defaultPage
+	}
+	limit := query.Limit
+	if limit < 1 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	requests, total, err := s.repo.ListRequests(ctx, query.Status, limit, (page-1)*limit)
+	if err != nil {
+		log.Printf("kyc service: failed to list tier upgrades: %v", err)
+		return nil, appErr.ErrTierUpgrade
+	}
+
+	items := make([]TierUpgradeResponseItem, 0, len(requests))
+	for i := range requests {
+		items = append(items, TierUpgradeResponseItem{
+			TierUpgradeResponse: toTierUpgradeResponse(&requests[i]),
+			MobileUserID:        requests[i].MobileUserID,
+		})
+	}
+
+	return &ListTierUpgradesResponse{Items: items, Page: page, Limit: limit, Total: total}, nil
+}
+
+// GetTierUpgradeForReview returns a submission with presigned links to its
+// documents.
+func (s *Service) GetTierUpgradeForReview(ctx context.Context, id string) (*TierUpgradeReview, error) {
+	request, err := s.repo.GetRequest(ctx, id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, appErr.ErrTierUpgradeNotFound
+		}
+		log.Printf("kyc service: failed to get tier upgrade: %v", err)
+		return nil, appErr.ErrTierUpgrade
+	}
+
+	user, err := s.repo.GetUser(ctx, request.MobileUserID)
+	if err != nil {
+		log.Printf("kyc service: failed to get user for review: %v", err)
+		return nil, appErr.ErrTierUpgrade
+	}
+
+	addressProofURL, err := s.storage.PresignURL(ctx, request.AddressProofKey, documentURLTTL)
+	if err != nil {
+		log.Printf("kyc service: failed to presign address proof: %v", err)
+		return nil, appErr.ErrTierUpgrade
+	}
+	selfieURL, err := s.storage.PresignURL(ctx, request.SelfieKey, documentURLTTL)
+	if err != nil {
+		log.Printf("kyc service: failed to presign selfie: %v", err)
+		return nil, appErr.ErrTierUpgrade
+	}
+
+	nin := request.NIN
+	if nin == "" {
+		nin = user.NIN
+	}
+
+	return &TierUpgradeReview{
+		TierUpgradeResponse: toTierUpgradeResponse(request),
+		MobileUserID:        request.MobileUserID,
+		FullName:            strings.TrimSpace(user.FirstName + " " + user.LastName),
+		NIN:                 maskNIN(nin),
+		NINVerified:         user.IsNinVerified || request.NINVerified,
+		Address:             request.Address,
+		AddressProofURL:     addressProofURL,
+		SelfieURL:           selfieURL,
+		ReviewedBy:          request.ReviewedBy,
+	}, nil
+}
+
+// ApproveTierUpgrade raises the wallet's tier at the provider, which is what
+// lets the wallet hold and move more, and only then records the approval and
+// the new tier label locally.
+func (s *Service) ApproveTierUpgrade(ctx context.Context, id, reviewer string) (*TierUpgradeResponse, error) {
+	if err := s.upgradeAtProvider(ctx, id); err != nil {
+		return nil, err
+	}
+
+	request, err := s.repo.ApproveRequest(ctx, id, strings.TrimSpace(reviewer), time.Now().UTC())
+	if err != nil {
+		return nil, reviewError(err)
+	}
+
+	s.notify(ctx, request.MobileUserID, "Account upgraded",
+		fmt.Sprintf("Your account is now Tier %d. Your new transaction limits are active.", request.RequestedTier),
+		request)
+
+	resp := toTierUpgradeResponse(request)
+	return &resp, nil
+}
+
+func (s *Service) upgradeAtProvider(ctx context.Context, id string) error {
+	request, err := s.repo.GetRequest(ctx, id)
+	if err != nil {
+		return reviewError(err)
+	}
+	if request.Status != TierUpgradeStatusPending {
+		return appErr.ErrTierUpgradeReviewed
+	}
+	if s.tiers == nil {
+		log.Printf("kyc service: wallet tier upgrader is not configured")
+		return appErr.ErrTierUpgrade
+	}
+
+	w, err := s.repo.GetWallet(ctx, request.MobileUserID)
+	if err != nil {
+		log.Printf("kyc service: failed to load wallet for tier upgrade %s: %v", id, err)
+		return appErr.ErrTierUpgrade
+	}
+	if err := s.tiers.UpgradeWalletTier(ctx, w.WalletCustomerID, tierLabel(w.Tier, request.RequestedTier)); err != nil {
+		log.Printf("kyc service: provider refused tier upgrade %s: %v", id, err)
+		return appErr.ErrTierUpgrade
+	}
+	return nil
+}
+
+func (s *Service) RejectTierUpgrade(ctx context.Context, id, reviewer, reason string) (*TierUpgradeResponse, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, appErr.ErrInvalidRequestBody
+	}
+
+	request, err := s.repo.RejectRequest(ctx, id, strings.TrimSpace(reviewer), reason, time.Now().UTC())
+	if err != nil {
+		return nil, reviewError(err)
+	}
+
+	s.notify(ctx, request.MobileUserID, "Tier upgrade declined",
+		fmt.Sprintf("We could not upgrade your account to Tier %d: %s. You can submit new documents from the app.", request.RequestedTier, reason),
+		request)
+
+	resp := toTierUpgradeResponse(request)
+	return &resp, nil
+}
+
+func (s *Service) notify(ctx context.Context, mobileUserID, title, body string, request *TierUpgradeRequest) {
+	if s.notifier == nil {
+		return
+	}
+	if err := s.notifier.SendToUser(ctx, mobileUserID, title, "account", body, map[string]any{
+		"tier_upgrade_id": request.ID,
+		"status":          string(request.Status),
+		"tier":            request.RequestedTier,
+	}); err != nil {
+		log.Printf("kyc service: failed to send tier upgrade notification: %v", err)
+	}
+}
+
+func reviewError(err error) error {
+	switch {
+	case isNotFound(err):
+		return appErr.ErrTierUpgradeNotFound
+	case errors.Is(err, ErrNotPending):
+		return appErr.ErrTierUpgradeReviewed
+	default:
+		log.Printf("kyc service: failed to review tier upgrade: %v", err)
+		return appErr.ErrTierUpgrade
+	}
+}
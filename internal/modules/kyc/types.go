@@ -0,0 +1,38 @@
+package kyc
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrNotPending = errors.New("tier upgrade request is not pending")
+
+type TierUpgradeStatus string
+
+const (
+	TierUpgradeStatusPending  TierUpgradeStatus = "pending"
+	TierUpgradeStatusApproved TierUpgradeStatus = "approved"
+	TierUpgradeStatusRejected TierUpgradeStatus = "rejected"
+)
+
+const (
+	maxDocumentSize = 8 << 20
+	// documentURLTTL is how long reviewers' presigned document links last.
+	documentURLTTL = 15 * time.Minute
+
+	defaultPage  = 1
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// ninDOBLayouts are the date formats the NIN provider returns birth dates in.
+var ninDOBLayouts = []string{"2006-01-02", "02-01-2006", "02/01/2006", "02-Jan-2006"}
+
+// documentExtensions lists the upload types we accept and the extension each
+// is stored under.
+var documentExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
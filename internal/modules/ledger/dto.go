@@ -0,0 +1,14 @@
+package ledger
+
+type WalletBalanceCheck struct {
+	WalletID      string `json:"wallet_id"`
+	MobileUserID  string `json:"mobile_user_id"`
+	LedgerBalance int64  `json:"ledger_balance"`
+	CachedBalance int64  `json:"cached_balance"`
+	Drift         int64  `json:"drift"`
+	HasAccount    bool   `json:"has_account"`
+}
+
+type EntriesQuery struct {
+	TransactionID string `form:"transaction_id" binding:"required"`
+}
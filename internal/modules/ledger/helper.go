@@ -0,0 +1,105 @@
+package ledger
+
+import "github.com/google/uuid"
+
+func newEntry(m Movement) *JournalEntry {
+	entry := &JournalEntry{
+		ID:          uuid.NewString(),
+		Reference:   m.Reference,
+		Description: m.Description,
+	}
+	if entry.Reference == "" {
+		entry.Reference = uuid.NewString()
+	}
+	if m.TransactionID != "" {
+		txID := m.TransactionID
+		entry.TransactionID = &txID
+	}
+	return entry
+}
+
+func addPosting(entry *JournalEntry, accountID string, direction Direction, amount int64) {
+	if amount == 0 {
+		return
+	}
+	entry.Postings = append(entry.Postings, Posting{
+		ID:             uuid.NewString(),
+		JournalEntryID: entry.ID,
+		AccountID:      accountID,
+		Direction:      direction,
+		Amount:         amount,
+	})
+}
+
+// buildWalletDebit moves the full total out of the wallet: the principal goes to
+// the counter account, charges to fee income and VAT to VAT payable.
+func buildWalletDebit(m Movement) *JournalEntry {
+	entry := newEntry(m)
+	addPosting(entry, WalletAccountID(m.WalletID), DirectionDebit, m.Total())
+	addPosting(entry, m.counterAccount(), DirectionCredit, m.Amount)
+	addPosting(entry, AccountCodeFees, DirectionCredit, m.Charges)
+	addPosting(entry, AccountCodeVAT, DirectionCredit, m.VAT)
+	return entry
+}
+
+// buildWalletCredit is the mirror of buildWalletDebit. For reversals it gives
+// back the charges and VAT that the original debit collected.
+func buildWalletCredit(m Movement) *JournalEntry {
+	entry := newEntry(m)
+	addPosting(entry, m.counterAccount(), DirectionDebit, m.Amount)
+	addPosting(entry, AccountCodeFees, DirectionDebit, m.Charges)
+	addPosting(entry, AccountCodeVAT, DirectionDebit, m.VAT)
+	addPosting(entry, WalletAccountID(m.WalletID), DirectionCredit, m.Total())
+	return entry
+}
+
+func buildOpeningBalance(walletID string, balance int64) *JournalEntry {
+	entry := newEntry(Movement{
+		Reference:   "opening:" + walletID,
+		Description: "Opening balance",
+	})
+	switch {
+	case balance > 0:
+		addPosting(entry, AccountCodeOpeningBalance, DirectionDebit, balance)
+		addPosting(entry, WalletAccountID(walletID), DirectionCredit, balance)
+	case balance < 0:
+		addPosting(entry, WalletAccountID(walletID), DirectionDebit, -balance)
+		addPosting(entry, AccountCodeOpeningBalance, DirectionCredit, -balance)
+	}
+	return entry
+}
+
+func validateEntry(entry *JournalEntry) error {
+	if entry == nil || len(entry.Postings) == 0 {
+		return ErrEmptyEntry
+	}
+
+	var debits, credits int64
+	for _, p := range entry.Postings {
+		if p.Amount <= 0 {
+			return ErrInvalidAmount
+		}
+		switch p.Direction {
+		case DirectionDebit:
+			debits += p.Amount
+		case DirectionCredit:
+			credits += p.Amount
+		default:
+			return ErrUnbalancedEntry
+		}
+	}
+
+	if debits != credits {
+		return ErrUnbalancedEntry
+	}
+	return nil
+}
+
+// normalBalance converts raw debit and credit totals into the account's balance.
+// Assets grow with debits; liabilities, equity and revenue grow with credits.
+func normalBalance(accountType AccountType, debits, credits int64) int64 {
+	if accountType == AccountTypeAsset {
+		return debits - credits
+	}
+	return credits - debits
+}
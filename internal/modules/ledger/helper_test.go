@@ -0,0 +1,92 @@
+package ledger
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBuildWalletMovementsBalance(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(Movement) *JournalEntry
+		m     Movement
+		want  int
+	}{
+		{name: "debit with charges and vat", build: buildWalletDebit, m: Movement{WalletID: "w1", Amount: 100000, Charges: 1000, VAT: 75}, want: 4},
+		{name: "debit without charges", build: buildWalletDebit, m: Movement{WalletID: "w1", Amount: 100000}, want: 2},
+		{name: "credit to savings", build: buildWalletCredit, m: Movement{WalletID: "w1", Amount: 5000, CounterAccount: AccountCodeSavings}, want: 2},
+		{name: "wallet to wallet", build: buildWalletDebit, m: Movement{WalletID: "w1", Amount: 2500, CounterAccount: WalletAccountID("w2")}, want: 2},
+		{name: "reversal returns fees", build: buildWalletCredit, m: Movement{WalletID: "w1", Amount: 100000, Charges: 1000, VAT: 75}, want: 4},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			entry := tc.build(tc.m)
+			if err := validateEntry(entry); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(entry.Postings) != tc.want {
+				t.Fatalf("got %d postings, want %d", len(entry.Postings), tc.want)
+			}
+		})
+	}
+}
+
+func TestValidateEntry(t *testing.T) {
+	tests := []struct {
+		name    string
+		entry   *JournalEntry
+		wantErr error
+	}{
+		{name: "nil entry", entry: nil, wantErr: ErrEmptyEntry},
+		{name: "no postings", entry: &JournalEntry{}, wantErr: ErrEmptyEntry},
+		{
+			name: "unbalanced",
+			entry: &JournalEntry{Postings: []Posting{
+				{Direction: DirectionDebit, Amount: 100},
+				{Direction: DirectionCredit, Amount: 90},
+			}},
+			wantErr: ErrUnbalancedEntry,
+		},
+		{
+			name: "non-positive amount",
+			entry: &JournalEntry{Postings: []Posting{
+				{Direction: DirectionDebit, Amount: 0},
+				{Direction: DirectionCredit, Amount: 0},
+			}},
+			wantErr: ErrInvalidAmount,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if err := validateEntry(tc.entry); !errors.Is(err, tc.wantErr) {
+				t.Fatalf("validateEntry() = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestOpeningBalanceSign(t *testing.T) {
+	entry := buildOpeningBalance("w1", -500)
+	if err := validateEntry(entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var debits, credits int64
+	for _, p := range entry.Postings {
+		if p.AccountID != WalletAccountID("w1") {
+			continue
+		}
+		if p.Direction == DirectionDebit {
+			debits += p.Amount
+		} else {
+			credits += p.Amount
+		}
+	}
+	if got := normalBalance(AccountTypeLiability, debits, credits); got != -500 {
+		t.Fatalf("wallet balance = %d, want -500", got)
+	}
+}
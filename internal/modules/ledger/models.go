@@ -0,0 +1,43 @@
+package ledger
+
+import "time"
+
+type Account struct {
+	ID        string      `gorm:"column:id;type:text;primaryKey"`
+	Code      string      `gorm:"column:code;type:text;not null;uniqueIndex"`
+	Name      string      `gorm:"column:name;type:text;not null"`
+	Type      AccountType `gorm:"column:type;type:text;not null"`
+	WalletID  *string     `gorm:"column:wallet_id;type:text;uniqueIndex"`
+	Currency  string      `gorm:"column:currency;type:text;not null;default:NGN"`
+	CreatedAt time.Time   `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime"`
+}
+
+func (Account) TableName() string {
+	return "wallet_ledger_accounts"
+}
+
+type JournalEntry struct {
+	ID            string    `gorm:"column:id;type:text;primaryKey"`
+	Reference     string    `gorm:"column:reference;type:text;not null;uniqueIndex"`
+	TransactionID *string   `gorm:"column:transaction_id;type:text;index"`
+	Description   string    `gorm:"column:description;type:text"`
+	Postings      []Posting `gorm:"foreignKey:JournalEntryID"`
+	CreatedAt     time.Time `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime"`
+}
+
+func (JournalEntry) TableName() string {
+	return "wallet_ledger_journal_entries"
+}
+
+type Posting struct {
+	ID             string    `gorm:"column:id;type:text;primaryKey"`
+	JournalEntryID string    `gorm:"column:journal_entry_id;type:text;not null;index"`
+	AccountID      string    `gorm:"column:account_id;type:text;not null;index"`
+	Direction      Direction `gorm:"column:direction;type:text;not null;check:direction IN ('debit','credit')"`
+	Amount         int64     `gorm:"column:amount;type:bigint;not null;check:amount > 0"`
+	CreatedAt      time.Time `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime"`
+}
+
+func (Posting) TableName() string {
+	return "wallet_ledger_postings"
+}
@@ -0,0 +1,145 @@
+package ledger
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// WithTx binds the repository to an open transaction so postings commit or roll
+// back together with the caller's balance update.
+func (r *Repository) WithTx(tx *gorm.DB) *Repository {
+	return &Repository{db: tx}
+}
+
+func SeedSystemAccounts(db *gorm.DB) error {
+	accounts := make([]Account, len(systemAccounts))
+	copy(accounts, systemAccounts)
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(&accounts).Error
+}
+
+func (r *Repository) PostEntry(ctx context.Context, entry *JournalEntry) error {
+	if err := validateEntry(entry); err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+func (r *Repository) PostWalletDebit(ctx context.Context, m Movement) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		l := r.WithTx(tx)
+		if err := l.ensureWalletAccount(ctx, m.WalletID, m.OpeningBalance); err != nil {
+			return err
+		}
+		return l.PostEntry(ctx, buildWalletDebit(m))
+	})
+}
+
+func (r *Repository) PostWalletCredit(ctx context.Context, m Movement) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		l := r.WithTx(tx)
+		if err := l.ensureWalletAccount(ctx, m.WalletID, m.OpeningBalance); err != nil {
+			return err
+		}
+		return l.PostEntry(ctx, buildWalletCredit(m))
+	})
+}
+
+// PostWalletTransfer moves m.Amount from m.WalletID straight into another
+// customer wallet, opening either ledger account on first use.
+func (r *Repository) PostWalletTransfer(ctx context.Context, m Movement, toWalletID string, toOpeningBalance int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		l := r.WithTx(tx)
+		if err := l.ensureWalletAccount(ctx, m.WalletID, m.OpeningBalance); err != nil {
+			return err
+		}
+		if err := l.ensureWalletAccount(ctx, toWalletID, toOpeningBalance); err != nil {
+			return err
+		}
+		m.CounterAccount = WalletAccountID(toWalletID)
+		return l.PostEntry(ctx, buildWalletDebit(m))
+	})
+}
+
+// ensureWalletAccount opens a ledger account for the wallet on its first
+// movement and seeds it with the cached balance the wallet already carried.
+func (r *Repository) ensureWalletAccount(ctx context.Context, walletID string, openingBalance int64) error {
+	id := WalletAccountID(walletID)
+	account := &Account{
+		ID:       id,
+		Code:     id,
+		Name:     "Customer wallet",
+		Type:     AccountTypeLiability,
+		WalletID: &walletID,
+	}
+
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(account)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 || openingBalance == 0 {
+		return nil
+	}
+
+	return r.PostEntry(ctx, buildOpeningBalance(walletID, openingBalance))
+}
+
+func (r *Repository) GetAccount(ctx context.Context, accountID string) (*Account, error) {
+	var account Account
+	err := r.db.WithContext(ctx).Where("id = ?", accountID).First(&account).Error
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *Repository) AccountBalance(ctx context.Context, account *Account) (int64, error) {
+	var totals struct {
+		Debits  int64 `gorm:"column:debits"`
+		Credits int64 `gorm:"column:credits"`
+	}
+	err := r.db.WithContext(ctx).
+		Model(&Posting{}).
+		Select(`
+			COALESCE(SUM(CASE WHEN direction = 'debit' THEN amount ELSE 0 END), 0)  AS debits,
+			COALESCE(SUM(CASE WHEN direction = 'credit' THEN amount ELSE 0 END), 0) AS credits
+		`).
+		Where("account_id = ?", account.ID).
+		Scan(&totals).Error
+	if err != nil {
+		return 0, err
+	}
+	return normalBalance(account.Type, totals.Debits, totals.Credits), nil
+}
+
+func (r *Repository) GetWalletBalanceRow(ctx context.Context, walletID string) (*walletBalanceRow, error) {
+	var row walletBalanceRow
+	err := r.db.WithContext(ctx).
+		Table("wallet_customer_wallets").
+		Select("internal_wallet_id, mobile_user_id, available_balance, booked_balance").
+		Where("internal_wallet_id = ?", walletID).
+		Take(&row).Error
+	if err != nil {
+		return nil, err
+	}
+	return &row, nil
+}
+
+func (r *Repository) GetEntriesByTransactionID(ctx context.Context, transactionID string) ([]JournalEntry, error) {
+	var entries []JournalEntry
+	err := r.db.WithContext(ctx).
+		Preload("Postings").
+		Where("transaction_id = ?", transactionID).
+		Order("created_at ASC").
+		Find(&entries).Error
+	return entries, err
+}
@@ -0,0 +1,287 @@
+package ledger
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func newMockRepository(t *testing.T) (*Repository, sqlmock.Sqlmock, func()) {
+	t.Helper()
+
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: sqlDB,
+	}), &gorm.Config{
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		_ = sqlDB.Close()
+		t.Fatalf("open gorm db: %v", err)
+	}
+
+	cleanup := func() {
+		_ = sqlDB.Close()
+	}
+
+	return NewRepository(gormDB), mock, cleanup
+}
+
+// postingColumns is how many values gorm binds per row of wallet_ledger_postings:
+// id, journal_entry_id, account_id, direction, amount, created_at.
+const postingColumns = 6
+
+// capturedPostings records the rows bound to a postings insert so a test can
+// check what was posted, not just that something was.
+type capturedPostings struct {
+	values []driver.Value
+}
+
+type captureArg struct {
+	into *capturedPostings
+}
+
+func (a captureArg) Match(v driver.Value) bool {
+	a.into.values = append(a.into.values, v)
+	return true
+}
+
+func (c *capturedPostings) args(rows int) []driver.Value {
+	out := make([]driver.Value, rows*postingColumns)
+	for i := range out {
+		out[i] = captureArg{into: c}
+	}
+	return out
+}
+
+func (c *capturedPostings) postings() []Posting {
+	var out []Posting
+	for i := 0; i+postingColumns <= len(c.values); i += postingColumns {
+		amount, _ := c.values[i+4].(int64)
+		out = append(out, Posting{
+			AccountID: c.values[i+2].(string),
+			Direction: Direction(c.values[i+3].(string)),
+			Amount:    amount,
+		})
+	}
+	return out
+}
+
+// assertBalanced fails unless the postings balance and move want on account.
+func assertBalanced(t *testing.T, postings []Posting, account string, want int64) {
+	t.Helper()
+
+	var debits, credits, net int64
+	for _, p := range postings {
+		switch p.Direction {
+		case DirectionDebit:
+			debits += p.Amount
+			if p.AccountID == account {
+				net -= p.Amount
+			}
+		case DirectionCredit:
+			credits += p.Amount
+			if p.AccountID == account {
+				net += p.Amount
+			}
+		}
+	}
+	if debits != credits {
+		t.Fatalf("unbalanced entry: debits %d, credits %d: %+v", debits, credits, postings)
+	}
+	if net != want {
+		t.Fatalf("account %s moved by %d, want %d: %+v", account, net, want, postings)
+	}
+}
+
+func insertAccountQueryPattern() string {
+	return regexp.QuoteMeta(`INSERT INTO "wallet_ledger_accounts"`)
+}
+
+func insertEntryQueryPattern() string {
+	return regexp.QuoteMeta(`INSERT INTO "wallet_ledger_journal_entries"`)
+}
+
+func insertPostingsQueryPattern() string {
+	return regexp.QuoteMeta(`INSERT INTO "wallet_ledger_postings"`)
+}
+
+func TestRepository_PostWalletDebit_SeedsOpeningBalanceOnFirstMovement(t *testing.T) {
+	repo, mock, cleanup := newMockRepository(t)
+	defer cleanup()
+
+	opening, debit := &capturedPostings{}, &capturedPostings{}
+	mock.ExpectBegin()
+	mock.ExpectExec(insertAccountQueryPattern()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(insertEntryQueryPattern()).
+		WithArgs(sqlmock.AnyArg(), "opening:iw-1", nil, "Opening balance", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(insertPostingsQueryPattern()).
+		WithArgs(opening.args(2)...).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(insertEntryQueryPattern()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(insertPostingsQueryPattern()).
+		WithArgs(debit.args(4)...).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectCommit()
+
+	err := repo.PostWalletDebit(context.Background(), Movement{
+		WalletID:       "iw-1",
+		OpeningBalance: 500_000,
+		TransactionID:  "tx-1",
+		Reference:      "debit:tx-1",
+		Amount:         100_000,
+		Charges:        1_000,
+		VAT:            75,
+	})
+	if err != nil {
+		t.Fatalf("PostWalletDebit returned error: %v", err)
+	}
+
+	assertBalanced(t, opening.postings(), WalletAccountID("iw-1"), 500_000)
+	assertBalanced(t, debit.postings(), WalletAccountID("iw-1"), -101_075)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sqlmock expectations: %v", err)
+	}
+}
+
+func TestRepository_PostWalletCredit_SkipsOpeningBalanceForKnownWallet(t *testing.T) {
+	repo, mock, cleanup := newMockRepository(t)
+	defer cleanup()
+
+	credit := &capturedPostings{}
+	mock.ExpectBegin()
+	mock.ExpectExec(insertAccountQueryPattern()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(insertEntryQueryPattern()).
+		WithArgs(sqlmock.AnyArg(), "reversal:tx-1", "tx-2", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(insertPostingsQueryPattern()).
+		WithArgs(credit.args(4)...).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectCommit()
+
+	err := repo.PostWalletCredit(context.Background(), Movement{
+		WalletID:       "iw-1",
+		OpeningBalance: 500_000,
+		TransactionID:  "tx-2",
+		Reference:      "reversal:tx-1",
+		Amount:         100_000,
+		Charges:        1_000,
+		VAT:            75,
+	})
+	if err != nil {
+		t.Fatalf("PostWalletCredit returned error: %v", err)
+	}
+
+	assertBalanced(t, credit.postings(), WalletAccountID("iw-1"), 101_075)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sqlmock expectations: %v", err)
+	}
+}
+
+func TestRepository_PostWalletTransfer_MovesBetweenWallets(t *testing.T) {
+	repo, mock, cleanup := newMockRepository(t)
+	defer cleanup()
+
+	opening, transfer := &capturedPostings{}, &capturedPostings{}
+	mock.ExpectBegin()
+	mock.ExpectExec(insertAccountQueryPattern()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	// the recipient has never moved money, so its cached balance is seeded
+	mock.ExpectExec(insertAccountQueryPattern()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(insertEntryQueryPattern()).
+		WithArgs(sqlmock.AnyArg(), "opening:iw-2", nil, "Opening balance", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(insertPostingsQueryPattern()).
+		WithArgs(opening.args(2)...).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(insertEntryQueryPattern()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(insertPostingsQueryPattern()).
+		WithArgs(transfer.args(2)...).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	err := repo.PostWalletTransfer(context.Background(), Movement{
+		WalletID:       "iw-1",
+		OpeningBalance: 500_000,
+		TransactionID:  "tx-1",
+		Reference:      "p2p:tx-1",
+		Amount:         100_000,
+	}, "iw-2", 20_000)
+	if err != nil {
+		t.Fatalf("PostWalletTransfer returned error: %v", err)
+	}
+
+	assertBalanced(t, opening.postings(), WalletAccountID("iw-2"), 20_000)
+	assertBalanced(t, transfer.postings(), WalletAccountID("iw-1"), -100_000)
+	assertBalanced(t, transfer.postings(), WalletAccountID("iw-2"), 100_000)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sqlmock expectations: %v", err)
+	}
+}
+
+func TestRepository_PostWalletDebit_RollsBackWhenPostingFails(t *testing.T) {
+	repo, mock, cleanup := newMockRepository(t)
+	defer cleanup()
+
+	postErr := errors.New("insert failed")
+	mock.ExpectBegin()
+	mock.ExpectExec(insertAccountQueryPattern()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(insertEntryQueryPattern()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(insertPostingsQueryPattern()).
+		WillReturnError(postErr)
+	mock.ExpectRollback()
+
+	err := repo.PostWalletDebit(context.Background(), Movement{
+		WalletID:      "iw-1",
+		TransactionID: "tx-1",
+		Reference:     "debit:tx-1",
+		Amount:        100_000,
+	})
+	if !errors.Is(err, postErr) {
+		t.Fatalf("expected the posting error, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sqlmock expectations: %v", err)
+	}
+}
+
+func TestRepository_PostWalletDebit_RefusesZeroAmountWithoutWriting(t *testing.T) {
+	repo, mock, cleanup := newMockRepository(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(insertAccountQueryPattern()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err := repo.PostWalletDebit(context.Background(), Movement{WalletID: "iw-1", Reference: "debit:tx-1"})
+	if !errors.Is(err, ErrEmptyEntry) {
+		t.Fatalf("expected ErrEmptyEntry, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sqlmock expectations: %v", err)
+	}
+}
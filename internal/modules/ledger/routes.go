@@ -0,0 +1,13 @@
+package ledger
+
+import "github.com/gin-gonic/gin"
+
+func RegisterInternalRoutes(rg *gin.RouterGroup, handler *Handler, internalAuth gin.HandlerFunc) {
+	ledger := rg.Group("/ledger")
+	ledger.Use(internalAuth)
+
+	{
+		ledger.GET("/wallets/:wallet_id/balance", handler.VerifyWalletBalance)
+		ledger.GET("/entries", handler.GetEntries)
+	}
+}
@@ -0,0 +1,61 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type Service struct {
+	repo *Repository
+}
+
+func NewService(repo *Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// VerifyWalletBalance compares the balance derived from postings with the
+// cached available balance on the wallet row. A wallet that has not moved since
+// the ledger went live has no account yet and is reported without drift.
+func (s *Service) VerifyWalletBalance(ctx context.Context, walletID string) (*WalletBalanceCheck, error) {
+	walletID = strings.TrimSpace(walletID)
+	if walletID == "" {
+		return nil, errors.New("missing wallet id")
+	}
+
+	row, err := s.repo.GetWalletBalanceRow(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	check := &WalletBalanceCheck{
+		WalletID:      row.InternalWalletID,
+		MobileUserID:  row.MobileUserID,
+		CachedBalance: row.AvailableBalance,
+		LedgerBalance: row.AvailableBalance,
+	}
+
+	account, err := s.repo.GetAccount(ctx, WalletAccountID(walletID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return check, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := s.repo.AccountBalance(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+
+	check.HasAccount = true
+	check.LedgerBalance = balance
+	check.Drift = row.AvailableBalance - balance
+	return check, nil
+}
+
+func (s *Service) GetEntriesByTransactionID(ctx context.Context, transactionID string) ([]JournalEntry, error) {
+	return s.repo.GetEntriesByTransactionID(ctx, strings.TrimSpace(transactionID))
+}
@@ -0,0 +1,84 @@
+package ledger
+
+import "errors"
+
+var (
+	ErrUnbalancedEntry = errors.New("journal entry is not balanced")
+	ErrEmptyEntry      = errors.New("journal entry has no postings")
+	ErrInvalidAmount   = errors.New("posting amount must be positive")
+)
+
+type AccountType string
+
+const (
+	AccountTypeAsset     AccountType = "asset"
+	AccountTypeLiability AccountType = "liability"
+	AccountTypeEquity    AccountType = "equity"
+	AccountTypeRevenue   AccountType = "revenue"
+)
+
+type Direction string
+
+const (
+	DirectionDebit  Direction = "debit"
+	DirectionCredit Direction = "credit"
+)
+
+// System accounts use their code as the primary key so postings can reference
+// them without a lookup.
+const (
+	AccountCodeSettlement     = "system:settlement"      // funds held at the BaaS provider
+	AccountCodeFees           = "system:fees"            // transfer and bill charges collected
+	AccountCodeVAT            = "system:vat"             // VAT collected on charges
+	AccountCodeSavings        = "system:savings"         // neatsave pots funded from wallets
+	AccountCodeOpeningBalance = "system:opening_balance" // balances that predate the ledger
+)
+
+var systemAccounts = []Account{
+	{ID: AccountCodeSettlement, Code: AccountCodeSettlement, Name: "Provider settlement", Type: AccountTypeAsset},
+	{ID: AccountCodeFees, Code: AccountCodeFees, Name: "Fee income", Type: AccountTypeRevenue},
+	{ID: AccountCodeVAT, Code: AccountCodeVAT, Name: "VAT payable", Type: AccountTypeLiability},
+	{ID: AccountCodeSavings, Code: AccountCodeSavings, Name: "Savings pots", Type: AccountTypeLiability},
+	{ID: AccountCodeOpeningBalance, Code: AccountCodeOpeningBalance, Name: "Opening balances", Type: AccountTypeEquity},
+}
+
+const walletAccountPrefix = "wallet:"
+
+// WalletAccountID returns the ledger account ID for a customer wallet's
+// internal wallet ID.
+func WalletAccountID(walletID string) string {
+	return walletAccountPrefix + walletID
+}
+
+// Movement describes a single money movement against a customer wallet.
+// Amount, Charges and VAT are in kobo.
+type Movement struct {
+	WalletID       string // CustomerWallet.InternalWalletID
+	OpeningBalance int64  // cached balance before this movement; seeds wallets that predate the ledger
+	TransactionID  string
+	Reference      string
+	Description    string
+	CounterAccount string // defaults to the settlement account
+	Amount         int64
+	Charges        int64
+	VAT            int64
+}
+
+func (m Movement) counterAccount() string {
+	if m.CounterAccount == "" {
+		return AccountCodeSettlement
+	}
+	return m.CounterAccount
+}
+
+// Total is the full amount leaving or entering the wallet.
+func (m Movement) Total() int64 {
+	return m.Amount + m.Charges + m.VAT
+}
+
+type walletBalanceRow struct {
+	InternalWalletID string `gorm:"column:internal_wallet_id"`
+	MobileUserID     string `gorm:"column:mobile_user_id"`
+	AvailableBalance int64  `gorm:"column:available_balance"`
+	BookedBalance    int64  `gorm:"column:booked_balance"`
+}
@@ -0,0 +1,33 @@
+package limits
+
+import "time"
+
+// ChannelUsage amounts are in kobo.
+type ChannelUsage struct {
+	Channel          Channel `json:"channel"`
+	PerTransaction   int64   `json:"per_transaction"`
+	Daily            int64   `json:"daily"`
+	Monthly          int64   `json:"monthly"`
+	DailySpent       int64   `json:"daily_spent"`
+	MonthlySpent     int64   `json:"monthly_spent"`
+	DailyRemaining   int64   `json:"daily_remaining"`
+	MonthlyRemaining int64   `json:"monthly_remaining"`
+}
+
+// ActivationCap is the total outflow a newly activated user may send before
+// the cap expires. Amounts are in kobo.
+type ActivationCap struct {
+	Active    bool      `json:"active"`
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+	Limit     int64     `json:"limit,omitempty"`
+	Spent     int64     `json:"spent,omitempty"`
+	Remaining int64     `json:"remaining,omitempty"`
+}
+
+type Summary struct {
+	Tier          Tier           `json:"tier"`
+	WalletTier    Tier           `json:"wallet_tier"`
+	KYCTier       Tier           `json:"kyc_tier"`
+	Channels      []ChannelUsage `json:"channels"`
+	ActivationCap ActivationCap  `json:"activation_cap"`
+}
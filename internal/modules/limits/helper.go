@@ -0,0 +1,108 @@
+package limits
+
+import (
+	"errors"
+	appErr "neat_mobile_app_backend/internal/errors"
+	"neat_mobile_app_backend/models"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// CheckAirtime returns an error when amount (kobo) is outside the range a
+// single airtime purchase may be.
+func CheckAirtime(amount int64) error {
+	if amount < airtimeMinimum || amount > airtimeMaximum {
+		return appErr.ErrInvalidISPAmount
+	}
+	return nil
+}
+
+// IsLimitError reports whether err came from a limit check rather than from
+// booking the debit it guarded.
+func IsLimitError(err error) bool {
+	for _, target := range []error{
+		appErr.ErrPerTransactionLimitExceeded, appErr.ErrDailyLimitExceeded, appErr.ErrMonthlyLimitExceeded,
+		appErr.ErrNewUserTransferRestriction, appErr.ErrActivationCapExceeded,
+		appErr.ErrCheckingLimits, appErr.ErrUnauthorized,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseTier reads the wallet provider's tier label ("1", "TIER_2", "Tier 3").
+// Anything it cannot read is treated as the lowest tier.
+func ParseTier(label string) Tier {
+	for _, r := range strings.TrimSpace(label) {
+		if unicode.IsDigit(r) {
+			tier := Tier(r - '0')
+			if tier >= Tier1 && tier <= Tier3 {
+				return tier
+			}
+			break
+		}
+	}
+	return Tier1
+}
+
+// kycTier is the highest tier the user's verified identity supports.
+func kycTier(user *models.User) Tier {
+	switch {
+	case user.IsBvnVerified && user.IsNinVerified:
+		return Tier3
+	case user.IsBvnVerified || user.IsNinVerified:
+		return Tier2
+	default:
+		return Tier1
+	}
+}
+
+// newUserRestricted reports whether the new-user transfer cap still applies.
+// An activation cap replaces it only once the cap itself has expired.
+func newUserRestricted(user *models.User, now time.Time) bool {
+	if user.ActivationCapExpiresAt != nil && now.After(*user.ActivationCapExpiresAt) {
+		return false
+	}
+	return user.CreatedAt.Add(newUserWindow).After(now)
+}
+
+func limitsFor(tier Tier, channel Channel, transferCeiling int64) Limit {
+	limit := tierLimits[tier][channel]
+	if channel == ChannelTransfer && transferCeiling > 0 {
+		limit.PerTransaction = min(limit.PerTransaction, transferCeiling)
+	}
+	return limit
+}
+
+// windowStarts returns the start of the day and month that contain now.
+func windowStarts(now time.Time) (day, month time.Time) {
+	local := now.In(limitsLocation)
+	y, m, d := local.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, limitsLocation), time.Date(y, m, 1, 0, 0, 0, 0, limitsLocation)
+}
+
+// checkLimit reports which limit, if any, the amounts break given what was
+// already spent today and this month.
+func checkLimit(limit Limit, dailySpent, monthlySpent int64, amounts []int64) error {
+	var total int64
+	for _, amount := range amounts {
+		if limit.PerTransaction > 0 && amount > limit.PerTransaction {
+			return appErr.ErrPerTransactionLimitExceeded
+		}
+		total += amount
+	}
+	if limit.Daily > 0 && dailySpent+total > limit.Daily {
+		return appErr.ErrDailyLimitExceeded
+	}
+	if limit.Monthly > 0 && monthlySpent+total > limit.Monthly {
+		return appErr.ErrMonthlyLimitExceeded
+	}
+	return nil
+}
+
+func remaining(limit, spent int64) int64 {
+	return max(limit-spent, 0)
+}
@@ -0,0 +1,121 @@
+package limits
+
+import (
+	"errors"
+	appErr "neat_mobile_app_backend/internal/errors"
+	"neat_mobile_app_backend/models"
+	"testing"
+	"time"
+)
+
+func TestParseTier(t *testing.T) {
+	tests := []struct {
+		label string
+		want  Tier
+	}{
+		{label: "1", want: Tier1},
+		{label: "TIER_2", want: Tier2},
+		{label: "Tier 3", want: Tier3},
+		{label: "", want: Tier1},
+		{label: "tier 9", want: Tier1},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.label, func(t *testing.T) {
+			if got := ParseTier(tc.label); got != tc.want {
+				t.Fatalf("ParseTier(%q) = %d, want %d", tc.label, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestKYCTier(t *testing.T) {
+	if got := kycTier(&models.User{}); got != Tier1 {
+		t.Fatalf("unverified user tier = %d, want %d", got, Tier1)
+	}
+	if got := kycTier(&models.User{IsBvnVerified: true}); got != Tier2 {
+		t.Fatalf("bvn-only user tier = %d, want %d", got, Tier2)
+	}
+	if got := kycTier(&models.User{IsBvnVerified: true, IsNinVerified: true}); got != Tier3 {
+		t.Fatalf("fully verified user tier = %d, want %d", got, Tier3)
+	}
+}
+
+func TestCheckLimit(t *testing.T) {
+	limit := Limit{PerTransaction: 1000, Daily: 2000, Monthly: 5000}
+	tests := []struct {
+		name    string
+		daily   int64
+		monthly int64
+		amounts []int64
+		wantErr error
+	}{
+		{name: "within limits", daily: 500, monthly: 500, amounts: []int64{1000}},
+		{name: "single too large", amounts: []int64{1001}, wantErr: appErr.ErrPerTransactionLimitExceeded},
+		{name: "batch breaks daily", daily: 500, monthly: 500, amounts: []int64{800, 800}, wantErr: appErr.ErrDailyLimitExceeded},
+		{name: "breaks monthly", daily: 0, monthly: 4500, amounts: []int64{600}, wantErr: appErr.ErrMonthlyLimitExceeded},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if err := checkLimit(limit, tc.daily, tc.monthly, tc.amounts); !errors.Is(err, tc.wantErr) {
+				t.Fatalf("checkLimit() error = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewUserRestricted(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	future := now.Add(48 * time.Hour)
+	past := now.Add(-time.Minute)
+
+	tests := []struct {
+		name string
+		user *models.User
+		want bool
+	}{
+		{name: "new user without activation cap", user: &models.User{CreatedAt: now.Add(-time.Hour)}, want: true},
+		{name: "new user with activation cap running", user: &models.User{CreatedAt: now.Add(-time.Hour), ActivationCapExpiresAt: &future}, want: true},
+		{name: "activation cap expired", user: &models.User{CreatedAt: now.Add(-time.Hour), ActivationCapExpiresAt: &past}},
+		{name: "past the new-user window", user: &models.User{CreatedAt: now.Add(-25 * time.Hour), ActivationCapExpiresAt: &future}},
+	}
+
+	for _, tc := range tests {
+		if got := newUserRestricted(tc.user, now); got != tc.want {
+			t.Fatalf("%s: newUserRestricted() = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestLimitsForAppliesTransferCeiling(t *testing.T) {
+	if got := limitsFor(Tier3, ChannelTransfer, 1_000_000_00).PerTransaction; got != 1_000_000_00 {
+		t.Fatalf("per-transaction = %d, want ceiling", got)
+	}
+	if got := limitsFor(Tier3, ChannelVAS, 1).PerTransaction; got != tierLimits[Tier3][ChannelVAS].PerTransaction {
+		t.Fatalf("ceiling leaked into vas limits: %d", got)
+	}
+}
+
+func TestWindowStartsUsesLagosTime(t *testing.T) {
+	// 23:30 UTC on the last day of the month is already the next month in Lagos.
+	day, month := windowStarts(time.Date(2026, 1, 31, 23, 30, 0, 0, time.UTC))
+	if day.Day() != 1 || day.Month() != time.February || !day.Equal(month) {
+		t.Fatalf("windowStarts() = %v, %v", day, month)
+	}
+}
+
+func TestCheckAirtime(t *testing.T) {
+	for _, amount := range []int64{100_00, 10_000_00} {
+		if err := CheckAirtime(amount); err != nil {
+			t.Fatalf("CheckAirtime(%d) error = %v, want nil", amount, err)
+		}
+	}
+	for _, amount := range []int64{99_99, 10_000_01} {
+		if err := CheckAirtime(amount); !errors.Is(err, appErr.ErrInvalidISPAmount) {
+			t.Fatalf("CheckAirtime(%d) error = %v, want %v", amount, err, appErr.ErrInvalidISPAmount)
+		}
+	}
+}
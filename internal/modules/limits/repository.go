@@ -0,0 +1,93 @@
+package limits
+
+import (
+	"context"
+	"neat_mobile_app_backend/internal/modules/transaction"
+	"neat_mobile_app_backend/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) WithTx(tx *gorm.DB) *Repository {
+	return &Repository{db: tx}
+}
+
+// LockUser takes a row lock on the user for the rest of the transaction, so
+// debits by the same user are checked one after another.
+func (r *Repository) LockUser(ctx context.Context, mobileUserID string) error {
+	var user models.User
+	return r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("id = ?", mobileUserID).
+		First(&user).Error
+}
+
+func (r *Repository) GetUser(ctx context.Context, mobileUserID string) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).Where("id = ?", mobileUserID).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetWalletTier returns the provider tier label on the user's wallet, or an
+// empty string when the user has no wallet yet.
+func (r *Repository) GetWalletTier(ctx context.Context, mobileUserID string) (string, error) {
+	var tiers []string
+	err := r.db.WithContext(ctx).
+		Table("wallet_customer_wallets").
+		Where("mobile_user_id = ?", mobileUserID).
+		Limit(1).
+		Pluck("tier", &tiers).Error
+	if err != nil || len(tiers) == 0 {
+		return "", err
+	}
+	return tiers[0], nil
+}
+
+type usageRow struct {
+	Daily   int64 `gorm:"column:daily"`
+	Monthly int64 `gorm:"column:monthly"`
+}
+
+// SumDebits totals successful and pending debits in the given categories since
+// monthStart, and separately since dayStart. Pending debits count so that
+// concurrent requests cannot both fit under a limit.
+func (r *Repository) SumDebits(ctx context.Context, mobileUserID string, categories []transaction.TransactionCategory, dayStart, monthStart time.Time) (daily, monthly int64, err error) {
+	var row usageRow
+	err = r.db.WithContext(ctx).
+		Model(&transaction.Transaction{}).
+		Select("COALESCE(SUM(amount) FILTER (WHERE created_at >= ?), 0) AS daily, COALESCE(SUM(amount), 0) AS monthly", dayStart).
+		Where("mobile_user_id = ? AND type = ? AND transaction_category IN ? AND status IN ? AND created_at >= ?",
+			mobileUserID, transaction.TransactionTypeDebit, categories, countedStatuses, monthStart).
+		Scan(&row).Error
+	return row.Daily, row.Monthly, err
+}
+
+// SumAllDebits totals successful and pending debits of every kind since from.
+func (r *Repository) SumAllDebits(ctx context.Context, mobileUserID string, from time.Time) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).
+		Model(&transaction.Transaction{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("mobile_user_id = ? AND type = ? AND status IN ? AND created_at >= ?",
+			mobileUserID, transaction.TransactionTypeDebit, countedStatuses, from).
+		Scan(&total).Error
+	return total, err
+}
+
+var countedStatuses = []transaction.TransactionStatus{
+	transaction.TransactionStatusSuccessful,
+	transaction.TransactionStatusPending,
+}
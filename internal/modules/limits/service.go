@@ -0,0 +1,166 @@
+package limits
+
+import (
+	"context"
+	"errors"
+	"log"
+	appErr "neat_mobile_app_backend/internal/errors"
+	"neat_mobile_app_backend/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Service is the one place debit limits are decided. Every debit path calls
+// CheckTx in the transaction that books money out of a wallet.
+type Service struct {
+	repo *Repository
+	// transferCeiling, when set, caps the per-transaction transfer limit of
+	// every tier. In kobo.
+	transferCeiling int64
+}
+
+func NewService(repo *Repository, transferCeiling int64) *Service {
+	return &Service{repo: repo, transferCeiling: transferCeiling}
+}
+
+// Check returns an error when debiting amounts (kobo) on channel would break
+// one of the user's limits. Each amount is one transaction; bulk debits pass
+// all of them so the batch total is held against the daily and monthly caps.
+func (s *Service) Check(ctx context.Context, mobileUserID string, channel Channel, amounts ...int64) error {
+	user, tier, err := s.loadTier(ctx, mobileUserID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+
+	restricted := channel == ChannelTransfer && newUserRestricted(user, now)
+	var total int64
+	for _, amount := range amounts {
+		total += amount
+		if restricted && amount > newUserTransferLimit {
+			return appErr.ErrNewUserTransferRestriction
+		}
+	}
+
+	if user.ActivationCapExpiresAt != nil && now.Before(*user.ActivationCapExpiresAt) {
+		spent, err := s.repo.SumAllDebits(ctx, mobileUserID, user.CreatedAt)
+		if err != nil {
+			log.Printf("limits service: failed to sum activation cap usage: %v", err)
+			return appErr.ErrCheckingLimits
+		}
+		if spent+total > user.ActivationCapAmount {
+			return appErr.ErrActivationCapExceeded
+		}
+	}
+
+	dayStart, monthStart := windowStarts(now)
+	daily, monthly, err := s.repo.SumDebits(ctx, mobileUserID, channelCategories[channel], dayStart, monthStart)
+	if err != nil {
+		log.Printf("limits service: failed to sum %s usage: %v", channel, err)
+		return appErr.ErrCheckingLimits
+	}
+
+	return checkLimit(limitsFor(tier, channel, s.transferCeiling), daily, monthly, amounts)
+}
+
+// CheckTx runs Check inside tx, the transaction that books the debit. It locks
+// the user's row first, so a concurrent debit waits until this one is booked
+// and then counts it as pending usage.
+func (s *Service) CheckTx(ctx context.Context, tx *gorm.DB, mobileUserID string, channel Channel, amounts ...int64) error {
+	locked := &Service{repo: s.repo.WithTx(tx), transferCeiling: s.transferCeiling}
+	err := locked.repo.LockUser(ctx, mobileUserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return appErr.ErrUnauthorized
+	}
+	if err != nil {
+		log.Printf("limits service: failed to lock user: %v", err)
+		return appErr.ErrCheckingLimits
+	}
+	return locked.Check(ctx, mobileUserID, channel, amounts...)
+}
+
+// Summary reports the user's tier, each channel's limits and what is left of
+// them, and any activation cap.
+func (s *Service) Summary(ctx context.Context, mobileUserID string) (*Summary, error) {
+	user, tier, err := s.loadTier(ctx, mobileUserID)
+	if err != nil {
+		return nil, err
+	}
+	walletTier, err := s.walletTier(ctx, mobileUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	summary := &Summary{
+		Tier:       tier,
+		WalletTier: walletTier,
+		KYCTier:    kycTier(user),
+		Channels:   make([]ChannelUsage, 0, len(channels)),
+	}
+
+	dayStart, monthStart := windowStarts(now)
+	for _, channel := range channels {
+		daily, monthly, err := s.repo.SumDebits(ctx, mobileUserID, channelCategories[channel], dayStart, monthStart)
+		if err != nil {
+			log.Printf("limits service: failed to sum %s usage: %v", channel, err)
+			return nil, appErr.ErrCheckingLimits
+		}
+		limit := limitsFor(tier, channel, s.transferCeiling)
+		summary.Channels = append(summary.Channels, ChannelUsage{
+			Channel:          channel,
+			PerTransaction:   limit.PerTransaction,
+			Daily:            limit.Daily,
+			Monthly:          limit.Monthly,
+			DailySpent:       daily,
+			MonthlySpent:     monthly,
+			DailyRemaining:   remaining(limit.Daily, daily),
+			MonthlyRemaining: remaining(limit.Monthly, monthly),
+		})
+	}
+
+	if user.ActivationCapExpiresAt != nil && now.Before(*user.ActivationCapExpiresAt) {
+		spent, err := s.repo.SumAllDebits(ctx, mobileUserID, user.CreatedAt)
+		if err != nil {
+			log.Printf("limits service: failed to sum activation cap usage: %v", err)
+			return nil, appErr.ErrCheckingLimits
+		}
+		summary.ActivationCap = ActivationCap{
+			Active:    true,
+			ExpiresAt: *user.ActivationCapExpiresAt,
+			Limit:     user.ActivationCapAmount,
+			Spent:     spent,
+			Remaining: remaining(user.ActivationCapAmount, spent),
+		}
+	}
+
+	return summary, nil
+}
+
+// loadTier returns the user and their effective tier.
+func (s *Service) loadTier(ctx context.Context, mobileUserID string) (*models.User, Tier, error) {
+	user, err := s.repo.GetUser(ctx, mobileUserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, 0, appErr.ErrUnauthorized
+	}
+	if err != nil {
+		log.Printf("limits service: failed to get user: %v", err)
+		return nil, 0, appErr.ErrCheckingLimits
+	}
+
+	walletTier, err := s.walletTier(ctx, mobileUserID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return user, min(walletTier, kycTier(user)), nil
+}
+
+func (s *Service) walletTier(ctx context.Context, mobileUserID string) (Tier, error) {
+	label, err := s.repo.GetWalletTier(ctx, mobileUserID)
+	if err != nil {
+		log.Printf("limits service: failed to get wallet tier: %v", err)
+		return 0, appErr.ErrCheckingLimits
+	}
+	return ParseTier(label), nil
+}
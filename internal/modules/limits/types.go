@@ -0,0 +1,82 @@
+package limits
+
+import (
+	"neat_mobile_app_backend/internal/modules/transaction"
+	"time"
+)
+
+// Channel groups debits that share a set of limits.
+type Channel string
+
+const (
+	ChannelTransfer Channel = "transfer"
+	ChannelVAS      Channel = "vas"
+	ChannelCard     Channel = "card"
+)
+
+var channels = []Channel{ChannelTransfer, ChannelVAS, ChannelCard}
+
+// channelCategories are the wallet transaction categories that count toward a
+// channel's daily and monthly usage.
+var channelCategories = map[Channel][]transaction.TransactionCategory{
+	ChannelTransfer: {transaction.TransactionCategoryTransferTo, transaction.TransactionCategoryLoanRepayment},
+	ChannelVAS: {
+		transaction.TransactionCategoryAirtime,
+		transaction.TransactionCategoryMobileData,
+		transaction.TransactionCategoryTV,
+		transaction.TransactionCategoryElectricity,
+	},
+	ChannelCard: {transaction.TransactionCategoryCardPayment},
+}
+
+// Tier is a KYC tier. A user's effective tier is the lower of their wallet tier
+// and the tier their verified KYC supports.
+type Tier int
+
+const (
+	Tier1 Tier = iota + 1
+	Tier2
+	Tier3
+)
+
+// Limit amounts are in kobo.
+type Limit struct {
+	PerTransaction int64
+	Daily          int64
+	Monthly        int64
+}
+
+var tierLimits = map[Tier]map[Channel]Limit{
+	Tier1: {
+		ChannelTransfer: {PerTransaction: 50_000_00, Daily: 50_000_00, Monthly: 300_000_00},
+		ChannelVAS:      {PerTransaction: 10_000_00, Daily: 20_000_00, Monthly: 100_000_00},
+		ChannelCard:     {PerTransaction: 50_000_00, Daily: 50_000_00, Monthly: 300_000_00},
+	},
+	Tier2: {
+		ChannelTransfer: {PerTransaction: 100_000_00, Daily: 200_000_00, Monthly: 2_000_000_00},
+		ChannelVAS:      {PerTransaction: 20_000_00, Daily: 50_000_00, Monthly: 500_000_00},
+		ChannelCard:     {PerTransaction: 100_000_00, Daily: 200_000_00, Monthly: 2_000_000_00},
+	},
+	Tier3: {
+		ChannelTransfer: {PerTransaction: 5_000_000_00, Daily: 5_000_000_00, Monthly: 50_000_000_00},
+		ChannelVAS:      {PerTransaction: 50_000_00, Daily: 500_000_00, Monthly: 5_000_000_00},
+		ChannelCard:     {PerTransaction: 1_000_000_00, Daily: 5_000_000_00, Monthly: 50_000_000_00},
+	},
+}
+
+// Airtime purchases must also fall in this range, in kobo, whatever the
+// tier's VAS limits allow.
+const (
+	airtimeMinimum = 100_00
+	airtimeMaximum = 10_000_00
+)
+
+const (
+	// newUserWindow and newUserTransferLimit cap single transfers for new
+	// users until their activation cap, if they have one, has run out.
+	newUserWindow        = 24 * time.Hour
+	newUserTransferLimit = 20_000_00
+)
+
+// limitsLocation sets where days and months start.
+var limitsLocation = time.FixedZone("WAT", 60*60)
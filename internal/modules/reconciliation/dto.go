@@ -0,0 +1,34 @@
+package reconciliation
+
+import (
+	"io"
+	"time"
+)
+
+// StatementInput is a settlement file to reconcile. From and To bound the
+// period checked for transactions missing at the provider; when nil they are
+// taken from the dates on the statement lines.
+type StatementInput struct {
+	Provider string
+	Source   string
+	Format   StatementFormat
+	Body     io.Reader
+	From     *time.Time
+	To       *time.Time
+}
+
+type RunSummary struct {
+	Run         Run               `json:"run"`
+	BreakCounts map[BreakType]int `json:"break_counts"`
+}
+
+type breakCountRow struct {
+	Type  BreakType `gorm:"column:type"`
+	Count int       `gorm:"column:count"`
+}
+
+type ReconcileQuery struct {
+	Format string `form:"format"`
+	From   string `form:"from"`
+	To     string `form:"to"`
+}
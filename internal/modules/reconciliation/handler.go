@@ -0,0 +1,130 @@
+package reconciliation
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	requestTimeout = 2 * time.Minute
+	// maxStatementSize caps uploaded settlement files.
+	maxStatementSize = 32 << 20
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+func withTimeout(c *gin.Context) (context.Context, context.CancelFunc) {
+	if c == nil || c.Request == nil {
+		return context.WithTimeout(context.Background(), requestTimeout)
+	}
+	return context.WithTimeout(c.Request.Context(), requestTimeout)
+}
+
+// ReconcileStatement accepts the settlement file either as a multipart "file"
+// field or as the raw request body with ?format=csv|json.
+func (h *Handler) ReconcileStatement(c *gin.Context) {
+	var query ReconcileQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid query params"})
+		return
+	}
+
+	from, to, err := parsePeriodQuery(query.From, query.To)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "from and to must be YYYY-MM-DD dates"})
+		return
+	}
+
+	input := StatementInput{
+		Provider: ProviderProvidus,
+		Format:   StatementFormat(strings.ToLower(strings.TrimSpace(query.Format))),
+		From:     from,
+		To:       to,
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxStatementSize)
+	if file, header, err := c.Request.FormFile("file"); err == nil {
+		defer file.Close()
+		input.Body = file
+		input.Source = header.Filename
+		if input.Format == "" {
+			input.Format = FormatFromName(header.Filename)
+		}
+	} else {
+		input.Body = c.Request.Body
+		input.Source = "upload"
+		if input.Format == "" {
+			input.Format = StatementFormatCSV
+		}
+	}
+
+	ctx, cancel := withTimeout(c)
+	defer cancel()
+
+	resp, err := h.service.ReconcileStatement(ctx, input)
+	switch {
+	case errors.Is(err, ErrUnsupportedFormat),
+		errors.Is(err, ErrEmptyStatement),
+		errors.Is(err, ErrMissingPeriod),
+		errors.Is(err, ErrMissingReference),
+		errors.Is(err, ErrInvalidStatementRow):
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, context.DeadlineExceeded):
+		c.AbortWithStatusJSON(http.StatusGatewayTimeout, gin.H{"error": "request timed out"})
+	case err != nil:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "something went wrong, please try again"})
+	default:
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+func (h *Handler) GetRun(c *gin.Context) {
+	ctx, cancel := withTimeout(c)
+	defer cancel()
+
+	resp, err := h.service.GetRun(ctx, c.Param("id"))
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "reconciliation run not found"})
+	case errors.Is(err, context.DeadlineExceeded):
+		c.AbortWithStatusJSON(http.StatusGatewayTimeout, gin.H{"error": "request timed out"})
+	case err != nil:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "something went wrong, please try again"})
+	default:
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// parsePeriodQuery reads optional from/to dates; to is inclusive, so the
+// returned bound is the start of the following day.
+func parsePeriodQuery(fromRaw, toRaw string) (*time.Time, *time.Time, error) {
+	var from, to *time.Time
+	if v := strings.TrimSpace(fromRaw); v != "" {
+		t, err := time.ParseInLocation(time.DateOnly, v, time.UTC)
+		if err != nil {
+			return nil, nil, err
+		}
+		from = &t
+	}
+	if v := strings.TrimSpace(toRaw); v != "" {
+		t, err := time.ParseInLocation(time.DateOnly, v, time.UTC)
+		if err != nil {
+			return nil, nil, err
+		}
+		t = t.AddDate(0, 0, 1)
+		to = &t
+	}
+	return from, to, nil
+}
@@ -0,0 +1,171 @@
+package reconciliation
+
+import (
+	"slices"
+	"time"
+
+	"neat_mobile_app_backend/internal/modules/transaction"
+
+	"github.com/google/uuid"
+)
+
+// statementPeriod spans whole UTC days from the earliest to the latest dated
+// line. ok is false when no line carries a date.
+func statementPeriod(lines []StatementLine) (start, end time.Time, ok bool) {
+	for _, line := range lines {
+		if line.Date == nil {
+			continue
+		}
+		if !ok || line.Date.Before(start) {
+			start = *line.Date
+		}
+		if !ok || line.Date.After(end) {
+			end = *line.Date
+		}
+		ok = true
+	}
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+
+	start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	end = time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+	return start, end, true
+}
+
+func statementKeys(lines []StatementLine) (refs, sessionIDs []string) {
+	for _, line := range lines {
+		if line.Reference != "" {
+			refs = append(refs, line.Reference)
+		}
+		if line.SessionID != "" {
+			sessionIDs = append(sessionIDs, line.SessionID)
+		}
+	}
+	return refs, sessionIDs
+}
+
+// compareStatement matches each statement line to a wallet transaction by
+// provider reference, our own reference, then session id, and reports every
+// difference. settled is the set of our transactions the provider should have
+// reported for the period; any of them left unmatched is missing at the provider.
+func compareStatement(runID string, lines []StatementLine, candidates, settled []transaction.Transaction) (int, []Break) {
+	byProviderRef := make(map[string]*transaction.Transaction, len(candidates))
+	byRef := make(map[string]*transaction.Transaction, len(candidates))
+	bySession := make(map[string]*transaction.Transaction, len(candidates))
+	for i := range candidates {
+		tx := &candidates[i]
+		if tx.ProviderReference != "" {
+			byProviderRef[tx.ProviderReference] = tx
+		}
+		if tx.Reference != "" {
+			byRef[tx.Reference] = tx
+		}
+		if tx.SessionID != "" {
+			bySession[tx.SessionID] = tx
+		}
+	}
+
+	matchedIDs := make(map[string]bool, len(lines))
+	matched := 0
+	var breaks []Break
+
+	for _, line := range lines {
+		tx := lookupTransaction(line, byProviderRef, byRef, bySession)
+		if tx == nil {
+			breaks = append(breaks, newLineBreak(runID, BreakMissingInternal, line, nil))
+			continue
+		}
+		if matchedIDs[tx.ID] {
+			breaks = append(breaks, newLineBreak(runID, BreakDuplicateProvider, line, tx))
+			continue
+		}
+		matchedIDs[tx.ID] = true
+
+		clean := true
+		if line.Amount != tx.Amount {
+			breaks = append(breaks, newLineBreak(runID, BreakAmountMismatch, line, tx))
+			clean = false
+		}
+		if line.Status != providerStatus(tx) {
+			breaks = append(breaks, newLineBreak(runID, BreakStatusMismatch, line, tx))
+			clean = false
+		}
+		if clean {
+			matched++
+		}
+	}
+
+	for i := range settled {
+		tx := &settled[i]
+		if matchedIDs[tx.ID] {
+			continue
+		}
+		txID := tx.ID
+		amount := tx.Amount
+		createdAt := tx.CreatedAt
+		breaks = append(breaks, Break{
+			ID:                uuid.NewString(),
+			RunID:             runID,
+			Type:              BreakMissingProvider,
+			ProviderReference: tx.ProviderReference,
+			SessionID:         tx.SessionID,
+			TransactionID:     &txID,
+			InternalAmount:    &amount,
+			InternalStatus:    string(tx.Status),
+			TransactionDate:   &createdAt,
+		})
+	}
+
+	return matched, breaks
+}
+
+// providerStatus is the status the provider reports for tx. A refunded bill
+// payment shows there as a settled debit, with the refund on its own line.
+func providerStatus(tx *transaction.Transaction) transaction.TransactionStatus {
+	if tx.Type == transaction.TransactionTypeDebit &&
+		slices.Contains(billCategories, tx.Category) &&
+		slices.Contains(refundedStatuses, tx.Status) {
+		return transaction.TransactionStatusSuccessful
+	}
+	return tx.Status
+}
+
+func lookupTransaction(line StatementLine, byProviderRef, byRef, bySession map[string]*transaction.Transaction) *transaction.Transaction {
+	if line.Reference != "" {
+		if tx, ok := byProviderRef[line.Reference]; ok {
+			return tx
+		}
+		if tx, ok := byRef[line.Reference]; ok {
+			return tx
+		}
+	}
+	if line.SessionID != "" {
+		if tx, ok := bySession[line.SessionID]; ok {
+			return tx
+		}
+	}
+	return nil
+}
+
+func newLineBreak(runID string, typ BreakType, line StatementLine, tx *transaction.Transaction) Break {
+	providerAmount := line.Amount
+	b := Break{
+		ID:                uuid.NewString(),
+		RunID:             runID,
+		Type:              typ,
+		ProviderReference: line.Reference,
+		SessionID:         line.SessionID,
+		ProviderAmount:    &providerAmount,
+		ProviderStatus:    string(line.Status),
+		TransactionDate:   line.Date,
+	}
+	if tx != nil {
+		txID := tx.ID
+		internalAmount := tx.Amount
+		b.TransactionID = &txID
+		b.InternalAmount = &internalAmount
+		b.InternalStatus = string(tx.Status)
+	}
+	return b
+}
@@ -0,0 +1,122 @@
+package reconciliation
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"neat_mobile_app_backend/internal/modules/transaction"
+)
+
+func TestParseStatementCSV(t *testing.T) {
+	body := "Transaction Reference,Session ID,Amount,DR/CR,Status,Transaction Date\n" +
+		"PRV-1,S1,\"1,500.50\",CR,Successful,2024-05-01 10:00:00\n" +
+		",S2,200,DR,,2024-05-02\n"
+
+	lines, err := ParseStatement(strings.NewReader(body), StatementFormatCSV)
+	if err != nil {
+		t.Fatalf("ParseStatement returned error: %v", err)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(lines))
+	}
+	if lines[0].Reference != "PRV-1" || lines[0].Amount != 150050 || lines[0].Type != transaction.TransactionTypeCredit {
+		t.Fatalf("unexpected first line: %+v", lines[0])
+	}
+	if lines[1].SessionID != "S2" || lines[1].Status != transaction.TransactionStatusSuccessful {
+		t.Fatalf("unexpected second line: %+v", lines[1])
+	}
+
+	start, end, ok := statementPeriod(lines)
+	if !ok || !start.Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)) || !end.Equal(time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected period: %v - %v (ok=%v)", start, end, ok)
+	}
+}
+
+func TestParseStatementJSONWrapped(t *testing.T) {
+	body := `{"transactions":[{"transactionReference":"PRV-1","amount":99.99,"status":"REVERSED"}]}`
+
+	lines, err := ParseStatement(strings.NewReader(body), StatementFormatJSON)
+	if err != nil {
+		t.Fatalf("ParseStatement returned error: %v", err)
+	}
+	if len(lines) != 1 || lines[0].Amount != 9999 || lines[0].Status != transaction.TransactionStatusReversed {
+		t.Fatalf("unexpected lines: %+v", lines)
+	}
+}
+
+func TestCompareStatement(t *testing.T) {
+	lines := []StatementLine{
+		{Reference: "PRV-OK", Amount: 1000, Status: transaction.TransactionStatusSuccessful},
+		{Reference: "PRV-AMT", Amount: 2500, Status: transaction.TransactionStatusSuccessful},
+		{SessionID: "S-REV", Amount: 300, Status: transaction.TransactionStatusReversed},
+		{Reference: "PRV-UNKNOWN", Amount: 700, Status: transaction.TransactionStatusSuccessful},
+		{Reference: "PRV-OK", Amount: 1000, Status: transaction.TransactionStatusSuccessful},
+	}
+	candidates := []transaction.Transaction{
+		{ID: "tx-ok", ProviderReference: "PRV-OK", Amount: 1000, Status: transaction.TransactionStatusSuccessful},
+		{ID: "tx-amt", Reference: "PRV-AMT", Amount: 2000, Status: transaction.TransactionStatusSuccessful},
+		{ID: "tx-rev", SessionID: "S-REV", Amount: 300, Status: transaction.TransactionStatusSuccessful},
+	}
+	settled := []transaction.Transaction{
+		candidates[0],
+		{ID: "tx-missing", ProviderReference: "PRV-MISSING", Amount: 400, Status: transaction.TransactionStatusSuccessful},
+	}
+
+	matched, breaks := compareStatement("run-1", lines, candidates, settled)
+	if matched != 1 {
+		t.Fatalf("expected 1 clean match, got %d", matched)
+	}
+
+	got := make(map[BreakType]string)
+	for _, b := range breaks {
+		key := b.ProviderReference
+		if b.TransactionID != nil {
+			key = *b.TransactionID
+		}
+		got[b.Type] = key
+	}
+
+	want := map[BreakType]string{
+		BreakAmountMismatch:    "tx-amt",
+		BreakStatusMismatch:    "tx-rev",
+		BreakMissingInternal:   "PRV-UNKNOWN",
+		BreakMissingProvider:   "tx-missing",
+		BreakDuplicateProvider: "tx-ok",
+	}
+	if len(breaks) != len(want) {
+		t.Fatalf("expected %d breaks, got %d: %+v", len(want), len(breaks), breaks)
+	}
+	for typ, key := range want {
+		if got[typ] != key {
+			t.Fatalf("break %s: expected %q, got %q", typ, key, got[typ])
+		}
+	}
+}
+
+func TestCompareStatementMatchesRefundedBillPayments(t *testing.T) {
+	lines := []StatementLine{
+		{Reference: "VAS-REF", Amount: 50000, Status: transaction.TransactionStatusSuccessful},
+		{Reference: "VAS-REFUND", Amount: 50000, Status: transaction.TransactionStatusSuccessful},
+	}
+	debit := transaction.Transaction{
+		ID: "tx-airtime", Type: transaction.TransactionTypeDebit, Category: transaction.TransactionCategoryAirtime,
+		Reference: "VAS-REF", Amount: 50000, Status: transaction.TransactionStatusReversed,
+	}
+	refund := transaction.Transaction{
+		ID: "tx-refund", Type: transaction.TransactionTypeCredit, Category: transaction.TransactionCategoryReversal,
+		ProviderReference: "VAS-REFUND", Amount: 50000, Status: transaction.TransactionStatusSuccessful,
+	}
+	pending := transaction.Transaction{
+		ID: "tx-data", Type: transaction.TransactionTypeDebit, Category: transaction.TransactionCategoryMobileData,
+		Reference: "VAS-PENDING", Amount: 20000, Status: transaction.TransactionStatusReversalPending,
+	}
+
+	matched, breaks := compareStatement("run-1", lines, []transaction.Transaction{debit, refund}, []transaction.Transaction{debit, refund, pending})
+	if matched != 2 {
+		t.Fatalf("expected the refunded debit and its refund to match, got %d: %+v", matched, breaks)
+	}
+	if len(breaks) != 1 || breaks[0].Type != BreakMissingProvider || *breaks[0].TransactionID != "tx-data" {
+		t.Fatalf("expected only the unreported bill payment to break, got %+v", breaks)
+	}
+}
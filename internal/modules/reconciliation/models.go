@@ -0,0 +1,46 @@
+package reconciliation
+
+import "time"
+
+// Run is one ingestion of a provider settlement file.
+type Run struct {
+	ID          string     `gorm:"column:id;type:text;primaryKey" json:"id"`
+	Provider    string     `gorm:"column:provider;type:text;not null" json:"provider"`
+	Source      string     `gorm:"column:source;type:text;not null" json:"source"`
+	Format      string     `gorm:"column:format;type:text;not null" json:"format"`
+	PeriodStart time.Time  `gorm:"column:period_start;type:timestamptz;not null;index" json:"period_start"`
+	PeriodEnd   time.Time  `gorm:"column:period_end;type:timestamptz;not null" json:"period_end"`
+	Status      RunStatus  `gorm:"column:status;type:text;not null;index" json:"status"`
+	Lines       int        `gorm:"column:lines;not null;default:0" json:"lines"`
+	Matched     int        `gorm:"column:matched;not null;default:0" json:"matched"`
+	Breaks      int        `gorm:"column:breaks;not null;default:0" json:"breaks"`
+	Error       string     `gorm:"column:error;type:text" json:"error"`
+	CompletedAt *time.Time `gorm:"column:completed_at;type:timestamptz" json:"completed_at"`
+	CreatedAt   time.Time  `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime" json:"created_at"`
+	UpdatedAt   *time.Time `gorm:"column:updated_at;type:timestamptz;autoUpdateTime" json:"updated_at"`
+}
+
+func (Run) TableName() string {
+	return "wallet_reconciliation_runs"
+}
+
+// Break is a single difference between the provider file and wallet_transactions.
+// Amounts are in kobo; a nil amount means that side has no record.
+type Break struct {
+	ID                string     `gorm:"column:id;type:text;primaryKey" json:"id"`
+	RunID             string     `gorm:"column:run_id;type:text;not null;index" json:"run_id"`
+	Type              BreakType  `gorm:"column:type;type:text;not null;index" json:"type"`
+	ProviderReference string     `gorm:"column:provider_reference;type:text;index" json:"provider_reference"`
+	SessionID         string     `gorm:"column:session_id;type:text" json:"session_id"`
+	TransactionID     *string    `gorm:"column:transaction_id;type:text;index" json:"transaction_id"`
+	ProviderAmount    *int64     `gorm:"column:provider_amount;type:bigint" json:"provider_amount"`
+	InternalAmount    *int64     `gorm:"column:internal_amount;type:bigint" json:"internal_amount"`
+	ProviderStatus    string     `gorm:"column:provider_status;type:text" json:"provider_status"`
+	InternalStatus    string     `gorm:"column:internal_status;type:text" json:"internal_status"`
+	TransactionDate   *time.Time `gorm:"column:transaction_date;type:timestamptz" json:"transaction_date"`
+	CreatedAt         time.Time  `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime" json:"created_at"`
+}
+
+func (Break) TableName() string {
+	return "wallet_reconciliation_breaks"
+}
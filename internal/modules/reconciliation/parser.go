@@ -0,0 +1,274 @@
+package reconciliation
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"neat_mobile_app_backend/internal/modules/transaction"
+)
+
+// StatementLine is one provider settlement record, normalised. Amount is in kobo.
+type StatementLine struct {
+	Reference string
+	SessionID string
+	Amount    int64
+	Type      transaction.TransactionType
+	Status    transaction.TransactionStatus
+	RawStatus string
+	Date      *time.Time
+	Narration string
+}
+
+// statementFields maps the column names seen in provider exports onto the
+// fields we reconcile on. Keys are lower-cased with separators removed.
+var statementFields = map[string]string{
+	"reference":            "reference",
+	"transactionreference": "reference",
+	"providerreference":    "reference",
+	"tranid":               "reference",
+	"settlementid":         "reference",
+	"sessionid":            "session_id",
+	"amount":               "amount",
+	"transactionamount":    "amount",
+	"settledamount":        "amount",
+	"type":                 "type",
+	"drcr":                 "type",
+	"transactiontype":      "type",
+	"status":               "status",
+	"transactionstatus":    "status",
+	"date":                 "date",
+	"transactiondate":      "date",
+	"trandatetime":         "date",
+	"valuedate":            "date",
+	"narration":            "narration",
+	"remarks":              "narration",
+}
+
+var statementDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+	"02/01/2006 15:04:05",
+	"02/01/2006",
+	"02-Jan-2006",
+}
+
+// FormatFromName picks the statement format from a file name, defaulting to CSV.
+func FormatFromName(name string) StatementFormat {
+	if strings.EqualFold(filepath.Ext(name), ".json") {
+		return StatementFormatJSON
+	}
+	return StatementFormatCSV
+}
+
+func ParseStatement(r io.Reader, format StatementFormat) ([]StatementLine, error) {
+	var (
+		rows []map[string]string
+		err  error
+	)
+
+	switch format {
+	case StatementFormatCSV:
+		rows, err = readCSVRows(r)
+	case StatementFormatJSON:
+		rows, err = readJSONRows(r)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]StatementLine, 0, len(rows))
+	for i, row := range rows {
+		line, err := lineFromFields(row)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return nil, ErrEmptyStatement
+	}
+
+	return lines, nil
+}
+
+func readCSVRows(r io.Reader) ([]map[string]string, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, ErrEmptyStatement
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read csv header: %w", err)
+	}
+
+	columns := make([]string, len(header))
+	for i, name := range header {
+		columns[i] = statementFields[normaliseFieldName(name)]
+	}
+
+	var rows []map[string]string
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read csv: %w", err)
+		}
+
+		row := make(map[string]string, len(columns))
+		for i, value := range record {
+			if i < len(columns) && columns[i] != "" {
+				row[columns[i]] = strings.TrimSpace(value)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// readJSONRows accepts either a bare array of records or an object wrapping the
+// array under "transactions", "data" or "records".
+func readJSONRows(r io.Reader) ([]map[string]string, error) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("decode json statement: %w", err)
+	}
+
+	var records []map[string]any
+	if err := json.Unmarshal(raw, &records); err != nil {
+		var wrapped map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &wrapped); err != nil {
+			return nil, fmt.Errorf("decode json statement: %w", err)
+		}
+		for _, key := range []string{"transactions", "data", "records"} {
+			if inner, ok := wrapped[key]; ok {
+				if err := json.Unmarshal(inner, &records); err != nil {
+					return nil, fmt.Errorf("decode json statement %s: %w", key, err)
+				}
+				break
+			}
+		}
+	}
+
+	rows := make([]map[string]string, 0, len(records))
+	for _, record := range records {
+		row := make(map[string]string, len(record))
+		for key, value := range record {
+			field := statementFields[normaliseFieldName(key)]
+			if field == "" || value == nil {
+				continue
+			}
+			switch v := value.(type) {
+			case string:
+				row[field] = strings.TrimSpace(v)
+			case float64:
+				row[field] = strconv.FormatFloat(v, 'f', -1, 64)
+			default:
+				row[field] = fmt.Sprint(v)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func lineFromFields(row map[string]string) (StatementLine, error) {
+	line := StatementLine{
+		Reference: row["reference"],
+		SessionID: row["session_id"],
+		Narration: row["narration"],
+		RawStatus: row["status"],
+		Status:    mapStatementStatus(row["status"]),
+		Type:      mapStatementType(row["type"]),
+	}
+	if line.Reference == "" && line.SessionID == "" {
+		return line, ErrMissingReference
+	}
+
+	amount, err := parseStatementAmount(row["amount"])
+	if err != nil {
+		return line, fmt.Errorf("%w: amount %q", ErrInvalidStatementRow, row["amount"])
+	}
+	line.Amount = amount
+
+	if raw := row["date"]; raw != "" {
+		date, err := parseStatementDate(raw)
+		if err != nil {
+			return line, fmt.Errorf("%w: date %q", ErrInvalidStatementRow, raw)
+		}
+		line.Date = &date
+	}
+
+	return line, nil
+}
+
+func normaliseFieldName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.NewReplacer("_", "", "-", "", " ", "", "/", "").Replace(name)
+}
+
+// parseStatementAmount reads a naira amount and returns kobo.
+func parseStatementAmount(raw string) (int64, error) {
+	raw = strings.ReplaceAll(strings.TrimSpace(raw), ",", "")
+	if raw == "" {
+		return 0, errors.New("empty amount")
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, err
+	}
+	return int64(math.Round(math.Abs(value) * 100)), nil
+}
+
+func parseStatementDate(raw string) (time.Time, error) {
+	for _, layout := range statementDateLayouts {
+		if t, err := time.ParseInLocation(layout, raw, time.UTC); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognised date %q", raw)
+}
+
+// mapStatementStatus treats a line without a status as settled, since that is
+// what being on a settlement file means.
+func mapStatementStatus(raw string) transaction.TransactionStatus {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "", "success", "successful", "completed", "settled":
+		return transaction.TransactionStatusSuccessful
+	case "failed", "declined":
+		return transaction.TransactionStatusFailed
+	case "reversed", "reversal":
+		return transaction.TransactionStatusReversed
+	default:
+		return transaction.TransactionStatusPending
+	}
+}
+
+func mapStatementType(raw string) transaction.TransactionType {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "credit", "cr", "c":
+		return transaction.TransactionTypeCredit
+	case "debit", "dr", "d":
+		return transaction.TransactionTypeDebit
+	default:
+		return ""
+	}
+}
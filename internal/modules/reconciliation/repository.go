@@ -0,0 +1,165 @@
+package reconciliation
+
+import (
+	"context"
+	"time"
+
+	"neat_mobile_app_backend/internal/modules/transaction"
+
+	"gorm.io/gorm"
+)
+
+// lookupChunkSize keeps IN lists well under the postgres bind-parameter limit.
+const lookupChunkSize = 1000
+
+// billCategories are the bill payments the provider debits directly and
+// refunds when fulfilment fails.
+var billCategories = []transaction.TransactionCategory{
+	transaction.TransactionCategoryAirtime,
+	transaction.TransactionCategoryMobileData,
+	transaction.TransactionCategoryTV,
+	transaction.TransactionCategoryElectricity,
+}
+
+// providerCategories are the transaction categories that move money through the
+// provider and so must appear on its settlement file.
+var providerCategories = append([]transaction.TransactionCategory{
+	transaction.TransactionCategoryTransferFrom,
+	transaction.TransactionCategoryTransferTo,
+	transaction.TransactionCategoryLoanRepayment,
+}, billCategories...)
+
+// refundedStatuses mark a debit the provider took and then refunded, or still
+// owes a refund for; the debit itself is still on the settlement file.
+var refundedStatuses = []transaction.TransactionStatus{
+	transaction.TransactionStatusReversalPending,
+	transaction.TransactionStatusReversed,
+}
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+func (r *Repository) CreateRun(ctx context.Context, run *Run) error {
+	return r.db.WithContext(ctx).Create(run).Error
+}
+
+func (r *Repository) GetRun(ctx context.Context, id string) (*Run, error) {
+	var run Run
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&run).Error; err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// CompleteRun stores the breaks and the run totals together.
+func (r *Repository) CompleteRun(ctx context.Context, runID string, lines, matched int, breaks []Break) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(breaks) > 0 {
+			if err := tx.CreateInBatches(breaks, 200).Error; err != nil {
+				return err
+			}
+		}
+
+		now := time.Now().UTC()
+		return tx.Model(&Run{}).
+			Where("id = ?", runID).
+			Updates(map[string]any{
+				"status":       RunStatusCompleted,
+				"lines":        lines,
+				"matched":      matched,
+				"breaks":       len(breaks),
+				"completed_at": now,
+			}).Error
+	})
+}
+
+func (r *Repository) FailRun(ctx context.Context, runID string, reason string) error {
+	now := time.Now().UTC()
+	return r.db.WithContext(ctx).
+		Model(&Run{}).
+		Where("id = ?", runID).
+		Updates(map[string]any{
+			"status":       RunStatusFailed,
+			"error":        reason,
+			"completed_at": now,
+		}).Error
+}
+
+// FindTransactionsByKeys loads wallet transactions whose provider reference,
+// internal reference or session id appears on the statement.
+func (r *Repository) FindTransactionsByKeys(ctx context.Context, refs, sessionIDs []string) ([]transaction.Transaction, error) {
+	seen := make(map[string]bool)
+	var out []transaction.Transaction
+
+	collect := func(keys []string, where func(db *gorm.DB, chunk []string) *gorm.DB) error {
+		for start := 0; start < len(keys); start += lookupChunkSize {
+			end := min(start+lookupChunkSize, len(keys))
+
+			var txs []transaction.Transaction
+			if err := where(r.db.WithContext(ctx), keys[start:end]).Find(&txs).Error; err != nil {
+				return err
+			}
+			for _, tx := range txs {
+				if !seen[tx.ID] {
+					seen[tx.ID] = true
+					out = append(out, tx)
+				}
+			}
+		}
+		return nil
+	}
+
+	if err := collect(refs, func(db *gorm.DB, chunk []string) *gorm.DB {
+		return db.Where("provider_reference IN ? OR reference IN ?", chunk, chunk)
+	}); err != nil {
+		return nil, err
+	}
+	if err := collect(sessionIDs, func(db *gorm.DB, chunk []string) *gorm.DB {
+		return db.Where("session_id IN ?", chunk)
+	}); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// ListSettledTransactions returns our provider-backed transactions created in
+// [from, to) that the provider settled: successful ones, bill payments it
+// debited before refunding, and reversal credits it refunded.
+func (r *Repository) ListSettledTransactions(ctx context.Context, from, to time.Time) ([]transaction.Transaction, error) {
+	var txs []transaction.Transaction
+	err := r.db.WithContext(ctx).
+		Where(r.db.
+			Where("transaction_category IN ? AND status = ?", providerCategories, transaction.TransactionStatusSuccessful).
+			Or("transaction_category IN ? AND status IN ?", billCategories, refundedStatuses).
+			Or("transaction_category = ? AND status = ? AND provider_reference <> ''",
+				transaction.TransactionCategoryReversal, transaction.TransactionStatusSuccessful)).
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Order("created_at ASC").
+		Find(&txs).Error
+	return txs, err
+}
+
+func (r *Repository) CountBreaksByType(ctx context.Context, runID string) (map[BreakType]int, error) {
+	var rows []breakCountRow
+	err := r.db.WithContext(ctx).
+		Model(&Break{}).
+		Select("type, COUNT(*) AS count").
+		Where("run_id = ?", runID).
+		Group("type").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[BreakType]int, len(rows))
+	for _, row := range rows {
+		counts[row.Type] = row.Count
+	}
+	return counts, nil
+}
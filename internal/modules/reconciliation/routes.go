@@ -0,0 +1,13 @@
+package reconciliation
+
+import "github.com/gin-gonic/gin"
+
+func RegisterInternalRoutes(rg *gin.RouterGroup, handler *Handler, internalAuth gin.HandlerFunc) {
+	reconciliation := rg.Group("/reconciliation")
+	reconciliation.Use(internalAuth)
+
+	{
+		reconciliation.POST("/statements", handler.ReconcileStatement)
+		reconciliation.GET("/runs/:id", handler.GetRun)
+	}
+}
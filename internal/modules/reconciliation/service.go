@@ -0,0 +1,100 @@
+package reconciliation
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type Service struct {
+	repo *Repository
+}
+
+func NewService(repo *Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// ReconcileStatement parses a provider settlement file, matches it against
+// wallet_transactions and records the run and its breaks.
+func (s *Service) ReconcileStatement(ctx context.Context, input StatementInput) (*RunSummary, error) {
+	lines, err := ParseStatement(input.Body, input.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	periodStart, periodEnd, ok := statementPeriod(lines)
+	if input.From != nil {
+		periodStart = input.From.UTC()
+	}
+	if input.To != nil {
+		periodEnd = input.To.UTC()
+	}
+	if (!ok && (input.From == nil || input.To == nil)) || !periodEnd.After(periodStart) {
+		return nil, ErrMissingPeriod
+	}
+
+	provider := strings.TrimSpace(input.Provider)
+	if provider == "" {
+		provider = ProviderProvidus
+	}
+
+	run := &Run{
+		ID:          uuid.NewString(),
+		Provider:    provider,
+		Source:      input.Source,
+		Format:      string(input.Format),
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+		Status:      RunStatusRunning,
+		Lines:       len(lines),
+	}
+	if err := s.repo.CreateRun(ctx, run); err != nil {
+		return nil, err
+	}
+
+	matched, breaks, err := s.compare(ctx, run, lines)
+	if err == nil {
+		err = s.repo.CompleteRun(ctx, run.ID, len(lines), matched, breaks)
+	}
+	if err != nil {
+		if failErr := s.repo.FailRun(context.WithoutCancel(ctx), run.ID, err.Error()); failErr != nil {
+			log.Printf("reconciliation: failed to mark run %s failed: %v", run.ID, failErr)
+		}
+		return nil, fmt.Errorf("reconcile statement: %w", err)
+	}
+
+	return s.GetRun(ctx, run.ID)
+}
+
+func (s *Service) compare(ctx context.Context, run *Run, lines []StatementLine) (int, []Break, error) {
+	refs, sessionIDs := statementKeys(lines)
+	candidates, err := s.repo.FindTransactionsByKeys(ctx, refs, sessionIDs)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	settled, err := s.repo.ListSettledTransactions(ctx, run.PeriodStart, run.PeriodEnd)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	matched, breaks := compareStatement(run.ID, lines, candidates, settled)
+	return matched, breaks, nil
+}
+
+func (s *Service) GetRun(ctx context.Context, id string) (*RunSummary, error) {
+	run, err := s.repo.GetRun(ctx, strings.TrimSpace(id))
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.repo.CountBreaksByType(ctx, run.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RunSummary{Run: *run, BreakCounts: counts}, nil
+}
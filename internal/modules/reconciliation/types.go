@@ -0,0 +1,42 @@
+package reconciliation
+
+import "errors"
+
+var (
+	ErrEmptyStatement      = errors.New("statement has no lines")
+	ErrUnsupportedFormat   = errors.New("unsupported statement format")
+	ErrMissingPeriod       = errors.New("statement period could not be determined")
+	ErrMissingReference    = errors.New("statement line has no reference or session id")
+	ErrInvalidStatementRow = errors.New("invalid statement row")
+)
+
+type StatementFormat string
+
+const (
+	StatementFormatCSV  StatementFormat = "csv"
+	StatementFormatJSON StatementFormat = "json"
+)
+
+type BreakType string
+
+const (
+	// BreakMissingInternal is a provider line with no wallet transaction.
+	BreakMissingInternal BreakType = "missing_internal"
+	// BreakMissingProvider is a settled wallet transaction the provider did not report.
+	BreakMissingProvider BreakType = "missing_provider"
+	// BreakDuplicateProvider is a further provider line for a wallet
+	// transaction an earlier line already matched.
+	BreakDuplicateProvider BreakType = "duplicate_provider"
+	BreakAmountMismatch    BreakType = "amount_mismatch"
+	BreakStatusMismatch    BreakType = "status_mismatch"
+)
+
+type RunStatus string
+
+const (
+	RunStatusRunning   RunStatus = "running"
+	RunStatusCompleted RunStatus = "completed"
+	RunStatusFailed    RunStatus = "failed"
+)
+
+const ProviderProvidus = "providus"
@@ -113,3 +113,182 @@ type UserTransactionResponse struct {
 	Limit        int               `json:"limit"`
 	TotalPages   int               `json:"total_pages"`
 }
+
+type ReconciliationRunsQuery struct {
+	Page  int `form:"page"`
+	Limit int `form:"limit"`
+}
+
+type reconciliationRunRow struct {
+	ID          string     `gorm:"column:id"`
+	Provider    string     `gorm:"column:provider"`
+	Source      string     `gorm:"column:source"`
+	PeriodStart time.Time  `gorm:"column:period_start"`
+	PeriodEnd   time.Time  `gorm:"column:period_end"`
+	Status      string     `gorm:"column:status"`
+	Lines       int        `gorm:"column:lines"`
+	Matched     int        `gorm:"column:matched"`
+	Breaks      int        `gorm:"column:breaks"`
+	Error       string     `gorm:"column:error"`
+	CompletedAt *time.Time `gorm:"column:completed_at"`
+	CreatedAt   time.Time  `gorm:"column:created_at"`
+}
+
+type ReconciliationRun struct {
+	ID          string     `json:"id"`
+	Provider    string     `json:"provider"`
+	Source      string     `json:"source"`
+	PeriodStart time.Time  `json:"period_start"`
+	PeriodEnd   time.Time  `json:"period_end"`
+	Status      string     `json:"status"`
+	Lines       int        `json:"lines"`
+	Matched     int        `json:"matched"`
+	Breaks      int        `json:"breaks"`
+	Error       string     `json:"error,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type ReconciliationRunsResponse struct {
+	Runs       []ReconciliationRun `json:"runs"`
+	Total      int64               `json:"total"`
+	Page       int                 `json:"page"`
+	Limit      int                 `json:"limit"`
+	TotalPages int                 `json:"total_pages"`
+}
+
+type ReconciliationBreaksQuery struct {
+	RunID string `form:"run_id"`
+	Type  string `form:"type"`
+	Page  int    `form:"page"`
+	Limit int    `form:"limit"`
+}
+
+type reconciliationBreakRow struct {
+	ID                string     `gorm:"column:id"`
+	RunID             string     `gorm:"column:run_id"`
+	Type              string     `gorm:"column:type"`
+	ProviderReference string     `gorm:"column:provider_reference"`
+	SessionID         string     `gorm:"column:session_id"`
+	TransactionID     *string    `gorm:"column:transaction_id"`
+	MobileUserID      *string    `gorm:"column:mobile_user_id"`
+	ProviderAmount    *int64     `gorm:"column:provider_amount"`
+	InternalAmount    *int64     `gorm:"column:internal_amount"`
+	ProviderStatus    string     `gorm:"column:provider_status"`
+	InternalStatus    string     `gorm:"column:internal_status"`
+	TransactionDate   *time.Time `gorm:"column:transaction_date"`
+	CreatedAt         time.Time  `gorm:"column:created_at"`
+}
+
+type ReconciliationBreak struct {
+	ID                string     `json:"id"`
+	RunID             string     `json:"run_id"`
+	Type              string     `json:"type"`
+	ProviderReference string     `json:"provider_reference,omitempty"`
+	SessionID         string     `json:"session_id,omitempty"`
+	TransactionID     *string    `json:"transaction_id,omitempty"`
+	MobileUserID      *string    `json:"mobile_user_id,omitempty"`
+	ProviderAmount    *float64   `json:"provider_amount,omitempty"`
+	InternalAmount    *float64   `json:"internal_amount,omitempty"`
+	ProviderStatus    string     `json:"provider_status,omitempty"`
+	InternalStatus    string     `json:"internal_status,omitempty"`
+	TransactionDate   *time.Time `json:"transaction_date,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+type ReconciliationBreaksResponse struct {
+	Breaks     []ReconciliationBreak `json:"breaks"`
+	Total      int64                 `json:"total"`
+	Page       int                   `json:"page"`
+	Limit      int                   `json:"limit"`
+	TotalPages int                   `json:"total_pages"`
+}
+
+type walletBalanceRow struct {
+	InternalWalletID string `gorm:"column:internal_wallet_id"`
+	MobileUserID     string `gorm:"column:mobile_user_id"`
+	WalletCustomerID string `gorm:"column:wallet_customer_id"`
+	AccountNumber    string `gorm:"column:account_number"`
+	AvailableBalance int64  `gorm:"column:available_balance"`
+	BookedBalance    int64  `gorm:"column:booked_balance"`
+}
+
+type BalanceSyncSummary struct {
+	RunID          string `json:"run_id"`
+	WalletsChecked int    `json:"wallets_checked"`
+	WalletsDrifted int    `json:"wallets_drifted"`
+	WalletsFlagged int    `json:"wallets_flagged"`
+	WalletsSkipped int    `json:"wallets_skipped"`
+	WalletsFailed  int    `json:"wallets_failed"`
+}
+
+type BalanceSyncRunsQuery struct {
+	Page  int `form:"page"`
+	Limit int `form:"limit"`
+}
+
+type BalanceSyncRunItem struct {
+	ID             string     `json:"id"`
+	Status         string     `json:"status"`
+	WalletsChecked int        `json:"wallets_checked"`
+	WalletsDrifted int        `json:"wallets_drifted"`
+	WalletsFlagged int        `json:"wallets_flagged"`
+	WalletsSkipped int        `json:"wallets_skipped"`
+	WalletsFailed  int        `json:"wallets_failed"`
+	Error          string     `json:"error,omitempty"`
+	StartedAt      time.Time  `json:"started_at"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+}
+
+type BalanceSyncRunsResponse struct {
+	Runs       []BalanceSyncRunItem `json:"runs"`
+	Total      int64                `json:"total"`
+	Page       int                  `json:"page"`
+	Limit      int                  `json:"limit"`
+	TotalPages int                  `json:"total_pages"`
+}
+
+type BalanceDriftQuery struct {
+	RunID        string `form:"run_id"`
+	FlaggedOnly  bool   `form:"flagged"`
+	ReviewStatus string `form:"review_status" binding:"omitempty,oneof=open resolved"`
+	Page         int    `form:"page"`
+	Limit        int    `form:"limit"`
+}
+
+type BalanceDriftItem struct {
+	ID                string     `json:"id"`
+	RunID             string     `json:"run_id"`
+	WalletID          string     `json:"wallet_id"`
+	MobileUserID      string     `json:"mobile_user_id"`
+	AccountNumber     string     `json:"account_number"`
+	LocalAvailable    float64    `json:"local_available"`
+	ProviderAvailable float64    `json:"provider_available"`
+	LocalBooked       float64    `json:"local_booked"`
+	ProviderBooked    float64    `json:"provider_booked"`
+	Drift             float64    `json:"drift"`
+	Flagged           bool       `json:"flagged"`
+	ReviewStatus      string     `json:"review_status,omitempty"`
+	ReviewedBy        string     `json:"reviewed_by,omitempty"`
+	ReviewNote        string     `json:"review_note,omitempty"`
+	ReviewedAt        *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+type BalanceDriftsResponse struct {
+	Drifts     []BalanceDriftItem `json:"drifts"`
+	Total      int64              `json:"total"`
+	Page       int                `json:"page"`
+	Limit      int                `json:"limit"`
+	TotalPages int                `json:"total_pages"`
+}
+
+type ResolveBalanceDriftRequest struct {
+	ReviewedBy string `json:"reviewed_by" binding:"required,max=255"`
+	Note       string `json:"note" binding:"required,max=1000"`
+}
+
+type ResolveBalanceDriftResponse struct {
+	WalletID string `json:"wallet_id"`
+	Resolved int64  `json:"resolved"`
+}
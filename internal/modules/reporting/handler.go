@@ -77,6 +77,113 @@ func (h *Handler) GetUserTransactions(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+func (h *Handler) ListReconciliationRuns(c *gin.Context) {
+	var query ReconciliationRunsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid query params"})
+		return
+	}
+
+	ctx, cancel := withTimeout(c)
+	defer cancel()
+
+	resp, err := h.service.ListReconciliationRuns(ctx, query.Page, query.Limit)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		c.AbortWithStatusJSON(http.StatusGatewayTimeout, gin.H{"error": "request timed out"})
+	case err != nil:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "something went wrong, please try again"})
+	default:
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+func (h *Handler) ListReconciliationBreaks(c *gin.Context) {
+	var query ReconciliationBreaksQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid query params"})
+		return
+	}
+
+	ctx, cancel := withTimeout(c)
+	defer cancel()
+
+	resp, err := h.service.ListReconciliationBreaks(ctx, query)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		c.AbortWithStatusJSON(http.StatusGatewayTimeout, gin.H{"error": "request timed out"})
+	case err != nil:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "something went wrong, please try again"})
+	default:
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+func (h *Handler) ListBalanceSyncRuns(c *gin.Context) {
+	var query BalanceSyncRunsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid query params"})
+		return
+	}
+
+	ctx, cancel := withTimeout(c)
+	defer cancel()
+
+	resp, err := h.service.ListBalanceSyncRuns(ctx, query.Page, query.Limit)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		c.AbortWithStatusJSON(http.StatusGatewayTimeout, gin.H{"error": "request timed out"})
+	case err != nil:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "something went wrong, please try again"})
+	default:
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+func (h *Handler) ListBalanceDrifts(c *gin.Context) {
+	var query BalanceDriftQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid query params"})
+		return
+	}
+
+	ctx, cancel := withTimeout(c)
+	defer cancel()
+
+	resp, err := h.service.ListBalanceDrifts(ctx, query)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		c.AbortWithStatusJSON(http.StatusGatewayTimeout, gin.H{"error": "request timed out"})
+	case err != nil:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "something went wrong, please try again"})
+	default:
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+func (h *Handler) ResolveBalanceDrift(c *gin.Context) {
+	var req ResolveBalanceDriftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	ctx, cancel := withTimeout(c)
+	defer cancel()
+
+	resp, err := h.service.ResolveBalanceDrift(ctx, c.Param("id"), req)
+	switch {
+	case errors.Is(err, ErrBalanceDriftNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, context.DeadlineExceeded):
+		c.AbortWithStatusJSON(http.StatusGatewayTimeout, gin.H{"error": "request timed out"})
+	case err != nil:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "something went wrong, please try again"})
+	default:
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
 func isUnprocessableEntityError(err error) bool {
 	msg := strings.TrimSpace(err.Error())
 	switch msg {
@@ -0,0 +1,11 @@
+package reporting
+
+import (
+	"context"
+	"neat_mobile_app_backend/internal/modules/auth"
+)
+
+// WalletLookup reads a wallet, balances included, from the BaaS provider.
+type WalletLookup interface {
+	LookupWalletByCustomerID(ctx context.Context, walletCustomerID string) (*auth.WalletResponse, bool, error)
+}
@@ -0,0 +1,48 @@
+package reporting
+
+import "time"
+
+// BalanceSyncRun is one pass of the provider balance sync.
+type BalanceSyncRun struct {
+	ID             string     `gorm:"column:id;type:text;primaryKey"`
+	Status         string     `gorm:"column:status;type:text;not null;index"`
+	WalletsChecked int        `gorm:"column:wallets_checked;not null;default:0"`
+	WalletsDrifted int        `gorm:"column:wallets_drifted;not null;default:0"`
+	WalletsFlagged int        `gorm:"column:wallets_flagged;not null;default:0"`
+	WalletsSkipped int        `gorm:"column:wallets_skipped;not null;default:0"`
+	WalletsFailed  int        `gorm:"column:wallets_failed;not null;default:0"`
+	Error          string     `gorm:"column:error;type:text"`
+	StartedAt      time.Time  `gorm:"column:started_at;type:timestamptz;not null"`
+	CompletedAt    *time.Time `gorm:"column:completed_at;type:timestamptz"`
+	CreatedAt      time.Time  `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime"`
+}
+
+func (BalanceSyncRun) TableName() string {
+	return "wallet_balance_sync_runs"
+}
+
+// BalanceDrift records a wallet whose local balance disagreed with the
+// provider's during a sync run. Amounts are in kobo; Drift is provider minus
+// local available balance.
+type BalanceDrift struct {
+	ID                string     `gorm:"column:id;type:text;primaryKey"`
+	RunID             string     `gorm:"column:run_id;type:text;not null;index"`
+	WalletID          string     `gorm:"column:wallet_id;type:text;not null;index"`
+	MobileUserID      string     `gorm:"column:mobile_user_id;type:text;not null"`
+	AccountNumber     string     `gorm:"column:account_number;type:text;not null"`
+	LocalAvailable    int64      `gorm:"column:local_available;type:bigint;not null"`
+	ProviderAvailable int64      `gorm:"column:provider_available;type:bigint;not null"`
+	LocalBooked       int64      `gorm:"column:local_booked;type:bigint;not null"`
+	ProviderBooked    int64      `gorm:"column:provider_booked;type:bigint;not null"`
+	Drift             int64      `gorm:"column:drift;type:bigint;not null"`
+	Flagged           bool       `gorm:"column:flagged;not null;default:false;index"`
+	ReviewStatus      string     `gorm:"column:review_status;type:text;index"`
+	ReviewedBy        string     `gorm:"column:reviewed_by;type:text"`
+	ReviewNote        string     `gorm:"column:review_note;type:text"`
+	ReviewedAt        *time.Time `gorm:"column:reviewed_at;type:timestamptz"`
+	CreatedAt         time.Time  `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime"`
+}
+
+func (BalanceDrift) TableName() string {
+	return "wallet_balance_drifts"
+}
@@ -156,3 +156,223 @@ func (r *Repository) GetTransactionsWithMobileUserID(ctx context.Context, mobile
 
 	return transactions, total, nil
 }
+
+func (r *Repository) ListReconciliationRuns(ctx context.Context, limit, offset int) ([]reconciliationRunRow, int64, error) {
+	var total int64
+	var rows []reconciliationRunRow
+
+	base := r.db.WithContext(ctx).Table("wallet_reconciliation_runs")
+
+	countStart := time.Now()
+	if err := base.Count(&total).Error; err != nil {
+		r.logQuery("ListReconciliationRuns.count", countStart, "", err)
+		return nil, 0, err
+	}
+	r.logQuery("ListReconciliationRuns.count", countStart, fmt.Sprintf("total=%d", total), nil)
+
+	if total == 0 {
+		return []reconciliationRunRow{}, 0, nil
+	}
+
+	listStart := time.Now()
+	err := base.
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&rows).Error
+	r.logQuery("ListReconciliationRuns.list", listStart, fmt.Sprintf("limit=%d offset=%d rows=%d", limit, offset, len(rows)), err)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return rows, total, nil
+}
+
+func (r *Repository) ListReconciliationBreaks(ctx context.Context, runID, breakType string, limit, offset int) ([]reconciliationBreakRow, int64, error) {
+	var total int64
+	var rows []reconciliationBreakRow
+
+	base := r.db.WithContext(ctx).
+		Table("wallet_reconciliation_breaks rb").
+		Joins("LEFT JOIN wallet_transactions wt ON wt.id = rb.transaction_id")
+	if runID != "" {
+		base = base.Where("rb.run_id = ?", runID)
+	}
+	if breakType != "" {
+		base = base.Where("rb.type = ?", breakType)
+	}
+
+	countStart := time.Now()
+	if err := base.Count(&total).Error; err != nil {
+		r.logQuery("ListReconciliationBreaks.count", countStart, "", err)
+		return nil, 0, err
+	}
+	r.logQuery("ListReconciliationBreaks.count", countStart, fmt.Sprintf("total=%d", total), nil)
+
+	if total == 0 {
+		return []reconciliationBreakRow{}, 0, nil
+	}
+
+	listStart := time.Now()
+	err := base.
+		Select(`
+			rb.id,
+			rb.run_id,
+			rb.type,
+			rb.provider_reference,
+			rb.session_id,
+			rb.transaction_id,
+			wt.mobile_user_id,
+			rb.provider_amount,
+			rb.internal_amount,
+			rb.provider_status,
+			rb.internal_status,
+			rb.transaction_date,
+			rb.created_at
+		`).
+		Order("rb.created_at DESC, rb.id").
+		Limit(limit).
+		Offset(offset).
+		Scan(&rows).Error
+	r.logQuery("ListReconciliationBreaks.list", listStart, fmt.Sprintf("limit=%d offset=%d rows=%d", limit, offset, len(rows)), err)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return rows, total, nil
+}
+
+func (r *Repository) CreateBalanceSyncRun(ctx context.Context, run *BalanceSyncRun) error {
+	return r.db.WithContext(ctx).Create(run).Error
+}
+
+func (r *Repository) SaveBalanceSyncRun(ctx context.Context, run *BalanceSyncRun) error {
+	return r.db.WithContext(ctx).Save(run).Error
+}
+
+// ListWalletsForBalanceSync pages through open wallets in wallet id order,
+// starting after afterID.
+func (r *Repository) ListWalletsForBalanceSync(ctx context.Context, afterID string, limit int) ([]walletBalanceRow, error) {
+	var rows []walletBalanceRow
+	startedAt := time.Now()
+	err := r.db.WithContext(ctx).
+		Table("wallet_customer_wallets").
+		Select("internal_wallet_id, mobile_user_id, wallet_customer_id, account_number, available_balance, booked_balance").
+		Where("internal_wallet_id > ? AND LOWER(status) <> ? AND wallet_customer_id <> ''", afterID, "closed").
+		Order("internal_wallet_id").
+		Limit(limit).
+		Scan(&rows).Error
+	r.logQuery("ListWalletsForBalanceSync", startedAt, fmt.Sprintf("after=%s rows=%d", afterID, len(rows)), err)
+	return rows, err
+}
+
+func (r *Repository) GetWalletBalance(ctx context.Context, walletID string) (*walletBalanceRow, error) {
+	var row walletBalanceRow
+	err := r.db.WithContext(ctx).
+		Table("wallet_customer_wallets").
+		Select("internal_wallet_id, mobile_user_id, wallet_customer_id, account_number, available_balance, booked_balance").
+		Where("internal_wallet_id = ?", walletID).
+		Take(&row).Error
+	if err != nil {
+		return nil, err
+	}
+	return &row, nil
+}
+
+func (r *Repository) CreateBalanceDrift(ctx context.Context, drift *BalanceDrift) error {
+	return r.db.WithContext(ctx).Create(drift).Error
+}
+
+func (r *Repository) ListBalanceSyncRuns(ctx context.Context, limit, offset int) ([]BalanceSyncRun, int64, error) {
+	var total int64
+	var rows []BalanceSyncRun
+
+	base := r.db.WithContext(ctx).Model(&BalanceSyncRun{})
+
+	countStart := time.Now()
+	if err := base.Count(&total).Error; err != nil {
+		r.logQuery("ListBalanceSyncRuns.count", countStart, "", err)
+		return nil, 0, err
+	}
+	r.logQuery("ListBalanceSyncRuns.count", countStart, fmt.Sprintf("total=%d", total), nil)
+
+	if total == 0 {
+		return []BalanceSyncRun{}, 0, nil
+	}
+
+	listStart := time.Now()
+	err := base.
+		Order("started_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&rows).Error
+	r.logQuery("ListBalanceSyncRuns.list", listStart, fmt.Sprintf("limit=%d offset=%d rows=%d", limit, offset, len(rows)), err)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return rows, total, nil
+}
+
+func (r *Repository) ListBalanceDrifts(ctx context.Context, query BalanceDriftQuery, limit, offset int) ([]BalanceDrift, int64, error) {
+	var total int64
+	var rows []BalanceDrift
+
+	base := r.db.WithContext(ctx).Model(&BalanceDrift{})
+	if query.RunID != "" {
+		base = base.Where("run_id = ?", query.RunID)
+	}
+	if query.FlaggedOnly {
+		base = base.Where("flagged = ?", true)
+	}
+	if query.ReviewStatus != "" {
+		base = base.Where("review_status = ?", query.ReviewStatus)
+	}
+
+	countStart := time.Now()
+	if err := base.Count(&total).Error; err != nil {
+		r.logQuery("ListBalanceDrifts.count", countStart, "", err)
+		return nil, 0, err
+	}
+	r.logQuery("ListBalanceDrifts.count", countStart, fmt.Sprintf("total=%d", total), nil)
+
+	if total == 0 {
+		return []BalanceDrift{}, 0, nil
+	}
+
+	listStart := time.Now()
+	err := base.
+		Order("created_at DESC, id").
+		Limit(limit).
+		Offset(offset).
+		Find(&rows).Error
+	r.logQuery("ListBalanceDrifts.list", listStart, fmt.Sprintf("limit=%d offset=%d rows=%d", limit, offset, len(rows)), err)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return rows, total, nil
+}
+
+func (r *Repository) GetBalanceDrift(ctx context.Context, id string) (*BalanceDrift, error) {
+	var drift BalanceDrift
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&drift).Error; err != nil {
+		return nil, err
+	}
+	return &drift, nil
+}
+
+// ResolveBalanceDrifts closes every open review for a wallet, since each sync
+// run flags a persisting drift again.
+func (r *Repository) ResolveBalanceDrifts(ctx context.Context, walletID, reviewedBy, note string, at time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&BalanceDrift{}).
+		Where("wallet_id = ? AND review_status = ?", walletID, DriftReviewStatusOpen).
+		Updates(map[string]interface{}{
+			"review_status": DriftReviewStatusResolved,
+			"reviewed_by":   reviewedBy,
+			"review_note":   note,
+			"reviewed_at":   at,
+		})
+	return result.RowsAffected, result.Error
+}
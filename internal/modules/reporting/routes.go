@@ -9,5 +9,10 @@ func RegisterInternalRoutes(rg *gin.RouterGroup, handler *Handler, internalAuth
 	{
 		reporting.GET("/users", handler.ListSignedUsers)
 		reporting.GET("/user/transaction", handler.GetUserTransactions)
+		reporting.GET("/reconciliation/runs", handler.ListReconciliationRuns)
+		reporting.GET("/reconciliation/breaks", handler.ListReconciliationBreaks)
+		reporting.GET("/balance-sync/runs", handler.ListBalanceSyncRuns)
+		reporting.GET("/balance-drift", handler.ListBalanceDrifts)
+		reporting.POST("/balance-drift/:id/resolve", handler.ResolveBalanceDrift)
 	}
 }
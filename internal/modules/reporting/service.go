@@ -3,8 +3,14 @@ package reporting
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log"
 	"math"
 	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 const (
@@ -14,11 +20,12 @@ const (
 )
 
 type Service struct {
-	repo *Repository
+	repo    *Repository
+	wallets WalletLookup
 }
 
-func NewService(repo *Repository) *Service {
-	return &Service{repo: repo}
+func NewService(repo *Repository, wallets WalletLookup) *Service {
+	return &Service{repo: repo, wallets: wallets}
 }
 
 func (s *Service) ListSignedUsers(ctx context.Context, page, limit int) (*ListSignedUsersResponse, error) {
@@ -133,3 +140,319 @@ func (s *Service) GetUserTransactions(ctx context.Context, mobileUserID string,
 		TotalPages:   totalPages,
 	}, nil
 }
+
+func (s *Service) ListReconciliationRuns(ctx context.Context, page, limit int) (*ReconciliationRunsResponse, error) {
+	page, limit = normalisePage(page, limit)
+	offset := (page - 1) * limit
+
+	rows, total, err := s.repo.ListReconciliationRuns(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]ReconciliationRun, 0, len(rows))
+	for _, r := range rows {
+		runs = append(runs, ReconciliationRun{
+			ID:          r.ID,
+			Provider:    r.Provider,
+			Source:      r.Source,
+			PeriodStart: r.PeriodStart,
+			PeriodEnd:   r.PeriodEnd,
+			Status:      r.Status,
+			Lines:       r.Lines,
+			Matched:     r.Matched,
+			Breaks:      r.Breaks,
+			Error:       r.Error,
+			CompletedAt: r.CompletedAt,
+			CreatedAt:   r.CreatedAt,
+		})
+	}
+
+	return &ReconciliationRunsResponse{
+		Runs:       runs,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: int(math.Ceil(float64(total) / float64(limit))),
+	}, nil
+}
+
+func (s *Service) ListReconciliationBreaks(ctx context.Context, query ReconciliationBreaksQuery) (*ReconciliationBreaksResponse, error) {
+	page, limit := normalisePage(query.Page, query.Limit)
+	offset := (page - 1) * limit
+
+	rows, total, err := s.repo.ListReconciliationBreaks(ctx, strings.TrimSpace(query.RunID), strings.TrimSpace(query.Type), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	breaks := make([]ReconciliationBreak, 0, len(rows))
+	for _, r := range rows {
+		breaks = append(breaks, ReconciliationBreak{
+			ID:                r.ID,
+			RunID:             r.RunID,
+			Type:              r.Type,
+			ProviderReference: r.ProviderReference,
+			SessionID:         r.SessionID,
+			TransactionID:     r.TransactionID,
+			MobileUserID:      r.MobileUserID,
+			ProviderAmount:    koboToNaira(r.ProviderAmount),
+			InternalAmount:    koboToNaira(r.InternalAmount),
+			ProviderStatus:    r.ProviderStatus,
+			InternalStatus:    r.InternalStatus,
+			TransactionDate:   r.TransactionDate,
+			CreatedAt:         r.CreatedAt,
+		})
+	}
+
+	return &ReconciliationBreaksResponse{
+		Breaks:     breaks,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: int(math.Ceil(float64(total) / float64(limit))),
+	}, nil
+}
+
+// SyncProviderBalances compares every open wallet's balance with the
+// provider's and records any drift. Wallets whose local balance moves while
+// the provider is being asked are skipped, since the two reads then straddle
+// a posting.
+func (s *Service) SyncProviderBalances(ctx context.Context) (*BalanceSyncSummary, error) {
+	run := &BalanceSyncRun{
+		ID:        uuid.NewString(),
+		Status:    BalanceSyncStatusRunning,
+		StartedAt: time.Now().UTC(),
+	}
+	if err := s.repo.CreateBalanceSyncRun(ctx, run); err != nil {
+		return nil, fmt.Errorf("create balance sync run: %w", err)
+	}
+
+	runErr := s.syncBalances(ctx, run)
+
+	now := time.Now().UTC()
+	run.CompletedAt = &now
+	run.Status = BalanceSyncStatusCompleted
+	if runErr != nil {
+		run.Status = BalanceSyncStatusFailed
+		run.Error = runErr.Error()
+	}
+	// the run context may have expired; the bookkeeping write must still land
+	saveCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := s.repo.SaveBalanceSyncRun(saveCtx, run); err != nil {
+		log.Printf("balance sync: failed to save run %s: %v", run.ID, err)
+	}
+
+	summary := &BalanceSyncSummary{
+		RunID:          run.ID,
+		WalletsChecked: run.WalletsChecked,
+		WalletsDrifted: run.WalletsDrifted,
+		WalletsFlagged: run.WalletsFlagged,
+		WalletsSkipped: run.WalletsSkipped,
+		WalletsFailed:  run.WalletsFailed,
+	}
+	return summary, runErr
+}
+
+func (s *Service) syncBalances(ctx context.Context, run *BalanceSyncRun) error {
+	afterID := ""
+	for {
+		wallets, err := s.repo.ListWalletsForBalanceSync(ctx, afterID, balanceSyncBatchSize)
+		if err != nil {
+			return fmt.Errorf("list wallets: %w", err)
+		}
+		if len(wallets) == 0 {
+			return nil
+		}
+
+		for i := range wallets {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			s.syncWalletBalance(ctx, run, &wallets[i])
+		}
+		afterID = wallets[len(wallets)-1].InternalWalletID
+	}
+}
+
+func (s *Service) syncWalletBalance(ctx context.Context, run *BalanceSyncRun, local *walletBalanceRow) {
+	lookupCtx, cancel := context.WithTimeout(ctx, balanceLookupTimeout)
+	defer cancel()
+
+	remote, found, err := s.wallets.LookupWalletByCustomerID(lookupCtx, local.WalletCustomerID)
+	if err != nil || !found || remote == nil || remote.Wallet == nil {
+		run.WalletsFailed++
+		if err != nil {
+			log.Printf("balance sync: provider lookup failed for wallet %s: %v", local.InternalWalletID, err)
+		} else {
+			log.Printf("balance sync: provider has no wallet for %s", local.InternalWalletID)
+		}
+		return
+	}
+	run.WalletsChecked++
+
+	after, err := s.repo.GetWalletBalance(ctx, local.InternalWalletID)
+	if err != nil {
+		run.WalletsFailed++
+		log.Printf("balance sync: failed to re-read wallet %s: %v", local.InternalWalletID, err)
+		return
+	}
+	if after.AvailableBalance != local.AvailableBalance || after.BookedBalance != local.BookedBalance {
+		run.WalletsSkipped++
+		return
+	}
+
+	drift := remote.Wallet.AvailableBalance - local.AvailableBalance
+	if drift == 0 && remote.Wallet.BookedBalance == local.BookedBalance {
+		return
+	}
+
+	record := &BalanceDrift{
+		ID:                uuid.NewString(),
+		RunID:             run.ID,
+		WalletID:          local.InternalWalletID,
+		MobileUserID:      local.MobileUserID,
+		AccountNumber:     local.AccountNumber,
+		LocalAvailable:    local.AvailableBalance,
+		ProviderAvailable: remote.Wallet.AvailableBalance,
+		LocalBooked:       local.BookedBalance,
+		ProviderBooked:    remote.Wallet.BookedBalance,
+		Drift:             drift,
+		Flagged:           driftNeedsReview(drift),
+	}
+	if record.Flagged {
+		record.ReviewStatus = DriftReviewStatusOpen
+	}
+	if err := s.repo.CreateBalanceDrift(ctx, record); err != nil {
+		run.WalletsFailed++
+		log.Printf("balance sync: failed to record drift for wallet %s: %v", local.InternalWalletID, err)
+		return
+	}
+
+	run.WalletsDrifted++
+	if record.Flagged {
+		run.WalletsFlagged++
+		log.Printf("balance sync: wallet %s flagged for review, drift=%d kobo", local.InternalWalletID, drift)
+	}
+}
+
+func (s *Service) ListBalanceSyncRuns(ctx context.Context, page, limit int) (*BalanceSyncRunsResponse, error) {
+	page, limit = normalisePage(page, limit)
+	offset := (page - 1) * limit
+
+	rows, total, err := s.repo.ListBalanceSyncRuns(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]BalanceSyncRunItem, 0, len(rows))
+	for _, r := range rows {
+		runs = append(runs, BalanceSyncRunItem{
+			ID:             r.ID,
+			Status:         r.Status,
+			WalletsChecked: r.WalletsChecked,
+			WalletsDrifted: r.WalletsDrifted,
+			WalletsFlagged: r.WalletsFlagged,
+			WalletsSkipped: r.WalletsSkipped,
+			WalletsFailed:  r.WalletsFailed,
+			Error:          r.Error,
+			StartedAt:      r.StartedAt,
+			CompletedAt:    r.CompletedAt,
+		})
+	}
+
+	return &BalanceSyncRunsResponse{
+		Runs:       runs,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: int(math.Ceil(float64(total) / float64(limit))),
+	}, nil
+}
+
+func (s *Service) ListBalanceDrifts(ctx context.Context, query BalanceDriftQuery) (*BalanceDriftsResponse, error) {
+	page, limit := normalisePage(query.Page, query.Limit)
+	offset := (page - 1) * limit
+	query.RunID = strings.TrimSpace(query.RunID)
+
+	rows, total, err := s.repo.ListBalanceDrifts(ctx, query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	drifts := make([]BalanceDriftItem, 0, len(rows))
+	for _, r := range rows {
+		drifts = append(drifts, BalanceDriftItem{
+			ID:                r.ID,
+			RunID:             r.RunID,
+			WalletID:          r.WalletID,
+			MobileUserID:      r.MobileUserID,
+			AccountNumber:     r.AccountNumber,
+			LocalAvailable:    float64(r.LocalAvailable) / 100,
+			ProviderAvailable: float64(r.ProviderAvailable) / 100,
+			LocalBooked:       float64(r.LocalBooked) / 100,
+			ProviderBooked:    float64(r.ProviderBooked) / 100,
+			Drift:             float64(r.Drift) / 100,
+			Flagged:           r.Flagged,
+			ReviewStatus:      r.ReviewStatus,
+			ReviewedBy:        r.ReviewedBy,
+			ReviewNote:        r.ReviewNote,
+			ReviewedAt:        r.ReviewedAt,
+			CreatedAt:         r.CreatedAt,
+		})
+	}
+
+	return &BalanceDriftsResponse{
+		Drifts:     drifts,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: int(math.Ceil(float64(total) / float64(limit))),
+	}, nil
+}
+
+// ResolveBalanceDrift closes the review of the drift's wallet.
+func (s *Service) ResolveBalanceDrift(ctx context.Context, id string, req ResolveBalanceDriftRequest) (*ResolveBalanceDriftResponse, error) {
+	drift, err := s.repo.GetBalanceDrift(ctx, strings.TrimSpace(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBalanceDriftNotFound
+		}
+		return nil, err
+	}
+
+	resolved, err := s.repo.ResolveBalanceDrifts(ctx, drift.WalletID, strings.TrimSpace(req.ReviewedBy), strings.TrimSpace(req.Note), time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	return &ResolveBalanceDriftResponse{WalletID: drift.WalletID, Resolved: resolved}, nil
+}
+
+func driftNeedsReview(drift int64) bool {
+	if drift < 0 {
+		drift = -drift
+	}
+	return drift > driftFlagThreshold
+}
+
+func normalisePage(page, limit int) (int, int) {
+	if page < 1 {
+		page = defaultPage
+	}
+	if limit < 1 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+	return page, limit
+}
+
+func koboToNaira(amount *int64) *float64 {
+	if amount == nil {
+		return nil
+	}
+	v := float64(*amount) / 100
+	return &v
+}
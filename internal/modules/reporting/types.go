@@ -0,0 +1,27 @@
+package reporting
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrBalanceDriftNotFound = errors.New("balance drift not found")
+
+const (
+	BalanceSyncStatusRunning   = "running"
+	BalanceSyncStatusCompleted = "completed"
+	BalanceSyncStatusFailed    = "failed"
+)
+
+const (
+	DriftReviewStatusOpen     = "open"
+	DriftReviewStatusResolved = "resolved"
+)
+
+const (
+	// driftFlagThreshold is the absolute drift, in kobo, above which a wallet
+	// is flagged for review.
+	driftFlagThreshold   int64 = 10_000
+	balanceSyncBatchSize       = 100
+	balanceLookupTimeout       = 15 * time.Second
+)
@@ -1,10 +1,55 @@
 package transaction
 
+import (
+	"neat_mobile_app_backend/internal/types"
+	"time"
+)
+
 type FetchAllTransactionsQuery struct {
+	TransactionFilterQuery
 	Limit  int    `form:"limit" binding:"required"`
 	Cursor string `form:"cursor"` // RFC3339 timestamp; empty = first page
 }
 
+// TransactionFilterQuery is shared by the list and export endpoints. Dates
+// take YYYY-MM-DD or RFC3339, a bare "to" date includes that whole day, and
+// amounts are in naira. Category and status accept comma-separated lists.
+type TransactionFilterQuery struct {
+	From         string          `form:"from"`
+	To           string          `form:"to"`
+	Type         TransactionType `form:"type" binding:"omitempty,oneof=debit credit"`
+	Category     string          `form:"category"`
+	Status       string          `form:"status"`
+	MinAmount    float64         `form:"min_amount" binding:"omitempty,gte=0"`
+	MaxAmount    float64         `form:"max_amount" binding:"omitempty,gte=0"`
+	Counterparty string          `form:"counterparty" binding:"omitempty,max=100"`
+	Search       string          `form:"q" binding:"omitempty,max=100"`
+}
+
+type ExportTransactionsQuery struct {
+	TransactionFilterQuery
+	Format ExportFormat `form:"format" binding:"omitempty,oneof=csv ndjson"`
+}
+
+// ExportedTransaction is one row of a transaction export. Amounts are in
+// naira with kobo precision.
+type ExportedTransaction struct {
+	ID                  string              `json:"id"`
+	Date                string              `json:"date"`
+	Reference           string              `json:"reference"`
+	Type                TransactionType     `json:"type"`
+	Category            TransactionCategory `json:"category"`
+	Status              TransactionStatus   `json:"status"`
+	Amount              float64             `json:"amount"`
+	Charges             float64             `json:"charges"`
+	BalanceAfter        float64             `json:"balance_after"`
+	Description         string              `json:"description"`
+	Narration           string              `json:"narration"`
+	CounterpartyName    string              `json:"counterparty_name"`
+	CounterpartyAccount string              `json:"counterparty_account"`
+	CounterpartyBank    string              `json:"counterparty_bank"`
+}
+
 type TransactionSection struct {
 	Month        string                `json:"month"` // e.g. "April 2026"
 	Transactions []TransactionResponse `json:"transactions"`
@@ -25,3 +70,106 @@ type TransactionResponse struct {
 	Status      TransactionStatus `json:"status" binding:"status"`
 	Amount      int64             `json:"amount" binding:"required"`
 }
+
+// InsightsQuery selects the month to analyse (YYYY-MM, default current) and
+// how many months of trend to return.
+type InsightsQuery struct {
+	Month  string `form:"month"`
+	Months int    `form:"months" binding:"omitempty,min=1,max=12"`
+}
+
+// InsightsResponse amounts are in naira. Change percentages are nil when the
+// previous month had nothing to compare against.
+type InsightsResponse struct {
+	Month                string                `json:"month"`
+	Inflow               float64               `json:"inflow"`
+	Outflow              float64               `json:"outflow"`
+	NetFlow              float64               `json:"net_flow"`
+	PreviousOutflow      float64               `json:"previous_outflow"`
+	OutflowChangePercent *float64              `json:"outflow_change_percent"`
+	AverageDailySpend    float64               `json:"average_daily_spend"`
+	Categories           []CategoryInsight     `json:"categories"`
+	TopCounterparties    []CounterpartyInsight `json:"top_counterparties"`
+	Trend                []MonthlyFlow         `json:"trend"`
+}
+
+type CategoryInsight struct {
+	Category          TransactionCategory `json:"category"`
+	Label             string              `json:"label"`
+	Amount            float64             `json:"amount"`
+	Count             int64               `json:"count"`
+	SharePercent      float64             `json:"share_percent"`
+	PreviousAmount    float64             `json:"previous_amount"`
+	ChangePercent     *float64            `json:"change_percent"`
+	Budget            *float64            `json:"budget,omitempty"`
+	BudgetUsedPercent *float64            `json:"budget_used_percent,omitempty"`
+}
+
+type CounterpartyInsight struct {
+	Name    string  `json:"name"`
+	Account string  `json:"account"`
+	Amount  float64 `json:"amount"`
+	Count   int64   `json:"count"`
+}
+
+type MonthlyFlow struct {
+	Month   string  `json:"month"`
+	Inflow  float64 `json:"inflow"`
+	Outflow float64 `json:"outflow"`
+}
+
+type SetBudgetRequest struct {
+	Amount float64 `json:"amount" binding:"required,gt=0"`
+}
+
+type BudgetResponse struct {
+	Category    TransactionCategory `json:"category"`
+	Label       string              `json:"label"`
+	Amount      float64             `json:"amount"`
+	Spent       float64             `json:"spent"`
+	UsedPercent float64             `json:"used_percent"`
+	Month       string              `json:"month"`
+	UpdatedAt   *time.Time          `json:"updated_at,omitempty"`
+}
+
+// TransactionDetailResponse is everything the app shows on a single
+// transaction screen. Amounts are in naira with kobo precision.
+type TransactionDetailResponse struct {
+	ID                string              `json:"id"`
+	Type              TransactionType     `json:"type"`
+	Category          TransactionCategory `json:"category"`
+	CategoryLabel     string              `json:"category_label"`
+	Source            TransactionSource   `json:"source"`
+	Status            TransactionStatus   `json:"status"`
+	Amount            float64             `json:"amount"`
+	Charges           float64             `json:"charges"`
+	VAT               float64             `json:"vat"`
+	TotalAmount       float64             `json:"total_amount"`
+	BalanceBefore     float64             `json:"balance_before"`
+	BalanceAfter      float64             `json:"balance_after"`
+	Reference         string              `json:"reference"`
+	ProviderReference string              `json:"provider_reference"`
+	SessionID         string              `json:"session_id"`
+	Description       string              `json:"description"`
+	Narration         *string             `json:"narration"`
+	Counterparty      CounterpartyDetail  `json:"counterparty"`
+	Metadata          types.JSONMap       `json:"metadata"`
+	Date              string              `json:"date"`
+	UpdatedAt         *time.Time          `json:"updated_at"`
+}
+
+type CounterpartyDetail struct {
+	Name    string `json:"name"`
+	Account string `json:"account"`
+	Bank    string `json:"bank"`
+}
+
+type ReceiptQuery struct {
+	Format ReceiptFormat `form:"format" binding:"omitempty,oneof=pdf png"`
+}
+
+type ReceiptResponse struct {
+	Format      ReceiptFormat `json:"format"`
+	DownloadURL string        `json:"download_url"`
+	ExpiresAt   time.Time     `json:"expires_at"`
+}
@@ -0,0 +1,313 @@
+package transaction
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"math"
+	appErr "neat_mobile_app_backend/internal/errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const filterDateLayout = "2006-01-02"
+
+// parseTransactionFilter validates the query and converts it to a filter.
+// Unknown categories or statuses, an inverted date range and an inverted
+// amount range are all rejected.
+func parseTransactionFilter(query TransactionFilterQuery) (TransactionFilter, error) {
+	filter := TransactionFilter{
+		Type:         query.Type,
+		MinAmount:    nairaToKobo(query.MinAmount),
+		MaxAmount:    nairaToKobo(query.MaxAmount),
+		Counterparty: strings.TrimSpace(query.Counterparty),
+		Search:       strings.TrimSpace(query.Search),
+	}
+
+	var err error
+	if filter.From, err = parseFilterTime(query.From, false); err != nil {
+		return filter, err
+	}
+	if filter.To, err = parseFilterTime(query.To, true); err != nil {
+		return filter, err
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return filter, appErr.ErrInvalidQueryParameter
+	}
+	if filter.MaxAmount > 0 && filter.MinAmount > filter.MaxAmount {
+		return filter, appErr.ErrInvalidQueryParameter
+	}
+
+	for _, value := range splitList(query.Category) {
+		category := TransactionCategory(value)
+		if _, ok := TransactionCategories[category]; !ok {
+			return filter, appErr.ErrInvalidQueryParameter
+		}
+		filter.Categories = append(filter.Categories, category)
+	}
+	for _, value := range splitList(query.Status) {
+		status := TransactionStatus(value)
+		if !validStatus(status) {
+			return filter, appErr.ErrInvalidQueryParameter
+		}
+		filter.Statuses = append(filter.Statuses, status)
+	}
+
+	return filter, nil
+}
+
+// parseFilterTime accepts RFC3339 or a bare date. A bare date used as the
+// end of a range moves to the following midnight so the whole day matches.
+func parseFilterTime(value string, end bool) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(filterDateLayout, value)
+	if err != nil {
+		return time.Time{}, appErr.ErrInvalidQueryParameter
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+func splitList(value string) []string {
+	var out []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.ToLower(strings.TrimSpace(part)); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+func validStatus(status TransactionStatus) bool {
+	switch status {
+	case TransactionStatusPending, TransactionStatusSuccessful, TransactionStatusFailed,
+		TransactionStatusReversed, TransactionStatusReversalPending:
+		return true
+	}
+	return false
+}
+
+func nairaToKobo(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// likePattern builds a substring pattern with LIKE wildcards in the input
+// escaped.
+func likePattern(value string) string {
+	value = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+	return "%" + value + "%"
+}
+
+func toExportedTransaction(t *Transaction) ExportedTransaction {
+	var narration string
+	if t.Narration != nil {
+		narration = *t.Narration
+	}
+	return ExportedTransaction{
+		ID:                  t.ID,
+		Date:                t.CreatedAt.Format(time.RFC3339),
+		Reference:           t.Reference,
+		Type:                t.Type,
+		Category:            t.Category,
+		Status:              t.Status,
+		Amount:              float64(t.Amount) / 100,
+		Charges:             float64(t.Charges) / 100,
+		BalanceAfter:        float64(t.BalanceAfter) / 100,
+		Description:         t.Description,
+		Narration:           narration,
+		CounterpartyName:    t.CounterpartyName,
+		CounterpartyAccount: t.CounterpartyAccount,
+		CounterpartyBank:    t.CounterpartyBank,
+	}
+}
+
+func toTransactionDetailResponse(t *Transaction) TransactionDetailResponse {
+	return TransactionDetailResponse{
+		ID:                t.ID,
+		Type:              t.Type,
+		Category:          t.Category,
+		CategoryLabel:     TransactionCategories[t.Category],
+		Source:            t.Source,
+		Status:            t.Status,
+		Amount:            koboToNaira(t.Amount),
+		Charges:           koboToNaira(t.Charges),
+		VAT:               koboToNaira(t.VAT),
+		TotalAmount:       koboToNaira(t.Amount + t.Charges + t.VAT),
+		BalanceBefore:     koboToNaira(t.BalanceBefore),
+		BalanceAfter:      koboToNaira(t.BalanceAfter),
+		Reference:         t.Reference,
+		ProviderReference: t.ProviderReference,
+		SessionID:         t.SessionID,
+		Description:       t.Description,
+		Narration:         t.Narration,
+		Counterparty: CounterpartyDetail{
+			Name:    t.CounterpartyName,
+			Account: t.CounterpartyAccount,
+			Bank:    t.CounterpartyBank,
+		},
+		Metadata:  t.Metadata,
+		Date:      t.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: t.UpdatedAt,
+	}
+}
+
+// receiptAvailable reports whether t has settled. Pending transactions may
+// still change, so they get no receipt.
+func receiptAvailable(t *Transaction) bool {
+	switch t.Status {
+	case TransactionStatusSuccessful, TransactionStatusFailed, TransactionStatusReversed:
+		return true
+	}
+	return false
+}
+
+type transactionEncoder interface {
+	Encode(row ExportedTransaction) error
+	Flush() error
+}
+
+func newTransactionEncoder(format ExportFormat, w io.Writer) (transactionEncoder, error) {
+	switch format {
+	case ExportFormatNDJSON:
+		return &ndjsonEncoder{enc: json.NewEncoder(w)}, nil
+	case ExportFormatCSV, "":
+		enc := &csvEncoder{w: csv.NewWriter(w)}
+		return enc, enc.w.Write(csvHeader)
+	}
+	return nil, appErr.ErrInvalidQueryParameter
+}
+
+var csvHeader = []string{
+	"id", "date", "reference", "type", "category", "status", "amount", "charges",
+	"balance_after", "description", "narration", "counterparty_name",
+	"counterparty_account", "counterparty_bank",
+}
+
+type csvEncoder struct {
+	w *csv.Writer
+}
+
+func (e *csvEncoder) Encode(row ExportedTransaction) error {
+	return e.w.Write([]string{
+		csvCell(row.ID),
+		csvCell(row.Date),
+		csvCell(row.Reference),
+		string(row.Type),
+		string(row.Category),
+		string(row.Status),
+		strconv.FormatFloat(row.Amount, 'f', 2, 64),
+		strconv.FormatFloat(row.Charges, 'f', 2, 64),
+		strconv.FormatFloat(row.BalanceAfter, 'f', 2, 64),
+		csvCell(row.Description),
+		csvCell(row.Narration),
+		csvCell(row.CounterpartyName),
+		csvCell(row.CounterpartyAccount),
+		csvCell(row.CounterpartyBank),
+	})
+}
+
+// csvCell stops a spreadsheet from reading text cells as formulas by
+// prefixing any that start with a formula character with a quote.
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func (e *csvEncoder) Flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type ndjsonEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *ndjsonEncoder) Encode(row ExportedTransaction) error {
+	return e.enc.Encode(row)
+}
+
+func (e *ndjsonEncoder) Flush() error {
+	return nil
+}
+
+// insightsMonthStart resolves a YYYY-MM month, or the current month when
+// empty, to its first instant in WAT.
+func insightsMonthStart(month string, now time.Time) (time.Time, error) {
+	month = strings.TrimSpace(month)
+	if month == "" {
+		now = now.In(insightsLocation)
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, insightsLocation), nil
+	}
+	start, err := time.ParseInLocation(insightsMonthLayout, month, insightsLocation)
+	if err != nil {
+		return time.Time{}, appErr.ErrInvalidQueryParameter
+	}
+	return start, nil
+}
+
+// daysElapsed is how many days of the month count towards the daily average:
+// all of them for a past month, today inclusive for the current one.
+func daysElapsed(monthStart, monthEnd, now time.Time) int {
+	if !now.Before(monthEnd) {
+		return int(monthEnd.Sub(monthStart).Hours()/24 + 0.5)
+	}
+	if now.Before(monthStart) {
+		return 0
+	}
+	return now.In(insightsLocation).Day()
+}
+
+// percentChange is nil when there is no previous value to compare against.
+func percentChange(previous, current int64) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	change := roundPercent(float64(current-previous) * 100 / float64(previous))
+	return &change
+}
+
+func roundPercent(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+func koboToNaira(amount int64) float64 {
+	return float64(amount) / 100
+}
+
+// budgetableCategory reports whether category is money going out, which is
+// all a budget can track.
+func budgetableCategory(category TransactionCategory) bool {
+	switch category {
+	case TransactionCategoryTransferTo, TransactionCategoryAirtime, TransactionCategoryMobileData,
+		TransactionCategoryTV, TransactionCategoryElectricity, TransactionCategoryCardPayment,
+		TransactionCategoryLoanRepayment:
+		return true
+	}
+	return false
+}
+
+// budgetAlertLevel returns the highest alert threshold spent has reached, or
+// zero when it is below all of them.
+func budgetAlertLevel(spent, budget int64) int {
+	if budget <= 0 {
+		return 0
+	}
+	level := 0
+	for _, percent := range budgetAlertPercents {
+		if spent*100 >= budget*int64(percent) {
+			level = percent
+		}
+	}
+	return level
+}
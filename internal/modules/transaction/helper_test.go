@@ -0,0 +1,194 @@
+package transaction
+
+import (
+	"errors"
+	appErr "neat_mobile_app_backend/internal/errors"
+	"testing"
+	"time"
+)
+
+func TestParseTransactionFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   TransactionFilterQuery
+		want    TransactionFilter
+		wantErr error
+	}{
+		{
+			name:  "empty",
+			query: TransactionFilterQuery{},
+			want:  TransactionFilter{},
+		},
+		{
+			name: "bare dates cover the whole end day",
+			query: TransactionFilterQuery{
+				From: "2026-04-01",
+				To:   "2026-04-30",
+			},
+			want: TransactionFilter{
+				From: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+				To:   time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "lists and amounts",
+			query: TransactionFilterQuery{
+				Category:  "airtime, Mobile_Data",
+				Status:    "successful",
+				MinAmount: 100.5,
+				MaxAmount: 2000,
+			},
+			want: TransactionFilter{
+				Categories: []TransactionCategory{TransactionCategoryAirtime, TransactionCategoryMobileData},
+				Statuses:   []TransactionStatus{TransactionStatusSuccessful},
+				MinAmount:  10050,
+				MaxAmount:  200000,
+			},
+		},
+		{
+			name:    "unknown category",
+			query:   TransactionFilterQuery{Category: "gifts"},
+			wantErr: appErr.ErrInvalidQueryParameter,
+		},
+		{
+			name:    "inverted dates",
+			query:   TransactionFilterQuery{From: "2026-05-01", To: "2026-04-01"},
+			wantErr: appErr.ErrInvalidQueryParameter,
+		},
+		{
+			name:    "inverted amounts",
+			query:   TransactionFilterQuery{MinAmount: 50, MaxAmount: 10},
+			wantErr: appErr.ErrInvalidQueryParameter,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseTransactionFilter(tc.query)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tc.wantErr)
+			}
+			if tc.wantErr != nil {
+				return
+			}
+			if !got.From.Equal(tc.want.From) || !got.To.Equal(tc.want.To) ||
+				got.MinAmount != tc.want.MinAmount || got.MaxAmount != tc.want.MaxAmount ||
+				len(got.Categories) != len(tc.want.Categories) || len(got.Statuses) != len(tc.want.Statuses) {
+				t.Fatalf("filter = %+v, want %+v", got, tc.want)
+			}
+			for i := range got.Categories {
+				if got.Categories[i] != tc.want.Categories[i] {
+					t.Fatalf("categories = %v, want %v", got.Categories, tc.want.Categories)
+				}
+			}
+		})
+	}
+}
+
+func TestLikePattern(t *testing.T) {
+	if got := likePattern("50%_off"); got != `%50\%\_off%` {
+		t.Fatalf("likePattern() = %q", got)
+	}
+}
+
+func TestCSVCellEscapesFormulas(t *testing.T) {
+	tests := map[string]string{
+		"=HYPERLINK(\"x\")": "'=HYPERLINK(\"x\")",
+		"+2348012345678":    "'+2348012345678",
+		"-1+1":              "'-1+1",
+		"@SUM(A1)":          "'@SUM(A1)",
+		"Transfer to Ada":   "Transfer to Ada",
+		"":                  "",
+	}
+	for value, want := range tests {
+		if got := csvCell(value); got != want {
+			t.Fatalf("csvCell(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestBudgetAlertLevel(t *testing.T) {
+	tests := []struct {
+		spent, budget int64
+		want          int
+	}{
+		{spent: 0, budget: 10000, want: 0},
+		{spent: 7999, budget: 10000, want: 0},
+		{spent: 8000, budget: 10000, want: 80},
+		{spent: 10000, budget: 10000, want: 100},
+		{spent: 25000, budget: 10000, want: 100},
+		{spent: 5000, budget: 0, want: 0},
+	}
+	for _, tc := range tests {
+		if got := budgetAlertLevel(tc.spent, tc.budget); got != tc.want {
+			t.Fatalf("budgetAlertLevel(%d, %d) = %d, want %d", tc.spent, tc.budget, got, tc.want)
+		}
+	}
+}
+
+func TestPercentChange(t *testing.T) {
+	if got := percentChange(0, 500); got != nil {
+		t.Fatalf("percentChange(0, 500) = %v, want nil", *got)
+	}
+	if got := percentChange(200, 300); got == nil || *got != 50 {
+		t.Fatalf("percentChange(200, 300) = %v, want 50", got)
+	}
+	if got := percentChange(300, 100); got == nil || *got != -66.67 {
+		t.Fatalf("percentChange(300, 100) = %v, want -66.67", got)
+	}
+}
+
+func TestInsightsMonthStartAndDaysElapsed(t *testing.T) {
+	now := time.Date(2026, 4, 10, 23, 30, 0, 0, time.UTC) // 11 April in WAT
+
+	start, err := insightsMonthStart("", now)
+	if err != nil {
+		t.Fatalf("insightsMonthStart() err = %v", err)
+	}
+	if want := time.Date(2026, 4, 1, 0, 0, 0, 0, insightsLocation); !start.Equal(want) {
+		t.Fatalf("start = %v, want %v", start, want)
+	}
+	if got := daysElapsed(start, start.AddDate(0, 1, 0), now); got != 11 {
+		t.Fatalf("daysElapsed(current) = %d, want 11", got)
+	}
+
+	feb, err := insightsMonthStart("2026-02", now)
+	if err != nil {
+		t.Fatalf("insightsMonthStart(2026-02) err = %v", err)
+	}
+	if got := daysElapsed(feb, feb.AddDate(0, 1, 0), now); got != 28 {
+		t.Fatalf("daysElapsed(past) = %d, want 28", got)
+	}
+
+	if _, err := insightsMonthStart("April", now); !errors.Is(err, appErr.ErrInvalidQueryParameter) {
+		t.Fatalf("insightsMonthStart(April) err = %v", err)
+	}
+}
+
+func TestReceiptAvailable(t *testing.T) {
+	for status, want := range map[TransactionStatus]bool{
+		TransactionStatusSuccessful:      true,
+		TransactionStatusFailed:          true,
+		TransactionStatusReversed:        true,
+		TransactionStatusPending:         false,
+		TransactionStatusReversalPending: false,
+	} {
+		if got := receiptAvailable(&Transaction{Status: status}); got != want {
+			t.Fatalf("receiptAvailable(%s) = %v, want %v", status, got, want)
+		}
+	}
+}
+
+func TestToTransactionDetailResponse(t *testing.T) {
+	got := toTransactionDetailResponse(&Transaction{
+		ID:       "tx-1",
+		Category: TransactionCategoryTransferTo,
+		Amount:   500000,
+		Charges:  1000,
+		VAT:      75,
+	})
+	if got.TotalAmount != 5010.75 || got.CategoryLabel != "Transfer To" {
+		t.Fatalf("detail = %+v", got)
+	}
+}
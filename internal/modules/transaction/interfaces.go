@@ -0,0 +1,12 @@
+package transaction
+
+import (
+	"context"
+	"time"
+)
+
+// ReceiptGenerator renders a transaction receipt, stores it and returns a
+// time-limited download URL.
+type ReceiptGenerator interface {
+	GenerateTransactionReceipt(ctx context.Context, mobileUserID string, txn *Transaction, format ReceiptFormat) (string, time.Time, error)
+}
@@ -34,3 +34,21 @@ type Transaction struct {
 func (Transaction) TableName() string {
 	return "wallet_transactions"
 }
+
+// CategoryBudget is a user's monthly spending cap for one category. The
+// alerted fields record the highest threshold already announced this month so
+// each one is only pushed once.
+type CategoryBudget struct {
+	ID             string              `gorm:"column:id;type:text;primaryKey"`
+	MobileUserID   string              `gorm:"column:mobile_user_id;type:text;not null;uniqueIndex:idx_budget_user_category"`
+	Category       TransactionCategory `gorm:"column:category;type:text;not null;uniqueIndex:idx_budget_user_category"`
+	Amount         int64               `gorm:"column:amount;type:bigint;not null"`
+	AlertedMonth   string              `gorm:"column:alerted_month;type:text"`
+	AlertedPercent int                 `gorm:"column:alerted_percent;not null;default:0"`
+	CreatedAt      time.Time           `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt      *time.Time          `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (CategoryBudget) TableName() string {
+	return "wallet_category_budgets"
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Repository struct {
@@ -35,10 +36,21 @@ func (r *Repository) FetchRecentTransactions(ctx context.Context, userID, wallet
 	return transactions, err
 }
 
-func (r *Repository) FetchTransactionPaged(ctx context.Context, userID, walletID string, cursor time.Time, limit int) ([]Transaction, error) {
+func (r *Repository) FetchTransactionByID(ctx context.Context, userID, txID string) (*Transaction, error) {
+	var txn Transaction
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND mobile_user_id = ?", txID, userID).
+		First(&txn).Error
+	if err != nil {
+		return nil, err
+	}
+	return &txn, nil
+}
+
+func (r *Repository) FetchTransactionPaged(ctx context.Context, userID, walletID string, filter TransactionFilter, cursor time.Time, limit int) ([]Transaction, error) {
 	var txs []Transaction
-	q := r.db.WithContext(ctx).
-		Where("mobile_user_id = ? AND wallet_id = ?", userID, walletID)
+	q := applyTransactionFilter(r.db.WithContext(ctx).
+		Where("mobile_user_id = ? AND wallet_id = ?", userID, walletID), filter)
 
 	if !cursor.IsZero() {
 		q = q.Where("created_at < ?", cursor)
@@ -49,6 +61,64 @@ func (r *Repository) FetchTransactionPaged(ctx context.Context, userID, walletID
 	return txs, err
 }
 
+// StreamTransactions walks the filtered history newest first, handing each row
+// to fn without loading the whole set.
+func (r *Repository) StreamTransactions(ctx context.Context, userID, walletID string, filter TransactionFilter, fn func(*Transaction) error) error {
+	db := r.db.WithContext(ctx)
+	rows, err := applyTransactionFilter(db.Model(&Transaction{}).
+		Where("mobile_user_id = ? AND wallet_id = ?", userID, walletID), filter).
+		Order("created_at DESC").
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t Transaction
+		if err := db.ScanRows(rows, &t); err != nil {
+			return err
+		}
+		if err := fn(&t); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func applyTransactionFilter(q *gorm.DB, filter TransactionFilter) *gorm.DB {
+	if !filter.From.IsZero() {
+		q = q.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		q = q.Where("created_at < ?", filter.To)
+	}
+	if filter.Type != "" {
+		q = q.Where("type = ?", filter.Type)
+	}
+	if len(filter.Categories) > 0 {
+		q = q.Where("transaction_category IN ?", filter.Categories)
+	}
+	if len(filter.Statuses) > 0 {
+		q = q.Where("status IN ?", filter.Statuses)
+	}
+	if filter.MinAmount > 0 {
+		q = q.Where("amount >= ?", filter.MinAmount)
+	}
+	if filter.MaxAmount > 0 {
+		q = q.Where("amount <= ?", filter.MaxAmount)
+	}
+	if filter.Counterparty != "" {
+		pattern := likePattern(filter.Counterparty)
+		q = q.Where("(counterparty_name ILIKE ? OR counterparty_account ILIKE ?)", pattern, pattern)
+	}
+	if filter.Search != "" {
+		pattern := likePattern(filter.Search)
+		q = q.Where("(narration ILIKE ? OR description ILIKE ? OR reference ILIKE ?)", pattern, pattern, pattern)
+	}
+	return q
+}
+
 func (r *Repository) AddTransaction(ctx context.Context, transaction *Transaction) error {
 	return r.db.WithContext(ctx).Create(transaction).Error
 }
@@ -59,3 +129,135 @@ func (r *Repository) UpdateTransactionStatus(ctx context.Context, txID string, b
 		"balance_after": balanceAfter,
 	}).Error
 }
+
+type monthlyFlowRow struct {
+	Month   string `gorm:"column:month"`
+	Inflow  int64  `gorm:"column:inflow"`
+	Outflow int64  `gorm:"column:outflow"`
+}
+
+// SumFlowsByMonth totals successful credits and debits per WAT calendar month
+// in [from, to).
+func (r *Repository) SumFlowsByMonth(ctx context.Context, userID, walletID string, from, to time.Time) ([]monthlyFlowRow, error) {
+	var rows []monthlyFlowRow
+	err := r.db.WithContext(ctx).
+		Model(&Transaction{}).
+		Select("to_char(created_at AT TIME ZONE 'Africa/Lagos', 'YYYY-MM') AS month, "+
+			"COALESCE(SUM(amount) FILTER (WHERE type = ?), 0) AS inflow, "+
+			"COALESCE(SUM(amount) FILTER (WHERE type = ?), 0) AS outflow",
+			TransactionTypeCredit, TransactionTypeDebit).
+		Where("mobile_user_id = ? AND wallet_id = ? AND status = ? AND created_at >= ? AND created_at < ?",
+			userID, walletID, TransactionStatusSuccessful, from, to).
+		Group("month").
+		Scan(&rows).Error
+	return rows, err
+}
+
+type categorySpendRow struct {
+	Category TransactionCategory `gorm:"column:category"`
+	Amount   int64               `gorm:"column:amount"`
+	Count    int64               `gorm:"column:count"`
+}
+
+func (r *Repository) SumDebitsByCategory(ctx context.Context, userID, walletID string, from, to time.Time) ([]categorySpendRow, error) {
+	var rows []categorySpendRow
+	err := r.db.WithContext(ctx).
+		Model(&Transaction{}).
+		Select("transaction_category AS category, COALESCE(SUM(amount), 0) AS amount, COUNT(*) AS count").
+		Where("mobile_user_id = ? AND wallet_id = ? AND type = ? AND status = ? AND created_at >= ? AND created_at < ?",
+			userID, walletID, TransactionTypeDebit, TransactionStatusSuccessful, from, to).
+		Group("transaction_category").
+		Order("amount DESC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+type counterpartyRow struct {
+	Name    string `gorm:"column:name"`
+	Account string `gorm:"column:account"`
+	Amount  int64  `gorm:"column:amount"`
+	Count   int64  `gorm:"column:count"`
+}
+
+// TopCounterparties returns who the user paid most in [from, to). Debits with
+// no named counterparty, such as airtime, are left out.
+func (r *Repository) TopCounterparties(ctx context.Context, userID, walletID string, from, to time.Time, limit int) ([]counterpartyRow, error) {
+	var rows []counterpartyRow
+	err := r.db.WithContext(ctx).
+		Model(&Transaction{}).
+		Select("counterparty_name AS name, counterparty_account AS account, SUM(amount) AS amount, COUNT(*) AS count").
+		Where("mobile_user_id = ? AND wallet_id = ? AND type = ? AND status = ? AND created_at >= ? AND created_at < ? AND counterparty_name <> ''",
+			userID, walletID, TransactionTypeDebit, TransactionStatusSuccessful, from, to).
+		Group("counterparty_name, counterparty_account").
+		Order("amount DESC").
+		Limit(limit).
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *Repository) ListBudgets(ctx context.Context, userID string) ([]CategoryBudget, error) {
+	var budgets []CategoryBudget
+	err := r.db.WithContext(ctx).
+		Where("mobile_user_id = ?", userID).
+		Order("category ASC").
+		Find(&budgets).Error
+	return budgets, err
+}
+
+// UpsertBudget sets the budget for a category. Changing the amount clears the
+// alert state so the new thresholds are announced afresh.
+func (r *Repository) UpsertBudget(ctx context.Context, budget *CategoryBudget) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "mobile_user_id"}, {Name: "category"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"amount":          budget.Amount,
+				"alerted_month":   "",
+				"alerted_percent": 0,
+				"updated_at":      time.Now().UTC(),
+			}),
+		}).
+		Create(budget).Error
+}
+
+func (r *Repository) DeleteBudget(ctx context.Context, userID string, category TransactionCategory) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("mobile_user_id = ? AND category = ?", userID, category).
+		Delete(&CategoryBudget{})
+	return result.RowsAffected > 0, result.Error
+}
+
+type budgetSpendRow struct {
+	CategoryBudget
+	Spent int64 `gorm:"column:spent"`
+}
+
+// ListBudgetSpend returns budgets with the user's successful spend in each
+// category since monthStart. An empty userID covers every user.
+func (r *Repository) ListBudgetSpend(ctx context.Context, userID string, monthStart time.Time) ([]budgetSpendRow, error) {
+	q := r.db.WithContext(ctx).
+		Table("wallet_category_budgets AS b").
+		Select("b.*, COALESCE(SUM(t.amount), 0) AS spent").
+		Joins("LEFT JOIN wallet_transactions t ON t.mobile_user_id = b.mobile_user_id "+
+			"AND t.transaction_category = b.category AND t.type = ? AND t.status = ? AND t.created_at >= ?",
+			TransactionTypeDebit, TransactionStatusSuccessful, monthStart).
+		Group("b.id").
+		Order("b.category ASC")
+	if userID != "" {
+		q = q.Where("b.mobile_user_id = ?", userID)
+	}
+
+	var rows []budgetSpendRow
+	err := q.Scan(&rows).Error
+	return rows, err
+}
+
+func (r *Repository) MarkBudgetAlerted(ctx context.Context, id, month string, percent int) error {
+	return r.db.WithContext(ctx).
+		Model(&CategoryBudget{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"alerted_month":   month,
+			"alerted_percent": percent,
+		}).Error
+}
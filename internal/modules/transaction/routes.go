@@ -7,5 +7,12 @@ func RegisterRoutes(rg *gin.RouterGroup, handler *Handler, authGuard, deviceVali
 	{
 		tx.GET("/recent", handler.FetchRecentTransactions)
 		tx.GET("/all", handler.FetchAllTransactions)
+		tx.GET("/export", handler.ExportTransactions)
+		tx.GET("/insights", handler.GetInsights)
+		tx.GET("/budgets", handler.ListBudgets)
+		tx.PUT("/budgets/:category", handler.SetBudget)
+		tx.DELETE("/budgets/:category", handler.DeleteBudget)
+		tx.GET("/:id", handler.GetTransaction)
+		tx.GET("/:id/receipt", handler.GenerateReceipt)
 	}
 }
@@ -3,18 +3,27 @@ package transaction
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"log"
 	appErr "neat_mobile_app_backend/internal/errors"
+	"neat_mobile_app_backend/internal/modules/notification"
+	"net/http"
+	"sort"
 	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type Service struct {
-	repo *Repository
+	repo     *Repository
+	notifier *notification.Service
+	receipts ReceiptGenerator
 }
 
-func NewServie(repo *Repository) *Service {
-	return &Service{repo: repo}
+func NewServie(repo *Repository, notifier *notification.Service, receipts ReceiptGenerator) *Service {
+	return &Service{repo: repo, notifier: notifier, receipts: receipts}
 }
 
 func (s *Service) FetchRecentTransactions(ctx context.Context, mobileUserID string) ([]TransactionResponse, error) {
@@ -52,11 +61,16 @@ func (s *Service) FetchRecentTransactions(ctx context.Context, mobileUserID stri
 	return result, nil
 }
 
-func (s *Service) FetchTransactionsPaged(ctx context.Context, userID, cursor string, limit int) (*PagedTransactionResponse, error) {
+func (s *Service) FetchTransactionsPaged(ctx context.Context, userID, cursor string, limit int, query TransactionFilterQuery) (*PagedTransactionResponse, error) {
 	if limit < 0 || limit > 50 {
 		limit = 20
 	}
 
+	filter, err := parseTransactionFilter(query)
+	if err != nil {
+		return nil, err
+	}
+
 	user, err := s.repo.FetchUserWithUserID(ctx, userID)
 
 	if err != nil {
@@ -74,7 +88,7 @@ func (s *Service) FetchTransactionsPaged(ctx context.Context, userID, cursor str
 		}
 	}
 
-	txs, err := s.repo.FetchTransactionPaged(ctx, userID, user.WalletID, cursorTime, limit)
+	txs, err := s.repo.FetchTransactionPaged(ctx, userID, user.WalletID, filter, cursorTime, limit)
 	if err != nil {
 		return nil, appErr.ErrFetchingTransactions
 	}
@@ -96,6 +110,325 @@ func (s *Service) FetchTransactionsPaged(ctx context.Context, userID, cursor str
 	}, nil
 }
 
+func (s *Service) GetTransaction(ctx context.Context, userID, txID string) (*TransactionDetailResponse, error) {
+	txn, err := s.fetchTransaction(ctx, userID, txID)
+	if err != nil {
+		return nil, err
+	}
+	resp := toTransactionDetailResponse(txn)
+	return &resp, nil
+}
+
+// GenerateReceipt renders a receipt for one of the user's settled
+// transactions and returns a short-lived link to it.
+func (s *Service) GenerateReceipt(ctx context.Context, userID, txID string, format ReceiptFormat) (*ReceiptResponse, error) {
+	if format == "" {
+		format = ReceiptFormatPDF
+	}
+	if s.receipts == nil {
+		return nil, appErr.ErrGeneratingReceipt
+	}
+
+	txn, err := s.fetchTransaction(ctx, userID, txID)
+	if err != nil {
+		return nil, err
+	}
+	if !receiptAvailable(txn) {
+		return nil, appErr.ErrReceiptUnavailable
+	}
+
+	url, expiresAt, err := s.receipts.GenerateTransactionReceipt(ctx, userID, txn, format)
+	if err != nil {
+		log.Printf("transaction service: failed to generate receipt for %s: %v", txn.ID, err)
+		return nil, appErr.ErrGeneratingReceipt
+	}
+
+	return &ReceiptResponse{
+		Format:      format,
+		DownloadURL: url,
+		ExpiresAt:   expiresAt,
+	}, nil
+}
+
+func (s *Service) fetchTransaction(ctx context.Context, userID, txID string) (*Transaction, error) {
+	txn, err := s.repo.FetchTransactionByID(ctx, userID, txID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErr.ErrTransactionNotFound
+		}
+		log.Printf("transaction service: failed to fetch transaction %s: %v", txID, err)
+		return nil, appErr.ErrFetchingTransactions
+	}
+	return txn, nil
+}
+
+// ExportTransactions writes the user's filtered history to w in the requested
+// format, flushing as it goes. Errors returned before anything is written can
+// still be reported to the client; later ones only cut the stream short.
+func (s *Service) ExportTransactions(ctx context.Context, userID string, query ExportTransactionsQuery, w io.Writer) error {
+	filter, err := parseTransactionFilter(query.TransactionFilterQuery)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.repo.FetchUserWithUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return appErr.ErrUnauthorized
+		}
+		return appErr.ErrFetchingTransactions
+	}
+
+	enc, err := newTransactionEncoder(query.Format, w)
+	if err != nil {
+		return err
+	}
+
+	flusher, _ := w.(http.Flusher)
+	count := 0
+	err = s.repo.StreamTransactions(ctx, userID, user.WalletID, filter, func(t *Transaction) error {
+		if err := enc.Encode(toExportedTransaction(t)); err != nil {
+			return err
+		}
+		count++
+		if count%exportFlushEvery == 0 {
+			if err := enc.Flush(); err != nil {
+				return err
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("transaction service: export stopped after %d rows: %v", count, err)
+		return appErr.ErrFetchingTransactions
+	}
+	return enc.Flush()
+}
+
+// GetInsights summarises the user's successful wallet activity for one WAT
+// calendar month against the month before it, with a trend of the months
+// leading up to it.
+func (s *Service) GetInsights(ctx context.Context, userID string, query InsightsQuery) (*InsightsResponse, error) {
+	now := time.Now()
+	monthStart, err := insightsMonthStart(query.Month, now)
+	if err != nil {
+		return nil, err
+	}
+	months := query.Months
+	if months <= 0 {
+		months = defaultInsightsMonths
+	}
+	if months > maxInsightsMonths {
+		months = maxInsightsMonths
+	}
+
+	user, err := s.repo.FetchUserWithUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErr.ErrUnauthorized
+		}
+		return nil, appErr.ErrFetchingInsights
+	}
+
+	monthEnd := monthStart.AddDate(0, 1, 0)
+	prevStart := monthStart.AddDate(0, -1, 0)
+	trendStart := monthStart.AddDate(0, -(months - 1), 0)
+	flowsFrom := trendStart
+	if prevStart.Before(flowsFrom) {
+		flowsFrom = prevStart
+	}
+
+	flows, err := s.repo.SumFlowsByMonth(ctx, userID, user.WalletID, flowsFrom, monthEnd)
+	if err != nil {
+		log.Printf("transaction service: failed to sum monthly flows for user %s: %v", userID, err)
+		return nil, appErr.ErrFetchingInsights
+	}
+	current, err := s.repo.SumDebitsByCategory(ctx, userID, user.WalletID, monthStart, monthEnd)
+	if err != nil {
+		log.Printf("transaction service: failed to sum category spend for user %s: %v", userID, err)
+		return nil, appErr.ErrFetchingInsights
+	}
+	previous, err := s.repo.SumDebitsByCategory(ctx, userID, user.WalletID, prevStart, monthStart)
+	if err != nil {
+		log.Printf("transaction service: failed to sum category spend for user %s: %v", userID, err)
+		return nil, appErr.ErrFetchingInsights
+	}
+	counterparties, err := s.repo.TopCounterparties(ctx, userID, user.WalletID, monthStart, monthEnd, topCounterpartyLimit)
+	if err != nil {
+		log.Printf("transaction service: failed to fetch top counterparties for user %s: %v", userID, err)
+		return nil, appErr.ErrFetchingInsights
+	}
+	budgets, err := s.repo.ListBudgets(ctx, userID)
+	if err != nil {
+		log.Printf("transaction service: failed to list budgets for user %s: %v", userID, err)
+		return nil, appErr.ErrFetchingInsights
+	}
+
+	byMonth := make(map[string]monthlyFlowRow, len(flows))
+	for _, row := range flows {
+		byMonth[row.Month] = row
+	}
+	month := monthStart.Format(insightsMonthLayout)
+	thisMonth := byMonth[month]
+	lastMonth := byMonth[prevStart.Format(insightsMonthLayout)]
+
+	resp := &InsightsResponse{
+		Month:                month,
+		Inflow:               koboToNaira(thisMonth.Inflow),
+		Outflow:              koboToNaira(thisMonth.Outflow),
+		NetFlow:              koboToNaira(thisMonth.Inflow - thisMonth.Outflow),
+		PreviousOutflow:      koboToNaira(lastMonth.Outflow),
+		OutflowChangePercent: percentChange(lastMonth.Outflow, thisMonth.Outflow),
+		Categories:           buildCategoryInsights(current, previous, budgets, thisMonth.Outflow),
+		TopCounterparties:    make([]CounterpartyInsight, len(counterparties)),
+		Trend:                make([]MonthlyFlow, 0, months),
+	}
+	if days := daysElapsed(monthStart, monthEnd, now); days > 0 {
+		resp.AverageDailySpend = roundPercent(koboToNaira(thisMonth.Outflow) / float64(days))
+	}
+	for i, row := range counterparties {
+		resp.TopCounterparties[i] = CounterpartyInsight{
+			Name:    row.Name,
+			Account: row.Account,
+			Amount:  koboToNaira(row.Amount),
+			Count:   row.Count,
+		}
+	}
+	for m := trendStart; m.Before(monthEnd); m = m.AddDate(0, 1, 0) {
+		row := byMonth[m.Format(insightsMonthLayout)]
+		resp.Trend = append(resp.Trend, MonthlyFlow{
+			Month:   m.Format(insightsMonthLayout),
+			Inflow:  koboToNaira(row.Inflow),
+			Outflow: koboToNaira(row.Outflow),
+		})
+	}
+
+	return resp, nil
+}
+
+// ListBudgets returns the user's budgets with what has been spent against
+// each so far this month.
+func (s *Service) ListBudgets(ctx context.Context, userID string) ([]BudgetResponse, error) {
+	monthStart, _ := insightsMonthStart("", time.Now())
+	rows, err := s.repo.ListBudgetSpend(ctx, userID, monthStart)
+	if err != nil {
+		log.Printf("transaction service: failed to list budgets for user %s: %v", userID, err)
+		return nil, appErr.ErrFetchingInsights
+	}
+
+	result := make([]BudgetResponse, len(rows))
+	for i := range rows {
+		result[i] = toBudgetResponse(&rows[i].CategoryBudget, rows[i].Spent, monthStart)
+	}
+	return result, nil
+}
+
+// SetBudget creates or replaces the user's monthly budget for a spending
+// category. A threshold the current spend has already crossed is announced
+// on the next alert sweep.
+func (s *Service) SetBudget(ctx context.Context, userID string, category TransactionCategory, req SetBudgetRequest) (*BudgetResponse, error) {
+	if !budgetableCategory(category) {
+		return nil, appErr.ErrInvalidBudgetCategory
+	}
+	amount := nairaToKobo(req.Amount)
+	if amount <= 0 {
+		return nil, appErr.ErrInvalidRequestBody
+	}
+
+	budget := &CategoryBudget{
+		ID:           uuid.NewString(),
+		MobileUserID: userID,
+		Category:     category,
+		Amount:       amount,
+	}
+	if err := s.repo.UpsertBudget(ctx, budget); err != nil {
+		log.Printf("transaction service: failed to save %s budget for user %s: %v", category, userID, err)
+		return nil, appErr.ErrSavingBudget
+	}
+
+	budgets, err := s.ListBudgets(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range budgets {
+		if budgets[i].Category == category {
+			return &budgets[i], nil
+		}
+	}
+	return nil, appErr.ErrSavingBudget
+}
+
+func (s *Service) DeleteBudget(ctx context.Context, userID string, category TransactionCategory) error {
+	deleted, err := s.repo.DeleteBudget(ctx, userID, category)
+	if err != nil {
+		log.Printf("transaction service: failed to delete %s budget for user %s: %v", category, userID, err)
+		return appErr.ErrSavingBudget
+	}
+	if !deleted {
+		return appErr.ErrBudgetNotFound
+	}
+	return nil
+}
+
+// ProcessBudgetAlerts pushes a notification for every budget whose spend has
+// reached a new alert threshold this month and returns how many were sent.
+func (s *Service) ProcessBudgetAlerts(ctx context.Context) (int, error) {
+	monthStart, _ := insightsMonthStart("", time.Now())
+	month := monthStart.Format(insightsMonthLayout)
+
+	rows, err := s.repo.ListBudgetSpend(ctx, "", monthStart)
+	if err != nil {
+		return 0, fmt.Errorf("list budget spend: %w", err)
+	}
+
+	sent := 0
+	for i := range rows {
+		budget := &rows[i].CategoryBudget
+		alerted := 0
+		if budget.AlertedMonth == month {
+			alerted = budget.AlertedPercent
+		}
+		level := budgetAlertLevel(rows[i].Spent, budget.Amount)
+		if level <= alerted {
+			continue
+		}
+
+		s.notifyBudget(ctx, budget, rows[i].Spent, level)
+		if err := s.repo.MarkBudgetAlerted(ctx, budget.ID, month, level); err != nil {
+			log.Printf("transaction service: failed to mark budget %s alerted: %v", budget.ID, err)
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+func (s *Service) notifyBudget(ctx context.Context, budget *CategoryBudget, spent int64, level int) {
+	if s.notifier == nil {
+		return
+	}
+
+	label := TransactionCategories[budget.Category]
+	limit := fmt.Sprintf("NGN %.2f", koboToNaira(budget.Amount))
+	title := "Budget almost used up"
+	body := fmt.Sprintf("You have used %d%% of your %s %s budget for this month.", level, label, limit)
+	if level >= 100 {
+		title = "Budget exceeded"
+		body = fmt.Sprintf("You have spent NGN %.2f on %s this month, over your %s budget.", koboToNaira(spent), label, limit)
+	}
+
+	if err := s.notifier.SendToUser(ctx, budget.MobileUserID, title, "transaction", body, map[string]any{
+		"budget_id": budget.ID,
+		"category":  string(budget.Category),
+		"percent":   level,
+	}); err != nil {
+		log.Printf("transaction service: failed to send budget alert for %s: %v", budget.ID, err)
+	}
+}
+
 func (s *Service) CreateTransaction(ctx context.Context, txn *Transaction) error {
 	return s.repo.AddTransaction(ctx, txn)
 }
@@ -137,3 +470,69 @@ func groupByMonth(txs []Transaction) []TransactionSection {
 	}
 	return sections
 }
+
+// buildCategoryInsights orders categories by spend, attaching last month's
+// figure and any budget. Budgeted categories with no spend yet are listed
+// after the rest.
+func buildCategoryInsights(current, previous []categorySpendRow, budgets []CategoryBudget, totalOutflow int64) []CategoryInsight {
+	prevByCategory := make(map[TransactionCategory]int64, len(previous))
+	for _, row := range previous {
+		prevByCategory[row.Category] = row.Amount
+	}
+	budgetByCategory := make(map[TransactionCategory]int64, len(budgets))
+	for _, b := range budgets {
+		budgetByCategory[b.Category] = b.Amount
+	}
+
+	rows := append([]categorySpendRow(nil), current...)
+	seen := make(map[TransactionCategory]bool, len(rows))
+	for _, row := range rows {
+		seen[row.Category] = true
+	}
+	var unspent []categorySpendRow
+	for _, b := range budgets {
+		if !seen[b.Category] {
+			unspent = append(unspent, categorySpendRow{Category: b.Category})
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].Amount > rows[j].Amount })
+	rows = append(rows, unspent...)
+
+	result := make([]CategoryInsight, len(rows))
+	for i, row := range rows {
+		insight := CategoryInsight{
+			Category:       row.Category,
+			Label:          TransactionCategories[row.Category],
+			Amount:         koboToNaira(row.Amount),
+			Count:          row.Count,
+			PreviousAmount: koboToNaira(prevByCategory[row.Category]),
+			ChangePercent:  percentChange(prevByCategory[row.Category], row.Amount),
+		}
+		if totalOutflow > 0 {
+			insight.SharePercent = roundPercent(float64(row.Amount) * 100 / float64(totalOutflow))
+		}
+		if budget, ok := budgetByCategory[row.Category]; ok {
+			amount := koboToNaira(budget)
+			used := roundPercent(float64(row.Amount) * 100 / float64(budget))
+			insight.Budget = &amount
+			insight.BudgetUsedPercent = &used
+		}
+		result[i] = insight
+	}
+	return result
+}
+
+func toBudgetResponse(budget *CategoryBudget, spent int64, monthStart time.Time) BudgetResponse {
+	resp := BudgetResponse{
+		Category:  budget.Category,
+		Label:     TransactionCategories[budget.Category],
+		Amount:    koboToNaira(budget.Amount),
+		Spent:     koboToNaira(spent),
+		Month:     monthStart.Format(insightsMonthLayout),
+		UpdatedAt: budget.UpdatedAt,
+	}
+	if budget.Amount > 0 {
+		resp.UsedPercent = roundPercent(float64(spent) * 100 / float64(budget.Amount))
+	}
+	return resp
+}
@@ -1,5 +1,7 @@
 package transaction
 
+import "time"
+
 type TransactionType string
 
 const (
@@ -14,6 +16,9 @@ const (
 	TransactionStatusSuccessful TransactionStatus = "successful"
 	TransactionStatusFailed     TransactionStatus = "failed"
 	TransactionStatusReversed   TransactionStatus = "reversed"
+	// TransactionStatusReversalPending marks a debit the provider still holds
+	// while a refund is outstanding.
+	TransactionStatusReversalPending TransactionStatus = "reversal_pending"
 )
 
 type TransactionSource string
@@ -26,6 +31,7 @@ const (
 	TransactionSourceLoanRepayment    TransactionSource = "loan_repayment"
 	TransactionSourceAutoRepayment    TransactionSource = "auto_repayment"
 	TransactionSourceCard             TransactionSource = "card"
+	TransactionSourceP2P              TransactionSource = "p2p"
 )
 
 type TransactionCategory string
@@ -53,3 +59,50 @@ var TransactionCategories = map[TransactionCategory]string{
 	TransactionCategoryCardPayment:   "Card Payment",
 	TransactionCategoryLoanRepayment: "Loan Repayment",
 }
+
+// TransactionFilter narrows a user's transaction history. Zero values match
+// everything; amounts are in kobo and To is exclusive.
+type TransactionFilter struct {
+	From         time.Time
+	To           time.Time
+	Type         TransactionType
+	Categories   []TransactionCategory
+	Statuses     []TransactionStatus
+	MinAmount    int64
+	MaxAmount    int64
+	Counterparty string
+	Search       string
+}
+
+type ExportFormat string
+
+const (
+	ExportFormatCSV    ExportFormat = "csv"
+	ExportFormatNDJSON ExportFormat = "ndjson"
+)
+
+type ReceiptFormat string
+
+const (
+	ReceiptFormatPDF ReceiptFormat = "pdf"
+	ReceiptFormatPNG ReceiptFormat = "png"
+)
+
+// exportFlushEvery bounds how many rows an export buffers before pushing them
+// to the client.
+const exportFlushEvery = 500
+
+// insightsLocation is where "this month" and "today" are measured for
+// insights and budgets.
+var insightsLocation = time.FixedZone("WAT", 60*60)
+
+const (
+	insightsMonthLayout   = "2006-01"
+	defaultInsightsMonths = 6
+	maxInsightsMonths     = 12
+	topCounterpartyLimit  = 5
+)
+
+// budgetAlertPercents are the spend levels that trigger a budget push, in
+// ascending order.
+var budgetAlertPercents = []int{80, 100}
@@ -1,5 +1,7 @@
 package vas
 
+import "time"
+
 type AirtimePayload struct {
 	UniqueCode  string `json:"unique_code" binding:"required"`
 	PhoneNumber string `json:"phone_number" binding:"required"`
@@ -107,10 +109,18 @@ type BeneficiaryRequestQuery struct {
 }
 
 type VAS struct {
-	ID             string `json:"id"`
-	PhoneNumber    string `json:"phone_number,omitempty"`
-	Email          string `json:"email,omitempty"`
-	BillingCompany string `json:"billing_company,omitempty"`
-	AccountNumber  string `json:"account_number,omitempty"`
-	AccountType    string `json:"account_type,omitempty"`
+	ID             string     `json:"id"`
+	PhoneNumber    string     `json:"phone_number,omitempty"`
+	Email          string     `json:"email,omitempty"`
+	BillingCompany string     `json:"billing_company,omitempty"`
+	AccountNumber  string     `json:"account_number,omitempty"`
+	AccountType    string     `json:"account_type,omitempty"`
+	Nickname       string     `json:"nickname,omitempty"`
+	IsFavourite    bool       `json:"is_favourite"`
+	LastUsedAt     *time.Time `json:"last_used_at,omitempty"`
+}
+
+type UpdateBeneficiaryRequest struct {
+	Nickname    *string `json:"nickname" binding:"omitempty,max=50"`
+	IsFavourite *bool   `json:"is_favourite"`
 }
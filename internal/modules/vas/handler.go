@@ -334,3 +334,52 @@ func (h *Handler) FetchBeneficiaries(c *gin.Context) {
 		Data:    &beneficiaries,
 	})
 }
+
+func (h *Handler) UpdateBeneficiary(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		log.Println("vas handler: missing user id in context for UpdateBeneficiary")
+		mapped := response.MapError(appErr.ErrMissingUserID)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{Status: "error", Error: &mapped.Error})
+		return
+	}
+
+	var payload UpdateBeneficiaryRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		mapped := response.MapError(appErr.ErrInvalidRequestBody)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{Status: "error", Error: &mapped.Error})
+		return
+	}
+
+	if err := h.service.UpdateBeneficiary(c.Request.Context(), mobileUserID, c.Param("id"), payload); err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{Status: "error", Error: &mapped.Error})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[any]{
+		Status:  "success",
+		Message: "Beneficiary updated successfully",
+	})
+}
+
+func (h *Handler) DeleteBeneficiary(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		log.Println("vas handler: missing user id in context for DeleteBeneficiary")
+		mapped := response.MapError(appErr.ErrMissingUserID)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{Status: "error", Error: &mapped.Error})
+		return
+	}
+
+	if err := h.service.DeleteBeneficiary(c.Request.Context(), mobileUserID, c.Param("id")); err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{Status: "error", Error: &mapped.Error})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[any]{
+		Status:  "success",
+		Message: "Beneficiary deleted successfully",
+	})
+}
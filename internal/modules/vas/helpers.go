@@ -1,7 +1,15 @@
 package vas
 
-import "strings"
+import (
+	"neat_mobile_app_backend/internal/modules/wallet"
+	"strings"
+)
 
 func ExtractBillingCompanyName(text string) string {
 	return strings.Split(text, "_")[0]
 }
+
+// debitAllowed applies the wallet module's status controls to a bill payment.
+func debitAllowed(w *CustomerWallet) error {
+	return wallet.DebitAllowed(w.Status)
+}
@@ -2,8 +2,12 @@ package vas
 
 import (
 	"context"
+	"neat_mobile_app_backend/internal/modules/limits"
+	"neat_mobile_app_backend/internal/modules/wallet"
 	"neat_mobile_app_backend/providers/baas"
 	vasprovider "neat_mobile_app_backend/providers/vas"
+
+	"gorm.io/gorm"
 )
 
 type VASService interface {
@@ -23,8 +27,15 @@ type WalletService interface {
 }
 
 type TransactionService interface {
-	AddTransaction(ctx context.Context, transaction *Transaction) error
+	AddTransaction(ctx context.Context, transaction *Transaction, check wallet.DebitCheck) error
 	UpdateTransactionStatus(ctx context.Context, txID string, balanceAfter int64, status TransactionStatus) error
+	CompleteDebitTransaction(ctx context.Context, txID, walletID string, amount, charges int64, status TransactionStatus) error
+	SetRefundReference(ctx context.Context, txID, refundRef string) error
+}
+
+// TransactionReverser refunds a booked debit into the wallet.
+type TransactionReverser interface {
+	ReverseTransaction(ctx context.Context, txID, reason string) (*wallet.ReversalResponse, error)
 }
 
 type BAAS interface {
@@ -35,3 +46,9 @@ type BAAS interface {
 type AuthService interface {
 	VerifyTransactionPin(ctx context.Context, mobileUserID, pin string) error
 }
+
+// LimitChecker enforces tier limits inside the database transaction that
+// books a bill payment.
+type LimitChecker interface {
+	CheckTx(ctx context.Context, tx *gorm.DB, mobileUserID string, channel limits.Channel, amounts ...int64) error
+}
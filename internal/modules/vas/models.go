@@ -1,15 +1,20 @@
 package vas
 
+import "time"
+
 type VASBeneficiary struct {
-	ID             string `gorm:"column:id;type:text;primaryKey"`
-	MobileUserID   string `gorm:"column:mobile_user_id;type:text;not null"`
-	PhoneNumber    string `gorm:"column:phone_number;type:text"`
-	Email          string `gorm:"column:email;type:text"`
-	BillingCompany string `gorm:"column:billing_company;type:text;not null"`
-	AccountNumber  string `gorm:"column:account_number;type:text;not null"`
-	AccountType    string `gorm:"column:account_type;type:text;not null"`
-	CreatedAt      string `gorm:"column:created_at;type:timestamptz;autoCreateTime;not null"`
-	UpdatedAt      string `gorm:"column:updated_at;type:timestamptz;autoUpdateTime;"`
+	ID             string     `gorm:"column:id;type:text;primaryKey"`
+	MobileUserID   string     `gorm:"column:mobile_user_id;type:text;not null"`
+	PhoneNumber    string     `gorm:"column:phone_number;type:text"`
+	Email          string     `gorm:"column:email;type:text"`
+	BillingCompany string     `gorm:"column:billing_company;type:text;not null"`
+	AccountNumber  string     `gorm:"column:account_number;type:text;not null"`
+	AccountType    string     `gorm:"column:account_type;type:text;not null"`
+	Nickname       string     `gorm:"column:nickname;type:text"`
+	IsFavourite    bool       `gorm:"column:is_favourite;not null;default:false"`
+	LastUsedAt     *time.Time `gorm:"column:last_used_at;type:timestamptz"`
+	CreatedAt      string     `gorm:"column:created_at;type:timestamptz;autoCreateTime;not null"`
+	UpdatedAt      string     `gorm:"column:updated_at;type:timestamptz;autoUpdateTime;"`
 }
 
 func (VASBeneficiary) TableName() string {
@@ -2,20 +2,27 @@ package vas
 
 import (
 	"context"
+	"errors"
+	appErr "neat_mobile_app_backend/internal/errors"
+	"neat_mobile_app_backend/internal/modules/ledger"
+	"neat_mobile_app_backend/internal/modules/wallet"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Repository struct {
-	db *gorm.DB
+	db     *gorm.DB
+	ledger *ledger.Repository
 }
 
 func NewRepository(db *gorm.DB) *Repository {
-	return &Repository{db: db}
+	return &Repository{db: db, ledger: ledger.NewRepository(db)}
 }
 
 func (r *Repository) WithTx(tx *gorm.DB) *Repository {
-	return &Repository{db: tx}
+	return &Repository{db: tx, ledger: r.ledger.WithTx(tx)}
 }
 
 func (r *Repository) GetBalance(ctx context.Context, mobileUserID string) (*CustomerWallet, error) {
@@ -29,8 +36,24 @@ func (r *Repository) GetBalance(ctx context.Context, mobileUserID string) (*Cust
 	return &wallet, nil
 }
 
-func (r *Repository) AddTransaction(ctx context.Context, txn *Transaction) error {
-	return r.db.WithContext(ctx).Create(txn).Error
+// AddTransaction writes a pending bill payment once check passes and the
+// wallet is confirmed debitable under its row lock, in one database
+// transaction.
+func (r *Repository) AddTransaction(ctx context.Context, txn *Transaction, check wallet.DebitCheck) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if check != nil {
+			if err := check(tx); err != nil {
+				return err
+			}
+		}
+		if _, err := wallet.LockWalletForDebit(tx, txn.WalletID, txn.Amount); err != nil {
+			if errors.Is(err, wallet.ErrInsufficientFunds) {
+				return appErr.ErrInsufficientBalance
+			}
+			return err
+		}
+		return tx.Create(txn).Error
+	})
 }
 
 func (r *Repository) UpdateTransactionStatus(ctx context.Context, txID string, balanceAfter int64, status TransactionStatus) error {
@@ -43,6 +66,61 @@ func (r *Repository) UpdateTransactionStatus(ctx context.Context, txID string, b
 		}).Error
 }
 
+// CompleteDebitTransaction records a bill payment the provider has already
+// debited: it posts the journal entry and brings the cached wallet balance in
+// line with the provider. amount and charges are in kobo.
+func (r *Repository) CompleteDebitTransaction(ctx context.Context, txID, walletID string, amount, charges int64, status TransactionStatus) error {
+	total := amount + charges
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var wallet CustomerWallet
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("internal_wallet_id = ?", walletID).
+			First(&wallet).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&Transaction{}).
+			Where("id = ?", txID).
+			Updates(map[string]interface{}{
+				"status":         status,
+				"charges":        charges,
+				"balance_before": wallet.AvailableBalance,
+				"balance_after":  wallet.AvailableBalance - total,
+			}).Error; err != nil {
+			return err
+		}
+
+		if err := r.ledger.WithTx(tx).PostWalletDebit(ctx, ledger.Movement{
+			WalletID:       wallet.InternalWalletID,
+			OpeningBalance: wallet.AvailableBalance,
+			TransactionID:  txID,
+			Reference:      "debit:" + txID,
+			Description:    "Bill payment",
+			Amount:         amount,
+			Charges:        charges,
+		}); err != nil {
+			return err
+		}
+
+		return tx.Model(&CustomerWallet{}).
+			Where("internal_wallet_id = ?", walletID).
+			Updates(map[string]interface{}{
+				"booked_balance":    gorm.Expr("booked_balance - ?", total),
+				"available_balance": gorm.Expr("available_balance - ?", total),
+				"updated_at":        time.Now(),
+			}).Error
+	})
+}
+
+// SetRefundReference records the provider reference of the refund for a
+// failed bill payment, so its reversal credit can be reconciled.
+func (r *Repository) SetRefundReference(ctx context.Context, txID, refundRef string) error {
+	return r.db.WithContext(ctx).
+		Model(&Transaction{}).
+		Where("id = ?", txID).
+		Update("metadata", gorm.Expr(`COALESCE(metadata, '{}'::jsonb) || jsonb_build_object('refund_reference', ?::text)`, refundRef)).Error
+}
+
 func (r *Repository) UpdateTransactionMetadata(ctx context.Context, txID string, metadata map[string]any) error {
 	return r.db.WithContext(ctx).
 		Model(&Transaction{}).
@@ -50,17 +128,50 @@ func (r *Repository) UpdateTransactionMetadata(ctx context.Context, txID string,
 		Update("metadata", metadata).Error
 }
 
-func (r *Repository) StoreVASAsBeneficiary(ctx context.Context, beneficiary *VASBeneficiary) error {
-	return r.db.WithContext(ctx).Create(beneficiary).Error
+// RecordVASBeneficiary saves the beneficiary of a successful purchase, or
+// bumps its last used time when the user has bought for it before.
+func (r *Repository) RecordVASBeneficiary(ctx context.Context, beneficiary *VASBeneficiary) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing VASBeneficiary
+		err := tx.Where("mobile_user_id = ? AND billing_company = ? AND phone_number = ? AND account_number = ?",
+			beneficiary.MobileUserID, beneficiary.BillingCompany, beneficiary.PhoneNumber, beneficiary.AccountNumber).
+			First(&existing).Error
+		if err == nil {
+			return tx.Model(&VASBeneficiary{}).
+				Where("id = ?", existing.ID).
+				Update("last_used_at", beneficiary.LastUsedAt).Error
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		return tx.Create(beneficiary).Error
+	})
 }
 
 func (r *Repository) FetchVASBeneficiaries(ctx context.Context, mobileUserID, biller string) ([]VAS, error) {
 	var beneficiaries []VAS
 	err := r.db.WithContext(ctx).
+		Model(&VASBeneficiary{}).
 		Where("mobile_user_id = ? AND billing_company = ?", mobileUserID, biller).
+		Order("is_favourite DESC, last_used_at DESC NULLS LAST, created_at DESC").
 		Find(&beneficiaries).Error
 	if err != nil {
 		return nil, err
 	}
 	return beneficiaries, nil
 }
+
+func (r *Repository) UpdateVASBeneficiary(ctx context.Context, mobileUserID, id string, updates map[string]any) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&VASBeneficiary{}).
+		Where("id = ? AND mobile_user_id = ?", id, mobileUserID).
+		Updates(updates)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *Repository) DeleteVASBeneficiary(ctx context.Context, mobileUserID, id string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND mobile_user_id = ?", id, mobileUserID).
+		Delete(&VASBeneficiary{})
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,206 @@
+package vas
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+
+	appErr "neat_mobile_app_backend/internal/errors"
+	"neat_mobile_app_backend/internal/modules/ledger"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func newMockRepository(t *testing.T) (*Repository, sqlmock.Sqlmock, func()) {
+	t.Helper()
+
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: sqlDB,
+	}), &gorm.Config{
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		_ = sqlDB.Close()
+		t.Fatalf("open gorm db: %v", err)
+	}
+
+	cleanup := func() {
+		_ = sqlDB.Close()
+	}
+
+	return NewRepository(gormDB), mock, cleanup
+}
+
+// postingColumns is how many values gorm binds per row of wallet_ledger_postings:
+// id, journal_entry_id, account_id, direction, amount, created_at.
+const postingColumns = 6
+
+// capturedPostings records the rows bound to a postings insert so a test can
+// check what the ledger was given.
+type capturedPostings struct {
+	values []driver.Value
+}
+
+type captureArg struct {
+	into *capturedPostings
+}
+
+func (a captureArg) Match(v driver.Value) bool {
+	a.into.values = append(a.into.values, v)
+	return true
+}
+
+func (c *capturedPostings) args(rows int) []driver.Value {
+	out := make([]driver.Value, rows*postingColumns)
+	for i := range out {
+		out[i] = captureArg{into: c}
+	}
+	return out
+}
+
+// walletMovement fails unless the captured postings balance, and returns how
+// far they moved the wallet's ledger account (credits less debits).
+func (c *capturedPostings) walletMovement(t *testing.T, walletID string) int64 {
+	t.Helper()
+
+	var debits, credits, net int64
+	for i := 0; i+postingColumns <= len(c.values); i += postingColumns {
+		account, _ := c.values[i+2].(string)
+		direction, _ := c.values[i+3].(string)
+		amount, _ := c.values[i+4].(int64)
+		sign := int64(1)
+		if ledger.Direction(direction) == ledger.DirectionDebit {
+			debits += amount
+			sign = -1
+		} else {
+			credits += amount
+		}
+		if account == ledger.WalletAccountID(walletID) {
+			net += sign * amount
+		}
+	}
+	if debits == 0 || debits != credits {
+		t.Fatalf("unbalanced entry: debits %d, credits %d", debits, credits)
+	}
+	return net
+}
+
+func lockWalletQueryPattern() string {
+	return regexp.QuoteMeta(`SELECT * FROM "wallet_customer_wallets" WHERE internal_wallet_id = $1 ORDER BY "wallet_customer_wallets"."id" LIMIT $2 FOR UPDATE`)
+}
+
+func walletRows(walletID string, available int64) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "internal_wallet_id", "available_balance", "booked_balance"}).
+		AddRow("row-"+walletID, walletID, available, available)
+}
+
+func TestRepository_CompleteDebitTransaction_BooksBillPaymentAndFee(t *testing.T) {
+	repo, mock, cleanup := newMockRepository(t)
+	defer cleanup()
+
+	opening, debit := &capturedPostings{}, &capturedPostings{}
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockWalletQueryPattern()).
+		WithArgs("iw-1", 1).
+		WillReturnRows(walletRows("iw-1", 500_000))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallet_transactions" SET "balance_after"=$1,"balance_before"=$2,"charges"=$3,"status"=$4`)).
+		WithArgs(int64(395_000), int64(500_000), int64(5_000), string(TransactionStatusSuccessful), sqlmock.AnyArg(), "tx-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+	// the wallet's first ledger movement seeds its cached balance
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "wallet_ledger_accounts"`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "wallet_ledger_journal_entries"`)).
+		WithArgs(sqlmock.AnyArg(), "opening:iw-1", nil, "Opening balance", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "wallet_ledger_postings"`)).
+		WithArgs(opening.args(2)...).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "wallet_ledger_journal_entries"`)).
+		WithArgs(sqlmock.AnyArg(), "debit:tx-1", "tx-1", "Bill payment", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "wallet_ledger_postings"`)).
+		WithArgs(debit.args(3)...).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallet_customer_wallets" SET "available_balance"=available_balance - $1,"booked_balance"=booked_balance - $2`)).
+		WithArgs(int64(105_000), int64(105_000), sqlmock.AnyArg(), "iw-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.CompleteDebitTransaction(context.Background(), "tx-1", "iw-1", 100_000, 5_000, TransactionStatusSuccessful)
+	if err != nil {
+		t.Fatalf("CompleteDebitTransaction returned error: %v", err)
+	}
+
+	if got := opening.walletMovement(t, "iw-1"); got != 500_000 {
+		t.Fatalf("opening balance seeded %d, want 500000", got)
+	}
+	if got := debit.walletMovement(t, "iw-1"); got != -105_000 {
+		t.Fatalf("debit moved the wallet by %d, want -105000", got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sqlmock expectations: %v", err)
+	}
+}
+
+func TestRepository_CompleteDebitTransaction_RollsBackWhenLedgerFails(t *testing.T) {
+	repo, mock, cleanup := newMockRepository(t)
+	defer cleanup()
+
+	postErr := errors.New("insert failed")
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockWalletQueryPattern()).
+		WithArgs("iw-1", 1).
+		WillReturnRows(walletRows("iw-1", 500_000))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallet_transactions"`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "wallet_ledger_accounts"`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "wallet_ledger_journal_entries"`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "wallet_ledger_postings"`)).
+		WillReturnError(postErr)
+	mock.ExpectExec(`ROLLBACK TO SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err := repo.CompleteDebitTransaction(context.Background(), "tx-1", "iw-1", 100_000, 5_000, TransactionStatusReversalPending)
+	if !errors.Is(err, postErr) {
+		t.Fatalf("expected the ledger error, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sqlmock expectations: %v", err)
+	}
+}
+
+func TestRepository_AddTransaction_RefusesDebitAboveAvailableBalance(t *testing.T) {
+	repo, mock, cleanup := newMockRepository(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockWalletQueryPattern()).
+		WithArgs("iw-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "internal_wallet_id", "status", "available_balance", "booked_balance"}).
+			AddRow("row-iw-1", "iw-1", "active", 50_000, 50_000))
+	mock.ExpectRollback()
+
+	txn := &Transaction{ID: "tx-1", WalletID: "iw-1", Amount: 100_000, Status: TransactionStatusPending}
+	if err := repo.AddTransaction(context.Background(), txn, nil); !errors.Is(err, appErr.ErrInsufficientBalance) {
+		t.Fatalf("expected ErrInsufficientBalance, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sqlmock expectations: %v", err)
+	}
+}
@@ -15,5 +15,7 @@ func RegisterRoutes(rg *gin.RouterGroup, authGuard, deviceValidator gin.HandlerF
 		vas.POST("/cable/validate", handler.ValidateCable)
 		vas.POST("/cable/pay", handler.PayCable)
 		vas.GET("/beneficiaries", handler.FetchBeneficiaries)
+		vas.PATCH("/beneficiaries/:id", handler.UpdateBeneficiary)
+		vas.DELETE("/beneficiaries/:id", handler.DeleteBeneficiary)
 	}
 }
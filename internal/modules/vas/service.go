@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"log"
 	appErr "neat_mobile_app_backend/internal/errors"
+	"neat_mobile_app_backend/internal/modules/limits"
+	walletmodule "neat_mobile_app_backend/internal/modules/wallet"
 	"neat_mobile_app_backend/internal/phone"
 	"neat_mobile_app_backend/providers/vas"
 	vasprovider "neat_mobile_app_backend/providers/vas"
@@ -13,6 +15,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type Service struct {
@@ -22,10 +25,12 @@ type Service struct {
 	Baas           BAAS
 	XpressPayments VASService
 	PinVerifier    AuthService
+	Reverser       TransactionReverser
+	Limits         LimitChecker
 }
 
-func NewService(repo *Repository, xpressPayments VASService, walletService WalletService, txr TransactionService, baas BAAS, pinVerifier AuthService) *Service {
-	return &Service{Repo: repo, XpressPayments: xpressPayments, WalletService: walletService, Txr: txr, Baas: baas, PinVerifier: pinVerifier}
+func NewService(repo *Repository, xpressPayments VASService, walletService WalletService, txr TransactionService, baas BAAS, pinVerifier AuthService, reverser TransactionReverser, limitChecker LimitChecker) *Service {
+	return &Service{Repo: repo, XpressPayments: xpressPayments, WalletService: walletService, Txr: txr, Baas: baas, PinVerifier: pinVerifier, Reverser: reverser, Limits: limitChecker}
 }
 
 func (s *Service) FetchAllCategories(ctx context.Context) ([]vas.Category, error) {
@@ -162,14 +167,9 @@ func (s *Service) GetAirtime(ctx context.Context, payload AirtimePayload, mobile
 	}
 	amount := payload.Amount
 
-	if amount < 100 {
-		log.Println("vas service: amount is less than NGN 100")
-		return nil, appErr.ErrInvalidISPAmount
-	}
-
-	if amount > 10000 {
-		log.Println("vas service: amount is greater than NGN 10,000")
-		return nil, appErr.ErrInvalidISPAmount
+	if err := limits.CheckAirtime(amount * 100); err != nil {
+		log.Printf("vas service: airtime amount NGN %d is out of range\n", amount)
+		return nil, err
 	}
 
 	// result, err := s.XpressPayments.FetchProductsByCategoryIDAndBillerID(ctx, categoryID, billerID)
@@ -212,6 +212,9 @@ func (s *Service) GetAirtime(ctx context.Context, payload AirtimePayload, mobile
 		log.Printf("vas service: failed to get wallet balance - %s\n", err)
 		return nil, appErr.ErrGettingAirtime
 	}
+	if err := debitAllowed(wallet); err != nil {
+		return nil, err
+	}
 
 	if wallet.AvailableBalance < amount*100 {
 		log.Println("vas service: insufficient balance")
@@ -248,7 +251,7 @@ func (s *Service) GetAirtime(ctx context.Context, payload AirtimePayload, mobile
 		CreatedAt:           time.Now().UTC(),
 	}
 
-	if err := s.Txr.AddTransaction(ctx, &txn); err != nil {
+	if err := s.Txr.AddTransaction(ctx, &txn, s.limitCheck(ctx, mobileUserID, amount*100)); err != nil {
 		log.Printf("vas service: failed to add transaction record at pending state - %s\n", err)
 		return nil, err
 	}
@@ -265,24 +268,25 @@ func (s *Service) GetAirtime(ctx context.Context, payload AirtimePayload, mobile
 	result, err := s.XpressPayments.GetAirtime(ctx, requestID, uniqueCode, localizedPhone, amount)
 	if err != nil {
 		log.Printf("vas service: unable to purchase airtime - %s\n", err)
-		s.handleFulfilFailure(ctx, txID, amount, debitResult.Data.TransactionFee, wallet.AvailableBalance, metadata, wallet.WalletCustomerID, err)
+		s.handleFulfilFailure(ctx, txID, wallet.InternalWalletID, amount, debitResult.Data.TransactionFee, wallet.AvailableBalance, metadata, wallet.WalletCustomerID, err)
 		return nil, appErr.ErrGettingAirtime
 	}
 
-	balanceAfter := wallet.AvailableBalance - ((amount + int64(debitResult.Data.TransactionFee)) * 100)
-	if err := s.Txr.UpdateTransactionStatus(ctx, txID, balanceAfter, TransactionStatusSuccessful); err != nil {
+	if err := s.Txr.CompleteDebitTransaction(ctx, txID, wallet.InternalWalletID, amount*100, int64(debitResult.Data.TransactionFee)*100, TransactionStatusSuccessful); err != nil {
 		log.Printf("vas service: failed to update transaction record to successful - %s", err)
 		return nil, appErr.ErrGettingAirtime
 	}
 
+	usedAt := time.Now().UTC()
 	beneficiary := VASBeneficiary{
 		ID:             uuid.NewString(),
 		MobileUserID:   mobileUserID,
 		PhoneNumber:    localizedPhone,
 		BillingCompany: strings.ToLower(ExtractBillingCompanyName(strings.TrimSpace(payload.UniqueCode))),
+		LastUsedAt:     &usedAt,
 	}
 
-	if err := s.Repo.StoreVASAsBeneficiary(ctx, &beneficiary); err != nil {
+	if err := s.Repo.RecordVASBeneficiary(ctx, &beneficiary); err != nil {
 		log.Printf("vas service: failed to store vas beneficiary - %s", err)
 	}
 
@@ -303,15 +307,14 @@ func (s *Service) GetData(ctx context.Context, payload DataPayload, mobileUserID
 		return nil, appErr.ErrInvalidISPAmount
 	}
 
-	if amount > 10000 {
-		return nil, appErr.ErrInvalidISPAmount
-	}
-
 	wallet, err := s.WalletService.GetBalance(ctx, mobileUserID)
 	if err != nil {
 		log.Printf("vas service: failed to get wallet balance - %s\n", err)
 		return nil, appErr.ErrGettingData
 	}
+	if err := debitAllowed(wallet); err != nil {
+		return nil, err
+	}
 
 	if wallet.AvailableBalance < amount*100 {
 		return nil, appErr.ErrInsufficientBalance
@@ -345,7 +348,7 @@ func (s *Service) GetData(ctx context.Context, payload DataPayload, mobileUserID
 		CreatedAt:           time.Now().UTC(),
 	}
 
-	if err := s.Txr.AddTransaction(ctx, &txn); err != nil {
+	if err := s.Txr.AddTransaction(ctx, &txn, s.limitCheck(ctx, mobileUserID, amount*100)); err != nil {
 		log.Printf("vas service: failed to add transaction record at pending state - %s\n", err)
 		return nil, err
 	}
@@ -362,12 +365,11 @@ func (s *Service) GetData(ctx context.Context, payload DataPayload, mobileUserID
 	result, err := s.XpressPayments.GetData(ctx, requestID, uniqueCode, localizedPhone, amount)
 	if err != nil {
 		log.Printf("vas service: unable to purchase data - %s\n", err)
-		s.handleFulfilFailure(ctx, txID, amount, debitResult.Data.TransactionFee, wallet.AvailableBalance, metadata, wallet.WalletCustomerID, err)
+		s.handleFulfilFailure(ctx, txID, wallet.InternalWalletID, amount, debitResult.Data.TransactionFee, wallet.AvailableBalance, metadata, wallet.WalletCustomerID, err)
 		return nil, appErr.ErrGettingData
 	}
 
-	balanceAfter := wallet.AvailableBalance - ((amount + int64(debitResult.Data.TransactionFee)) * 100)
-	if err := s.Txr.UpdateTransactionStatus(ctx, txID, balanceAfter, TransactionStatusSuccessful); err != nil {
+	if err := s.Txr.CompleteDebitTransaction(ctx, txID, wallet.InternalWalletID, amount*100, int64(debitResult.Data.TransactionFee)*100, TransactionStatusSuccessful); err != nil {
 		log.Printf("vas service: failed to update transaction record to successful - %s", err)
 		return nil, appErr.ErrGettingData
 	}
@@ -376,14 +378,16 @@ func (s *Service) GetData(ctx context.Context, payload DataPayload, mobileUserID
 		bgCtx := context.Background()
 		ctx, cancel := context.WithTimeout(bgCtx, time.Second*5)
 		defer cancel()
+		usedAt := time.Now().UTC()
 		beneficiary := VASBeneficiary{
 			ID:             uuid.NewString(),
 			MobileUserID:   mobileUserID,
 			PhoneNumber:    localizedPhone,
 			BillingCompany: strings.ToLower(ExtractBillingCompanyName(strings.TrimSpace(payload.UniqueCode))),
+			LastUsedAt:     &usedAt,
 		}
 
-		if err := s.Repo.StoreVASAsBeneficiary(ctx, &beneficiary); err != nil {
+		if err := s.Repo.RecordVASBeneficiary(ctx, &beneficiary); err != nil {
 			log.Printf("vas service: failed to store vas beneficiary - %s", err)
 		}
 	}()
@@ -417,6 +421,9 @@ func (s *Service) PayElectricity(ctx context.Context, payload PayElectricityPayl
 		log.Printf("vas service: failed to get wallet balance - %s\n", err)
 		return nil, appErr.ErrPayingElectricityBill
 	}
+	if err := debitAllowed(wallet); err != nil {
+		return nil, err
+	}
 
 	if wallet.AvailableBalance < amount*100 {
 		return nil, appErr.ErrInsufficientBalance
@@ -466,7 +473,7 @@ func (s *Service) PayElectricity(ctx context.Context, payload PayElectricityPayl
 		CreatedAt:           time.Now().UTC(),
 	}
 
-	if err := s.Txr.AddTransaction(ctx, &txn); err != nil {
+	if err := s.Txr.AddTransaction(ctx, &txn, s.limitCheck(ctx, mobileUserID, amount*100)); err != nil {
 		log.Printf("vas service: failed to add transaction record at pending state - %s\n", err)
 		return nil, err
 	}
@@ -487,12 +494,11 @@ func (s *Service) PayElectricity(ctx context.Context, payload PayElectricityPayl
 	)
 	if err != nil {
 		log.Printf("vas service: failed to pay electricity bill - %s\n", err)
-		s.handleFulfilFailure(ctx, txID, amount, debitResult.Data.TransactionFee, wallet.AvailableBalance, metadata, wallet.WalletCustomerID, err)
+		s.handleFulfilFailure(ctx, txID, wallet.InternalWalletID, amount, debitResult.Data.TransactionFee, wallet.AvailableBalance, metadata, wallet.WalletCustomerID, err)
 		return nil, appErr.ErrPayingElectricityBill
 	}
 
-	balanceAfter := wallet.AvailableBalance - ((amount + int64(debitResult.Data.TransactionFee)) * 100)
-	if err := s.Txr.UpdateTransactionStatus(ctx, txID, balanceAfter, TransactionStatusSuccessful); err != nil {
+	if err := s.Txr.CompleteDebitTransaction(ctx, txID, wallet.InternalWalletID, amount*100, int64(debitResult.Data.TransactionFee)*100, TransactionStatusSuccessful); err != nil {
 		log.Printf("vas service: failed to update transaction record to successful - %s", err)
 		return nil, appErr.ErrPayingElectricityBill
 	}
@@ -538,6 +544,9 @@ func (s *Service) PayCable(ctx context.Context, payload PayCablePayload, mobileU
 		log.Printf("vas service: failed to get wallet balance - %s\n", err)
 		return nil, appErr.ErrPayingCableBill
 	}
+	if err := debitAllowed(wallet); err != nil {
+		return nil, err
+	}
 
 	if wallet.AvailableBalance < amount*100 {
 		return nil, appErr.ErrInsufficientBalance
@@ -567,7 +576,7 @@ func (s *Service) PayCable(ctx context.Context, payload PayCablePayload, mobileU
 		CreatedAt:           time.Now().UTC(),
 	}
 
-	if err := s.Txr.AddTransaction(ctx, &txn); err != nil {
+	if err := s.Txr.AddTransaction(ctx, &txn, s.limitCheck(ctx, mobileUserID, amount*100)); err != nil {
 		log.Printf("vas service: failed to add transaction record at pending state - %s\n", err)
 		return nil, err
 	}
@@ -588,12 +597,11 @@ func (s *Service) PayCable(ctx context.Context, payload PayCablePayload, mobileU
 	)
 	if err != nil {
 		log.Printf("vas service: failed to pay cable bill - %s\n", err)
-		s.handleFulfilFailure(ctx, txID, amount, debitResult.Data.TransactionFee, wallet.AvailableBalance, metadata, wallet.WalletCustomerID, err)
+		s.handleFulfilFailure(ctx, txID, wallet.InternalWalletID, amount, debitResult.Data.TransactionFee, wallet.AvailableBalance, metadata, wallet.WalletCustomerID, err)
 		return nil, appErr.ErrPayingCableBill
 	}
 
-	balanceAfter := wallet.AvailableBalance - ((amount + int64(debitResult.Data.TransactionFee)) * 100)
-	if err := s.Txr.UpdateTransactionStatus(ctx, txID, balanceAfter, TransactionStatusSuccessful); err != nil {
+	if err := s.Txr.CompleteDebitTransaction(ctx, txID, wallet.InternalWalletID, amount*100, int64(debitResult.Data.TransactionFee)*100, TransactionStatusSuccessful); err != nil {
 		log.Printf("vas service: failed to update transaction record to successful - %s", err)
 		return nil, appErr.ErrPayingCableBill
 	}
@@ -601,27 +609,93 @@ func (s *Service) PayCable(ctx context.Context, payload PayCablePayload, mobileU
 	return result, nil
 }
 
+// limitCheck holds a bill payment (kobo) against the user's VAS limits inside
+// the database transaction that books it.
+func (s *Service) limitCheck(ctx context.Context, mobileUserID string, amount int64) walletmodule.DebitCheck {
+	if s.Limits == nil {
+		return nil
+	}
+	return func(tx *gorm.DB) error {
+		return s.Limits.CheckTx(ctx, tx, mobileUserID, limits.ChannelVAS, amount)
+	}
+}
+
 // handleFulfilFailure handles the post-debit failure path for all fulfil operations.
 // ErrVASAmbiguous (timeout/5xx) → marks reversal_pending for manual reconciliation.
-// Any other error → credits the customer back and marks reversed.
-func (s *Service) handleFulfilFailure(ctx context.Context, txID string, amount int64, txFee int, balanceBefore int64, metadata map[string]any, customerID string, vasErr error) {
+// Any other error → credits the customer back and reverses the booked debit.
+func (s *Service) handleFulfilFailure(ctx context.Context, txID, walletID string, amount int64, txFee int, balanceBefore int64, metadata map[string]any, customerID string, vasErr error) {
 	if errors.Is(vasErr, appErr.ErrVASAmbiguous) {
-		debitedBalance := balanceBefore - ((amount + int64(txFee)) * 100)
-		if updateErr := s.Txr.UpdateTransactionStatus(ctx, txID, debitedBalance, TransactionStatusReversalPending); updateErr != nil {
+		// The provider still holds the debit, so the ledger records it until
+		// the reversal is settled.
+		if updateErr := s.Txr.CompleteDebitTransaction(ctx, txID, walletID, amount*100, int64(txFee)*100, TransactionStatusReversalPending); updateErr != nil {
 			log.Printf("vas service: failed to mark transaction as reversal_pending - %s\n", updateErr)
 		}
 		return
 	}
 
+	// refund the fee as well: the local reversal credits the whole debit back
 	reversalRef := uuid.NewString()
-	if _, creditErr := s.Baas.CreditCustomer(ctx, amount, reversalRef, customerID, metadata); creditErr != nil {
+	if _, creditErr := s.Baas.CreditCustomer(ctx, amount+int64(txFee), reversalRef, customerID, metadata); creditErr != nil {
 		log.Printf("vas service: failed to credit customer back after VAS failure - %s\n", creditErr)
+		// The provider still holds the funds; keep the debit booked until the
+		// refund is settled through the reversal API.
+		if updateErr := s.Txr.CompleteDebitTransaction(ctx, txID, walletID, amount*100, int64(txFee)*100, TransactionStatusReversalPending); updateErr != nil {
+			log.Printf("vas service: failed to mark transaction as reversal_pending - %s\n", updateErr)
+		}
+		return
+	}
+
+	// Book the debit and its refund so the wallet history shows both legs.
+	if updateErr := s.Txr.CompleteDebitTransaction(ctx, txID, walletID, amount*100, int64(txFee)*100, TransactionStatusReversalPending); updateErr != nil {
+		log.Printf("vas service: failed to book debit before reversal - %s\n", updateErr)
+		if updateErr := s.Txr.UpdateTransactionStatus(ctx, txID, balanceBefore, TransactionStatusReversed); updateErr != nil {
+			log.Printf("vas service: failed to mark transaction as reversed - %s\n", updateErr)
+		}
+		return
 	}
-	if updateErr := s.Txr.UpdateTransactionStatus(ctx, txID, balanceBefore, TransactionStatusReversed); updateErr != nil {
-		log.Printf("vas service: failed to mark transaction as reversed - %s\n", updateErr)
+	if refErr := s.Txr.SetRefundReference(ctx, txID, reversalRef); refErr != nil {
+		log.Printf("vas service: failed to record refund reference for %s - %s\n", txID, refErr)
+	}
+	if _, revErr := s.Reverser.ReverseTransaction(ctx, txID, "VAS fulfilment failed"); revErr != nil {
+		log.Printf("vas service: failed to reverse transaction %s - %s\n", txID, revErr)
 	}
 }
 
 func (s *Service) FetchBeneficiaries(ctx context.Context, mobileUserID, biller string) ([]VAS, error) {
 	return s.Repo.FetchVASBeneficiaries(ctx, mobileUserID, biller)
 }
+
+func (s *Service) UpdateBeneficiary(ctx context.Context, mobileUserID, id string, payload UpdateBeneficiaryRequest) error {
+	updates := map[string]any{}
+	if payload.Nickname != nil {
+		updates["nickname"] = strings.TrimSpace(*payload.Nickname)
+	}
+	if payload.IsFavourite != nil {
+		updates["is_favourite"] = *payload.IsFavourite
+	}
+	if len(updates) == 0 {
+		return appErr.ErrInvalidRequestBody
+	}
+
+	updated, err := s.Repo.UpdateVASBeneficiary(ctx, mobileUserID, strings.TrimSpace(id), updates)
+	if err != nil {
+		log.Printf("vas service: failed to update vas beneficiary - %s", err)
+		return appErr.ErrUpdatingBeneficiary
+	}
+	if !updated {
+		return appErr.ErrBeneficiaryNotFound
+	}
+	return nil
+}
+
+func (s *Service) DeleteBeneficiary(ctx context.Context, mobileUserID, id string) error {
+	deleted, err := s.Repo.DeleteVASBeneficiary(ctx, mobileUserID, strings.TrimSpace(id))
+	if err != nil {
+		log.Printf("vas service: failed to delete vas beneficiary - %s", err)
+		return appErr.ErrUpdatingBeneficiary
+	}
+	if !deleted {
+		return appErr.ErrBeneficiaryNotFound
+	}
+	return nil
+}
@@ -1,6 +1,13 @@
 package wallet
 
-import "time"
+import (
+	"neat_mobile_app_backend/internal/modules/transaction"
+	"time"
+)
+
+type BankSearchQuery struct {
+	Query string `form:"q" binding:"omitempty,max=100"`
+}
 
 type BankDetailsQuery struct {
 	AccountNumber string `form:"account_number" binding:"required"`
@@ -15,6 +22,8 @@ type TransferRequest struct {
 	AccountName    *string        `json:"account_name" binding:"required,max=255"`
 	Metadata       map[string]any `json:"metadata" binding:"omitempty"`
 	TransactionPin string         `json:"transaction_pin" binding:"required"`
+	OTPCode        string         `json:"otp_code"` // authenticator code, required on high-value transfers once enrolled
+	Reference      string         `json:"-"`        // our transaction reference, used to requery the provider
 }
 
 type TransferResponse struct {
@@ -36,10 +45,52 @@ type TransferResult struct {
 	Description          string                 `json:"description"`
 }
 
+type TransferRequeryResult struct {
+	Status               ProviderTransferStatus
+	Amount               float64
+	Charges              float64
+	Vat                  float64
+	TransactionReference string
+	SessionID            string
+	Message              string
+}
+
+type ReconcileSummary struct {
+	Scanned    int `json:"scanned"`
+	Successful int `json:"successful"`
+	Failed     int `json:"failed"`
+	Reversed   int `json:"reversed"`
+	Pending    int `json:"pending"`
+}
+
+type ReverseTransactionRequest struct {
+	Reason string `json:"reason"`
+}
+
+type ReversalResponse struct {
+	TransactionID         string                        `json:"transaction_id"`
+	ReversalTransactionID string                        `json:"reversal_transaction_id,omitempty"`
+	AmountRefunded        float64                       `json:"amount_refunded"`
+	Status                transaction.TransactionStatus `json:"status"`
+	Reason                string                        `json:"reason"`
+}
+
 type AddBeneficiaryRequest struct {
 	BankCode      string `json:"bank_code" binding:"required"`
 	AccountNumber string `json:"account_number" binding:"required"`
 	AccountName   string `json:"account_name" binding:"required"`
+	Nickname      string `json:"nickname" binding:"omitempty,max=50"`
+	IsFavourite   bool   `json:"is_favourite"`
+}
+
+// UpdateBeneficiaryRequest changes only the fields that are set. New account
+// details are checked against the bank before they are saved.
+type UpdateBeneficiaryRequest struct {
+	Nickname      *string `json:"nickname" binding:"omitempty,max=50"`
+	IsFavourite   *bool   `json:"is_favourite"`
+	BankCode      *string `json:"bank_code"`
+	AccountNumber *string `json:"account_number"`
+	AccountName   *string `json:"account_name" binding:"omitempty,max=255"`
 }
 
 type AddBeneficiaryResponse struct {
@@ -59,9 +110,20 @@ type FetchBeneficiariesResponse struct {
 }
 
 type BeneficiaryResponseStruct struct {
-	BankCode      string `json:"bank_code"`
-	AccountNumber string `json:"account_number"`
-	AccountName   string `json:"account_name"`
+	ID            string     `json:"id"`
+	BankCode      string     `json:"bank_code"`
+	AccountNumber string     `json:"account_number"`
+	AccountName   string     `json:"account_name"`
+	Nickname      string     `json:"nickname,omitempty"`
+	IsFavourite   bool       `json:"is_favourite"`
+	LastUsedAt    *time.Time `json:"last_used_at,omitempty"`
+	VerifiedAt    *time.Time `json:"verified_at,omitempty"`
+}
+
+type VerifyBeneficiaryResponse struct {
+	Beneficiary  BeneficiaryResponseStruct `json:"beneficiary"`
+	NameChanged  bool                      `json:"name_changed"`
+	PreviousName string                    `json:"previous_name,omitempty"`
 }
 
 type ProvidusCredit struct {
@@ -81,6 +143,20 @@ type ProvidusCredit struct {
 	BeneficiaryAccountNo   string `json:"beneficiaryAccountNumber"`
 }
 
+type WebhookOutcome struct {
+	Status        WebhookInboxStatus `json:"status"`
+	TransactionID *string            `json:"transaction_id,omitempty"`
+	Duplicate     bool               `json:"duplicate"`
+}
+
+type WebhookReplaySummary struct {
+	Scanned   int `json:"scanned"`
+	Processed int `json:"processed"`
+	Ignored   int `json:"ignored"`
+	Failed    int `json:"failed"`
+}
+
+// InitiatedDepositRequest takes the amount in naira, like DepositObj returns it.
 type InitiatedDepositRequest struct {
 	ExpectedAmount int64 `json:"expected_amount" binding:"omitempty,gt=0"`
 }
@@ -106,8 +182,8 @@ type PolledDepositResponse struct {
 type DepositObj struct {
 	TrackingID     string                `json:"tracking_id" binding:"required"`
 	Status         ExpectedDepositStatus `json:"status" binding:"required"`
-	ExpectedAmount int64                 `json:"expected_amount" binding:"required"`
-	ActualAmount   int64                 `json:"actual_amount" binding:"required"`
+	ExpectedAmount float64               `json:"expected_amount" binding:"required"`
+	ActualAmount   float64               `json:"actual_amount" binding:"required"`
 	ExpiresAt      time.Time             `json:"expires_at" binding:"required"`
 	Transaction    TransactionObj        `json:"transaction" binding:"required"`
 }
@@ -120,28 +196,55 @@ type TransactionObj struct {
 	CreatedAt time.Time `json:"created_at" binding:"required"`
 }
 
+// BulkTransferRecipientInfo amounts are in naira from clients; the service
+// converts them to kobo before they reach the provider.
 type BulkTransferRecipientInfo struct {
 	Amount        int64          `json:"amount" binding:"required,gt=0"`
 	SortCode      string         `json:"sort_code" binding:"required"`
 	Narration     *string        `json:"narration" binding:"omitempty,max=255"`
 	AccountNumber string         `json:"account_number" binding:"required"`
-	AccountName   *string        `json:"account_name" binding:"required,max=255"`
+	AccountName   *string        `json:"account_name" binding:"omitempty,max=255"` // replaced by the name lookup
 	Metadata      map[string]any `json:"metadata" binding:"omitempty"`
+	Reference     string         `json:"-"` // our transaction reference for this recipient
 }
 
 type BulkTransferRequest struct {
-	RecipientInfo  []BulkTransferRecipientInfo `json:"recipient_info" binding:"required"`
+	RecipientInfo  []BulkTransferRecipientInfo `json:"recipient_info" binding:"required,dive"`
 	TransactionPin string                      `json:"transaction_pin" binding:"required"`
+	OTPCode        string                      `json:"otp_code"` // authenticator code, required when the batch total is high-value
 }
 
-type BulkTransferResponse struct {
-	Status  string `json:"status"`
-	Message string `json:"message"`
-	Data    struct {
-		All      []BulkTransferResult `json:"all"`
-		Rejected []BulkTransferResult `json:"rejected"`
-		Accepted []BulkTransferResult `json:"accepted"`
-	} `json:"data"`
+// BulkRecipientError explains why a recipient failed validation. Row is 1-based.
+type BulkRecipientError struct {
+	Row           int    `json:"row"`
+	AccountNumber string `json:"account_number"`
+	SortCode      string `json:"sort_code"`
+	Reason        string `json:"reason"`
+}
+
+type TransferBatchRecipient struct {
+	TransactionID string                        `json:"transaction_id"`
+	Reference     string                        `json:"reference"`
+	AccountNumber string                        `json:"account_number"`
+	AccountName   string                        `json:"account_name"`
+	SortCode      string                        `json:"sort_code"`
+	Amount        float64                       `json:"amount"`
+	Charges       float64                       `json:"charges"`
+	Status        transaction.TransactionStatus `json:"status"`
+}
+
+type TransferBatchResponse struct {
+	BatchID        string                   `json:"batch_id"`
+	Status         TransferBatchStatus      `json:"status"`
+	TotalAmount    float64                  `json:"total_amount"`
+	RecipientCount int                      `json:"recipient_count"`
+	Successful     int                      `json:"successful"`
+	Failed         int                      `json:"failed"`
+	Pending        int                      `json:"pending"`
+	Message        string                   `json:"message,omitempty"`
+	CreatedAt      time.Time                `json:"created_at"`
+	Recipients     []TransferBatchRecipient `json:"recipients"`
+	InvalidRows    []BulkRecipientError     `json:"invalid_rows,omitempty"`
 }
 
 type ProvidusBatchTransferResponse struct {
@@ -166,14 +269,170 @@ type ProvidusBatchTransferResult struct {
 	Total         int64  `json:"total"`
 }
 
-type BulkTransferResult struct {
-	Amount        int64  `json:"amount"`
-	VAT           int64  `json:"vat"`
-	SortCode      string `json:"sort_code"`
-	Reference     string `json:"reference"`
-	Narration     string `json:"narration"`
-	AccountName   string `json:"accoun_name"`
-	Fee           int64  `json:"fee"`
-	AccountNumber string `json:"accoun_number"`
-	Total         int64  `json:"total"`
+// CreateScheduledTransferRequest amounts are in naira, like TransferRequest.
+type CreateScheduledTransferRequest struct {
+	Amount         int64             `json:"amount" binding:"required,gt=0"`
+	SortCode       string            `json:"sort_code" binding:"required"`
+	AccountNumber  string            `json:"account_number" binding:"required"`
+	AccountName    string            `json:"account_name" binding:"required,max=255"`
+	Narration      *string           `json:"narration" binding:"omitempty,max=255"`
+	Frequency      ScheduleFrequency `json:"frequency" binding:"required,oneof=once daily weekly monthly"`
+	StartAt        time.Time         `json:"start_at" binding:"required"`
+	EndAt          *time.Time        `json:"end_at"`
+	TransactionPin string            `json:"transaction_pin" binding:"required"`
+	OTPCode        string            `json:"otp_code"` // authenticator code, required on high-value schedules once enrolled
+}
+
+// UpdateScheduledTransferRequest needs the PIN again since it changes what the
+// user authorised.
+type UpdateScheduledTransferRequest struct {
+	Amount         *int64     `json:"amount" binding:"omitempty,gt=0"`
+	Narration      *string    `json:"narration" binding:"omitempty,max=255"`
+	EndAt          *time.Time `json:"end_at"`
+	TransactionPin string     `json:"transaction_pin" binding:"required"`
+	OTPCode        string     `json:"otp_code"` // authenticator code, required on high-value schedules once enrolled
+}
+
+type ScheduledTransferResponse struct {
+	ID            string                         `json:"id"`
+	Amount        float64                        `json:"amount"`
+	SortCode      string                         `json:"sort_code"`
+	AccountNumber string                         `json:"account_number"`
+	AccountName   string                         `json:"account_name"`
+	Narration     string                         `json:"narration,omitempty"`
+	Frequency     ScheduleFrequency              `json:"frequency"`
+	StartAt       time.Time                      `json:"start_at"`
+	EndAt         *time.Time                     `json:"end_at,omitempty"`
+	NextRunAt     *time.Time                     `json:"next_run_at,omitempty"`
+	Status        ScheduledTransferStatus        `json:"status"`
+	RunCount      int                            `json:"run_count"`
+	LastRunAt     *time.Time                     `json:"last_run_at,omitempty"`
+	LastRunStatus ScheduledRunStatus             `json:"last_run_status,omitempty"`
+	CreatedAt     time.Time                      `json:"created_at"`
+	Runs          []ScheduledTransferRunResponse `json:"runs,omitempty"`
+}
+
+type ScheduledTransferRunResponse struct {
+	ID            string             `json:"id"`
+	TransactionID *string            `json:"transaction_id,omitempty"`
+	ScheduledFor  time.Time          `json:"scheduled_for"`
+	Attempt       int                `json:"attempt"`
+	Status        ScheduledRunStatus `json:"status"`
+	Error         string             `json:"error,omitempty"`
+	CreatedAt     time.Time          `json:"created_at"`
+}
+
+type ScheduledTransferSummary struct {
+	Due        int `json:"due"`
+	Successful int `json:"successful"`
+	Processing int `json:"processing"`
+	Retrying   int `json:"retrying"`
+	Failed     int `json:"failed"`
+}
+
+// P2PTransferRequest amounts are in naira. RecipientType may be left empty to
+// infer it from the recipient value.
+type P2PTransferRequest struct {
+	Recipient      string           `json:"recipient" binding:"required"`
+	RecipientType  P2PRecipientType `json:"recipient_type" binding:"omitempty,oneof=phone username account_number"`
+	Amount         int64            `json:"amount" binding:"required,gt=0"`
+	Note           *string          `json:"note" binding:"omitempty,max=140"`
+	TransactionPin string           `json:"transaction_pin" binding:"required"`
+	OTPCode        string           `json:"otp_code"` // authenticator code, required on high-value transfers once enrolled
+}
+
+type P2PRecipient struct {
+	AccountNumber string `json:"account_number"`
+	AccountName   string `json:"account_name"`
+	BankName      string `json:"bank_name"`
+}
+
+type P2PTransferResponse struct {
+	TransactionID string                        `json:"transaction_id"`
+	Reference     string                        `json:"reference"`
+	Amount        float64                       `json:"amount"`
+	Recipient     P2PRecipient                  `json:"recipient"`
+	Note          string                        `json:"note,omitempty"`
+	Status        transaction.TransactionStatus `json:"status"`
+	BalanceAfter  float64                       `json:"balance_after"`
+	CreatedAt     time.Time                     `json:"created_at"`
+}
+
+// CreatePaymentRequestRequest amounts are in naira.
+type CreatePaymentRequestRequest struct {
+	Amount         int64   `json:"amount" binding:"required,gt=0"`
+	Memo           *string `json:"memo" binding:"omitempty,max=140"`
+	ExpiresInHours int     `json:"expires_in_hours" binding:"omitempty,gt=0,lte=720"`
+}
+
+type PaymentRequestResponse struct {
+	ID            string               `json:"id"`
+	Code          string               `json:"code"`
+	Amount        float64              `json:"amount"`
+	Memo          string               `json:"memo,omitempty"`
+	Status        PaymentRequestStatus `json:"status"`
+	Link          string               `json:"link"`
+	QRPayload     string               `json:"qr_payload"`
+	Account       AccountObj           `json:"account"`
+	TransactionID *string              `json:"transaction_id,omitempty"`
+	PaidAt        *time.Time           `json:"paid_at,omitempty"`
+	ExpiresAt     time.Time            `json:"expires_at"`
+	CreatedAt     time.Time            `json:"created_at"`
+}
+
+// PayLinkResponse is what a payer sees when they open a pay link.
+type PayLinkResponse struct {
+	Code          string               `json:"code"`
+	RequesterName string               `json:"requester_name"`
+	Amount        float64              `json:"amount"`
+	Memo          string               `json:"memo,omitempty"`
+	Status        PaymentRequestStatus `json:"status"`
+	Account       AccountObj           `json:"account"`
+	ExpiresAt     time.Time            `json:"expires_at"`
+}
+
+type PayPaymentRequestRequest struct {
+	TransactionPin string `json:"transaction_pin" binding:"required"`
+	OTPCode        string `json:"otp_code"` // authenticator code, required on high-value payments once enrolled
+}
+
+type ChangeWalletStatusRequest struct {
+	Action     WalletStatusAction `json:"action" binding:"required,oneof=freeze unfreeze post_no_debit close"`
+	ReasonCode WalletStatusReason `json:"reason_code" binding:"required"`
+	Note       string             `json:"note" binding:"omitempty,max=500"`
+	Actor      string             `json:"actor" binding:"required,max=255"`
+}
+
+type WalletStatusEventResponse struct {
+	ID              string             `json:"id"`
+	FromStatus      WalletStatus       `json:"from_status"`
+	ToStatus        WalletStatus       `json:"to_status"`
+	ReasonCode      WalletStatusReason `json:"reason_code"`
+	Note            string             `json:"note,omitempty"`
+	Actor           string             `json:"actor"`
+	BalanceAtChange float64            `json:"balance_at_change"`
+	CreatedAt       time.Time          `json:"created_at"`
+}
+
+type WalletStatusResponse struct {
+	AccountNumber string                      `json:"account_number"`
+	Status        WalletStatus                `json:"status"`
+	History       []WalletStatusEventResponse `json:"history"`
+}
+
+type CloseWalletRequest struct {
+	TransactionPin string `json:"transaction_pin" binding:"required"`
+	BankCode       string `json:"bank_code" binding:"required"`
+	AccountNumber  string `json:"account_number" binding:"required,len=10,numeric"`
+	Reason         string `json:"reason" binding:"omitempty,max=500"`
+	OTPCode        string `json:"otp_code"` // authenticator code, required when the sweep is high-value
+}
+
+type CloseWalletResponse struct {
+	Status             WalletStatus `json:"status"`
+	SweptAmount        float64      `json:"swept_amount"`
+	SweepTransactionID string       `json:"sweep_transaction_id,omitempty"`
+	DestinationAccount string       `json:"destination_account,omitempty"`
+	DestinationName    string       `json:"destination_name,omitempty"`
+	RemainingBalance   float64      `json:"remaining_balance"`
 }
@@ -1,6 +1,8 @@
 package wallet
 
 import (
+	"errors"
+	"io"
 	"log"
 	appErr "neat_mobile_app_backend/internal/errors"
 	"neat_mobile_app_backend/internal/middleware"
@@ -20,7 +22,17 @@ func NewHandler(service *Service) *Handler {
 }
 
 func (h *Handler) FetchBanks(c *gin.Context) {
-	banks, err := h.service.FetchBanks(c.Request.Context())
+	var query BankSearchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		mapped := response.MapError(appErr.ErrInvalidQueryParameter)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	banks, err := h.service.FetchBanks(c.Request.Context(), query.Query)
 	if err != nil {
 		mapped := response.MapError(err)
 		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
@@ -184,11 +196,19 @@ func (h *Handler) HandleCreditWebhook(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.HandleCreditWebhook(c.Request.Context(), &payload); err != nil {
+	outcome, err := h.service.HandleCreditWebhook(c.Request.Context(), &payload)
+	if err != nil {
 		log.Printf("providus credit webhook: processing error: %v", err)
 	}
+	if outcome == nil {
+		c.JSON(http.StatusOK, gin.H{"status": true})
+		return
+	}
+	if outcome.Duplicate {
+		log.Printf("providus credit webhook: duplicate callback for %s answered from inbox (%s)", creditProviderRef(&payload), outcome.Status)
+	}
 
-	c.JSON(http.StatusOK, gin.H{"status": true})
+	c.JSON(http.StatusOK, gin.H{"status": true, "duplicate": outcome.Duplicate, "outcome": outcome.Status})
 }
 
 func (h *Handler) GetBeneficiaries(c *gin.Context) {
@@ -206,12 +226,8 @@ func (h *Handler) GetBeneficiaries(c *gin.Context) {
 	}
 
 	result := make([]BeneficiaryResponseStruct, len(beneficiaries))
-	for i, b := range beneficiaries {
-		result[i] = BeneficiaryResponseStruct{
-			BankCode:      b.BankCode,
-			AccountNumber: b.AccountNumber,
-			AccountName:   b.AccountName,
-		}
+	for i := range beneficiaries {
+		result[i] = toBeneficiaryResponse(&beneficiaries[i])
 	}
 
 	response := &FetchBeneficiariesResponse{
@@ -223,6 +239,101 @@ func (h *Handler) GetBeneficiaries(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+func (h *Handler) UpdateBeneficiary(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrMissingUserID)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	var req UpdateBeneficiaryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		mapped := response.MapError(appErr.ErrInvalidRequestBody)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	beneficiary, err := h.service.UpdateBeneficiary(c.Request.Context(), mobileUserID, c.Param("id"), &req)
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	dto := toBeneficiaryResponse(beneficiary)
+	c.JSON(http.StatusOK, response.APIResponse[BeneficiaryResponseStruct]{
+		Status:  "success",
+		Message: "Beneficiary updated successfully",
+		Data:    &dto,
+	})
+}
+
+func (h *Handler) VerifyBeneficiary(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrMissingUserID)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	result, err := h.service.VerifyBeneficiary(c.Request.Context(), mobileUserID, c.Param("id"))
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[VerifyBeneficiaryResponse]{
+		Status:  "success",
+		Message: "Beneficiary verified successfully",
+		Data:    result,
+	})
+}
+
+func (h *Handler) DeleteBeneficiary(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrMissingUserID)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	if err := h.service.DeleteBeneficiary(c.Request.Context(), mobileUserID, c.Param("id")); err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[any]{
+		Status:  "success",
+		Message: "Beneficiary deleted successfully",
+	})
+}
+
+// InitiateBulkTransfer accepts either a JSON recipient list or a multipart
+// upload with a CSV or Excel sheet in the "recipients" field.
 func (h *Handler) InitiateBulkTransfer(c *gin.Context) {
 	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
 	if mobileUserID == "" {
@@ -243,9 +354,12 @@ func (h *Handler) InitiateBulkTransfer(c *gin.Context) {
 			return
 		}
 
-		fileHeader, err := c.FormFile("recipients_excel")
+		fileHeader, err := c.FormFile("recipients")
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "recipients_excel file is required"})
+			fileHeader, err = c.FormFile("recipients_excel")
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "recipients file is required"})
 			return
 		}
 
@@ -256,7 +370,13 @@ func (h *Handler) InitiateBulkTransfer(c *gin.Context) {
 		}
 		defer file.Close()
 
-		recipients, err := parseExcel(file)
+		parse := parseExcel
+		if strings.HasSuffix(strings.ToLower(fileHeader.Filename), ".csv") ||
+			strings.Contains(fileHeader.Header.Get("Content-Type"), "csv") {
+			parse = parseCSV
+		}
+
+		recipients, err := parse(file)
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
@@ -280,11 +400,695 @@ func (h *Handler) InitiateBulkTransfer(c *gin.Context) {
 	resp, err := h.service.InitiateBulkTransfer(c.Request.Context(), mobileUserID, &req)
 	if err != nil {
 		mapped := response.MapError(err)
+		apiResp := response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		}
+		if errors.Is(err, appErr.ErrInvalidBulkRecipients) && resp != nil {
+			var data any = resp
+			apiResp.Data = &data
+		}
+		c.AbortWithStatusJSON(mapped.Status, apiResp)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[TransferBatchResponse]{
+		Status:  "success",
+		Message: "Bulk transfer submitted",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) GetTransferBatch(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrMissingUserID)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	resp, err := h.service.GetTransferBatch(c.Request.Context(), mobileUserID, c.Param("batch_id"))
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[TransferBatchResponse]{
+		Status:  "success",
+		Message: "Transfer batch fetched successfully",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) ResolveP2PRecipient(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrMissingUserID)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	recipient := strings.TrimSpace(c.Query("recipient"))
+	if recipient == "" {
+		mapped := response.MapError(appErr.ErrInvalidRequestBody)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	resp, err := h.service.ResolveP2PRecipient(c.Request.Context(), mobileUserID, recipient, P2PRecipientType(c.Query("recipient_type")))
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[P2PRecipient]{
+		Status:  "success",
+		Message: "Recipient resolved successfully",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) P2PTransfer(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrMissingUserID)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	var req P2PTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		mapped := response.MapError(appErr.ErrInvalidRequestBody)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	resp, err := h.service.P2PTransfer(c.Request.Context(), mobileUserID, &req)
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[P2PTransferResponse]{
+		Status:  "success",
+		Message: "Transfer successful",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) CreatePaymentRequest(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrMissingUserID)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	var req CreatePaymentRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		mapped := response.MapError(appErr.ErrInvalidRequestBody)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	resp, err := h.service.CreatePaymentRequest(c.Request.Context(), mobileUserID, &req)
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.APIResponse[PaymentRequestResponse]{
+		Status:  "success",
+		Message: "Payment request created",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) ListPaymentRequests(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrMissingUserID)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	resp, err := h.service.ListPaymentRequests(c.Request.Context(), mobileUserID)
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[[]PaymentRequestResponse]{
+		Status:  "success",
+		Message: "Payment requests fetched successfully",
+		Data:    &resp,
+	})
+}
+
+func (h *Handler) GetPaymentRequest(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrMissingUserID)
 		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
 			Status: "error",
 			Error:  &mapped.Error,
 		})
+		return
 	}
 
-	c.JSON(http.StatusOK, resp)
+	resp, err := h.service.GetPaymentRequest(c.Request.Context(), mobileUserID, c.Param("id"))
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[PaymentRequestResponse]{
+		Status:  "success",
+		Message: "Payment request fetched successfully",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) CancelPaymentRequest(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrMissingUserID)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	if err := h.service.CancelPaymentRequest(c.Request.Context(), mobileUserID, c.Param("id")); err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[any]{
+		Status:  "success",
+		Message: "Payment request cancelled",
+	})
+}
+
+// GetPayLink is public so external payers can open a shared link.
+func (h *Handler) GetPayLink(c *gin.Context) {
+	resp, err := h.service.GetPayLink(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[PayLinkResponse]{
+		Status:  "success",
+		Message: "Payment request fetched successfully",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) PayPaymentRequest(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrMissingUserID)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	var req PayPaymentRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		mapped := response.MapError(appErr.ErrInvalidRequestBody)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	resp, err := h.service.PayPaymentRequest(c.Request.Context(), mobileUserID, c.Param("code"), &req)
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[P2PTransferResponse]{
+		Status:  "success",
+		Message: "Payment request paid",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) InitiateDeposit(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrMissingUserID)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	var req InitiatedDepositRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		mapped := response.MapError(appErr.ErrInvalidRequestBody)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	deviceID := strings.TrimSpace(c.Request.Header.Get("X-Device-ID"))
+	resp, err := h.service.InitiateDeposit(c.Request.Context(), deviceID, mobileUserID, req)
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[InitiatedDepositResponse]{
+		Status:  "success",
+		Message: "Deposit initiated successfully",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) GetExpectedDeposit(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrMissingUserID)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	deposit, err := h.service.GetExpectedDeposit(c.Request.Context(), mobileUserID, c.Param("tracking_id"))
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[DepositObj]{
+		Status:  "success",
+		Message: "Deposit fetched successfully",
+		Data:    deposit,
+	})
+}
+
+func (h *Handler) ReverseTransaction(c *gin.Context) {
+	var req ReverseTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		mapped := response.MapError(appErr.ErrInvalidRequestBody)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	resp, err := h.service.ReverseTransaction(c.Request.Context(), c.Param("id"), req.Reason)
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[ReversalResponse]{
+		Status:  "success",
+		Message: "Transaction reversed successfully",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) CreateScheduledTransfer(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrMissingUserID)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	var req CreateScheduledTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		mapped := response.MapError(appErr.ErrInvalidRequestBody)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	resp, err := h.service.CreateScheduledTransfer(c.Request.Context(), mobileUserID, &req)
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[ScheduledTransferResponse]{
+		Status:  "success",
+		Message: "Transfer scheduled successfully",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) ListScheduledTransfers(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrMissingUserID)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	schedules, err := h.service.ListScheduledTransfers(c.Request.Context(), mobileUserID)
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[[]ScheduledTransferResponse]{
+		Status:  "success",
+		Message: "Scheduled transfers fetched successfully",
+		Data:    &schedules,
+	})
+}
+
+func (h *Handler) GetScheduledTransfer(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrMissingUserID)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	resp, err := h.service.GetScheduledTransfer(c.Request.Context(), mobileUserID, c.Param("id"))
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[ScheduledTransferResponse]{
+		Status:  "success",
+		Message: "Scheduled transfer fetched successfully",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) UpdateScheduledTransfer(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrMissingUserID)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	var req UpdateScheduledTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		mapped := response.MapError(appErr.ErrInvalidRequestBody)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	resp, err := h.service.UpdateScheduledTransfer(c.Request.Context(), mobileUserID, c.Param("id"), &req)
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[ScheduledTransferResponse]{
+		Status:  "success",
+		Message: "Scheduled transfer updated successfully",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) PauseScheduledTransfer(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrMissingUserID)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	resp, err := h.service.PauseScheduledTransfer(c.Request.Context(), mobileUserID, c.Param("id"))
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[ScheduledTransferResponse]{
+		Status:  "success",
+		Message: "Scheduled transfer paused",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) ResumeScheduledTransfer(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrMissingUserID)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	resp, err := h.service.ResumeScheduledTransfer(c.Request.Context(), mobileUserID, c.Param("id"))
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[ScheduledTransferResponse]{
+		Status:  "success",
+		Message: "Scheduled transfer resumed",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) CancelScheduledTransfer(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrMissingUserID)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	if err := h.service.CancelScheduledTransfer(c.Request.Context(), mobileUserID, c.Param("id")); err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[any]{
+		Status:  "success",
+		Message: "Scheduled transfer cancelled",
+	})
+}
+
+func (h *Handler) CloseWallet(c *gin.Context) {
+	mobileUserID := strings.TrimSpace(c.GetString(middleware.UserIDContextKey))
+	if mobileUserID == "" {
+		mapped := response.MapError(appErr.ErrMissingUserID)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	var req CloseWalletRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		mapped := response.MapError(appErr.ErrInvalidRequestBody)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	resp, err := h.service.CloseWallet(c.Request.Context(), mobileUserID, &req)
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[CloseWalletResponse]{
+		Status:  "success",
+		Message: "Account closed successfully",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) GetWalletStatus(c *gin.Context) {
+	resp, err := h.service.GetWalletStatus(c.Request.Context(), c.Param("account_number"))
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[WalletStatusResponse]{
+		Status:  "success",
+		Message: "Wallet status fetched successfully",
+		Data:    resp,
+	})
+}
+
+func (h *Handler) ChangeWalletStatus(c *gin.Context) {
+	var req ChangeWalletStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		mapped := response.MapError(appErr.ErrInvalidRequestBody)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	resp, err := h.service.ChangeWalletStatus(c.Request.Context(), c.Param("account_number"), &req)
+	if err != nil {
+		mapped := response.MapError(err)
+		c.AbortWithStatusJSON(mapped.Status, response.APIResponse[any]{
+			Status: "error",
+			Error:  &mapped.Error,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.APIResponse[WalletStatusResponse]{
+		Status:  "success",
+		Message: "Wallet status updated successfully",
+		Data:    resp,
+	})
 }
@@ -1,12 +1,24 @@
 package wallet
 
 import (
+	"crypto/rand"
 	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	appErr "neat_mobile_app_backend/internal/errors"
+	"neat_mobile_app_backend/internal/modules/transaction"
+	"neat_mobile_app_backend/internal/phone"
+	"neat_mobile_app_backend/internal/types"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
+	"github.com/google/uuid"
 	"github.com/xuri/excelize/v2"
 )
 
@@ -100,3 +112,557 @@ func parseExcel(reader io.Reader) ([]BulkTransferRecipientInfo, error) {
 
 	return recipients, nil
 }
+
+func creditProviderRef(payload *ProvidusCredit) string {
+	if ref := strings.TrimSpace(payload.TranID); ref != "" {
+		return ref
+	}
+	return strings.TrimSpace(payload.SessionID)
+}
+
+func creditPayloadMap(payload *ProvidusCredit) types.JSONMap {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return types.JSONMap{}
+	}
+	var out types.JSONMap
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return types.JSONMap{}
+	}
+	return out
+}
+
+func creditPayloadFromMap(m types.JSONMap) (*ProvidusCredit, error) {
+	raw, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	var payload ProvidusCredit
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
+
+// pickExpectedDeposit chooses the open expected deposit a credit most likely
+// settles. A payment request whose code appears in the narration wins
+// outright. Otherwise only deposits whose outstanding amount is within
+// tolerance of the credit qualify, the closest first and oldest on ties.
+// Candidates must be ordered by creation time. Rows without an expected
+// amount accept any credit.
+func pickExpectedDeposit(candidates []ExpectedDeposit, amount int64, narration string, tolerance int64) *ExpectedDeposit {
+	narration = strings.ToUpper(narration)
+	var best *ExpectedDeposit
+	var bestDistance int64
+	for i := range candidates {
+		code := candidates[i].PaymentRequestCode
+		if code != "" && strings.Contains(narration, code) {
+			return &candidates[i]
+		}
+
+		distance := int64(0)
+		if candidates[i].ExpectedAmount > 0 {
+			distance = absInt64(candidates[i].ExpectedAmount - candidates[i].ActualAmount - amount)
+		}
+		if distance > tolerance {
+			continue
+		}
+		if best == nil || distance < bestDistance {
+			best = &candidates[i]
+			bestDistance = distance
+		}
+	}
+	return best
+}
+
+// applyDepositCredit adds the credit to the expected deposit and returns its
+// new status. Totals within tolerance of the expected amount count as matched.
+func applyDepositCredit(deposit *ExpectedDeposit, amount, tolerance int64) ExpectedDepositStatus {
+	deposit.ActualAmount += amount
+
+	switch diff := deposit.ActualAmount - deposit.ExpectedAmount; {
+	case deposit.ExpectedAmount == 0, absInt64(diff) <= tolerance:
+		deposit.Status = ExpectedDepositStatusMatched
+	case diff < 0:
+		deposit.Status = ExpectedDepositStatusPartiallyPaid
+	default:
+		deposit.Status = ExpectedDepositStatusOverpaid
+	}
+	return deposit.Status
+}
+
+func absInt64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// advanceOccurrence returns the occurrence after from. Monthly schedules keep
+// their day of month, falling back to the last day in shorter months.
+func advanceOccurrence(freq ScheduleFrequency, dayOfMonth int, from time.Time) time.Time {
+	switch freq {
+	case ScheduleFrequencyDaily:
+		return from.AddDate(0, 0, 1)
+	case ScheduleFrequencyWeekly:
+		return from.AddDate(0, 0, 7)
+	default:
+		y, m, _ := from.Date()
+		firstOfNext := time.Date(y, m+1, 1, from.Hour(), from.Minute(), from.Second(), 0, from.Location())
+		lastDay := firstOfNext.AddDate(0, 1, -1).Day()
+		return time.Date(firstOfNext.Year(), firstOfNext.Month(), min(dayOfMonth, lastDay),
+			from.Hour(), from.Minute(), from.Second(), 0, from.Location())
+	}
+}
+
+// nextOccurrence returns the first occurrence of the schedule after now,
+// skipping any that were missed. ok is false once the schedule has no more
+// occurrences.
+func nextOccurrence(schedule *ScheduledTransfer, now time.Time) (next time.Time, ok bool) {
+	if schedule.Frequency == ScheduleFrequencyOnce {
+		return time.Time{}, false
+	}
+
+	next = advanceOccurrence(schedule.Frequency, schedule.DayOfMonth, schedule.OccurrenceAt)
+	for !next.After(now) {
+		next = advanceOccurrence(schedule.Frequency, schedule.DayOfMonth, next)
+	}
+	if schedule.EndAt != nil && next.After(*schedule.EndAt) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+// matchBatchResults pairs provider batch results with our recipient rows, by
+// our reference when the provider echoes it and by account and bank otherwise.
+// Each row is matched at most once.
+func matchBatchResults(txs []transaction.Transaction, results []ProvidusBatchTransferResult) map[string]ProvidusBatchTransferResult {
+	matched := make(map[string]ProvidusBatchTransferResult, len(results))
+	for _, result := range results {
+		for i := range txs {
+			tx := &txs[i]
+			if _, taken := matched[tx.ID]; taken {
+				continue
+			}
+			if tx.Reference == result.Reference ||
+				(tx.CounterpartyAccount == result.AccountNumber && tx.CounterpartyBank == result.SortCode) {
+				matched[tx.ID] = result
+				break
+			}
+		}
+	}
+	return matched
+}
+
+func findTransaction(txs []transaction.Transaction, id string) *transaction.Transaction {
+	for i := range txs {
+		if txs[i].ID == id {
+			return &txs[i]
+		}
+	}
+	return nil
+}
+
+// summarizeBatch derives the batch outcome from its recipient rows.
+func summarizeBatch(batch *TransferBatch, txs []transaction.Transaction) *TransferBatchResponse {
+	resp := &TransferBatchResponse{
+		BatchID:        batch.ID,
+		TotalAmount:    float64(batch.TotalAmount) / 100,
+		RecipientCount: batch.RecipientCount,
+		Message:        batch.ProviderMessage,
+		CreatedAt:      batch.CreatedAt,
+		Recipients:     make([]TransferBatchRecipient, 0, len(txs)),
+	}
+
+	for _, tx := range txs {
+		switch tx.Status {
+		case transaction.TransactionStatusSuccessful:
+			resp.Successful++
+		case transaction.TransactionStatusFailed, transaction.TransactionStatusReversed:
+			resp.Failed++
+		default:
+			resp.Pending++
+		}
+		resp.Recipients = append(resp.Recipients, TransferBatchRecipient{
+			TransactionID: tx.ID,
+			Reference:     tx.Reference,
+			AccountNumber: tx.CounterpartyAccount,
+			AccountName:   tx.CounterpartyName,
+			SortCode:      tx.CounterpartyBank,
+			Amount:        float64(tx.Amount) / 100,
+			Charges:       float64(tx.Charges+tx.VAT) / 100,
+			Status:        tx.Status,
+		})
+	}
+
+	switch {
+	case resp.Pending > 0:
+		resp.Status = TransferBatchStatusProcessing
+	case resp.Successful == len(txs):
+		resp.Status = TransferBatchStatusCompleted
+	case resp.Failed == len(txs):
+		resp.Status = TransferBatchStatusFailed
+	default:
+		resp.Status = TransferBatchStatusPartiallyCompleted
+	}
+	return resp
+}
+
+// classifyP2PRecipient works out how a P2P recipient is addressed when the
+// client did not say, and normalises the value for lookup. Ten digits is read
+// as an account number (NUBAN); other digit strings as a phone number.
+func classifyP2PRecipient(value string, kind P2PRecipientType) (P2PRecipientType, string, error) {
+	value = strings.TrimSpace(value)
+	if kind == "" {
+		digits := strings.TrimPrefix(value, "+")
+		_, err := strconv.ParseUint(digits, 10, 64)
+		switch {
+		case err == nil && len(digits) == 10:
+			kind = P2PRecipientAccountNumber
+		case err == nil:
+			kind = P2PRecipientPhone
+		default:
+			kind = P2PRecipientUsername
+		}
+	}
+
+	switch kind {
+	case P2PRecipientPhone:
+		normalized, err := phone.NormalizeNigerianNumber(value)
+		return kind, normalized, err
+	case P2PRecipientUsername:
+		value = strings.TrimPrefix(value, "@")
+	}
+	if value == "" {
+		return kind, "", appErr.ErrInvalidRequestBody
+	}
+	return kind, value, nil
+}
+
+// payLinkAlphabet leaves out characters that are easy to misread.
+const payLinkAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+func newPaymentRequestCode() (string, error) {
+	buf := make([]byte, paymentRequestCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	for i, b := range buf {
+		buf[i] = payLinkAlphabet[int(b)%len(payLinkAlphabet)]
+	}
+	return string(buf), nil
+}
+
+// effectivePaymentRequestStatus reports a pending request as expired once its
+// expiry passes, before the expiry job gets to it.
+func effectivePaymentRequestStatus(request *PaymentRequest, now time.Time) PaymentRequestStatus {
+	if request.Status == PaymentRequestStatusPending && !request.ExpiresAt.After(now) {
+		return PaymentRequestStatusExpired
+	}
+	return request.Status
+}
+
+// paymentRequestQRPayload encodes what a scanner needs to pay the request in
+// the app or by bank transfer.
+func paymentRequestQRPayload(request *PaymentRequest, link string, account AccountObj) string {
+	raw, _ := json.Marshal(map[string]any{
+		"type":           "payment_request",
+		"code":           request.Code,
+		"link":           link,
+		"amount":         float64(request.Amount) / 100,
+		"account_number": account.AccountNumber,
+		"bank_code":      account.BankCode,
+	})
+	return string(raw)
+}
+
+// awaitingProvider reports whether tx is an outbound provider transfer whose
+// outcome the provider has not confirmed yet.
+func awaitingProvider(tx *transaction.Transaction) bool {
+	if tx.Type != transaction.TransactionTypeDebit || tx.Source == transaction.TransactionSourceP2P {
+		return false
+	}
+	if tx.Category != transaction.TransactionCategoryTransferTo && tx.Category != transaction.TransactionCategoryLoanRepayment {
+		return false
+	}
+	ambiguous, _ := tx.Metadata["ambiguous"].(bool)
+	return tx.Status == transaction.TransactionStatusPending ||
+		(tx.Status == transaction.TransactionStatusFailed && ambiguous)
+}
+
+// refundableAtProvider reports whether reversing tx has to put money back into
+// the provider wallet first: the debit settled there and nothing has refunded
+// it yet. A reversal_pending debit was already refunded by its own flow.
+func refundableAtProvider(tx *transaction.Transaction) bool {
+	return tx.Type == transaction.TransactionTypeDebit &&
+		tx.Source != transaction.TransactionSourceP2P &&
+		tx.Status == transaction.TransactionStatusSuccessful
+}
+
+// newP2PTransactions builds the transfer_to and transfer_from pair for an
+// in-app transfer of amount kobo. Each leg gets its own reference, derived
+// from one shared transfer reference kept in their metadata, and is sent to
+// the provider with its wallet movement. The debit starts pending until the
+// provider has moved the money.
+func newP2PTransactions(sender, recipient *CustomerWallet, amount int64, note string) (debit, credit *transaction.Transaction) {
+	reference := uuid.NewString()
+	debit = &transaction.Transaction{
+		ID:                  uuid.NewString(),
+		MobileUserID:        sender.MobileUserID,
+		WalletID:            sender.InternalWalletID,
+		Category:            transaction.TransactionCategoryTransferTo,
+		Type:                transaction.TransactionTypeDebit,
+		Description:         fmt.Sprintf("Transfer to %s", recipient.AccountName),
+		Amount:              amount,
+		Reference:           reference + "-DR",
+		Narration:           &note,
+		CounterpartyAccount: recipient.AccountNumber,
+		CounterpartyName:    recipient.AccountName,
+		CounterpartyBank:    recipient.BankCode,
+		Source:              transaction.TransactionSourceP2P,
+		Status:              transaction.TransactionStatusPending,
+	}
+	credit = &transaction.Transaction{
+		ID:                  uuid.NewString(),
+		MobileUserID:        recipient.MobileUserID,
+		WalletID:            recipient.InternalWalletID,
+		Category:            transaction.TransactionCategoryTransferFrom,
+		Type:                transaction.TransactionTypeCredit,
+		Description:         fmt.Sprintf("Transfer from %s", sender.AccountName),
+		Amount:              amount,
+		Reference:           reference + "-CR",
+		Narration:           &note,
+		CounterpartyAccount: sender.AccountNumber,
+		CounterpartyName:    sender.AccountName,
+		CounterpartyBank:    sender.BankCode,
+		Source:              transaction.TransactionSourceP2P,
+		Status:              transaction.TransactionStatusSuccessful,
+	}
+	debit.Metadata = types.JSONMap{"counterpart_transaction_id": credit.ID, "p2p_reference": reference}
+	credit.Metadata = types.JSONMap{"counterpart_transaction_id": debit.ID, "p2p_reference": reference}
+	return debit, credit
+}
+
+func toBeneficiaryResponse(b *Beneficiary) BeneficiaryResponseStruct {
+	return BeneficiaryResponseStruct{
+		ID:            b.ID,
+		BankCode:      b.BankCode,
+		AccountNumber: b.AccountNumber,
+		AccountName:   b.AccountName,
+		Nickname:      b.Nickname,
+		IsFavourite:   b.IsFavourite,
+		LastUsedAt:    b.LastUsedAt,
+		VerifiedAt:    b.VerifiedAt,
+	}
+}
+
+// sameAccountName compares account names the way banks return them: case,
+// punctuation, spacing and word order are ignored.
+func sameAccountName(a, b string) bool {
+	words := func(name string) []string {
+		fields := strings.FieldsFunc(strings.ToUpper(name), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		sort.Strings(fields)
+		return fields
+	}
+	return slices.Equal(words(a), words(b))
+}
+
+// searchBanks filters entries by a fuzzy match on name or code and returns the
+// best matches first. Equal matches keep the directory's popular-first order.
+func searchBanks(entries []BankEntry, query string) []Bank {
+	query = normalizeBankName(query)
+	if query == "" {
+		banks := make([]Bank, len(entries))
+		for i, entry := range entries {
+			banks[i] = Bank{Code: entry.Code, Name: entry.Name}
+		}
+		return banks
+	}
+
+	type scored struct {
+		bank  Bank
+		score int
+	}
+	matches := make([]scored, 0, len(entries))
+	for _, entry := range entries {
+		if score := bankMatchScore(entry, query); score > 0 {
+			matches = append(matches, scored{bank: Bank{Code: entry.Code, Name: entry.Name}, score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	banks := make([]Bank, len(matches))
+	for i, match := range matches {
+		banks[i] = match.bank
+	}
+	return banks
+}
+
+// bankMatchScore ranks how well a normalised query matches a bank; zero means
+// no match. Initials let "gtb" find "Guaranty Trust Bank".
+func bankMatchScore(entry BankEntry, query string) int {
+	name := normalizeBankName(entry.Name)
+	words := strings.Fields(name)
+	initials := ""
+	for _, word := range words {
+		initials += string([]rune(word)[:1])
+	}
+
+	switch {
+	case entry.Code == query:
+		return 100
+	case name == query:
+		return 90
+	case strings.HasPrefix(name, query):
+		return 80
+	case slices.ContainsFunc(words, func(word string) bool { return strings.HasPrefix(word, query) }):
+		return 70
+	case strings.Contains(name, query):
+		return 60
+	case strings.HasPrefix(initials, strings.ReplaceAll(query, " ", "")):
+		return 50
+	case isSubsequence(strings.ReplaceAll(query, " ", ""), strings.ReplaceAll(name, " ", "")):
+		return 20
+	}
+	return 0
+}
+
+func normalizeBankName(name string) string {
+	fields := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(fields, " ")
+}
+
+func isSubsequence(needle, haystack string) bool {
+	if needle == "" {
+		return false
+	}
+	want := []rune(needle)
+	i := 0
+	for _, r := range haystack {
+		if i < len(want) && want[i] == r {
+			i++
+		}
+	}
+	return i == len(want)
+}
+
+// NormalizeWalletStatus maps a stored status onto our controls; anything we do
+// not restrict, including the provider's own status strings, is active.
+func NormalizeWalletStatus(status string) WalletStatus {
+	switch WalletStatus(strings.ToLower(strings.TrimSpace(status))) {
+	case WalletStatusFrozen:
+		return WalletStatusFrozen
+	case WalletStatusPostNoDebit:
+		return WalletStatusPostNoDebit
+	case WalletStatusClosed:
+		return WalletStatusClosed
+	}
+	return WalletStatusActive
+}
+
+// DebitAllowed reports whether money may leave a wallet in the given status.
+// Every debit path checks it before touching the provider.
+func DebitAllowed(status string) error {
+	switch NormalizeWalletStatus(status) {
+	case WalletStatusFrozen:
+		return appErr.ErrWalletFrozen
+	case WalletStatusPostNoDebit:
+		return appErr.ErrWalletPostNoDebit
+	case WalletStatusClosed:
+		return appErr.ErrWalletClosed
+	}
+	return nil
+}
+
+// IsWalletRestricted reports whether err is a DebitAllowed refusal, which
+// debit paths hand back to the user unchanged.
+func IsWalletRestricted(err error) bool {
+	return errors.Is(err, appErr.ErrWalletFrozen) ||
+		errors.Is(err, appErr.ErrWalletPostNoDebit) ||
+		errors.Is(err, appErr.ErrWalletClosed)
+}
+
+// creditAllowed reports whether an in-app transfer may pay into a wallet.
+func creditAllowed(status string) bool {
+	switch NormalizeWalletStatus(status) {
+	case WalletStatusFrozen, WalletStatusClosed:
+		return false
+	}
+	return true
+}
+
+func canChangeWalletStatus(from, to WalletStatus) bool {
+	return from != WalletStatusClosed && from != to
+}
+
+// terminalTransferError reports whether err rules a transfer out until
+// something about the wallet or its limits changes, so retrying it an hour
+// later is pointless.
+func terminalTransferError(err error) bool {
+	for _, target := range []error{
+		appErr.ErrWalletFrozen, appErr.ErrWalletPostNoDebit, appErr.ErrWalletClosed,
+		appErr.ErrPerTransactionLimitExceeded, appErr.ErrDailyLimitExceeded, appErr.ErrMonthlyLimitExceeded,
+		appErr.ErrNewUserTransferRestriction, appErr.ErrActivationCapExceeded,
+		appErr.ErrInvalidTransferAmount, appErr.ErrInvalidRequestBody, appErr.ErrMissingUserWallet,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// TransferChargeKobo estimates the NIP charge, VAT included, the provider
+// takes for sending amount kobo.
+func TransferChargeKobo(amount int64) int64 {
+	switch {
+	case amount <= 500_000:
+		return 1_075
+	case amount <= 5_000_000:
+		return 2_688
+	}
+	return 5_375
+}
+
+// closureSweepAmount is the whole-naira amount that can be swept out of a
+// balance of balance kobo once the transfer charge is held back.
+func closureSweepAmount(balance int64) int64 {
+	var best int64
+	for _, charge := range []int64{1_075, 2_688, 5_375} {
+		amount := (balance - charge) / 100
+		if amount > best && amount*100+TransferChargeKobo(amount*100) <= balance {
+			best = amount
+		}
+	}
+	return best
+}
+
+// accountHeldBy reports whether a bank's account name contains the wallet
+// holder's first and last names.
+func accountHeldBy(accountName, firstName, lastName string) bool {
+	words := strings.FieldsFunc(strings.ToUpper(accountName), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	first := strings.ToUpper(strings.TrimSpace(firstName))
+	last := strings.ToUpper(strings.TrimSpace(lastName))
+	return first != "" && last != "" && slices.Contains(words, first) && slices.Contains(words, last)
+}
+
+func toWalletStatusEventResponse(event *WalletStatusEvent) WalletStatusEventResponse {
+	return WalletStatusEventResponse{
+		ID:              event.ID,
+		FromStatus:      event.FromStatus,
+		ToStatus:        event.ToStatus,
+		ReasonCode:      event.ReasonCode,
+		Note:            event.Note,
+		Actor:           event.Actor,
+		BalanceAtChange: float64(event.BalanceAtChange) / 100,
+		CreatedAt:       event.CreatedAt,
+	}
+}
@@ -0,0 +1,471 @@
+package wallet
+
+import (
+	"fmt"
+	appErr "neat_mobile_app_backend/internal/errors"
+	"neat_mobile_app_backend/internal/modules/transaction"
+	"neat_mobile_app_backend/internal/types"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCreditProviderRef(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload ProvidusCredit
+		want    string
+	}{
+		{name: "prefers tran id", payload: ProvidusCredit{TranID: " T1 ", SessionID: "S1"}, want: "T1"},
+		{name: "falls back to session id", payload: ProvidusCredit{SessionID: "S1"}, want: "S1"},
+		{name: "empty", payload: ProvidusCredit{TranID: "  "}, want: ""},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if got := creditProviderRef(&tc.payload); got != tc.want {
+				t.Fatalf("creditProviderRef() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCreditPayloadRoundTrip(t *testing.T) {
+	in := &ProvidusCredit{
+		AccountNumber:     "9900000001",
+		TransactionAmount: "1500.50",
+		TranType:          "C",
+		SessionID:         "S1",
+		TranID:            "T1",
+	}
+
+	out, err := creditPayloadFromMap(creditPayloadMap(in))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *out != *in {
+		t.Fatalf("round trip mismatch: got %+v, want %+v", *out, *in)
+	}
+}
+
+func TestApplyDepositCredit(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected int64
+		actual   int64
+		credit   int64
+		want     ExpectedDepositStatus
+	}{
+		{name: "exact amount", expected: 500000, credit: 500000, want: ExpectedDepositStatusMatched},
+		{name: "within tolerance", expected: 500000, credit: 499950, want: ExpectedDepositStatusMatched},
+		{name: "short", expected: 500000, credit: 200000, want: ExpectedDepositStatusPartiallyPaid},
+		{name: "topped up to full", expected: 500000, actual: 200000, credit: 300000, want: ExpectedDepositStatusMatched},
+		{name: "over", expected: 500000, credit: 600000, want: ExpectedDepositStatusOverpaid},
+		{name: "open amount", expected: 0, credit: 123400, want: ExpectedDepositStatusMatched},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			deposit := &ExpectedDeposit{ExpectedAmount: tc.expected, ActualAmount: tc.actual}
+			if got := applyDepositCredit(deposit, tc.credit, defaultExpectedDepositTolerance); got != tc.want {
+				t.Fatalf("applyDepositCredit() = %q, want %q", got, tc.want)
+			}
+			if deposit.ActualAmount != tc.actual+tc.credit {
+				t.Fatalf("actual amount = %d, want %d", deposit.ActualAmount, tc.actual+tc.credit)
+			}
+		})
+	}
+}
+
+func TestPickExpectedDepositPrefersClosestAmount(t *testing.T) {
+	candidates := []ExpectedDeposit{
+		{ID: "a", ExpectedAmount: 100000},
+		{ID: "b", ExpectedAmount: 500000},
+		{ID: "c", ExpectedAmount: 500000},
+	}
+
+	got := pickExpectedDeposit(candidates, 500000, "", defaultExpectedDepositTolerance)
+	if got == nil || got.ID != "b" {
+		t.Fatalf("pickExpectedDeposit() = %+v, want b", got)
+	}
+	if pickExpectedDeposit(nil, 500000, "", defaultExpectedDepositTolerance) != nil {
+		t.Fatal("expected nil for no candidates")
+	}
+}
+
+func TestPickExpectedDepositRejectsAmountsOutsideTolerance(t *testing.T) {
+	candidates := []ExpectedDeposit{
+		{ID: "deposit", ExpectedAmount: 500000},
+		{ID: "topped-up", ExpectedAmount: 500000, ActualAmount: 200000},
+	}
+
+	if got := pickExpectedDeposit(candidates, 450000, "", defaultExpectedDepositTolerance); got != nil {
+		t.Fatalf("expected a credit ₦500 short to stay unmatched, got %+v", got)
+	}
+	if got := pickExpectedDeposit(candidates, 300000, "", defaultExpectedDepositTolerance); got == nil || got.ID != "topped-up" {
+		t.Fatalf("expected the credit to settle the outstanding balance, got %+v", got)
+	}
+	if got := pickExpectedDeposit(candidates, 450000, "", 50000); got == nil || got.ID != "deposit" {
+		t.Fatalf("expected a wider tolerance to accept the short credit, got %+v", got)
+	}
+}
+
+func TestPickExpectedDepositOnlyMatchesPaymentRequestsOnCodeOrAmount(t *testing.T) {
+	candidates := []ExpectedDeposit{
+		{ID: "request", ExpectedAmount: 500000, PaymentRequestCode: "ABCD234567"},
+	}
+
+	if got := pickExpectedDeposit(candidates, 25000000, "SALARY MARCH", defaultExpectedDepositTolerance); got != nil {
+		t.Fatalf("expected an unrelated credit to stay unmatched, got %+v", got)
+	}
+	if got := pickExpectedDeposit(candidates, 500050, "", defaultExpectedDepositTolerance); got == nil || got.ID != "request" {
+		t.Fatalf("expected a credit for the amount due to match, got %+v", got)
+	}
+	if got := pickExpectedDeposit(candidates, 200000, "trf abcd234567 part 1", defaultExpectedDepositTolerance); got == nil || got.ID != "request" {
+		t.Fatalf("expected a credit quoting the code to match, got %+v", got)
+	}
+}
+
+func TestAdvanceOccurrenceMonthlyClampsToMonthEnd(t *testing.T) {
+	jan31 := time.Date(2024, 1, 31, 9, 0, 0, 0, time.UTC)
+
+	feb := advanceOccurrence(ScheduleFrequencyMonthly, 31, jan31)
+	if want := time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC); !feb.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, feb)
+	}
+
+	mar := advanceOccurrence(ScheduleFrequencyMonthly, 31, feb)
+	if want := time.Date(2024, 3, 31, 9, 0, 0, 0, time.UTC); !mar.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, mar)
+	}
+}
+
+func TestNextOccurrence(t *testing.T) {
+	start := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		schedule ScheduledTransfer
+		now      time.Time
+		want     time.Time
+		ok       bool
+	}{
+		{
+			name:     "one-off has no next run",
+			schedule: ScheduledTransfer{Frequency: ScheduleFrequencyOnce, OccurrenceAt: start},
+			now:      start,
+		},
+		{
+			name:     "daily skips missed days",
+			schedule: ScheduledTransfer{Frequency: ScheduleFrequencyDaily, OccurrenceAt: start},
+			now:      start.AddDate(0, 0, 3).Add(time.Hour),
+			want:     start.AddDate(0, 0, 4),
+			ok:       true,
+		},
+		{
+			name:     "weekly stops after end date",
+			schedule: ScheduledTransfer{Frequency: ScheduleFrequencyWeekly, OccurrenceAt: start.AddDate(0, 0, 14), EndAt: &end},
+			now:      start.AddDate(0, 0, 14),
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := nextOccurrence(&tc.schedule, tc.now)
+			if ok != tc.ok || !got.Equal(tc.want) {
+				t.Fatalf("expected (%v, %v), got (%v, %v)", tc.want, tc.ok, got, ok)
+			}
+		})
+	}
+}
+
+func TestMatchBatchResults(t *testing.T) {
+	txs := []transaction.Transaction{
+		{ID: "tx-1", Reference: "ref-1", CounterpartyAccount: "0000000001", CounterpartyBank: "000013"},
+		{ID: "tx-2", Reference: "ref-2", CounterpartyAccount: "0000000002", CounterpartyBank: "000013"},
+		{ID: "tx-3", Reference: "ref-3", CounterpartyAccount: "0000000002", CounterpartyBank: "000013"},
+	}
+	results := []ProvidusBatchTransferResult{
+		{Reference: "ref-1"},
+		{Reference: "P-9", AccountNumber: "0000000002", SortCode: "000013"},
+		{Reference: "P-10", AccountNumber: "0000000002", SortCode: "000013"},
+	}
+
+	matched := matchBatchResults(txs, results)
+	if len(matched) != 3 {
+		t.Fatalf("matched %d rows, want 3", len(matched))
+	}
+	if matched["tx-2"].Reference != "P-9" || matched["tx-3"].Reference != "P-10" {
+		t.Fatalf("duplicate accounts matched out of order: %+v", matched)
+	}
+}
+
+func TestSummarizeBatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []transaction.TransactionStatus
+		want     TransferBatchStatus
+	}{
+		{name: "pending", statuses: []transaction.TransactionStatus{transaction.TransactionStatusSuccessful, transaction.TransactionStatusPending}, want: TransferBatchStatusProcessing},
+		{name: "completed", statuses: []transaction.TransactionStatus{transaction.TransactionStatusSuccessful, transaction.TransactionStatusSuccessful}, want: TransferBatchStatusCompleted},
+		{name: "failed", statuses: []transaction.TransactionStatus{transaction.TransactionStatusFailed, transaction.TransactionStatusReversed}, want: TransferBatchStatusFailed},
+		{name: "partial", statuses: []transaction.TransactionStatus{transaction.TransactionStatusSuccessful, transaction.TransactionStatusFailed}, want: TransferBatchStatusPartiallyCompleted},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			txs := make([]transaction.Transaction, len(tc.statuses))
+			for i, status := range tc.statuses {
+				txs[i] = transaction.Transaction{Status: status}
+			}
+			if got := summarizeBatch(&TransferBatch{}, txs).Status; got != tc.want {
+				t.Fatalf("summarizeBatch() status = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestClassifyP2PRecipient(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		kind      P2PRecipientType
+		wantKind  P2PRecipientType
+		wantValue string
+	}{
+		{name: "account number", value: "9900000001", wantKind: P2PRecipientAccountNumber, wantValue: "9900000001"},
+		{name: "local phone", value: "08031234567", wantKind: P2PRecipientPhone, wantValue: "2348031234567"},
+		{name: "international phone", value: "+2348031234567", wantKind: P2PRecipientPhone, wantValue: "2348031234567"},
+		{name: "username", value: "@ada", wantKind: P2PRecipientUsername, wantValue: "ada"},
+		{name: "explicit phone", value: "8031234567", kind: P2PRecipientPhone, wantKind: P2PRecipientPhone, wantValue: "2348031234567"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			kind, value, err := classifyP2PRecipient(tc.value, tc.kind)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if kind != tc.wantKind || value != tc.wantValue {
+				t.Fatalf("classifyP2PRecipient() = (%q, %q), want (%q, %q)", kind, value, tc.wantKind, tc.wantValue)
+			}
+		})
+	}
+}
+
+func TestAwaitingProvider(t *testing.T) {
+	debit := func(category transaction.TransactionCategory, source transaction.TransactionSource, status transaction.TransactionStatus, ambiguous bool) *transaction.Transaction {
+		tx := &transaction.Transaction{Type: transaction.TransactionTypeDebit, Category: category, Source: source, Status: status}
+		if ambiguous {
+			tx.Metadata = types.JSONMap{"ambiguous": true}
+		}
+		return tx
+	}
+
+	tests := []struct {
+		name string
+		tx   *transaction.Transaction
+		want bool
+	}{
+		{name: "pending transfer", tx: debit(transaction.TransactionCategoryTransferTo, transaction.TransactionSourceDebit, transaction.TransactionStatusPending, false), want: true},
+		{name: "ambiguous repayment", tx: debit(transaction.TransactionCategoryLoanRepayment, transaction.TransactionSourceLoanRepayment, transaction.TransactionStatusFailed, true), want: true},
+		{name: "failed transfer", tx: debit(transaction.TransactionCategoryTransferTo, transaction.TransactionSourceDebit, transaction.TransactionStatusFailed, false)},
+		{name: "successful transfer", tx: debit(transaction.TransactionCategoryTransferTo, transaction.TransactionSourceDebit, transaction.TransactionStatusSuccessful, false)},
+		{name: "p2p", tx: debit(transaction.TransactionCategoryTransferTo, transaction.TransactionSourceP2P, transaction.TransactionStatusPending, false)},
+		{name: "bill payment", tx: debit(transaction.TransactionCategoryAirtime, transaction.TransactionSourceDebit, transaction.TransactionStatusPending, false)},
+	}
+
+	for _, tc := range tests {
+		if got := awaitingProvider(tc.tx); got != tc.want {
+			t.Fatalf("%s: awaitingProvider() = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestRefundableAtProvider(t *testing.T) {
+	tests := []struct {
+		name string
+		tx   *transaction.Transaction
+		want bool
+	}{
+		{name: "settled transfer", tx: &transaction.Transaction{Type: transaction.TransactionTypeDebit, Source: transaction.TransactionSourceDebit, Status: transaction.TransactionStatusSuccessful}, want: true},
+		{name: "refund already made", tx: &transaction.Transaction{Type: transaction.TransactionTypeDebit, Source: transaction.TransactionSourceDebit, Status: transaction.TransactionStatusReversalPending}},
+		{name: "failed at provider", tx: &transaction.Transaction{Type: transaction.TransactionTypeDebit, Source: transaction.TransactionSourceDebit, Status: transaction.TransactionStatusFailed}},
+		{name: "p2p", tx: &transaction.Transaction{Type: transaction.TransactionTypeDebit, Source: transaction.TransactionSourceP2P, Status: transaction.TransactionStatusSuccessful}},
+		{name: "credit", tx: &transaction.Transaction{Type: transaction.TransactionTypeCredit, Source: transaction.TransactionSourceCredit, Status: transaction.TransactionStatusSuccessful}},
+	}
+
+	for _, tc := range tests {
+		if got := refundableAtProvider(tc.tx); got != tc.want {
+			t.Fatalf("%s: refundableAtProvider() = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestEffectivePaymentRequestStatus(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		request PaymentRequest
+		want    PaymentRequestStatus
+	}{
+		{name: "open", request: PaymentRequest{Status: PaymentRequestStatusPending, ExpiresAt: now.Add(time.Hour)}, want: PaymentRequestStatusPending},
+		{name: "past expiry", request: PaymentRequest{Status: PaymentRequestStatusPending, ExpiresAt: now}, want: PaymentRequestStatusExpired},
+		{name: "paid stays paid", request: PaymentRequest{Status: PaymentRequestStatusPaid, ExpiresAt: now.Add(-time.Hour)}, want: PaymentRequestStatusPaid},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if got := effectivePaymentRequestStatus(&tc.request, now); got != tc.want {
+				t.Fatalf("effectivePaymentRequestStatus() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSameAccountName(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{name: "identical", a: "JOHN DOE", b: "JOHN DOE", want: true},
+		{name: "case and spacing", a: "john  doe", b: "JOHN DOE", want: true},
+		{name: "word order and punctuation", a: "DOE, JOHN", b: "John Doe", want: true},
+		{name: "different person", a: "JOHN DOE", b: "JANE DOE", want: false},
+		{name: "extra name", a: "JOHN ADE DOE", b: "JOHN DOE", want: false},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if got := sameAccountName(tc.a, tc.b); got != tc.want {
+				t.Fatalf("sameAccountName(%q, %q) = %v, want %v", tc.a, tc.b, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSearchBanks(t *testing.T) {
+	entries := []BankEntry{
+		{Code: "058", Name: "Guaranty Trust Bank", TransferCount: 40},
+		{Code: "044", Name: "Access Bank", TransferCount: 30},
+		{Code: "063", Name: "Access Bank (Diamond)", TransferCount: 5},
+		{Code: "011", Name: "First Bank of Nigeria"},
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{name: "no query keeps directory order", query: "", want: []string{"058", "044", "063", "011"}},
+		{name: "name prefix", query: "acc", want: []string{"044", "063"}},
+		{name: "initials", query: "GTB", want: []string{"058"}},
+		{name: "code", query: "011", want: []string{"011"}},
+		{name: "word prefix", query: "diamond", want: []string{"063"}},
+		{name: "no match", query: "zenith", want: []string{}},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got := searchBanks(entries, tc.query)
+			codes := make([]string, len(got))
+			for i, bank := range got {
+				codes[i] = bank.Code
+			}
+			if !slices.Equal(codes, tc.want) {
+				t.Fatalf("searchBanks(%q) = %v, want %v", tc.query, codes, tc.want)
+			}
+		})
+	}
+}
+
+func TestDebitAllowed(t *testing.T) {
+	tests := []struct {
+		status string
+		want   error
+	}{
+		{status: "active", want: nil},
+		{status: "ACTIVE", want: nil},
+		{status: "", want: nil},
+		{status: "Frozen", want: appErr.ErrWalletFrozen},
+		{status: "post_no_debit", want: appErr.ErrWalletPostNoDebit},
+		{status: "closed", want: appErr.ErrWalletClosed},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.status, func(t *testing.T) {
+			if got := DebitAllowed(tc.status); got != tc.want {
+				t.Fatalf("DebitAllowed(%q) = %v, want %v", tc.status, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTerminalTransferError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{err: appErr.ErrWalletPostNoDebit, want: true},
+		{err: fmt.Errorf("wrapped: %w", appErr.ErrDailyLimitExceeded), want: true},
+		{err: appErr.ErrFundsTransfer},
+		{err: appErr.ErrCheckingLimits},
+		{err: nil},
+	}
+
+	for _, tc := range tests {
+		if got := terminalTransferError(tc.err); got != tc.want {
+			t.Fatalf("terminalTransferError(%v) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestClosureSweepAmount(t *testing.T) {
+	tests := []struct {
+		name    string
+		balance int64
+		want    int64
+	}{
+		{name: "empty", balance: 0, want: 0},
+		{name: "below charge", balance: 900, want: 0},
+		{name: "small balance", balance: 100_000, want: 989},
+		{name: "stays in lower tier", balance: 501_000, want: 4_999},
+		{name: "large balance", balance: 10_000_000, want: 99_946},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got := closureSweepAmount(tc.balance)
+			if got != tc.want {
+				t.Fatalf("closureSweepAmount(%d) = %d, want %d", tc.balance, got, tc.want)
+			}
+			if got > 0 && got*100+TransferChargeKobo(got*100) > tc.balance {
+				t.Fatalf("closureSweepAmount(%d) = %d overdraws the wallet", tc.balance, got)
+			}
+		})
+	}
+}
+
+func TestAccountHeldBy(t *testing.T) {
+	if !accountHeldBy("OKAFOR, CHIDI EMEKA", "Chidi", "Okafor") {
+		t.Fatal("expected name with middle name and reordering to match")
+	}
+	if accountHeldBy("CHIDI NWOSU", "Chidi", "Okafor") {
+		t.Fatal("expected different surname not to match")
+	}
+	if accountHeldBy("CHIDI OKAFOR", "", "Okafor") {
+		t.Fatal("expected missing first name not to match")
+	}
+}
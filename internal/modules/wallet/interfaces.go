@@ -3,6 +3,9 @@ package wallet
 import (
 	"context"
 	"neat_mobile_app_backend/internal/modules/device"
+	"neat_mobile_app_backend/internal/modules/limits"
+
+	"gorm.io/gorm"
 )
 
 type BankResponse struct {
@@ -31,8 +34,26 @@ type ProvidusService interface {
 	FetchBankDetails(ctx context.Context, accountNumber, bankCode string) (*BankDetails, error)
 	InitiateTransfer(ctx context.Context, customerID string, req *TransferRequest) (*TransferResponse, error)
 	InitiateBulkTransfer(ctx context.Context, req []BulkTransferRecipientInfo) (*ProvidusBatchTransferResponse, error)
+	RequeryTransfer(ctx context.Context, reference string) (*TransferRequeryResult, error)
+	// DebitWallet and CreditWallet move amount kobo out of or into a
+	// customer's Providus wallet under reference.
+	DebitWallet(ctx context.Context, customerID string, amount int64, reference string) error
+	CreditWallet(ctx context.Context, customerID string, amount int64, reference string) error
 }
 
 type DeviceVerifier interface {
 	VerifyUserDevice(ctx context.Context, mobileUserID, deviceID string) (*device.UserDevice, error)
 }
+
+// LimitChecker enforces tier limits inside the database transaction that
+// books money out of a wallet.
+type LimitChecker interface {
+	CheckTx(ctx context.Context, tx *gorm.DB, mobileUserID string, channel limits.Channel, amounts ...int64) error
+}
+
+// SecondFactorVerifier checks authenticator app codes for users who have
+// enrolled one.
+type SecondFactorVerifier interface {
+	TOTPEnabled(ctx context.Context, userID string) (bool, error)
+	VerifyTOTP(ctx context.Context, userID, code string) error
+}
@@ -48,28 +48,197 @@ type Beneficiary struct {
 	BankCode      string     `gorm:"column:bank_code;type:text;not null"`
 	AccountNumber string     `gorm:"column:account_number;type:text;not null"`
 	AccountName   string     `gorm:"column:account_name;type:text;not null"`
-	CreatedAt     time.Time  `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime"`
-	UpdatedAt     *time.Time `gorm:"column:updated_at;type:timestamptz;autoUpdateTime"`
+	Nickname      string     `gorm:"column:nickname;type:text"`
+	IsFavourite   bool       `gorm:"column:is_favourite;not null;default:false"`
+	LastUsedAt    *time.Time `gorm:"column:last_used_at;type:timestamptz"`
+	// VerifiedAt is when AccountName was last confirmed against the bank.
+	VerifiedAt *time.Time `gorm:"column:verified_at;type:timestamptz"`
+	CreatedAt  time.Time  `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime"`
+	UpdatedAt  *time.Time `gorm:"column:updated_at;type:timestamptz;autoUpdateTime"`
 }
 
 func (Beneficiary) TableName() string {
 	return "wallet_beneficiaries"
 }
 
+// ExpectedDeposit is a credit the user told us to expect. Amounts are in kobo.
+// PaymentRequestCode is set on the deposit behind a payment request; such
+// deposits also take credits that quote the code, whatever their amount.
 type ExpectedDeposit struct {
-	ID             string                `gorm:"column:id;type:text;primaryKey;index"`
-	MobileUserID   string                `gorm:"column:mobile_user_id;type:text;index"`
-	TrackingID     string                `gorm:"column:tracking_id;type:text;not null;uniqueIndex"`
-	WalletID       string                `gorm:"column:wallet_id;type:text;not null;"`
-	ExpectedAmount int64                 `gorm:"column:expected_amount;type:bigint;not null;default:0"`
-	ActualAmount   int64                 `gorm:"column:actual_amount;type:bigint;not null;default:0"`
-	TransactionID  *string               `gorm:"column:transaction_id;type:text"`
-	Status         ExpectedDepositStatus `gorm:"column:status;type:text;not null;default:pending"`
-	ExpiresAt      time.Time             `gorm:"column:expires_at;type:timestamptz;not null"`
-	CreatedAt      time.Time             `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime"`
-	UpdatedAt      *time.Time            `gorm:"column:updated_at;type:timestamptz;autoUpdateTime"`
+	ID                 string                `gorm:"column:id;type:text;primaryKey;index"`
+	MobileUserID       string                `gorm:"column:mobile_user_id;type:text;index"`
+	TrackingID         string                `gorm:"column:tracking_id;type:text;not null;uniqueIndex"`
+	WalletID           string                `gorm:"column:wallet_id;type:text;not null;"`
+	ExpectedAmount     int64                 `gorm:"column:expected_amount_kobo;type:bigint;not null;default:0"`
+	ActualAmount       int64                 `gorm:"column:actual_amount;type:bigint;not null;default:0"`
+	TransactionID      *string               `gorm:"column:transaction_id;type:text"`
+	PaymentRequestCode string                `gorm:"column:payment_request_code;type:text"`
+	Status             ExpectedDepositStatus `gorm:"column:status;type:text;not null;default:pending"`
+	ExpiresAt          time.Time             `gorm:"column:expires_at;type:timestamptz;not null"`
+	CreatedAt          time.Time             `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime"`
+	UpdatedAt          *time.Time            `gorm:"column:updated_at;type:timestamptz;autoUpdateTime"`
 }
 
 func (ExpectedDeposit) TableName() string {
 	return "wallet_expected_deposits"
 }
+
+// WebhookInbox keeps every provider callback as received, keyed by the
+// provider's reference, so retries can be answered from the stored outcome and
+// failed rows can be replayed.
+type WebhookInbox struct {
+	ID                string             `gorm:"column:id;type:text;primaryKey"`
+	Provider          string             `gorm:"column:provider;type:text;not null;uniqueIndex:idx_wallet_webhook_inbox_provider_ref"`
+	ProviderReference string             `gorm:"column:provider_reference;type:text;not null;uniqueIndex:idx_wallet_webhook_inbox_provider_ref"`
+	SessionID         string             `gorm:"column:session_id;type:text;index"`
+	AccountNumber     string             `gorm:"column:account_number;type:text"`
+	Payload           types.JSONMap      `gorm:"column:payload;type:jsonb;not null"`
+	Status            WebhookInboxStatus `gorm:"column:status;type:text;not null;index"`
+	TransactionID     *string            `gorm:"column:transaction_id;type:text"`
+	LastError         string             `gorm:"column:last_error;type:text"`
+	Attempts          int                `gorm:"column:attempts;not null;default:0"`
+	ProcessedAt       *time.Time         `gorm:"column:processed_at;type:timestamptz"`
+	CreatedAt         time.Time          `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime"`
+	UpdatedAt         *time.Time         `gorm:"column:updated_at;type:timestamptz;autoUpdateTime"`
+}
+
+func (WebhookInbox) TableName() string {
+	return "wallet_webhook_inbox"
+}
+
+// ScheduledTransfer is a transfer the user authorised with their PIN at setup
+// and that the executor sends on their behalf. Amount is in kobo.
+type ScheduledTransfer struct {
+	ID             string                  `gorm:"column:id;type:text;primaryKey"`
+	MobileUserID   string                  `gorm:"column:mobile_user_id;type:text;not null;index"`
+	Amount         int64                   `gorm:"column:amount;type:bigint;not null"`
+	SortCode       string                  `gorm:"column:sort_code;type:text;not null"`
+	AccountNumber  string                  `gorm:"column:account_number;type:text;not null"`
+	AccountName    string                  `gorm:"column:account_name;type:text;not null"`
+	Narration      string                  `gorm:"column:narration;type:text"`
+	Frequency      ScheduleFrequency       `gorm:"column:frequency;type:text;not null"`
+	DayOfMonth     int                     `gorm:"column:day_of_month;not null;default:0"`
+	StartAt        time.Time               `gorm:"column:start_at;type:timestamptz;not null"`
+	EndAt          *time.Time              `gorm:"column:end_at;type:timestamptz"`
+	OccurrenceAt   time.Time               `gorm:"column:occurrence_at;type:timestamptz;not null"`
+	NextRunAt      time.Time               `gorm:"column:next_run_at;type:timestamptz;not null;index"`
+	Status         ScheduledTransferStatus `gorm:"column:status;type:text;not null;index"`
+	RunCount       int                     `gorm:"column:run_count;not null;default:0"`
+	FailedAttempts int                     `gorm:"column:failed_attempts;not null;default:0"`
+	LastRunAt      *time.Time              `gorm:"column:last_run_at;type:timestamptz"`
+	LastRunStatus  ScheduledRunStatus      `gorm:"column:last_run_status;type:text"`
+	ClaimedUntil   *time.Time              `gorm:"column:claimed_until;type:timestamptz"`
+	CreatedAt      time.Time               `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime"`
+	UpdatedAt      *time.Time              `gorm:"column:updated_at;type:timestamptz;autoUpdateTime"`
+}
+
+func (ScheduledTransfer) TableName() string {
+	return "wallet_scheduled_transfers"
+}
+
+// ScheduledTransferRun records every attempt the executor makes.
+type ScheduledTransferRun struct {
+	ID            string             `gorm:"column:id;type:text;primaryKey"`
+	ScheduleID    string             `gorm:"column:schedule_id;type:text;not null;index"`
+	TransactionID *string            `gorm:"column:transaction_id;type:text"`
+	ScheduledFor  time.Time          `gorm:"column:scheduled_for;type:timestamptz;not null"`
+	Attempt       int                `gorm:"column:attempt;not null"`
+	Status        ScheduledRunStatus `gorm:"column:status;type:text;not null"`
+	Error         string             `gorm:"column:error;type:text"`
+	CreatedAt     time.Time          `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime"`
+}
+
+func (ScheduledTransferRun) TableName() string {
+	return "wallet_scheduled_transfer_runs"
+}
+
+// TransferBatch groups the per-recipient transactions of one bulk transfer.
+// Each recipient is a wallet_transactions row carrying the batch id in its
+// metadata; the batch outcome is derived from those rows.
+type TransferBatch struct {
+	ID              string     `gorm:"column:id;type:text;primaryKey"`
+	MobileUserID    string     `gorm:"column:mobile_user_id;type:text;not null;index"`
+	WalletID        string     `gorm:"column:wallet_id;type:text;not null"`
+	TotalAmount     int64      `gorm:"column:total_amount;type:bigint;not null"`
+	RecipientCount  int        `gorm:"column:recipient_count;not null"`
+	ProviderMessage string     `gorm:"column:provider_message;type:text"`
+	CreatedAt       time.Time  `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime"`
+	UpdatedAt       *time.Time `gorm:"column:updated_at;type:timestamptz;autoUpdateTime"`
+}
+
+func (TransferBatch) TableName() string {
+	return "wallet_transfer_batches"
+}
+
+// PaymentRequest asks for money into the requester's wallet. A Neat user pays
+// it in-app through a P2P transfer; an external payer pays into the wallet's
+// account and is matched through the request's own ExpectedDeposit. Amount is
+// in kobo.
+type PaymentRequest struct {
+	ID                string               `gorm:"column:id;type:text;primaryKey"`
+	Code              string               `gorm:"column:code;type:text;not null;uniqueIndex"`
+	MobileUserID      string               `gorm:"column:mobile_user_id;type:text;not null;index"`
+	WalletID          string               `gorm:"column:wallet_id;type:text;not null"`
+	Amount            int64                `gorm:"column:amount;type:bigint;not null"`
+	Memo              string               `gorm:"column:memo;type:text"`
+	Status            PaymentRequestStatus `gorm:"column:status;type:text;not null;index"`
+	ExpectedDepositID string               `gorm:"column:expected_deposit_id;type:text;not null;index"`
+	PaidByUserID      *string              `gorm:"column:paid_by_user_id;type:text"`
+	TransactionID     *string              `gorm:"column:transaction_id;type:text"`
+	PaidAt            *time.Time           `gorm:"column:paid_at;type:timestamptz"`
+	ExpiresAt         time.Time            `gorm:"column:expires_at;type:timestamptz;not null"`
+	CreatedAt         time.Time            `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime"`
+	UpdatedAt         *time.Time           `gorm:"column:updated_at;type:timestamptz;autoUpdateTime"`
+}
+
+func (PaymentRequest) TableName() string {
+	return "wallet_payment_requests"
+}
+
+// BankEntry is a bank in the cached directory. TransferCount tracks successful
+// transfers to the bank and drives the popular-first ordering.
+type BankEntry struct {
+	Code          string     `gorm:"column:code;type:text;primaryKey"`
+	Name          string     `gorm:"column:name;type:text;not null"`
+	TransferCount int64      `gorm:"column:transfer_count;type:bigint;not null;default:0"`
+	RefreshedAt   time.Time  `gorm:"column:refreshed_at;type:timestamptz;not null;index"`
+	CreatedAt     time.Time  `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime"`
+	UpdatedAt     *time.Time `gorm:"column:updated_at;type:timestamptz;autoUpdateTime"`
+}
+
+func (BankEntry) TableName() string {
+	return "wallet_bank_directory"
+}
+
+// AccountNameLookup caches a successful account-name resolution until
+// ExpiresAt.
+type AccountNameLookup struct {
+	BankCode      string    `gorm:"column:bank_code;type:text;primaryKey"`
+	AccountNumber string    `gorm:"column:account_number;type:text;primaryKey"`
+	AccountName   string    `gorm:"column:account_name;type:text;not null"`
+	ExpiresAt     time.Time `gorm:"column:expires_at;type:timestamptz;not null;index"`
+	CreatedAt     time.Time `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime"`
+}
+
+func (AccountNameLookup) TableName() string {
+	return "wallet_account_name_lookups"
+}
+
+// WalletStatusEvent is the audit trail of wallet status changes.
+type WalletStatusEvent struct {
+	ID           string             `gorm:"column:id;type:text;primaryKey"`
+	WalletID     string             `gorm:"column:wallet_id;type:text;not null;index"`
+	MobileUserID string             `gorm:"column:mobile_user_id;type:text;not null;index"`
+	FromStatus   WalletStatus       `gorm:"column:from_status;type:text;not null"`
+	ToStatus     WalletStatus       `gorm:"column:to_status;type:text;not null"`
+	ReasonCode   WalletStatusReason `gorm:"column:reason_code;type:text;not null"`
+	Note         string             `gorm:"column:note;type:text"`
+	Actor        string             `gorm:"column:actor;type:text;not null"`
+	// BalanceAtChange is the available balance in kobo when the change was made.
+	BalanceAtChange int64     `gorm:"column:balance_at_change;type:bigint;not null;default:0"`
+	CreatedAt       time.Time `gorm:"column:created_at;type:timestamptz;not null;autoCreateTime"`
+}
+
+func (WalletStatusEvent) TableName() string {
+	return "wallet_status_events"
+}
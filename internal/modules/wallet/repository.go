@@ -2,20 +2,26 @@ package wallet
 
 import (
 	"context"
+	"errors"
 	"neat_mobile_app_backend/internal/modules/device"
+	"neat_mobile_app_backend/internal/modules/ledger"
 	"neat_mobile_app_backend/internal/modules/transaction"
+	"neat_mobile_app_backend/internal/types"
 	"neat_mobile_app_backend/models"
 	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Repository struct {
-	db *gorm.DB
+	db     *gorm.DB
+	ledger *ledger.Repository
 }
 
 func NewRepository(db *gorm.DB) *Repository {
-	return &Repository{db: db}
+	return &Repository{db: db, ledger: ledger.NewRepository(db)}
 }
 
 func (r *Repository) GetUserByMobileUserID(ctx context.Context, mobileUserID string) (*models.User, error) {
@@ -106,10 +112,74 @@ func (r *Repository) AddTransaction(ctx context.Context, transaction *transactio
 	return r.db.WithContext(ctx).Create(transaction).Error
 }
 
+// DebitCheck runs inside the database transaction that books a debit, before
+// the debit row is written, and rolls the booking back by returning an error.
+// A nil DebitCheck is skipped.
+type DebitCheck func(tx *gorm.DB) error
+
+func (check DebitCheck) run(tx *gorm.DB) error {
+	if check == nil {
+		return nil
+	}
+	return check(tx)
+}
+
+// LockWalletForDebit locks the wallet row inside tx and refuses the debit
+// unless the wallet's status lets money out and its available balance covers
+// need kobo. Debit paths call it in the transaction that books the debit, so
+// a freeze or post-no-debit committed while the debit is in flight is seen,
+// and money that already left the wallet can't be sent again through the
+// provider.
+func LockWalletForDebit(tx *gorm.DB, internalWalletID string, need int64) (*CustomerWallet, error) {
+	var wallet CustomerWallet
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("internal_wallet_id = ?", internalWalletID).
+		First(&wallet).Error; err != nil {
+		return nil, err
+	}
+	if err := DebitAllowed(wallet.Status); err != nil {
+		return nil, err
+	}
+	if wallet.AvailableBalance < need {
+		return nil, ErrInsufficientFunds
+	}
+	return &wallet, nil
+}
+
+// AddDebitTransaction writes a pending debit once check passes and the locked
+// wallet can cover the amount plus the charges (kobo, VAT included) the
+// provider is expected to take, in one database transaction.
+func (r *Repository) AddDebitTransaction(ctx context.Context, debit *transaction.Transaction, charges int64, check DebitCheck) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return bookDebit(tx, debit, charges, check)
+	})
+}
+
+func bookDebit(tx *gorm.DB, debit *transaction.Transaction, charges int64, check DebitCheck) error {
+	if err := check.run(tx); err != nil {
+		return err
+	}
+	if _, err := LockWalletForDebit(tx, debit.WalletID, debit.Amount+charges); err != nil {
+		return err
+	}
+	return tx.Create(debit).Error
+}
+
 func (r *Repository) UpdateTransactionStatus(ctx context.Context, txID string, status transaction.TransactionStatus) error {
 	return r.db.WithContext(ctx).Model(&transaction.Transaction{}).Where("id = ?", txID).Update("status", status).Error
 }
 
+// MarkRefundedAtProvider marks a debit reversal_pending once the provider has
+// refunded it under refundRef, which the reversal credit then carries.
+func (r *Repository) MarkRefundedAtProvider(ctx context.Context, txID, refundRef string) error {
+	return r.db.WithContext(ctx).Model(&transaction.Transaction{}).
+		Where("id = ?", txID).
+		Updates(map[string]interface{}{
+			"status":   transaction.TransactionStatusReversalPending,
+			"metadata": gorm.Expr(`COALESCE(metadata, '{}'::jsonb) || jsonb_build_object('refund_reference', ?::text)`, refundRef),
+		}).Error
+}
+
 func (r *Repository) UpdateTransactionProviderRef(ctx context.Context, txID, providerRef string, status transaction.TransactionStatus) error {
 	return r.db.WithContext(ctx).Model(&transaction.Transaction{}).
 		Where("id = ?", txID).
@@ -119,46 +189,359 @@ func (r *Repository) UpdateTransactionProviderRef(ctx context.Context, txID, pro
 		}).Error
 }
 
-func (r *Repository) CompleteDebitTransaction(ctx context.Context, txID, providerRef string, status transaction.TransactionStatus, walletID string, totalDebit int64) error {
+// CompleteDebitTransaction settles a pending debit: it snapshots balances on the
+// transaction, posts the journal entry and moves the cached wallet balance in
+// the same database transaction. Amounts are in kobo. A debit whose amount was
+// reserved up front only takes whatever the charges exceed the reserved
+// estimate by, or gets the difference back, from the available balance.
+func (r *Repository) CompleteDebitTransaction(ctx context.Context, txID, providerRef string, status transaction.TransactionStatus, walletID string, amount, charges, vat int64) error {
+	totalDebit := amount + charges + vat
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 
 		var wallet CustomerWallet
-		if err := tx.Set("gorm:query_option", "FOR UPDATE").
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
 			Where("internal_wallet_id = ?", walletID).
 			First(&wallet).Error; err != nil {
 			return err
 		}
 
+		var current transaction.Transaction
+		if err := tx.Select("id", "metadata").Where("id = ?", txID).First(&current).Error; err != nil {
+			return err
+		}
+
+		balanceBefore := wallet.AvailableBalance
+		availableDebit := totalDebit
+		if isReserved(current.Metadata) {
+			held := reservedCharges(current.Metadata)
+			balanceBefore += amount + held
+			availableDebit = charges + vat - held
+		}
+
 		if err := tx.Model(&transaction.Transaction{}).
 			Where("id = ?", txID).
 			Updates(map[string]interface{}{
 				"provider_reference": providerRef,
 				"status":             status,
-				"balance_before":     wallet.AvailableBalance,
-				"balance_after":      wallet.AvailableBalance - totalDebit,
+				"charges":            charges,
+				"vat":                vat,
+				"balance_before":     balanceBefore,
+				"balance_after":      balanceBefore - totalDebit,
+				"metadata":           gorm.Expr(`COALESCE(metadata, '{}'::jsonb) - 'reserved' - 'reserved_charges'`),
 			}).Error; err != nil {
 			return err
 		}
+
+		if err := r.ledger.WithTx(tx).PostWalletDebit(ctx, ledger.Movement{
+			WalletID:       wallet.InternalWalletID,
+			OpeningBalance: balanceBefore,
+			TransactionID:  txID,
+			Reference:      "debit:" + txID,
+			Description:    "Wallet debit",
+			Amount:         amount,
+			Charges:        charges,
+			VAT:            vat,
+		}); err != nil {
+			return err
+		}
+
 		return tx.Model(&CustomerWallet{}).
 			Where("internal_wallet_id = ?", walletID).
 			Updates(map[string]interface{}{
 				"booked_balance":    gorm.Expr("booked_balance - ?", totalDebit),
-				"available_balance": gorm.Expr("available_balance - ?", totalDebit),
+				"available_balance": gorm.Expr("available_balance - ?", availableDebit),
 				"updated_at":        time.Now(),
 			}).Error
 	})
 }
 
+// MarkTransactionAmbiguous records that the provider call for txID ended without
+// a definite answer, so the reconciler requeries it later.
+func (r *Repository) MarkTransactionAmbiguous(ctx context.Context, txID string, status transaction.TransactionStatus) error {
+	return r.db.WithContext(ctx).Model(&transaction.Transaction{}).
+		Where("id = ?", txID).
+		Updates(map[string]interface{}{
+			"status":   status,
+			"metadata": gorm.Expr(`COALESCE(metadata, '{}'::jsonb) || '{"ambiguous": true}'::jsonb`),
+		}).Error
+}
+
+// SettleTransactionStatus sets the final status and clears the ambiguous flag.
+// A reserved amount goes back to the available balance unless the debit
+// succeeded.
+func (r *Repository) SettleTransactionStatus(ctx context.Context, txID string, status transaction.TransactionStatus) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current transaction.Transaction
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", txID).
+			First(&current).Error; err != nil {
+			return err
+		}
+
+		metadata := gorm.Expr(`COALESCE(metadata, '{}'::jsonb) - 'ambiguous'`)
+		if status != transaction.TransactionStatusSuccessful && isReserved(current.Metadata) {
+			if err := releaseReservation(tx, &current); err != nil {
+				return err
+			}
+			metadata = gorm.Expr(`COALESCE(metadata, '{}'::jsonb) - 'ambiguous' - 'reserved' - 'reserved_charges'`)
+		}
+
+		return tx.Model(&transaction.Transaction{}).
+			Where("id = ?", txID).
+			Updates(map[string]interface{}{
+				"status":   status,
+				"metadata": metadata,
+			}).Error
+	})
+}
+
+// ListTransfersForRequery returns outbound transfers created before the cutoff
+// that are still pending or whose failure was ambiguous.
+func (r *Repository) ListTransfersForRequery(ctx context.Context, before time.Time, limit int) ([]transaction.Transaction, error) {
+	var txs []transaction.Transaction
+	err := r.db.WithContext(ctx).
+		Where("type = ? AND transaction_category IN ?", transaction.TransactionTypeDebit,
+			[]transaction.TransactionCategory{transaction.TransactionCategoryTransferTo, transaction.TransactionCategoryLoanRepayment}).
+		Where("status = ? OR (status = ? AND metadata->>'ambiguous' = 'true')",
+			transaction.TransactionStatusPending, transaction.TransactionStatusFailed).
+		Where("created_at < ?", before).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&txs).Error
+	return txs, err
+}
+
+// ReverseDebitTransaction undoes a debit. When the debit moved funds it books a
+// linked reversal credit, restores the wallet balance and posts the ledger
+// credit in one transaction; a debit that never left the wallet (still pending
+// or ambiguous) is only marked reversed, and nil is returned in place of the
+// reversal row.
+func (r *Repository) ReverseDebitTransaction(ctx context.Context, txID, reason string) (*transaction.Transaction, error) {
+	var reversal *transaction.Transaction
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var original transaction.Transaction
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", txID).
+			First(&original).Error; err != nil {
+			return err
+		}
+		// a wallet-to-wallet debit can't be undone without clawing the credit
+		// back from the recipient
+		if original.Type != transaction.TransactionTypeDebit || original.Source == transaction.TransactionSourceP2P {
+			return ErrNotReversible
+		}
+		if original.Status == transaction.TransactionStatusReversed {
+			return ErrAlreadyReversed
+		}
+
+		debited := original.Status == transaction.TransactionStatusSuccessful ||
+			original.Status == transaction.TransactionStatusReversalPending
+		if !debited {
+			posted, err := debitPosted(ctx, r.ledger.WithTx(tx), original.ID)
+			if err != nil {
+				return err
+			}
+			debited = posted
+		}
+
+		if !debited {
+			ambiguous, _ := original.Metadata["ambiguous"].(bool)
+			if original.Status == transaction.TransactionStatusFailed && !ambiguous {
+				return ErrNotReversible
+			}
+			if isReserved(original.Metadata) {
+				if err := releaseReservation(tx, &original); err != nil {
+					return err
+				}
+			}
+			return tx.Model(&transaction.Transaction{}).
+				Where("id = ?", original.ID).
+				Updates(map[string]interface{}{
+					"status": transaction.TransactionStatusReversed,
+					"metadata": gorm.Expr(`(COALESCE(metadata, '{}'::jsonb) - 'ambiguous' - 'reserved' - 'reserved_charges') || jsonb_build_object('reversal_reason', ?::text)`,
+						reason),
+				}).Error
+		}
+
+		var wallet CustomerWallet
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("internal_wallet_id = ?", original.WalletID).
+			First(&wallet).Error; err != nil {
+			return err
+		}
+
+		total := original.Amount + original.Charges + original.VAT
+		reversal = &transaction.Transaction{
+			ID:                  uuid.NewString(),
+			MobileUserID:        original.MobileUserID,
+			WalletID:            original.WalletID,
+			Type:                transaction.TransactionTypeCredit,
+			Category:            transaction.TransactionCategoryReversal,
+			Amount:              total,
+			BalanceBefore:       wallet.AvailableBalance,
+			BalanceAfter:        wallet.AvailableBalance + total,
+			Reference:           uuid.NewString(),
+			Description:         "Reversal: " + original.Description,
+			CounterpartyName:    original.CounterpartyName,
+			CounterpartyAccount: original.CounterpartyAccount,
+			CounterpartyBank:    original.CounterpartyBank,
+			Status:              transaction.TransactionStatusSuccessful,
+			Source:              transaction.TransactionSourceCredit,
+			Metadata: types.JSONMap{
+				"original_transaction_id": original.ID,
+				"reversal_reason":         reason,
+			},
+		}
+		// the provider's refund, when there was one, is what reconciles it
+		if refundRef, _ := original.Metadata["refund_reference"].(string); refundRef != "" {
+			reversal.ProviderReference = refundRef
+		}
+		if err := tx.Create(reversal).Error; err != nil {
+			return err
+		}
+
+		if err := r.ledger.WithTx(tx).PostWalletCredit(ctx, ledger.Movement{
+			WalletID:       wallet.InternalWalletID,
+			OpeningBalance: wallet.AvailableBalance,
+			TransactionID:  reversal.ID,
+			Reference:      "reversal:" + original.ID,
+			Description:    reason,
+			Amount:         original.Amount,
+			Charges:        original.Charges,
+			VAT:            original.VAT,
+		}); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&transaction.Transaction{}).
+			Where("id = ?", original.ID).
+			Updates(map[string]interface{}{
+				"status": transaction.TransactionStatusReversed,
+				"metadata": gorm.Expr(`(COALESCE(metadata, '{}'::jsonb) - 'ambiguous') || jsonb_build_object('reversal_reason', ?::text, 'reversal_transaction_id', ?::text)`,
+					reason, reversal.ID),
+			}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&CustomerWallet{}).
+			Where("internal_wallet_id = ?", wallet.InternalWalletID).
+			Updates(map[string]interface{}{
+				"booked_balance":    gorm.Expr("booked_balance + ?", total),
+				"available_balance": gorm.Expr("available_balance + ?", total),
+				"updated_at":        time.Now(),
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reversal, nil
+}
+
+// releaseReservation returns a reserved debit amount to the available balance.
+func releaseReservation(tx *gorm.DB, t *transaction.Transaction) error {
+	return tx.Model(&CustomerWallet{}).
+		Where("internal_wallet_id = ?", t.WalletID).
+		Updates(map[string]interface{}{
+			"available_balance": gorm.Expr("available_balance + ?", t.Amount+reservedCharges(t.Metadata)),
+			"updated_at":        time.Now(),
+		}).Error
+}
+
+func isReserved(metadata types.JSONMap) bool {
+	reserved, _ := metadata["reserved"].(bool)
+	return reserved
+}
+
+// reservedCharges is the charge estimate, in kobo, held on top of the amount
+// of a reserved debit.
+func reservedCharges(metadata types.JSONMap) int64 {
+	switch v := metadata["reserved_charges"].(type) {
+	case float64:
+		return int64(v)
+	case int64:
+		return v
+	}
+	return 0
+}
+
+func debitPosted(ctx context.Context, l *ledger.Repository, txID string) (bool, error) {
+	entries, err := l.GetEntriesByTransactionID(ctx, txID)
+	if err != nil {
+		return false, err
+	}
+	for _, entry := range entries {
+		if entry.Reference == "debit:"+txID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func (r *Repository) CreateBeneficiary(ctx context.Context, beneficiary *Beneficiary) error {
 	return r.db.WithContext(ctx).Create(beneficiary).Error
 }
 
+// GetBeneficiaries lists favourites first, then the most recently used.
 func (r *Repository) GetBeneficiaries(ctx context.Context, mobileUserID string) ([]Beneficiary, error) {
 	var beneficiaries []Beneficiary
-	err := r.db.WithContext(ctx).Select("wallet_id, bank_code, account_number, account_name").Where("mobile_user_id = ?", mobileUserID).Find(&beneficiaries).Error
+	err := r.db.WithContext(ctx).
+		Where("mobile_user_id = ?", mobileUserID).
+		Order("is_favourite DESC, last_used_at DESC NULLS LAST, created_at DESC").
+		Find(&beneficiaries).Error
 	return beneficiaries, err
 }
 
+func (r *Repository) GetBeneficiary(ctx context.Context, mobileUserID, id string) (*Beneficiary, error) {
+	var beneficiary Beneficiary
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND mobile_user_id = ?", id, mobileUserID).
+		First(&beneficiary).Error
+	if err != nil {
+		return nil, err
+	}
+	return &beneficiary, nil
+}
+
+// FindBeneficiary looks up the user's saved beneficiary for an account,
+// ignoring excludeID so an update can check for clashes with other rows.
+func (r *Repository) FindBeneficiary(ctx context.Context, mobileUserID, bankCode, accountNumber, excludeID string) (*Beneficiary, error) {
+	query := r.db.WithContext(ctx).
+		Where("mobile_user_id = ? AND bank_code = ? AND account_number = ?", mobileUserID, bankCode, accountNumber)
+	if excludeID != "" {
+		query = query.Where("id <> ?", excludeID)
+	}
+
+	var beneficiary Beneficiary
+	if err := query.First(&beneficiary).Error; err != nil {
+		return nil, err
+	}
+	return &beneficiary, nil
+}
+
+func (r *Repository) UpdateBeneficiary(ctx context.Context, id string, updates map[string]any) error {
+	return r.db.WithContext(ctx).
+		Model(&Beneficiary{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+}
+
+func (r *Repository) DeleteBeneficiary(ctx context.Context, mobileUserID, id string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND mobile_user_id = ?", id, mobileUserID).
+		Delete(&Beneficiary{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// TouchBeneficiary stamps the user's saved beneficiary for an account as used.
+// It is a no-op when the account is not saved.
+func (r *Repository) TouchBeneficiary(ctx context.Context, mobileUserID, bankCode, accountNumber string, usedAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&Beneficiary{}).
+		Where("mobile_user_id = ? AND bank_code = ? AND account_number = ?", mobileUserID, bankCode, accountNumber).
+		Update("last_used_at", usedAt).Error
+}
+
 func (r *Repository) GetWalletByAccountNumber(ctx context.Context, accountNumber string) (*CustomerWallet, error) {
 	var w CustomerWallet
 	err := r.db.WithContext(ctx).Where("account_number = ?", accountNumber).First(&w).Error
@@ -172,7 +555,7 @@ func (r *Repository) CreditWalletAtomically(ctx context.Context, tx *transaction
 	return r.db.WithContext(ctx).Transaction(func(db *gorm.DB) error {
 
 		var wallet CustomerWallet
-		if err := db.Set("gorm:query_option", "FOR UPDATE").
+		if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).
 			Where("wallet_id = ?", tx.WalletID).
 			First(&wallet).Error; err != nil {
 			return err
@@ -185,6 +568,17 @@ func (r *Repository) CreditWalletAtomically(ctx context.Context, tx *transaction
 			return err
 		}
 
+		if err := r.ledger.WithTx(db).PostWalletCredit(ctx, ledger.Movement{
+			WalletID:       wallet.InternalWalletID,
+			OpeningBalance: wallet.AvailableBalance,
+			TransactionID:  tx.ID,
+			Reference:      "credit:" + tx.ID,
+			Description:    "Wallet credit",
+			Amount:         amount,
+		}); err != nil {
+			return err
+		}
+
 		return db.Model(&CustomerWallet{}).
 			Where("wallet_id = ?", tx.WalletID).
 			Updates(map[string]interface{}{
@@ -198,3 +592,688 @@ func (r *Repository) CreditWalletAtomically(ctx context.Context, tx *transaction
 func (r *Repository) CreateExpectedDeposit(ctx context.Context, expectedDeposit *ExpectedDeposit) error {
 	return r.db.WithContext(ctx).Create(expectedDeposit).Error
 }
+
+func (r *Repository) GetExpectedDeposit(ctx context.Context, mobileUserID, trackingID string) (*ExpectedDeposit, error) {
+	var deposit ExpectedDeposit
+	err := r.db.WithContext(ctx).
+		Where("tracking_id = ? AND mobile_user_id = ?", trackingID, mobileUserID).
+		First(&deposit).Error
+	if err != nil {
+		return nil, err
+	}
+	return &deposit, nil
+}
+
+// MatchExpectedDeposit links a credit to the best open expected deposit on the
+// wallet. It returns nil when nothing open matches within tolerance (kobo).
+func (r *Repository) MatchExpectedDeposit(ctx context.Context, walletID, transactionID string, amount int64, narration string, tolerance int64, now time.Time) (*ExpectedDeposit, error) {
+	var matched *ExpectedDeposit
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var candidates []ExpectedDeposit
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("wallet_id = ? AND status IN ? AND expires_at > ?", walletID, openExpectedDepositStatuses, now.Add(-expectedDepositGrace)).
+			Order("created_at ASC").
+			Find(&candidates).Error; err != nil {
+			return err
+		}
+
+		deposit := pickExpectedDeposit(candidates, amount, narration, tolerance)
+		if deposit == nil {
+			return nil
+		}
+		applyDepositCredit(deposit, amount, tolerance)
+		deposit.TransactionID = &transactionID
+
+		if err := tx.Model(&ExpectedDeposit{}).
+			Where("id = ?", deposit.ID).
+			Updates(map[string]interface{}{
+				"actual_amount":  deposit.ActualAmount,
+				"status":         deposit.Status,
+				"transaction_id": transactionID,
+			}).Error; err != nil {
+			return err
+		}
+		matched = deposit
+		return nil
+	})
+	return matched, err
+}
+
+func (r *Repository) ListStaleExpectedDeposits(ctx context.Context, cutoff time.Time, limit int) ([]ExpectedDeposit, error) {
+	var deposits []ExpectedDeposit
+	err := r.db.WithContext(ctx).
+		Where("status IN ? AND expires_at < ?", openExpectedDepositStatuses, cutoff).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&deposits).Error
+	return deposits, err
+}
+
+// ExpireExpectedDeposit reports false when the row was matched or expired
+// by someone else first.
+func (r *Repository) ExpireExpectedDeposit(ctx context.Context, id string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&ExpectedDeposit{}).
+		Where("id = ? AND status IN ?", id, openExpectedDepositStatuses).
+		Update("status", ExpectedDepositStatusExpired)
+	return result.RowsAffected > 0, result.Error
+}
+
+// RecordWebhook inserts the inbox row unless one already exists for the same
+// provider reference. It reports whether this call created the row.
+func (r *Repository) RecordWebhook(ctx context.Context, entry *WebhookInbox) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "provider"}, {Name: "provider_reference"}},
+			DoNothing: true,
+		}).
+		Create(entry)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *Repository) GetWebhookByProviderRef(ctx context.Context, provider, providerRef string) (*WebhookInbox, error) {
+	var entry WebhookInbox
+	err := r.db.WithContext(ctx).
+		Where("provider = ? AND provider_reference = ?", provider, providerRef).
+		First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// ListReplayableWebhooks returns failed inbox rows and rows still in received
+// that were recorded before staleBefore, oldest first.
+func (r *Repository) ListReplayableWebhooks(ctx context.Context, provider string, staleBefore time.Time, limit int) ([]WebhookInbox, error) {
+	var entries []WebhookInbox
+	err := r.db.WithContext(ctx).
+		Where("provider = ? AND (status = ? OR (status = ? AND created_at < ?))",
+			provider, WebhookInboxStatusFailed, WebhookInboxStatusReceived, staleBefore).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&entries).Error
+	return entries, err
+}
+
+func (r *Repository) IncrementWebhookAttempts(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Model(&WebhookInbox{}).
+		Where("id = ?", id).
+		Update("attempts", gorm.Expr("attempts + 1")).Error
+}
+
+func (r *Repository) FinishWebhook(ctx context.Context, id string, status WebhookInboxStatus, transactionID *string, lastError string) error {
+	return r.db.WithContext(ctx).Model(&WebhookInbox{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":         status,
+			"transaction_id": transactionID,
+			"last_error":     lastError,
+			"processed_at":   time.Now(),
+		}).Error
+}
+
+func (r *Repository) GetTransactionByID(ctx context.Context, txID string) (*transaction.Transaction, error) {
+	var tx transaction.Transaction
+	err := r.db.WithContext(ctx).Where("id = ?", txID).First(&tx).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+func (r *Repository) GetCreditByProviderRef(ctx context.Context, providerRef string) (*transaction.Transaction, error) {
+	var tx transaction.Transaction
+	err := r.db.WithContext(ctx).
+		Where("provider_reference = ? AND type = ?", providerRef, transaction.TransactionTypeCredit).
+		First(&tx).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+func (r *Repository) CreateScheduledTransfer(ctx context.Context, schedule *ScheduledTransfer) error {
+	return r.db.WithContext(ctx).Create(schedule).Error
+}
+
+func (r *Repository) GetScheduledTransfer(ctx context.Context, mobileUserID, id string) (*ScheduledTransfer, error) {
+	var schedule ScheduledTransfer
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND mobile_user_id = ?", id, mobileUserID).
+		First(&schedule).Error
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (r *Repository) ListScheduledTransfers(ctx context.Context, mobileUserID string) ([]ScheduledTransfer, error) {
+	var schedules []ScheduledTransfer
+	err := r.db.WithContext(ctx).
+		Where("mobile_user_id = ? AND status <> ?", mobileUserID, ScheduledTransferStatusCancelled).
+		Order("created_at DESC").
+		Find(&schedules).Error
+	return schedules, err
+}
+
+func (r *Repository) ListScheduledTransferRuns(ctx context.Context, scheduleID string, limit int) ([]ScheduledTransferRun, error) {
+	var runs []ScheduledTransferRun
+	err := r.db.WithContext(ctx).
+		Where("schedule_id = ?", scheduleID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&runs).Error
+	return runs, err
+}
+
+func (r *Repository) UpdateScheduledTransfer(ctx context.Context, id string, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).Model(&ScheduledTransfer{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+}
+
+// ListDueScheduledTransfers returns active schedules whose next run is due and
+// that no executor currently holds.
+func (r *Repository) ListDueScheduledTransfers(ctx context.Context, now time.Time, limit int) ([]ScheduledTransfer, error) {
+	var schedules []ScheduledTransfer
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND next_run_at <= ?", ScheduledTransferStatusActive, now).
+		Where("claimed_until IS NULL OR claimed_until < ?", now).
+		Order("next_run_at ASC").
+		Limit(limit).
+		Find(&schedules).Error
+	return schedules, err
+}
+
+// ClaimScheduledTransfer takes the schedule for one run. It reports false when
+// another executor got there first or the schedule changed since it was listed.
+func (r *Repository) ClaimScheduledTransfer(ctx context.Context, id string, nextRunAt, now time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&ScheduledTransfer{}).
+		Where("id = ? AND status = ? AND next_run_at = ?", id, ScheduledTransferStatusActive, nextRunAt).
+		Where("claimed_until IS NULL OR claimed_until < ?", now).
+		Update("claimed_until", now.Add(scheduledTransferClaimTTL))
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+// FinishScheduledRun records the attempt and moves the schedule on, releasing
+// the claim.
+func (r *Repository) FinishScheduledRun(ctx context.Context, run *ScheduledTransferRun, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(run).Error; err != nil {
+			return err
+		}
+		updates["claimed_until"] = nil
+		return tx.Model(&ScheduledTransfer{}).
+			Where("id = ?", run.ScheduleID).
+			Updates(updates).Error
+	})
+}
+
+// CreateTransferBatch reserves each recipient's amount plus its estimated
+// charges on the available balance and writes the batch with one pending
+// debit per recipient, once check passes.
+func (r *Repository) CreateTransferBatch(ctx context.Context, batch *TransferBatch, txs []transaction.Transaction, check DebitCheck) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := check.run(tx); err != nil {
+			return err
+		}
+		var wallet CustomerWallet
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("internal_wallet_id = ?", batch.WalletID).
+			First(&wallet).Error; err != nil {
+			return err
+		}
+		if err := DebitAllowed(wallet.Status); err != nil {
+			return err
+		}
+		var reserve int64
+		for i := range txs {
+			reserve += txs[i].Amount + reservedCharges(txs[i].Metadata)
+		}
+		if wallet.AvailableBalance < reserve {
+			return ErrInsufficientFunds
+		}
+
+		if err := tx.Create(batch).Error; err != nil {
+			return err
+		}
+		if err := tx.CreateInBatches(txs, 100).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&CustomerWallet{}).
+			Where("internal_wallet_id = ?", batch.WalletID).
+			Updates(map[string]interface{}{
+				"available_balance": gorm.Expr("available_balance - ?", reserve),
+				"updated_at":        time.Now(),
+			}).Error
+	})
+}
+
+func (r *Repository) GetTransferBatch(ctx context.Context, mobileUserID, batchID string) (*TransferBatch, error) {
+	var batch TransferBatch
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND mobile_user_id = ?", batchID, mobileUserID).
+		First(&batch).Error
+	if err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+func (r *Repository) SetTransferBatchMessage(ctx context.Context, batchID, message string) error {
+	return r.db.WithContext(ctx).Model(&TransferBatch{}).
+		Where("id = ?", batchID).
+		Update("provider_message", message).Error
+}
+
+func (r *Repository) ListBatchTransactions(ctx context.Context, batchID string) ([]transaction.Transaction, error) {
+	var txs []transaction.Transaction
+	err := r.db.WithContext(ctx).
+		Where("metadata->>'batch_id' = ?", batchID).
+		Order("created_at ASC, id ASC").
+		Find(&txs).Error
+	return txs, err
+}
+
+// FindP2PRecipient returns the wallet a P2P transfer addressed by kind and
+// value should credit. Phone numbers must already be normalised to 234….
+func (r *Repository) FindP2PRecipient(ctx context.Context, kind P2PRecipientType, value string) (*CustomerWallet, error) {
+	if kind == P2PRecipientAccountNumber {
+		return r.GetWalletByAccountNumber(ctx, value)
+	}
+
+	query := r.db.WithContext(ctx).
+		Model(&CustomerWallet{}).
+		Joins("JOIN wallet_users u ON u.id = wallet_customer_wallets.mobile_user_id")
+	if kind == P2PRecipientPhone {
+		query = query.Where("u.phone = ?", value)
+	} else {
+		query = query.Where("LOWER(u.username) = LOWER(?)", value)
+	}
+
+	var w CustomerWallet
+	if err := query.First(&w).Error; err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// TransferBetweenWallets books a P2P transfer the provider has already moved
+// between the two customers' wallets, in one database transaction: it locks
+// both wallets, marks the pending debit successful and writes the credit with
+// their balance snapshots, and moves the amount between the two ledger wallet
+// accounts. The debit must have been booked with AddDebitTransaction.
+func (r *Repository) TransferBetweenWallets(ctx context.Context, debit, credit *transaction.Transaction) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return r.transferBetweenWallets(ctx, tx, debit, credit)
+	})
+}
+
+func (r *Repository) transferBetweenWallets(ctx context.Context, tx *gorm.DB, debit, credit *transaction.Transaction) error {
+	// lock in id order so opposite transfers between the same pair cannot deadlock
+	var wallets []CustomerWallet
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("internal_wallet_id IN ?", []string{debit.WalletID, credit.WalletID}).
+		Order("internal_wallet_id ASC").
+		Find(&wallets).Error; err != nil {
+		return err
+	}
+
+	var sender, recipient *CustomerWallet
+	for i := range wallets {
+		switch wallets[i].InternalWalletID {
+		case debit.WalletID:
+			sender = &wallets[i]
+		case credit.WalletID:
+			recipient = &wallets[i]
+		}
+	}
+	if sender == nil || recipient == nil {
+		return gorm.ErrRecordNotFound
+	}
+
+	// the provider has already moved the money, so the booking follows it
+	// whatever the wallets' balances or status are by now
+	debit.Status = transaction.TransactionStatusSuccessful
+	debit.BalanceBefore = sender.AvailableBalance
+	debit.BalanceAfter = sender.AvailableBalance - debit.Amount
+	credit.BalanceBefore = recipient.AvailableBalance
+	credit.BalanceAfter = recipient.AvailableBalance + credit.Amount
+
+	settled := tx.Model(&transaction.Transaction{}).
+		Where("id = ? AND status = ?", debit.ID, transaction.TransactionStatusPending).
+		Updates(map[string]interface{}{
+			"status":         debit.Status,
+			"balance_before": debit.BalanceBefore,
+			"balance_after":  debit.BalanceAfter,
+		})
+	if settled.Error != nil {
+		return settled.Error
+	}
+	if settled.RowsAffected != 1 {
+		return ErrDebitNotPending
+	}
+	if err := tx.Create(credit).Error; err != nil {
+		return err
+	}
+
+	if err := r.ledger.WithTx(tx).PostWalletTransfer(ctx, ledger.Movement{
+		WalletID:       sender.InternalWalletID,
+		OpeningBalance: sender.AvailableBalance,
+		TransactionID:  debit.ID,
+		Reference:      "p2p:" + debit.ID,
+		Description:    "Wallet to wallet transfer",
+		Amount:         debit.Amount,
+	}, recipient.InternalWalletID, recipient.AvailableBalance); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := tx.Model(&CustomerWallet{}).
+		Where("internal_wallet_id = ?", sender.InternalWalletID).
+		Updates(map[string]interface{}{
+			"booked_balance":    gorm.Expr("booked_balance - ?", debit.Amount),
+			"available_balance": gorm.Expr("available_balance - ?", debit.Amount),
+			"updated_at":        now,
+		}).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&CustomerWallet{}).
+		Where("internal_wallet_id = ?", recipient.InternalWalletID).
+		Updates(map[string]interface{}{
+			"booked_balance":    gorm.Expr("booked_balance + ?", credit.Amount),
+			"available_balance": gorm.Expr("available_balance + ?", credit.Amount),
+			"updated_at":        now,
+		}).Error
+}
+
+func (r *Repository) CreatePaymentRequest(ctx context.Context, request *PaymentRequest, deposit *ExpectedDeposit) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(deposit).Error; err != nil {
+			return err
+		}
+		return tx.Create(request).Error
+	})
+}
+
+func (r *Repository) GetPaymentRequest(ctx context.Context, mobileUserID, id string) (*PaymentRequest, error) {
+	var request PaymentRequest
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND mobile_user_id = ?", id, mobileUserID).
+		First(&request).Error
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (r *Repository) GetPaymentRequestByCode(ctx context.Context, code string) (*PaymentRequest, error) {
+	var request PaymentRequest
+	if err := r.db.WithContext(ctx).Where("code = ?", code).First(&request).Error; err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (r *Repository) ListPaymentRequests(ctx context.Context, mobileUserID string, limit int) ([]PaymentRequest, error) {
+	var requests []PaymentRequest
+	err := r.db.WithContext(ctx).
+		Where("mobile_user_id = ?", mobileUserID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&requests).Error
+	return requests, err
+}
+
+// ClosePaymentRequest moves a pending request to status and closes its
+// expected deposit. It reports false when the request was no longer pending.
+func (r *Repository) ClosePaymentRequest(ctx context.Context, id string, status PaymentRequestStatus) (bool, error) {
+	closed := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var request PaymentRequest
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", id).
+			First(&request).Error; err != nil {
+			return err
+		}
+		if request.Status != PaymentRequestStatusPending {
+			return nil
+		}
+
+		if err := tx.Model(&PaymentRequest{}).
+			Where("id = ?", id).
+			Update("status", status).Error; err != nil {
+			return err
+		}
+		closed = true
+		return closeExpectedDeposit(tx, request.ExpectedDepositID)
+	})
+	return closed, err
+}
+
+// ClaimPaymentRequest books the payer's pending debit for a pending request
+// and marks the request processing, in one database transaction, so no one
+// else can pay it while the provider moves the money.
+func (r *Repository) ClaimPaymentRequest(ctx context.Context, id, payerID string, debit *transaction.Transaction, now time.Time, check DebitCheck) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var request PaymentRequest
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", id).
+			First(&request).Error; err != nil {
+			return err
+		}
+		if request.Status != PaymentRequestStatusPending || !request.ExpiresAt.After(now) {
+			return ErrPaymentRequestClosed
+		}
+
+		if err := bookDebit(tx, debit, 0, check); err != nil {
+			return err
+		}
+
+		return tx.Model(&PaymentRequest{}).
+			Where("id = ?", id).
+			Updates(map[string]interface{}{
+				"status":          PaymentRequestStatusProcessing,
+				"paid_by_user_id": payerID,
+			}).Error
+	})
+}
+
+// ReleasePaymentRequest fails the claimed debit and reopens the request after
+// the provider could not move the money.
+func (r *Repository) ReleasePaymentRequest(ctx context.Context, id, debitID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&transaction.Transaction{}).
+			Where("id = ? AND status = ?", debitID, transaction.TransactionStatusPending).
+			Update("status", transaction.TransactionStatusFailed).Error; err != nil {
+			return err
+		}
+		return tx.Model(&PaymentRequest{}).
+			Where("id = ? AND status = ?", id, PaymentRequestStatusProcessing).
+			Updates(map[string]interface{}{
+				"status":          PaymentRequestStatusPending,
+				"paid_by_user_id": nil,
+			}).Error
+	})
+}
+
+// PayPaymentRequest books the in-app transfer the provider has made for a
+// claimed request, in the same database transaction that marks it paid.
+func (r *Repository) PayPaymentRequest(ctx context.Context, id string, debit, credit *transaction.Transaction, now time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var request PaymentRequest
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", id).
+			First(&request).Error; err != nil {
+			return err
+		}
+		if request.Status != PaymentRequestStatusProcessing {
+			return ErrPaymentRequestClosed
+		}
+
+		if err := r.transferBetweenWallets(ctx, tx, debit, credit); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&PaymentRequest{}).
+			Where("id = ?", id).
+			Updates(map[string]interface{}{
+				"status":         PaymentRequestStatusPaid,
+				"transaction_id": credit.ID,
+				"paid_at":        now,
+			}).Error; err != nil {
+			return err
+		}
+		return closeExpectedDeposit(tx, request.ExpectedDepositID)
+	})
+}
+
+// SettlePaymentRequestByDeposit updates the pending request behind an expected
+// deposit. It returns nil when no pending request uses the deposit.
+func (r *Repository) SettlePaymentRequestByDeposit(ctx context.Context, depositID string, status PaymentRequestStatus, transactionID *string, now time.Time) (*PaymentRequest, error) {
+	var settled *PaymentRequest
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var request PaymentRequest
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("expected_deposit_id = ? AND status = ?", depositID, PaymentRequestStatusPending).
+			First(&request).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{"status": status}
+		if status == PaymentRequestStatusPaid {
+			updates["transaction_id"] = transactionID
+			updates["paid_at"] = now
+		}
+		if err := tx.Model(&PaymentRequest{}).Where("id = ?", request.ID).Updates(updates).Error; err != nil {
+			return err
+		}
+		request.Status = status
+		settled = &request
+		return nil
+	})
+	return settled, err
+}
+
+func closeExpectedDeposit(tx *gorm.DB, depositID string) error {
+	return tx.Model(&ExpectedDeposit{}).
+		Where("id = ? AND status IN ?", depositID, openExpectedDepositStatuses).
+		Update("status", ExpectedDepositStatusCancelled).Error
+}
+
+func (r *Repository) ListBankDirectory(ctx context.Context) ([]BankEntry, error) {
+	var banks []BankEntry
+	err := r.db.WithContext(ctx).
+		Order("transfer_count DESC, name ASC").
+		Find(&banks).Error
+	return banks, err
+}
+
+// UpsertBankDirectory stores the provider's bank list, keeping transfer counts
+// of banks already known, and drops banks absent from refreshes for longer
+// than staleBefore.
+func (r *Repository) UpsertBankDirectory(ctx context.Context, banks []BankEntry, staleBefore time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "code"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "refreshed_at", "updated_at"}),
+		}).CreateInBatches(banks, 200).Error; err != nil {
+			return err
+		}
+		return tx.Where("refreshed_at < ?", staleBefore).Delete(&BankEntry{}).Error
+	})
+}
+
+func (r *Repository) IncrementBankTransferCount(ctx context.Context, bankCode string) error {
+	return r.db.WithContext(ctx).
+		Model(&BankEntry{}).
+		Where("code = ?", bankCode).
+		UpdateColumn("transfer_count", gorm.Expr("transfer_count + 1")).Error
+}
+
+func (r *Repository) GetAccountNameLookup(ctx context.Context, bankCode, accountNumber string, now time.Time) (*AccountNameLookup, error) {
+	var lookup AccountNameLookup
+	err := r.db.WithContext(ctx).
+		Where("bank_code = ? AND account_number = ? AND expires_at > ?", bankCode, accountNumber, now).
+		First(&lookup).Error
+	if err != nil {
+		return nil, err
+	}
+	return &lookup, nil
+}
+
+func (r *Repository) SaveAccountNameLookup(ctx context.Context, lookup *AccountNameLookup) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "bank_code"}, {Name: "account_number"}},
+			DoUpdates: clause.AssignmentColumns([]string{"account_name", "expires_at"}),
+		}).
+		Create(lookup).Error
+}
+
+func (r *Repository) PurgeExpiredAccountNameLookups(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("expires_at <= ?", now).
+		Delete(&AccountNameLookup{})
+	return result.RowsAffected, result.Error
+}
+
+// SetWalletStatus applies event to its wallet under a row lock. The wallet must
+// still be in event.FromStatus; closing needs an empty wallet unless
+// allowBalance is set.
+func (r *Repository) SetWalletStatus(ctx context.Context, event *WalletStatusEvent, allowBalance bool) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var wallet CustomerWallet
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("internal_wallet_id = ?", event.WalletID).
+			First(&wallet).Error; err != nil {
+			return err
+		}
+		if NormalizeWalletStatus(wallet.Status) != event.FromStatus {
+			return ErrWalletStatusConflict
+		}
+		if event.ToStatus == WalletStatusClosed && !allowBalance && wallet.AvailableBalance != 0 {
+			return ErrWalletNotEmpty
+		}
+
+		if err := tx.Model(&CustomerWallet{}).
+			Where("internal_wallet_id = ?", event.WalletID).
+			Updates(map[string]interface{}{
+				"status":     string(event.ToStatus),
+				"updated_at": time.Now(),
+			}).Error; err != nil {
+			return err
+		}
+
+		event.BalanceAtChange = wallet.AvailableBalance
+		return tx.Create(event).Error
+	})
+}
+
+func (r *Repository) ListWalletStatusEvents(ctx context.Context, walletID string) ([]WalletStatusEvent, error) {
+	var events []WalletStatusEvent
+	err := r.db.WithContext(ctx).
+		Where("wallet_id = ?", walletID).
+		Order("created_at DESC").
+		Find(&events).Error
+	return events, err
+}
+
+func (r *Repository) CancelOpenScheduledTransfers(ctx context.Context, mobileUserID string) error {
+	return r.db.WithContext(ctx).
+		Model(&ScheduledTransfer{}).
+		Where("mobile_user_id = ? AND status IN ?", mobileUserID,
+			[]ScheduledTransferStatus{ScheduledTransferStatusActive, ScheduledTransferStatusPaused}).
+		Update("status", ScheduledTransferStatusCancelled).Error
+}
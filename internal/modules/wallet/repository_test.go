@@ -0,0 +1,501 @@
+package wallet
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	appErr "neat_mobile_app_backend/internal/errors"
+	"neat_mobile_app_backend/internal/modules/ledger"
+	"neat_mobile_app_backend/internal/modules/transaction"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func newMockRepository(t *testing.T) (*Repository, sqlmock.Sqlmock, func()) {
+	t.Helper()
+
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("create sqlmock: %v", err)
+	}
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: sqlDB,
+	}), &gorm.Config{
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		_ = sqlDB.Close()
+		t.Fatalf("open gorm db: %v", err)
+	}
+
+	cleanup := func() {
+		_ = sqlDB.Close()
+	}
+
+	return NewRepository(gormDB), mock, cleanup
+}
+
+func lockWalletQueryPattern() string {
+	return regexp.QuoteMeta(`SELECT * FROM "wallet_customer_wallets" WHERE internal_wallet_id = $1 ORDER BY "wallet_customer_wallets"."id" LIMIT $2 FOR UPDATE`)
+}
+
+func insertTransactionQueryPattern() string {
+	return regexp.QuoteMeta(`INSERT INTO "wallet_transactions"`)
+}
+
+func walletRows(walletID, status string, available int64) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "internal_wallet_id", "mobile_user_id", "wallet_customer_id", "status", "available_balance", "booked_balance"}).
+		AddRow("row-"+walletID, walletID, "user-"+walletID, "cust-"+walletID, status, available, available)
+}
+
+func pendingDebit(walletID string, amount int64) *transaction.Transaction {
+	return &transaction.Transaction{
+		ID:           "tx-1",
+		MobileUserID: "user-" + walletID,
+		WalletID:     walletID,
+		Type:         transaction.TransactionTypeDebit,
+		Category:     transaction.TransactionCategoryTransferTo,
+		Amount:       amount,
+		Reference:    "ref-1",
+		Source:       "transfer",
+		Status:       transaction.TransactionStatusPending,
+	}
+}
+
+func TestRepository_AddDebitTransaction_RefusesWalletFrozenInFlight(t *testing.T) {
+	repo, mock, cleanup := newMockRepository(t)
+	defer cleanup()
+
+	// the service read the wallet as active; a freeze committed since then
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockWalletQueryPattern()).
+		WithArgs("iw-1", 1).
+		WillReturnRows(walletRows("iw-1", string(WalletStatusFrozen), 500_000))
+	mock.ExpectRollback()
+
+	err := repo.AddDebitTransaction(context.Background(), pendingDebit("iw-1", 100_000), 0, nil)
+	if !errors.Is(err, appErr.ErrWalletFrozen) {
+		t.Fatalf("expected ErrWalletFrozen, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sqlmock expectations: %v", err)
+	}
+}
+
+func TestRepository_AddDebitTransaction_BooksDebitOnActiveWallet(t *testing.T) {
+	repo, mock, cleanup := newMockRepository(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockWalletQueryPattern()).
+		WithArgs("iw-1", 1).
+		WillReturnRows(walletRows("iw-1", string(WalletStatusActive), 500_000))
+	mock.ExpectExec(insertTransactionQueryPattern()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := repo.AddDebitTransaction(context.Background(), pendingDebit("iw-1", 100_000), 0, nil); err != nil {
+		t.Fatalf("AddDebitTransaction returned error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sqlmock expectations: %v", err)
+	}
+}
+
+func TestRepository_AddDebitTransaction_RefusesFundsAlreadyMovedInApp(t *testing.T) {
+	repo, mock, cleanup := newMockRepository(t)
+	defer cleanup()
+
+	// ₦1,000 of a ₦1,500 balance already went out through an in-app transfer,
+	// so a ₦500 external transfer can't also cover its charge
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockWalletQueryPattern()).
+		WithArgs("iw-1", 1).
+		WillReturnRows(walletRows("iw-1", string(WalletStatusActive), 50_000))
+	mock.ExpectRollback()
+
+	err := repo.AddDebitTransaction(context.Background(), pendingDebit("iw-1", 50_000), TransferChargeKobo(50_000), nil)
+	if !errors.Is(err, ErrInsufficientFunds) {
+		t.Fatalf("expected ErrInsufficientFunds, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sqlmock expectations: %v", err)
+	}
+}
+
+func paymentRequestRows(id string, status PaymentRequestStatus, expiresAt time.Time) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "mobile_user_id", "wallet_id", "amount", "status", "expected_deposit_id", "expires_at"}).
+		AddRow(id, "user-iw-2", "iw-2", 100_000, string(status), "dep-1", expiresAt)
+}
+
+func TestRepository_PayPaymentRequest_PaidFundsCannotBeSpentExternally(t *testing.T) {
+	repo, mock, cleanup := newMockRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+	debit := pendingDebit("iw-1", 100_000)
+	debit.Source = "p2p"
+	credit := &transaction.Transaction{
+		ID:           "tx-2",
+		MobileUserID: "user-iw-2",
+		WalletID:     "iw-2",
+		Type:         transaction.TransactionTypeCredit,
+		Category:     transaction.TransactionCategoryTransferFrom,
+		Amount:       100_000,
+		Reference:    "ref-2",
+		Source:       "p2p",
+		Status:       transaction.TransactionStatusSuccessful,
+	}
+
+	// claim: the ₦1,000 is held against the ₦1,500 balance before the provider moves it
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "wallet_payment_requests" WHERE id = $1 ORDER BY "wallet_payment_requests"."id" LIMIT $2 FOR UPDATE`)).
+		WithArgs("pr-1", 1).
+		WillReturnRows(paymentRequestRows("pr-1", PaymentRequestStatusPending, now.Add(time.Hour)))
+	mock.ExpectQuery(lockWalletQueryPattern()).
+		WithArgs("iw-1", 1).
+		WillReturnRows(walletRows("iw-1", string(WalletStatusActive), 150_000))
+	mock.ExpectExec(insertTransactionQueryPattern()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallet_payment_requests" SET "paid_by_user_id"=$1,"status"=$2`)).
+		WithArgs("user-iw-1", string(PaymentRequestStatusProcessing), sqlmock.AnyArg(), "pr-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// settle: the provider has moved the money, so the wallet row is debited
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "wallet_payment_requests" WHERE id = $1 ORDER BY "wallet_payment_requests"."id" LIMIT $2 FOR UPDATE`)).
+		WithArgs("pr-1", 1).
+		WillReturnRows(paymentRequestRows("pr-1", PaymentRequestStatusProcessing, now.Add(time.Hour)))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "wallet_customer_wallets" WHERE internal_wallet_id IN ($1,$2) ORDER BY internal_wallet_id ASC FOR UPDATE`)).
+		WithArgs("iw-1", "iw-2").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "internal_wallet_id", "status", "available_balance", "booked_balance"}).
+			AddRow("row-iw-1", "iw-1", string(WalletStatusActive), 150_000, 150_000).
+			AddRow("row-iw-2", "iw-2", string(WalletStatusActive), 20_000, 20_000))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallet_transactions" SET "balance_after"=$1,"balance_before"=$2,"status"=$3`)).
+		WithArgs(int64(50_000), int64(150_000), string(transaction.TransactionStatusSuccessful), sqlmock.AnyArg(), "tx-1", string(transaction.TransactionStatusPending)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(insertTransactionQueryPattern()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "wallet_ledger_accounts"`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "wallet_ledger_accounts"`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "wallet_ledger_journal_entries"`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "wallet_ledger_postings"`)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallet_customer_wallets" SET "available_balance"=available_balance - $1,"booked_balance"=booked_balance - $2`)).
+		WithArgs(int64(100_000), int64(100_000), sqlmock.AnyArg(), "iw-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallet_customer_wallets" SET "available_balance"=available_balance + $1,"booked_balance"=booked_balance + $2`)).
+		WithArgs(int64(100_000), int64(100_000), sqlmock.AnyArg(), "iw-2").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallet_payment_requests" SET "paid_at"=$1,"status"=$2,"transaction_id"=$3`)).
+		WithArgs(sqlmock.AnyArg(), string(PaymentRequestStatusPaid), "tx-2", sqlmock.AnyArg(), "pr-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallet_expected_deposits" SET "status"=$1`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// the ₦500 left can't fund a ₦1,000 external transfer as well
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockWalletQueryPattern()).
+		WithArgs("iw-1", 1).
+		WillReturnRows(walletRows("iw-1", string(WalletStatusActive), 50_000))
+	mock.ExpectRollback()
+
+	if err := repo.ClaimPaymentRequest(ctx, "pr-1", "user-iw-1", debit, now, nil); err != nil {
+		t.Fatalf("ClaimPaymentRequest returned error: %v", err)
+	}
+	if err := repo.PayPaymentRequest(ctx, "pr-1", debit, credit, now); err != nil {
+		t.Fatalf("PayPaymentRequest returned error: %v", err)
+	}
+
+	external := pendingDebit("iw-1", 100_000)
+	external.ID = "tx-3"
+	err := repo.AddDebitTransaction(ctx, external, TransferChargeKobo(100_000), nil)
+	if !errors.Is(err, ErrInsufficientFunds) {
+		t.Fatalf("expected ErrInsufficientFunds, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sqlmock expectations: %v", err)
+	}
+}
+
+// postingColumns is how many values gorm binds per row of wallet_ledger_postings:
+// id, journal_entry_id, account_id, direction, amount, created_at.
+const postingColumns = 6
+
+// capturedPostings records the rows bound to a postings insert so a test can
+// check what the ledger was given.
+type capturedPostings struct {
+	values []driver.Value
+}
+
+type captureArg struct {
+	into *capturedPostings
+}
+
+func (a captureArg) Match(v driver.Value) bool {
+	a.into.values = append(a.into.values, v)
+	return true
+}
+
+func (c *capturedPostings) args(rows int) []driver.Value {
+	out := make([]driver.Value, rows*postingColumns)
+	for i := range out {
+		out[i] = captureArg{into: c}
+	}
+	return out
+}
+
+// walletMovement fails unless the captured postings balance, and returns how
+// far they moved the wallet's ledger account (credits less debits).
+func (c *capturedPostings) walletMovement(t *testing.T, walletID string) int64 {
+	t.Helper()
+
+	var debits, credits, net int64
+	for i := 0; i+postingColumns <= len(c.values); i += postingColumns {
+		account, _ := c.values[i+2].(string)
+		direction, _ := c.values[i+3].(string)
+		amount, _ := c.values[i+4].(int64)
+		sign := int64(1)
+		if ledger.Direction(direction) == ledger.DirectionDebit {
+			debits += amount
+			sign = -1
+		} else {
+			credits += amount
+		}
+		if account == ledger.WalletAccountID(walletID) {
+			net += sign * amount
+		}
+	}
+	if debits == 0 || debits != credits {
+		t.Fatalf("unbalanced entry: debits %d, credits %d", debits, credits)
+	}
+	return net
+}
+
+func lockTransactionQueryPattern() string {
+	return regexp.QuoteMeta(`SELECT * FROM "wallet_transactions" WHERE id = $1 ORDER BY "wallet_transactions"."id" LIMIT $2 FOR UPDATE`)
+}
+
+func TestRepository_CompleteDebitTransaction_SettlesReservedDebit(t *testing.T) {
+	repo, mock, cleanup := newMockRepository(t)
+	defer cleanup()
+
+	// ₦1,000 and a ₦10 charge estimate were reserved from ₦5,010; the
+	// provider charged ₦10 plus 75 kobo VAT
+	opening, debit := &capturedPostings{}, &capturedPostings{}
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockWalletQueryPattern()).
+		WithArgs("iw-1", 1).
+		WillReturnRows(walletRows("iw-1", string(WalletStatusActive), 400_000))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id","metadata" FROM "wallet_transactions" WHERE id = $1`)).
+		WithArgs("tx-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "metadata"}).
+			AddRow("tx-1", []byte(`{"reserved": true, "reserved_charges": 1000}`)))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallet_transactions" SET "balance_after"=$1,"balance_before"=$2,"charges"=$3`)).
+		WithArgs(int64(399_925), int64(501_000), int64(1_000), "PRV-1", string(transaction.TransactionStatusSuccessful), int64(75), sqlmock.AnyArg(), "tx-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "wallet_ledger_accounts"`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "wallet_ledger_journal_entries"`)).
+		WithArgs(sqlmock.AnyArg(), "opening:iw-1", nil, "Opening balance", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "wallet_ledger_postings"`)).
+		WithArgs(opening.args(2)...).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "wallet_ledger_journal_entries"`)).
+		WithArgs(sqlmock.AnyArg(), "debit:tx-1", "tx-1", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "wallet_ledger_postings"`)).
+		WithArgs(debit.args(4)...).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	// the booked balance takes the whole debit; the available balance only
+	// the VAT the reservation didn't cover
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallet_customer_wallets" SET "available_balance"=available_balance - $1,"booked_balance"=booked_balance - $2`)).
+		WithArgs(int64(75), int64(101_075), sqlmock.AnyArg(), "iw-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.CompleteDebitTransaction(context.Background(), "tx-1", "PRV-1", transaction.TransactionStatusSuccessful, "iw-1", 100_000, 1_000, 75)
+	if err != nil {
+		t.Fatalf("CompleteDebitTransaction returned error: %v", err)
+	}
+
+	if got := opening.walletMovement(t, "iw-1"); got != 501_000 {
+		t.Fatalf("opening balance seeded %d, want 501000", got)
+	}
+	if got := debit.walletMovement(t, "iw-1"); got != -101_075 {
+		t.Fatalf("debit moved the wallet by %d, want -101075", got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sqlmock expectations: %v", err)
+	}
+}
+
+func TestRepository_CompleteDebitTransaction_RollsBackWhenLedgerFails(t *testing.T) {
+	repo, mock, cleanup := newMockRepository(t)
+	defer cleanup()
+
+	postErr := errors.New("insert failed")
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockWalletQueryPattern()).
+		WithArgs("iw-1", 1).
+		WillReturnRows(walletRows("iw-1", string(WalletStatusActive), 500_000))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "id","metadata" FROM "wallet_transactions" WHERE id = $1`)).
+		WithArgs("tx-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "metadata"}).AddRow("tx-1", nil))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallet_transactions"`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "wallet_ledger_accounts"`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "wallet_ledger_journal_entries"`)).
+		WillReturnError(postErr)
+	mock.ExpectExec(`ROLLBACK TO SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err := repo.CompleteDebitTransaction(context.Background(), "tx-1", "PRV-1", transaction.TransactionStatusSuccessful, "iw-1", 100_000, 1_000, 75)
+	if !errors.Is(err, postErr) {
+		t.Fatalf("expected the ledger error, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sqlmock expectations: %v", err)
+	}
+}
+
+func TestRepository_ReverseDebitTransaction_CreditsSettledDebitBack(t *testing.T) {
+	repo, mock, cleanup := newMockRepository(t)
+	defer cleanup()
+
+	credit := &capturedPostings{}
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockTransactionQueryPattern()).
+		WithArgs("tx-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "mobile_user_id", "wallet_id", "type", "transaction_category", "amount", "charges", "vat", "status", "source", "metadata"}).
+			AddRow("tx-1", "user-iw-1", "iw-1", string(transaction.TransactionTypeDebit), string(transaction.TransactionCategoryTransferTo),
+				100_000, 1_000, 75, string(transaction.TransactionStatusReversalPending), string(transaction.TransactionSourceDebit),
+				[]byte(`{"refund_reference": "ref-1-RV"}`)))
+	mock.ExpectQuery(lockWalletQueryPattern()).
+		WithArgs("iw-1", 1).
+		WillReturnRows(walletRows("iw-1", string(WalletStatusActive), 398_925))
+	mock.ExpectExec(insertTransactionQueryPattern()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "wallet_ledger_accounts"`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "wallet_ledger_journal_entries"`)).
+		WithArgs(sqlmock.AnyArg(), "reversal:tx-1", sqlmock.AnyArg(), "dispute upheld", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "wallet_ledger_postings"`)).
+		WithArgs(credit.args(4)...).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallet_transactions" SET "metadata"=`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallet_customer_wallets" SET "available_balance"=available_balance + $1,"booked_balance"=booked_balance + $2`)).
+		WithArgs(int64(101_075), int64(101_075), sqlmock.AnyArg(), "iw-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	reversal, err := repo.ReverseDebitTransaction(context.Background(), "tx-1", "dispute upheld")
+	if err != nil {
+		t.Fatalf("ReverseDebitTransaction returned error: %v", err)
+	}
+	if reversal.Amount != 101_075 || reversal.BalanceAfter != 500_000 {
+		t.Fatalf("reversal = %d leaving %d, want 101075 leaving 500000", reversal.Amount, reversal.BalanceAfter)
+	}
+	if reversal.ProviderReference != "ref-1-RV" {
+		t.Fatalf("reversal provider reference = %q, want the refund's", reversal.ProviderReference)
+	}
+	if got := credit.walletMovement(t, "iw-1"); got != 101_075 {
+		t.Fatalf("reversal moved the wallet by %d, want 101075", got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sqlmock expectations: %v", err)
+	}
+}
+
+func TestRepository_ReverseDebitTransaction_RollsBackWhenBalanceUpdateFails(t *testing.T) {
+	repo, mock, cleanup := newMockRepository(t)
+	defer cleanup()
+
+	updateErr := errors.New("update failed")
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockTransactionQueryPattern()).
+		WithArgs("tx-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "wallet_id", "type", "amount", "status", "source"}).
+			AddRow("tx-1", "iw-1", string(transaction.TransactionTypeDebit), 100_000,
+				string(transaction.TransactionStatusSuccessful), string(transaction.TransactionSourceDebit)))
+	mock.ExpectQuery(lockWalletQueryPattern()).
+		WithArgs("iw-1", 1).
+		WillReturnRows(walletRows("iw-1", string(WalletStatusActive), 400_000))
+	mock.ExpectExec(insertTransactionQueryPattern()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "wallet_ledger_accounts"`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "wallet_ledger_journal_entries"`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "wallet_ledger_postings"`)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallet_transactions"`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallet_customer_wallets"`)).
+		WillReturnError(updateErr)
+	mock.ExpectRollback()
+
+	if _, err := repo.ReverseDebitTransaction(context.Background(), "tx-1", "dispute upheld"); !errors.Is(err, updateErr) {
+		t.Fatalf("expected the balance update error, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sqlmock expectations: %v", err)
+	}
+}
+
+func TestRepository_TransferBetweenWallets_RollsBackWhenDebitAlreadySettled(t *testing.T) {
+	repo, mock, cleanup := newMockRepository(t)
+	defer cleanup()
+
+	debit := pendingDebit("iw-1", 100_000)
+	credit := &transaction.Transaction{ID: "tx-2", WalletID: "iw-2", Type: transaction.TransactionTypeCredit, Amount: 100_000}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "wallet_customer_wallets" WHERE internal_wallet_id IN ($1,$2) ORDER BY internal_wallet_id ASC FOR UPDATE`)).
+		WithArgs("iw-1", "iw-2").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "internal_wallet_id", "status", "available_balance", "booked_balance"}).
+			AddRow("row-iw-1", "iw-1", string(WalletStatusActive), 150_000, 150_000).
+			AddRow("row-iw-2", "iw-2", string(WalletStatusActive), 20_000, 20_000))
+	// a retry finds the debit already booked, so nothing else is written
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallet_transactions" SET "balance_after"=$1,"balance_before"=$2,"status"=$3`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	if err := repo.TransferBetweenWallets(context.Background(), debit, credit); !errors.Is(err, ErrDebitNotPending) {
+		t.Fatalf("expected ErrDebitNotPending, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet sqlmock expectations: %v", err)
+	}
+}
@@ -8,10 +8,34 @@ func RegisterRoutes(rg *gin.RouterGroup, handler *Handler, authGuard, deviceVali
 		wallet.GET("/banks", handler.FetchBanks)
 		wallet.GET("/bank/details", handler.FetchBankDetails)
 		wallet.POST("/transfer", handler.InitiateTransfer)
+		wallet.POST("/transfer/bulk", handler.InitiateBulkTransfer)
+		wallet.GET("/transfer/bulk/:batch_id", handler.GetTransferBatch)
+		wallet.GET("/p2p/recipient", handler.ResolveP2PRecipient)
+		wallet.POST("/p2p/transfer", handler.P2PTransfer)
+		wallet.POST("/payment-requests", handler.CreatePaymentRequest)
+		wallet.GET("/payment-requests", handler.ListPaymentRequests)
+		wallet.GET("/payment-requests/:id", handler.GetPaymentRequest)
+		wallet.DELETE("/payment-requests/:id", handler.CancelPaymentRequest)
+		wallet.POST("/pay/:code", handler.PayPaymentRequest)
 		wallet.POST("/beneficiary", handler.AddBeneficiary)
 		wallet.GET("/beneficiaries", handler.GetBeneficiaries)
+		wallet.PATCH("/beneficiaries/:id", handler.UpdateBeneficiary)
+		wallet.DELETE("/beneficiaries/:id", handler.DeleteBeneficiary)
+		wallet.POST("/beneficiaries/:id/verify", handler.VerifyBeneficiary)
+		wallet.POST("/deposit", handler.InitiateDeposit)
+		wallet.GET("/deposit/:tracking_id", handler.GetExpectedDeposit)
+		wallet.POST("/scheduled-transfers", handler.CreateScheduledTransfer)
+		wallet.GET("/scheduled-transfers", handler.ListScheduledTransfers)
+		wallet.GET("/scheduled-transfers/:id", handler.GetScheduledTransfer)
+		wallet.PATCH("/scheduled-transfers/:id", handler.UpdateScheduledTransfer)
+		wallet.POST("/scheduled-transfers/:id/pause", handler.PauseScheduledTransfer)
+		wallet.POST("/scheduled-transfers/:id/resume", handler.ResumeScheduledTransfer)
+		wallet.DELETE("/scheduled-transfers/:id", handler.CancelScheduledTransfer)
+		wallet.POST("/close", handler.CloseWallet)
 	}
 
+	// pay links are shared outside the app, so viewing one needs no session
+	rg.GET("/pay/:code", handler.GetPayLink)
 }
 
 func RegisterWebhookRoutes(rg *gin.RouterGroup, handler *Handler, webhookAuth gin.HandlerFunc) {
@@ -20,3 +44,14 @@ func RegisterWebhookRoutes(rg *gin.RouterGroup, handler *Handler, webhookAuth gi
 		providus.POST("/credit", handler.HandleCreditWebhook)
 	}
 }
+
+func RegisterInternalRoutes(rg *gin.RouterGroup, handler *Handler, internalAuth gin.HandlerFunc) {
+	wallet := rg.Group("/wallet")
+	wallet.Use(internalAuth)
+
+	{
+		wallet.POST("/transactions/:id/reverse", handler.ReverseTransaction)
+		wallet.GET("/wallets/:account_number/status", handler.GetWalletStatus)
+		wallet.POST("/wallets/:account_number/status", handler.ChangeWalletStatus)
+	}
+}
@@ -8,9 +8,13 @@ import (
 	"math"
 	"neat_mobile_app_backend/internal/authchecker"
 	appErr "neat_mobile_app_backend/internal/errors"
+	"neat_mobile_app_backend/internal/modules/limits"
+	"neat_mobile_app_backend/internal/modules/notification"
 	"neat_mobile_app_backend/internal/modules/transaction"
+	"neat_mobile_app_backend/internal/types"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -29,33 +33,140 @@ type Service struct {
 	pinVerifier       *authchecker.Verifier
 	settlementAccount SettlementAccount
 	deviceVerifier    DeviceVerifier
+	notifier          *notification.Service
+	payLinkBaseURL    string
+	limits            LimitChecker
+	secondFactor      SecondFactorVerifier
+	highValueKobo     int64
+	depositTolerance  int64
 }
 
-func NewService(repo *Repository, providusService ProvidusService, pinVerifier *authchecker.Verifier, settlementAccount SettlementAccount, deviceVerifier DeviceVerifier) *Service {
+func NewService(repo *Repository, providusService ProvidusService, pinVerifier *authchecker.Verifier, settlementAccount SettlementAccount, deviceVerifier DeviceVerifier, notifier *notification.Service, payLinkBaseURL string, limitChecker LimitChecker) *Service {
 	return &Service{
 		repo:              repo,
 		providusService:   providusService,
 		pinVerifier:       pinVerifier,
 		settlementAccount: settlementAccount,
 		deviceVerifier:    deviceVerifier,
+		notifier:          notifier,
+		payLinkBaseURL:    strings.TrimRight(payLinkBaseURL, "/"),
+		limits:            limitChecker,
+		depositTolerance:  defaultExpectedDepositTolerance,
 	}
 }
 
-func (s *Service) FetchBanks(ctx context.Context) ([]Bank, error) {
-	banks, err := s.providusService.FetchBanks(ctx)
+// ConfigureSecondFactor makes transfers of at least highValueKobo require an
+// authenticator code from users who have enabled one. Users without an
+// authenticator are unaffected.
+func (s *Service) ConfigureSecondFactor(verifier SecondFactorVerifier, highValueKobo int64) {
+	s.secondFactor = verifier
+	s.highValueKobo = highValueKobo
+}
+
+// ConfigureDepositMatching sets how far, in kobo, a credit may be from an
+// expected deposit's outstanding amount and still settle it.
+func (s *Service) ConfigureDepositMatching(toleranceKobo int64) {
+	if toleranceKobo >= 0 {
+		s.depositTolerance = toleranceKobo
+	}
+}
+
+// FetchBanks serves the bank directory from Postgres, most used banks first,
+// filtered by query when one is given. Providus is only called when the
+// directory has never been populated.
+func (s *Service) FetchBanks(ctx context.Context, query string) ([]Bank, error) {
+	entries, err := s.repo.ListBankDirectory(ctx)
 	if err != nil {
+		log.Printf("wallet service: failed to load bank directory: %v", err)
 		return nil, appErr.ErrFetchingBanks
 	}
 
-	return banks, nil
+	if len(entries) == 0 {
+		if err := s.RefreshBankDirectory(ctx); err != nil {
+			log.Printf("wallet service: bank directory is empty and refresh failed: %v", err)
+			return nil, appErr.ErrFetchingBanks
+		}
+		if entries, err = s.repo.ListBankDirectory(ctx); err != nil {
+			log.Printf("wallet service: failed to load bank directory: %v", err)
+			return nil, appErr.ErrFetchingBanks
+		}
+	}
+
+	return searchBanks(entries, query), nil
+}
+
+// RefreshBankDirectory replaces the cached directory with Providus's current
+// bank list. On failure the existing directory is left untouched.
+func (s *Service) RefreshBankDirectory(ctx context.Context) error {
+	banks, err := s.providusService.FetchBanks(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch banks from provider: %w", err)
+	}
+
+	now := time.Now().UTC()
+	entries := make([]BankEntry, 0, len(banks))
+	seen := make(map[string]struct{}, len(banks))
+	for _, bank := range banks {
+		code := strings.TrimSpace(bank.Code)
+		name := strings.TrimSpace(bank.Name)
+		if code == "" || name == "" {
+			continue
+		}
+		if _, ok := seen[code]; ok {
+			continue
+		}
+		seen[code] = struct{}{}
+		entries = append(entries, BankEntry{Code: code, Name: name, RefreshedAt: now})
+	}
+	if len(entries) == 0 {
+		return errors.New("provider returned no banks")
+	}
+
+	if err := s.repo.UpsertBankDirectory(ctx, entries, now.Add(-bankDirectoryStaleAfter)); err != nil {
+		return fmt.Errorf("store bank directory: %w", err)
+	}
+	return nil
 }
 
+// PurgeAccountNameLookups deletes expired account-name cache entries.
+func (s *Service) PurgeAccountNameLookups(ctx context.Context) error {
+	if _, err := s.repo.PurgeExpiredAccountNameLookups(ctx, time.Now().UTC()); err != nil {
+		return fmt.Errorf("purge account name lookups: %w", err)
+	}
+	return nil
+}
+
+// FetchBankDetails resolves an account name, answering repeat lookups from a
+// short-lived cache.
 func (s *Service) FetchBankDetails(ctx context.Context, accountNumber, bankCode string) (*BankDetails, error) {
+	accountNumber = strings.TrimSpace(accountNumber)
+	bankCode = strings.TrimSpace(bankCode)
+
+	now := time.Now().UTC()
+	cached, err := s.repo.GetAccountNameLookup(ctx, bankCode, accountNumber, now)
+	if err == nil {
+		return &BankDetails{BankCode: bankCode, AccountNumber: accountNumber, AccountName: cached.AccountName}, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("wallet service: failed to read account name cache: %v", err)
+	}
+
 	bankDetails, err := s.providusService.FetchBankDetails(ctx, accountNumber, bankCode)
 	if err != nil {
 		return nil, appErr.ErrFetchingBankDetails
 	}
 
+	if name := strings.TrimSpace(bankDetails.AccountName); name != "" {
+		if err := s.repo.SaveAccountNameLookup(ctx, &AccountNameLookup{
+			BankCode:      bankCode,
+			AccountNumber: accountNumber,
+			AccountName:   name,
+			ExpiresAt:     now.Add(accountNameLookupTTL),
+		}); err != nil {
+			log.Printf("wallet service: failed to cache account name lookup: %v", err)
+		}
+	}
+
 	return bankDetails, nil
 }
 
@@ -63,26 +174,21 @@ func (s *Service) InitiateTransfer(ctx context.Context, mobileUserID string, req
 	if err := s.pinVerifier.Verify(ctx, mobileUserID, req.TransactionPin); err != nil {
 		return nil, err
 	}
+	if err := s.checkSecondFactor(ctx, mobileUserID, req.Amount*100, req.OTPCode); err != nil {
+		return nil, err
+	}
+
+	return s.executeTransfer(ctx, mobileUserID, uuid.NewString(), req)
+}
 
+// executeTransfer sends an already-authorised transfer, recording it under txID.
+// req.Amount is in naira.
+func (s *Service) executeTransfer(ctx context.Context, mobileUserID, txID string, req *TransferRequest) (*TransferResponse, error) {
 	if req.Amount <= 50 {
 		return nil, appErr.ErrInvalidTransferAmount
 	}
 	req.Amount = req.Amount * 100 // convert Naira → kobo for storage and downstream use
 
-	user, err := s.repo.GetUserByMobileUserID(ctx, mobileUserID)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			log.Printf("wallet service: user record not found: %v", err)
-			return nil, appErr.ErrUnauthorized
-		}
-		log.Printf("wallet service: failed to get user: %v", err)
-		return nil, appErr.ErrFundsTransfer
-	}
-
-	if user.CreatedAt.Add(24*time.Hour).After(time.Now()) && req.Amount > 20000*100 {
-		return nil, appErr.ErrNewUserTransferRestriction
-	}
-
 	accountNumber := strings.TrimSpace(req.AccountNumber)
 	accountName := ""
 	if req.AccountName != nil {
@@ -115,8 +221,11 @@ func (s *Service) InitiateTransfer(ctx context.Context, mobileUserID string, req
 		log.Printf("wallet service: failed to get wallet: %v", err)
 		return nil, appErr.ErrFundsTransfer
 	}
+	if err := DebitAllowed(wallet.Status); err != nil {
+		return nil, err
+	}
 
-	txID := uuid.NewString()
+	req.Reference = uuid.NewString()
 	txRecord := &transaction.Transaction{
 		ID:                  txID,
 		MobileUserID:        mobileUserID,
@@ -125,7 +234,7 @@ func (s *Service) InitiateTransfer(ctx context.Context, mobileUserID string, req
 		Type:                transaction.TransactionTypeDebit,
 		Description:         fmt.Sprintf("Transfer to %s", accountName),
 		Amount:              req.Amount,
-		Reference:           uuid.NewString(),
+		Reference:           req.Reference,
 		Narration:           &narration,
 		CounterpartyAccount: accountNumber,
 		CounterpartyName:    accountName,
@@ -134,12 +243,24 @@ func (s *Service) InitiateTransfer(ctx context.Context, mobileUserID string, req
 		Status:              transaction.TransactionStatusPending,
 	}
 
-	if err := s.repo.AddTransaction(ctx, txRecord); err != nil {
+	if err := s.repo.AddDebitTransaction(ctx, txRecord, TransferChargeKobo(req.Amount), s.limitCheck(ctx, mobileUserID, req.Amount)); err != nil {
+		if errors.Is(err, ErrInsufficientFunds) {
+			return nil, appErr.ErrInsufficientBalance
+		}
+		if limits.IsLimitError(err) || IsWalletRestricted(err) {
+			return nil, err
+		}
 		log.Printf("wallet service: failed to add transaction: %v", err)
 		return nil, appErr.ErrFundsTransfer
 	}
 
 	resp, err := s.providusService.InitiateTransfer(ctx, wallet.WalletCustomerID, req)
+	if errors.Is(err, appErr.ErrTransferAmbiguous) {
+		// leave it pending; the reconciler settles it once the provider knows
+		_ = s.repo.MarkTransactionAmbiguous(ctx, txID, transaction.TransactionStatusPending)
+		log.Printf("wallet service: transfer %s outcome unknown: %v", txID, err)
+		return nil, appErr.ErrTransferAmbiguous
+	}
 	if err != nil {
 		_ = s.repo.UpdateTransactionStatus(ctx, txID, transaction.TransactionStatusFailed)
 		log.Printf("wallet service: failed to initiate transfer: %v", err)
@@ -162,16 +283,57 @@ func (s *Service) InitiateTransfer(ctx context.Context, mobileUserID string, req
 		return nil, appErr.ErrFundsTransfer
 	}
 
-	totalDebit := req.Amount + int64(math.Round(resp.Transfer.Charges*100)) + int64(math.Round(resp.Transfer.Vat*100))
+	charges := int64(math.Round(resp.Transfer.Charges * 100))
+	vat := int64(math.Round(resp.Transfer.Vat * 100))
 
-	if err := s.repo.CompleteDebitTransaction(ctx, txID, resp.Transfer.TransactionReference, transaction.TransactionStatusSuccessful, walletUser.WalletID, totalDebit); err != nil {
+	if err := s.repo.CompleteDebitTransaction(ctx, txID, resp.Transfer.TransactionReference, transaction.TransactionStatusSuccessful, walletUser.WalletID, req.Amount, charges, vat); err != nil {
 		log.Printf("wallet service: failed to complete debit transaction: %v", err)
 		return nil, appErr.ErrFundsTransfer
 	}
 
+	if err := s.repo.TouchBeneficiary(ctx, mobileUserID, strings.TrimSpace(req.SortCode), accountNumber, time.Now().UTC()); err != nil {
+		log.Printf("wallet service: failed to update beneficiary last used for transfer %s: %v", txID, err)
+	}
+	if err := s.repo.IncrementBankTransferCount(ctx, strings.TrimSpace(req.SortCode)); err != nil {
+		log.Printf("wallet service: failed to count transfer %s towards bank popularity: %v", txID, err)
+	}
+
 	return resp, nil
 }
 
+// limitCheck holds transfer amounts (kobo) against the user's tier limits
+// inside the database transaction that books them.
+func (s *Service) limitCheck(ctx context.Context, mobileUserID string, amounts ...int64) DebitCheck {
+	if s.limits == nil {
+		return nil
+	}
+	return func(tx *gorm.DB) error {
+		return s.limits.CheckTx(ctx, tx, mobileUserID, limits.ChannelTransfer, amounts...)
+	}
+}
+
+// checkSecondFactor asks for an authenticator code on high-value transfers
+// when the user has one enrolled. amountKobo is the total leaving the wallet.
+func (s *Service) checkSecondFactor(ctx context.Context, mobileUserID string, amountKobo int64, code string) error {
+	if s.secondFactor == nil || s.highValueKobo <= 0 || amountKobo < s.highValueKobo {
+		return nil
+	}
+
+	enabled, err := s.secondFactor.TOTPEnabled(ctx, mobileUserID)
+	if err != nil {
+		log.Printf("wallet service: failed to check totp enrollment: %v", err)
+		return appErr.ErrFundsTransfer
+	}
+	if !enabled {
+		return nil
+	}
+
+	if strings.TrimSpace(code) == "" {
+		return appErr.ErrSecondFactorRequired
+	}
+	return s.secondFactor.VerifyTOTP(ctx, mobileUserID, code)
+}
+
 func (s *Service) TransferForLoanRepayment(ctx context.Context, mobileUserID string, amountNaira int64) error {
 	if amountNaira <= 50 {
 		return appErr.ErrInvalidTransferAmount
@@ -187,6 +349,9 @@ func (s *Service) TransferForLoanRepayment(ctx context.Context, mobileUserID str
 		}
 		return appErr.ErrMakingLoanRepayment
 	}
+	if err := DebitAllowed(w.Status); err != nil {
+		return err
+	}
 
 	amountKobo := amountNaira * 100
 	if w.AvailableBalance < amountKobo {
@@ -197,6 +362,7 @@ func (s *Service) TransferForLoanRepayment(ctx context.Context, mobileUserID str
 	narration := "Loan repayment"
 	accountName := s.settlementAccount.AccountName
 	txID := uuid.NewString()
+	reference := uuid.NewString()
 	txRecord := &transaction.Transaction{
 		ID:                  txID,
 		MobileUserID:        mobileUserID,
@@ -205,14 +371,20 @@ func (s *Service) TransferForLoanRepayment(ctx context.Context, mobileUserID str
 		Category:            transaction.TransactionCategoryLoanRepayment,
 		Source:              transaction.TransactionSourceLoanRepayment,
 		Amount:              amountKobo,
-		Reference:           uuid.NewString(),
+		Reference:           reference,
 		Narration:           &narration,
 		CounterpartyAccount: s.settlementAccount.AccountNumber,
 		CounterpartyName:    accountName,
 		CounterpartyBank:    s.settlementAccount.BankCode,
 		Status:              transaction.TransactionStatusPending,
 	}
-	if err := s.repo.AddTransaction(ctx, txRecord); err != nil {
+	if err := s.repo.AddDebitTransaction(ctx, txRecord, TransferChargeKobo(amountKobo), s.limitCheck(ctx, mobileUserID, amountKobo)); err != nil {
+		if errors.Is(err, ErrInsufficientFunds) {
+			return appErr.ErrInsufficientBalance
+		}
+		if limits.IsLimitError(err) || IsWalletRestricted(err) {
+			return err
+		}
 		return fmt.Errorf("failed to create transaction record: %w", err)
 	}
 
@@ -222,7 +394,12 @@ func (s *Service) TransferForLoanRepayment(ctx context.Context, mobileUserID str
 		AccountNumber: s.settlementAccount.AccountNumber,
 		AccountName:   &accountName,
 		Narration:     &narration,
+		Reference:     reference,
 	})
+	if errors.Is(err, appErr.ErrTransferAmbiguous) {
+		_ = s.repo.MarkTransactionAmbiguous(ctx, txID, transaction.TransactionStatusFailed)
+		return fmt.Errorf("%w: %v", ErrTransferProviderFailed, err)
+	}
 	if err != nil {
 		_ = s.repo.UpdateTransactionStatus(ctx, txID, transaction.TransactionStatusFailed)
 		return fmt.Errorf("%w: %v", ErrTransferProviderFailed, err)
@@ -236,244 +413,2050 @@ func (s *Service) TransferForLoanRepayment(ctx context.Context, mobileUserID str
 		return fmt.Errorf("%w: %s", ErrTransferProviderFailed, msg)
 	}
 
-	totalDebit := amountKobo + int64(math.Round(resp.Transfer.Charges*100)) + int64(math.Round(resp.Transfer.Vat*100))
+	charges := int64(math.Round(resp.Transfer.Charges * 100))
+	vat := int64(math.Round(resp.Transfer.Vat * 100))
 	return s.repo.CompleteDebitTransaction(ctx, txID, resp.Transfer.TransactionReference,
-		transaction.TransactionStatusSuccessful, w.InternalWalletID, totalDebit)
+		transaction.TransactionStatusSuccessful, w.InternalWalletID, amountKobo, charges, vat)
 }
 
-func (s *Service) InitiateBulkTransfer(ctx context.Context, mobileUserID string, req *BulkTransferRequest) (*BulkTransferResponse, error) {
-	mobileUserID = strings.TrimSpace(mobileUserID)
-	if mobileUserID == "" {
-		return nil, fmt.Errorf("%w: mobile user ID is required", ErrInvalidTransferRequest)
+// ReconcilePendingTransfers requeries outbound transfers that have been pending,
+// or failed ambiguously, for longer than olderThan and settles each one from the
+// provider's answer.
+func (s *Service) ReconcilePendingTransfers(ctx context.Context, olderThan time.Duration, limit int) (*ReconcileSummary, error) {
+	now := time.Now().UTC()
+	txs, err := s.repo.ListTransfersForRequery(ctx, now.Add(-olderThan), limit)
+	if err != nil {
+		return nil, err
 	}
 
-	if req == nil || len(req.RecipientInfo) == 0 {
-		return nil, fmt.Errorf("%w: at least one recipient is required", ErrInvalidTransferRequest)
-	}
+	summary := &ReconcileSummary{Scanned: len(txs)}
+	for i := range txs {
+		status, err := s.reconcileTransfer(ctx, &txs[i], now)
+		if err != nil {
+			log.Printf("wallet service: failed to reconcile transfer %s: %v", txs[i].ID, err)
+		}
 
-	if s.providusService == nil {
-		return nil, errors.New("transfer service is not configured")
+		switch status {
+		case transaction.TransactionStatusSuccessful:
+			summary.Successful++
+		case transaction.TransactionStatusFailed:
+			summary.Failed++
+		case transaction.TransactionStatusReversed:
+			summary.Reversed++
+		default:
+			summary.Pending++
+		}
 	}
 
-	if err := s.pinVerifier.Verify(ctx, mobileUserID, req.TransactionPin); err != nil {
-		return nil, err
-	}
+	return summary, nil
+}
 
-	resp, err := s.providusService.InitiateBulkTransfer(ctx, req.RecipientInfo)
+func (s *Service) reconcileTransfer(ctx context.Context, tx *transaction.Transaction, now time.Time) (transaction.TransactionStatus, error) {
+	result, err := s.providusService.RequeryTransfer(ctx, tx.Reference)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrTransferProviderFailed, err)
+		return transaction.TransactionStatusPending, err
 	}
 
-	if resp == nil {
-		return nil, errors.New("transfer service returned no response body")
-	}
-
-	toResults := func(src []ProvidusBatchTransferResult) []BulkTransferResult {
-		out := make([]BulkTransferResult, len(src))
-		for i, r := range src {
-			out[i] = BulkTransferResult{
-				Amount:        r.Amount,
-				VAT:           r.VAT,
-				SortCode:      r.SortCode,
-				Reference:     r.Reference,
-				Narration:     r.Narration,
-				AccountName:   r.AccountName,
-				Fee:           r.Fee,
-				AccountNumber: r.AccountNumber,
-				Total:         r.Total,
-			}
+	switch result.Status {
+	case ProviderTransferStatusSuccessful:
+		charges := int64(math.Round(result.Charges * 100))
+		vat := int64(math.Round(result.Vat * 100))
+		if err := s.repo.CompleteDebitTransaction(ctx, tx.ID, result.TransactionReference,
+			transaction.TransactionStatusSuccessful, tx.WalletID, tx.Amount, charges, vat); err != nil {
+			return transaction.TransactionStatusPending, err
 		}
-		return out
-	}
+		if err := s.repo.SettleTransactionStatus(ctx, tx.ID, transaction.TransactionStatusSuccessful); err != nil {
+			return transaction.TransactionStatusSuccessful, err
+		}
+		if tx.Category == transaction.TransactionCategoryLoanRepayment {
+			// the CBA repayment was never booked for this transfer
+			log.Printf("wallet service: loan repayment transfer %s settled late (provider_ref=%s); confirm repayment with CBA",
+				tx.ID, result.TransactionReference)
+		}
+		s.notifyTransferSettled(ctx, tx, "Transfer successful", "Your pending transfer has been completed.")
+		return transaction.TransactionStatusSuccessful, nil
 
-	return &BulkTransferResponse{
-		Status:  "success",
-		Message: resp.Message,
-		Data: struct {
-			All      []BulkTransferResult `json:"all"`
-			Rejected []BulkTransferResult `json:"rejected"`
-			Accepted []BulkTransferResult `json:"accepted"`
-		}{
-			All:      toResults(resp.Data.All),
-			Rejected: toResults(resp.Data.Rejected),
-			Accepted: toResults(resp.Data.Accepted),
-		},
-	}, nil
-}
+	case ProviderTransferStatusFailed:
+		if err := s.repo.SettleTransactionStatus(ctx, tx.ID, transaction.TransactionStatusFailed); err != nil {
+			return transaction.TransactionStatusPending, err
+		}
+		s.notifyTransferSettled(ctx, tx, "Transfer failed", "Your pending transfer could not be completed. Your wallet was not debited.")
+		return transaction.TransactionStatusFailed, nil
 
-func (s *Service) AddBeneficiary(ctx context.Context, mobileUserID string, req *AddBeneficiaryRequest) (*Beneficiary, error) {
-	mobileUserID = strings.TrimSpace(mobileUserID)
+	case ProviderTransferStatusReversed:
+		if _, err := s.repo.ReverseDebitTransaction(ctx, tx.ID, "reversed by provider"); err != nil && !errors.Is(err, ErrAlreadyReversed) {
+			return transaction.TransactionStatusPending, err
+		}
+		s.notifyTransferSettled(ctx, tx, "Transfer reversed", "Your pending transfer was reversed by the bank.")
+		return transaction.TransactionStatusReversed, nil
 
-	user, err := s.repo.GetUserWalletID(ctx, mobileUserID)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, appErr.ErrUnauthorized
+	case ProviderTransferStatusNotFound:
+		if now.Sub(tx.CreatedAt) < transferNotFoundCutoff {
+			return transaction.TransactionStatusPending, nil
 		}
-		return nil, appErr.ErrAddingBeneficiary
+		if err := s.repo.SettleTransactionStatus(ctx, tx.ID, transaction.TransactionStatusFailed); err != nil {
+			return transaction.TransactionStatusPending, err
+		}
+		s.notifyTransferSettled(ctx, tx, "Transfer failed", "Your pending transfer could not be completed. Your wallet was not debited.")
+		return transaction.TransactionStatusFailed, nil
 	}
 
-	walletID := strings.TrimSpace(user.WalletID)
-	accountNumber := strings.TrimSpace(req.AccountNumber)
-	accountName := strings.TrimSpace(req.AccountName)
-	bankCode := strings.TrimSpace(req.BankCode)
+	return transaction.TransactionStatusPending, nil
+}
 
-	if mobileUserID == "" {
-		return nil, appErr.ErrInvalidRequestBody
+// ReverseTransaction reverses a debit. Funds that left the wallet come back as
+// a linked reversal credit; the user is told either way. A provider transfer
+// whose outcome is still open is requeried first, and refused while the
+// provider can't say whether the money left. A settled debit is refunded into
+// the provider wallet before the reversal credit is booked.
+func (s *Service) ReverseTransaction(ctx context.Context, txID, reason string) (*ReversalResponse, error) {
+	txID = strings.TrimSpace(txID)
+	if txID == "" {
+		return nil, appErr.ErrMissingRequiredPathParameter
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		reason = "transaction reversed"
 	}
 
-	if walletID == "" {
-		return nil, appErr.ErrInvalidRequestBody
+	original, err := s.repo.GetTransactionByID(ctx, txID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, appErr.ErrTransactionNotFound
+	}
+	if err != nil {
+		log.Printf("wallet service: failed to load transaction %s for reversal: %v", txID, err)
+		return nil, appErr.ErrReversingTransaction
 	}
 
-	if accountNumber == "" {
-		return nil, appErr.ErrInvalidRequestBody
+	if awaitingProvider(original) {
+		if s.providusService == nil {
+			return nil, appErr.ErrTransferOutcomePending
+		}
+		status, err := s.reconcileTransfer(ctx, original, time.Now().UTC())
+		if err != nil {
+			log.Printf("wallet service: failed to requery transaction %s before reversal: %v", txID, err)
+			return nil, appErr.ErrTransferOutcomePending
+		}
+		if status == transaction.TransactionStatusPending {
+			return nil, appErr.ErrTransferOutcomePending
+		}
+		original.Status = status
 	}
 
-	if accountName == "" {
-		return nil, appErr.ErrInvalidRequestBody
+	if refundableAtProvider(original) {
+		if err := s.refundAtProvider(ctx, original); err != nil {
+			log.Printf("wallet service: failed to refund transaction %s at the provider: %v", txID, err)
+			return nil, appErr.ErrReversingTransaction
+		}
 	}
 
-	if bankCode == "" {
-		return nil, appErr.ErrInvalidRequestBody
+	reversal, err := s.repo.ReverseDebitTransaction(ctx, txID, reason)
+	switch {
+	case errors.Is(err, ErrNotReversible):
+		return nil, appErr.ErrTransactionNotReversible
+	case errors.Is(err, ErrAlreadyReversed):
+		return nil, appErr.ErrTransactionAlreadyReversed
+	case err != nil:
+		log.Printf("wallet service: failed to reverse transaction %s: %v", txID, err)
+		return nil, appErr.ErrReversingTransaction
 	}
 
-	beneficiary := &Beneficiary{
-		ID:            uuid.NewString(),
-		MobileUserID:  mobileUserID,
-		WalletID:      walletID,
-		BankCode:      bankCode,
-		AccountNumber: accountNumber,
-		AccountName:   accountName,
+	resp := &ReversalResponse{
+		TransactionID: original.ID,
+		Status:        transaction.TransactionStatusReversed,
+		Reason:        reason,
+	}
+	body := "Your transaction was reversed. Your wallet was not debited."
+	if reversal != nil {
+		resp.ReversalTransactionID = reversal.ID
+		resp.AmountRefunded = float64(reversal.Amount) / 100
+		body = fmt.Sprintf("NGN %.2f has been refunded to your wallet.", resp.AmountRefunded)
 	}
 
-	if err := s.repo.CreateBeneficiary(ctx, beneficiary); err != nil {
-		return nil, appErr.ErrAddingBeneficiary
+	if s.notifier != nil {
+		if err := s.notifier.SendToUser(ctx, original.MobileUserID, "Transaction reversed", "transaction", body,
+			map[string]any{"transaction_id": original.ID, "reversal_transaction_id": resp.ReversalTransactionID}); err != nil {
+			log.Printf("wallet service: failed to notify user about reversal of %s: %v", original.ID, err)
+		}
 	}
 
-	return beneficiary, nil
+	return resp, nil
 }
 
-func (s *Service) InitiateDeposit(ctx context.Context, deviceID, mobileUserID string, req InitiatedDepositRequest) (*InitiatedDepositResponse, error) {
+func (s *Service) notifyTransferSettled(ctx context.Context, tx *transaction.Transaction, title, body string) {
+	if s.notifier == nil || tx.Status != transaction.TransactionStatusPending {
+		return
+	}
+	if err := s.notifier.SendToUser(ctx, tx.MobileUserID, title, "transaction", body,
+		map[string]any{"transaction_id": tx.ID}); err != nil {
+		log.Printf("wallet service: failed to notify user about transfer %s: %v", tx.ID, err)
+	}
+}
+
+// InitiateBulkTransfer checks every recipient with a name lookup, reserves the
+// batch total and its charges on the wallet and sends the batch to the
+// provider. Recipient amounts are in naira. Invalid recipients reject the
+// whole batch and are listed in the response.
+func (s *Service) InitiateBulkTransfer(ctx context.Context, mobileUserID string, req *BulkTransferRequest) (*TransferBatchResponse, error) {
 	mobileUserID = strings.TrimSpace(mobileUserID)
 	if mobileUserID == "" {
-		return nil, errors.New("invalid mobile user id")
+		return nil, appErr.ErrMissingUserID
+	}
+	if req == nil || len(req.RecipientInfo) == 0 {
+		return nil, appErr.ErrInvalidRequestBody
+	}
+	if len(req.RecipientInfo) > maxBulkTransferRecipients {
+		return nil, appErr.ErrTooManyBulkRecipients
 	}
 
-	_, err := s.repo.GetDevice(ctx, mobileUserID, deviceID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to verify device: %s", err.Error())
+	if err := s.pinVerifier.Verify(ctx, mobileUserID, req.TransactionPin); err != nil {
+		return nil, err
+	}
+	var batchTotal int64
+	for _, recipient := range req.RecipientInfo {
+		batchTotal += recipient.Amount * 100
+	}
+	if err := s.checkSecondFactor(ctx, mobileUserID, batchTotal, req.OTPCode); err != nil {
+		return nil, err
 	}
 
-	wallet, err := s.repo.GetWallet(ctx, mobileUserID)
+	if invalid := s.validateBulkRecipients(ctx, req.RecipientInfo); len(invalid) > 0 {
+		return &TransferBatchResponse{InvalidRows: invalid}, appErr.ErrInvalidBulkRecipients
+	}
+
+	w, err := s.repo.GetWallet(ctx, mobileUserID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("wallet not found")
+			return nil, appErr.ErrMissingUserWallet
 		}
-		return nil, fmt.Errorf("error fetching wallet: %s", err.Error())
+		log.Printf("wallet service: failed to get wallet for bulk transfer: %v", err)
+		return nil, appErr.ErrFundsTransfer
+	}
+	if err := DebitAllowed(w.Status); err != nil {
+		return nil, err
 	}
 
-	trackingID := uuid.NewString()
-	now := time.Now().UTC()
-	expiresAt := now.Add(30 * time.Minute)
-
-	expectedDeposit := &ExpectedDeposit{
+	batch := &TransferBatch{
 		ID:             uuid.NewString(),
-		TrackingID:     trackingID,
 		MobileUserID:   mobileUserID,
-		ExpectedAmount: req.ExpectedAmount,
-		WalletID:       wallet.InternalWalletID,
-		Status:         ExpectedDepositStatusPending,
-		ExpiresAt:      expiresAt,
-		CreatedAt:      now,
+		WalletID:       w.InternalWalletID,
+		RecipientCount: len(req.RecipientInfo),
 	}
+	items := make([]BulkTransferRecipientInfo, len(req.RecipientInfo))
+	txs := make([]transaction.Transaction, len(req.RecipientInfo))
+	for i, recipient := range req.RecipientInfo {
+		amount := recipient.Amount * 100
+		batch.TotalAmount += amount
+
+		narration := ""
+		if recipient.Narration != nil {
+			narration = *recipient.Narration
+		}
+		reference := uuid.NewString()
+		txs[i] = transaction.Transaction{
+			ID:                  uuid.NewString(),
+			MobileUserID:        mobileUserID,
+			WalletID:            w.InternalWalletID,
+			Category:            transaction.TransactionCategoryTransferTo,
+			Type:                transaction.TransactionTypeDebit,
+			Description:         fmt.Sprintf("Transfer to %s", *recipient.AccountName),
+			Amount:              amount,
+			Reference:           reference,
+			Narration:           &narration,
+			CounterpartyAccount: recipient.AccountNumber,
+			CounterpartyName:    *recipient.AccountName,
+			CounterpartyBank:    recipient.SortCode,
+			Source:              "transfer",
+			Status:              transaction.TransactionStatusPending,
+			Metadata:            types.JSONMap{"batch_id": batch.ID, "reserved": true, "reserved_charges": TransferChargeKobo(amount)},
+		}
 
-	if err := s.repo.CreateExpectedDeposit(ctx, expectedDeposit); err != nil {
-		return nil, errors.New("could not create deposit")
+		metadata := map[string]any{}
+		for k, v := range recipient.Metadata {
+			metadata[k] = v
+		}
+		metadata["batch_id"] = batch.ID
+		items[i] = recipient
+		items[i].Amount = amount
+		items[i].Reference = reference
+		items[i].Metadata = metadata
 	}
 
-	account := &AccountObj{
-		AccountNumber: wallet.AccountNumber,
-		AccountName:   wallet.AccountName,
-		BankName:      wallet.BankName,
-		BankCode:      wallet.BankCode,
+	amounts := make([]int64, len(txs))
+	for i := range txs {
+		amounts[i] = txs[i].Amount
 	}
 
-	return &InitiatedDepositResponse{
-		Status:     true,
-		TrackingID: trackingID,
-		ExpiresAt:  expiresAt,
-		Account:    *account,
-	}, nil
+	if err := s.repo.CreateTransferBatch(ctx, batch, txs, s.limitCheck(ctx, mobileUserID, amounts...)); err != nil {
+		if errors.Is(err, ErrInsufficientFunds) {
+			return nil, appErr.ErrInsufficientBalance
+		}
+		if limits.IsLimitError(err) || IsWalletRestricted(err) {
+			return nil, err
+		}
+		log.Printf("wallet service: failed to create transfer batch: %v", err)
+		return nil, appErr.ErrFundsTransfer
+	}
+
+	resp, err := s.providusService.InitiateBulkTransfer(ctx, items)
+	switch {
+	case errors.Is(err, appErr.ErrTransferAmbiguous):
+		// leave every row pending; the reconciler requeries each reference
+		log.Printf("wallet service: bulk transfer %s outcome unknown: %v", batch.ID, err)
+		for i := range txs {
+			_ = s.repo.MarkTransactionAmbiguous(ctx, txs[i].ID, transaction.TransactionStatusPending)
+		}
+	case err != nil || resp == nil || !resp.Status:
+		message := "provider returned an unsuccessful bulk transfer response"
+		if err != nil {
+			message = err.Error()
+		} else if resp != nil && strings.TrimSpace(resp.Message) != "" {
+			message = resp.Message
+		}
+		log.Printf("wallet service: bulk transfer %s failed: %s", batch.ID, message)
+		for i := range txs {
+			if err := s.repo.SettleTransactionStatus(ctx, txs[i].ID, transaction.TransactionStatusFailed); err != nil {
+				log.Printf("wallet service: failed to release bulk transfer row %s: %v", txs[i].ID, err)
+			}
+		}
+		_ = s.repo.SetTransferBatchMessage(ctx, batch.ID, message)
+		return nil, appErr.ErrFundsTransfer
+	default:
+		_ = s.repo.SetTransferBatchMessage(ctx, batch.ID, resp.Message)
+		s.applyBatchResults(ctx, w.InternalWalletID, txs, resp)
+	}
 
+	return s.GetTransferBatch(ctx, mobileUserID, batch.ID)
 }
 
-func (s *Service) HandleCreditWebhook(ctx context.Context, payload *ProvidusCredit) error {
-	if strings.TrimSpace(payload.TranType) != "C" {
-		return fmt.Errorf("unexpected tranType: %s", payload.TranType)
-	}
+// validateBulkRecipients resolves each account through the name lookup cache
+// or the provider, replacing the submitted account name with the bank's.
+func (s *Service) validateBulkRecipients(ctx context.Context, recipients []BulkTransferRecipientInfo) []BulkRecipientError {
+	results := make([]*BulkRecipientError, len(recipients))
+	sem := make(chan struct{}, bulkLookupConcurrency)
+	var wg sync.WaitGroup
 
-	amountFloat, err := strconv.ParseFloat(strings.TrimSpace(payload.TransactionAmount), 64)
-	if err != nil || amountFloat <= 0 {
-		return fmt.Errorf("invalid transaction amount: %s", payload.TransactionAmount)
-	}
-	amountKobo := int64(math.Round(amountFloat * 100))
+	for i := range recipients {
+		recipient := &recipients[i]
+		recipient.AccountNumber = strings.TrimSpace(recipient.AccountNumber)
+		recipient.SortCode = strings.TrimSpace(recipient.SortCode)
 
-	providerRef := strings.TrimSpace(payload.TranID)
-	if providerRef == "" {
-		providerRef = strings.TrimSpace(payload.SessionID)
+		invalid := func(reason string) *BulkRecipientError {
+			return &BulkRecipientError{Row: i + 1, AccountNumber: recipient.AccountNumber, SortCode: recipient.SortCode, Reason: reason}
+		}
+		if recipient.Amount <= 50 {
+			results[i] = invalid(appErr.ErrInvalidTransferAmount.Error())
+			continue
+		}
+		if recipient.AccountNumber == "" || recipient.SortCode == "" {
+			results[i] = invalid("account number and sort code are required")
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			details, err := s.FetchBankDetails(ctx, recipient.AccountNumber, recipient.SortCode)
+			if err != nil || details == nil || strings.TrimSpace(details.AccountName) == "" {
+				results[i] = invalid("account could not be verified")
+				return
+			}
+			name := strings.TrimSpace(details.AccountName)
+			recipient.AccountName = &name
+		}()
 	}
-	if providerRef == "" {
-		return errors.New("no usable provider reference in payload")
+	wg.Wait()
+
+	var out []BulkRecipientError
+	for _, r := range results {
+		if r != nil {
+			out = append(out, *r)
+		}
 	}
+	return out
+}
 
-	wallet, err := s.repo.GetWalletByAccountNumber(ctx, strings.TrimSpace(payload.AccountNumber))
-	if err != nil {
-		// account not ours — log upstream and return nil so handler responds 200
-		return nil
+// applyBatchResults settles the rows the provider answered for. Rows it did
+// not mention stay pending for the reconciler.
+func (s *Service) applyBatchResults(ctx context.Context, walletID string, txs []transaction.Transaction, resp *ProvidusBatchTransferResponse) {
+	for txID, result := range matchBatchResults(txs, resp.Data.Accepted) {
+		tx := findTransaction(txs, txID)
+		charges := result.Fee * 100
+		vat := result.VAT * 100
+		if err := s.repo.CompleteDebitTransaction(ctx, txID, result.Reference, transaction.TransactionStatusSuccessful,
+			walletID, tx.Amount, charges, vat); err != nil {
+			log.Printf("wallet service: failed to complete bulk transfer row %s: %v", txID, err)
+		}
 	}
 
-	// existing, _ := s.repo.GetTransferByProviderRef(ctx, providerRef)
-	// if existing != nil {
-	// 	return nil // duplicate webhook, already processed
-	// }
+	for txID := range matchBatchResults(txs, resp.Data.Rejected) {
+		if err := s.repo.SettleTransactionStatus(ctx, txID, transaction.TransactionStatusFailed); err != nil {
+			log.Printf("wallet service: failed to release rejected bulk transfer row %s: %v", txID, err)
+		}
+	}
+}
 
-	narration := strings.TrimSpace(payload.TranRemarks)
-	transfer := &transaction.Transaction{
-		ID:                  uuid.NewString(),
-		MobileUserID:        wallet.MobileUserID,
-		WalletID:            wallet.WalletID,
-		Reference:           uuid.NewString(),
-		ProviderReference:   providerRef,
-		SessionID:           payload.SessionID,
-		Amount:              amountKobo,
-		Narration:           &narration,
-		CounterpartyAccount: payload.AccountNumber,
-		Description:         payload.TranRemarks,
-		Status:              transaction.TransactionStatusSuccessful,
-		Type:                transaction.TransactionTypeCredit,
+func (s *Service) GetTransferBatch(ctx context.Context, mobileUserID, batchID string) (*TransferBatchResponse, error) {
+	batch, err := s.repo.GetTransferBatch(ctx, mobileUserID, strings.TrimSpace(batchID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, appErr.ErrTransferBatchNotFound
+	}
+	if err != nil {
+		log.Printf("wallet service: failed to get transfer batch: %v", err)
+		return nil, appErr.ErrFetchingTransferBatch
 	}
 
-	if err := s.repo.CreditWalletAtomically(ctx, transfer, amountKobo); err != nil {
-		return fmt.Errorf("failed to credit wallet: %w", err)
+	txs, err := s.repo.ListBatchTransactions(ctx, batch.ID)
+	if err != nil {
+		log.Printf("wallet service: failed to list transfer batch rows: %v", err)
+		return nil, appErr.ErrFetchingTransferBatch
 	}
 
-	return nil
+	return summarizeBatch(batch, txs), nil
 }
 
-func (s *Service) GetBeneficiaries(ctx context.Context, mobileUserID string) ([]Beneficiary, error) {
-	beneficiaries, err := s.repo.GetBeneficiaries(ctx, mobileUserID)
+// ResolveP2PRecipient finds the Neat wallet a P2P transfer would credit so the
+// app can confirm the name before the user sends.
+func (s *Service) ResolveP2PRecipient(ctx context.Context, mobileUserID, recipient string, kind P2PRecipientType) (*P2PRecipient, error) {
+	w, err := s.findP2PRecipient(ctx, recipient, kind)
 	if err != nil {
-		return nil, appErr.ErrFetchingBeneficiaries
+		return nil, err
+	}
+	if w.MobileUserID == mobileUserID {
+		return nil, appErr.ErrSelfTransfer
+	}
+
+	return &P2PRecipient{AccountNumber: w.AccountNumber, AccountName: w.AccountName, BankName: w.BankName}, nil
+}
+
+func (s *Service) findP2PRecipient(ctx context.Context, recipient string, kind P2PRecipientType) (*CustomerWallet, error) {
+	kind, value, err := classifyP2PRecipient(recipient, kind)
+	if err != nil {
+		return nil, appErr.ErrP2PRecipientNotFound
+	}
+
+	w, err := s.repo.FindP2PRecipient(ctx, kind, value)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, appErr.ErrP2PRecipientNotFound
+	}
+	if err != nil {
+		log.Printf("wallet service: failed to find p2p recipient: %v", err)
+		return nil, appErr.ErrFundsTransfer
+	}
+	return w, nil
+}
+
+// P2PTransfer moves money between two Neat wallets. The debit is booked
+// pending against the locked sender wallet, the provider moves the money
+// between the two customers' wallets, and the transfer_to and transfer_from
+// pair is then settled in our ledger. It carries no transfer charges.
+// req.Amount is in naira.
+func (s *Service) P2PTransfer(ctx context.Context, mobileUserID string, req *P2PTransferRequest) (*P2PTransferResponse, error) {
+	if err := s.pinVerifier.Verify(ctx, mobileUserID, req.TransactionPin); err != nil {
+		return nil, err
+	}
+	amount := req.Amount * 100
+	if err := s.checkSecondFactor(ctx, mobileUserID, amount, req.OTPCode); err != nil {
+		return nil, err
+	}
+
+	sender, err := s.loadP2PSender(ctx, mobileUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	recipient, err := s.findP2PRecipient(ctx, req.Recipient, req.RecipientType)
+	if err != nil {
+		return nil, err
+	}
+	if recipient.MobileUserID == mobileUserID || recipient.InternalWalletID == sender.InternalWalletID {
+		return nil, appErr.ErrSelfTransfer
+	}
+	if !creditAllowed(recipient.Status) {
+		return nil, appErr.ErrRecipientWalletUnavailable
+	}
+
+	note := ""
+	if req.Note != nil {
+		note = strings.TrimSpace(*req.Note)
+	}
+	debit, credit := newP2PTransactions(sender, recipient, amount, note)
+
+	if err := s.repo.AddDebitTransaction(ctx, debit, 0, s.limitCheck(ctx, mobileUserID, amount)); err != nil {
+		if errors.Is(err, ErrInsufficientFunds) {
+			return nil, appErr.ErrInsufficientBalance
+		}
+		if limits.IsLimitError(err) || IsWalletRestricted(err) {
+			return nil, err
+		}
+		log.Printf("wallet service: failed to book p2p transfer: %v", err)
+		return nil, appErr.ErrFundsTransfer
+	}
+
+	if err := s.moveP2PFunds(ctx, sender, recipient, debit, credit); err != nil {
+		_ = s.repo.UpdateTransactionStatus(ctx, debit.ID, transaction.TransactionStatusFailed)
+		log.Printf("wallet service: p2p transfer %s failed at the provider: %v", debit.ID, err)
+		return nil, appErr.ErrFundsTransfer
+	}
+
+	if err := s.repo.TransferBetweenWallets(ctx, debit, credit); err != nil {
+		// the balance sync reports the wallets as drifted until this is booked
+		log.Printf("wallet service: p2p transfer %s moved at the provider but was not booked: %v", debit.ID, err)
+		return nil, appErr.ErrFundsTransfer
+	}
+
+	s.notifyP2PTransfer(ctx, debit, credit, note)
+
+	return &P2PTransferResponse{
+		TransactionID: debit.ID,
+		Reference:     debit.Reference,
+		Amount:        float64(amount) / 100,
+		Recipient: P2PRecipient{
+			AccountNumber: recipient.AccountNumber,
+			AccountName:   recipient.AccountName,
+			BankName:      recipient.BankName,
+		},
+		Note:         note,
+		Status:       debit.Status,
+		BalanceAfter: float64(debit.BalanceAfter) / 100,
+		CreatedAt:    debit.CreatedAt,
+	}, nil
+}
+
+// moveP2PFunds makes the same move between the two customers' Providus
+// wallets, so the provider holds what our balances say. A credit that fails
+// after the debit went through is put back on the sender.
+func (s *Service) moveP2PFunds(ctx context.Context, sender, recipient *CustomerWallet, debit, credit *transaction.Transaction) error {
+	if err := s.providusService.DebitWallet(ctx, sender.WalletCustomerID, debit.Amount, debit.Reference); err != nil {
+		return fmt.Errorf("debit sender wallet: %w", err)
+	}
+	if err := s.providusService.CreditWallet(ctx, recipient.WalletCustomerID, credit.Amount, credit.Reference); err != nil {
+		if refundErr := s.providusService.CreditWallet(ctx, sender.WalletCustomerID, debit.Amount, debit.Reference+"-RF"); refundErr != nil {
+			log.Printf("wallet service: p2p transfer %s left the sender's provider wallet short by %d kobo: %v", debit.ID, debit.Amount, refundErr)
+		}
+		return fmt.Errorf("credit recipient wallet: %w", err)
+	}
+	return nil
+}
+
+// refundAtProvider credits a settled debit's total back into the customer's
+// provider wallet and marks the debit reversal_pending, so a retried reversal
+// books the local credit without refunding twice.
+func (s *Service) refundAtProvider(ctx context.Context, original *transaction.Transaction) error {
+	if s.providusService == nil {
+		return errors.New("provider not configured")
+	}
+	wallet, err := s.repo.GetWallet(ctx, original.MobileUserID)
+	if err != nil {
+		return err
+	}
+	if wallet.InternalWalletID != original.WalletID {
+		return fmt.Errorf("wallet %s is not the user's current wallet", original.WalletID)
+	}
+
+	total := original.Amount + original.Charges + original.VAT
+	refundRef := original.Reference + "-RV"
+	if err := s.providusService.CreditWallet(ctx, wallet.WalletCustomerID, total, refundRef); err != nil {
+		return err
+	}
+	return s.repo.MarkRefundedAtProvider(ctx, original.ID, refundRef)
+}
+
+// loadP2PSender returns the paying user's wallet if it may be debited.
+func (s *Service) loadP2PSender(ctx context.Context, mobileUserID string) (*CustomerWallet, error) {
+	sender, err := s.repo.GetWallet(ctx, mobileUserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErr.ErrMissingUserWallet
+		}
+		log.Printf("wallet service: failed to get wallet: %v", err)
+		return nil, appErr.ErrFundsTransfer
+	}
+	if err := DebitAllowed(sender.Status); err != nil {
+		return nil, err
+	}
+	return sender, nil
+}
+
+func (s *Service) notifyP2PTransfer(ctx context.Context, debit, credit *transaction.Transaction, note string) {
+	if s.notifier == nil {
+		return
+	}
+
+	amount := fmt.Sprintf("NGN %.2f", float64(debit.Amount)/100)
+	received := fmt.Sprintf("%s sent you %s.", credit.CounterpartyName, amount)
+	if note != "" {
+		received += " Note: " + note
+	}
+
+	if err := s.notifier.SendToUser(ctx, debit.MobileUserID, "Transfer successful", "transaction",
+		fmt.Sprintf("You sent %s to %s.", amount, debit.CounterpartyName),
+		map[string]any{"transaction_id": debit.ID}); err != nil {
+		log.Printf("wallet service: failed to notify sender about p2p transfer %s: %v", debit.ID, err)
+	}
+	if err := s.notifier.SendToUser(ctx, credit.MobileUserID, "Money received", "transaction", received,
+		map[string]any{"transaction_id": credit.ID}); err != nil {
+		log.Printf("wallet service: failed to notify recipient about p2p transfer %s: %v", credit.ID, err)
+	}
+}
+
+// CreatePaymentRequest opens a request for req.Amount naira into the user's
+// wallet, backed by its own expected deposit so external payments that quote
+// the request code, or pay exactly the amount due, match it.
+func (s *Service) CreatePaymentRequest(ctx context.Context, mobileUserID string, req *CreatePaymentRequestRequest) (*PaymentRequestResponse, error) {
+	w, err := s.repo.GetWallet(ctx, mobileUserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErr.ErrMissingUserWallet
+		}
+		log.Printf("wallet service: failed to get wallet for payment request: %v", err)
+		return nil, appErr.ErrPaymentRequest
+	}
+
+	code, err := newPaymentRequestCode()
+	if err != nil {
+		log.Printf("wallet service: failed to generate payment request code: %v", err)
+		return nil, appErr.ErrPaymentRequest
+	}
+
+	ttl := defaultPaymentRequestTTL
+	if req.ExpiresInHours > 0 {
+		ttl = min(time.Duration(req.ExpiresInHours)*time.Hour, maxPaymentRequestTTL)
+	}
+	now := time.Now().UTC()
+	memo := ""
+	if req.Memo != nil {
+		memo = strings.TrimSpace(*req.Memo)
+	}
+
+	deposit := &ExpectedDeposit{
+		ID:                 uuid.NewString(),
+		TrackingID:         uuid.NewString(),
+		MobileUserID:       mobileUserID,
+		WalletID:           w.InternalWalletID,
+		ExpectedAmount:     req.Amount * 100,
+		PaymentRequestCode: code,
+		Status:             ExpectedDepositStatusPending,
+		ExpiresAt:          now.Add(ttl),
+		CreatedAt:          now,
+	}
+	request := &PaymentRequest{
+		ID:                uuid.NewString(),
+		Code:              code,
+		MobileUserID:      mobileUserID,
+		WalletID:          w.InternalWalletID,
+		Amount:            req.Amount * 100,
+		Memo:              memo,
+		Status:            PaymentRequestStatusPending,
+		ExpectedDepositID: deposit.ID,
+		ExpiresAt:         deposit.ExpiresAt,
+	}
+
+	if err := s.repo.CreatePaymentRequest(ctx, request, deposit); err != nil {
+		log.Printf("wallet service: failed to create payment request: %v", err)
+		return nil, appErr.ErrPaymentRequest
+	}
+
+	return s.toPaymentRequestResponse(request, w), nil
+}
+
+func (s *Service) ListPaymentRequests(ctx context.Context, mobileUserID string) ([]PaymentRequestResponse, error) {
+	w, err := s.repo.GetWallet(ctx, mobileUserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErr.ErrMissingUserWallet
+		}
+		log.Printf("wallet service: failed to get wallet for payment requests: %v", err)
+		return nil, appErr.ErrPaymentRequest
+	}
+
+	requests, err := s.repo.ListPaymentRequests(ctx, mobileUserID, 100)
+	if err != nil {
+		log.Printf("wallet service: failed to list payment requests: %v", err)
+		return nil, appErr.ErrPaymentRequest
+	}
+
+	out := make([]PaymentRequestResponse, 0, len(requests))
+	for i := range requests {
+		out = append(out, *s.toPaymentRequestResponse(&requests[i], w))
+	}
+	return out, nil
+}
+
+func (s *Service) GetPaymentRequest(ctx context.Context, mobileUserID, id string) (*PaymentRequestResponse, error) {
+	request, err := s.repo.GetPaymentRequest(ctx, mobileUserID, strings.TrimSpace(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, appErr.ErrPaymentRequestNotFound
+	}
+	if err != nil {
+		log.Printf("wallet service: failed to get payment request: %v", err)
+		return nil, appErr.ErrPaymentRequest
+	}
+
+	w, err := s.repo.GetWallet(ctx, mobileUserID)
+	if err != nil {
+		log.Printf("wallet service: failed to get wallet for payment request: %v", err)
+		return nil, appErr.ErrPaymentRequest
+	}
+	return s.toPaymentRequestResponse(request, w), nil
+}
+
+func (s *Service) CancelPaymentRequest(ctx context.Context, mobileUserID, id string) error {
+	request, err := s.repo.GetPaymentRequest(ctx, mobileUserID, strings.TrimSpace(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return appErr.ErrPaymentRequestNotFound
+	}
+	if err != nil {
+		log.Printf("wallet service: failed to get payment request: %v", err)
+		return appErr.ErrPaymentRequest
+	}
+
+	closed, err := s.repo.ClosePaymentRequest(ctx, request.ID, PaymentRequestStatusCancelled)
+	if err != nil {
+		log.Printf("wallet service: failed to cancel payment request %s: %v", request.ID, err)
+		return appErr.ErrPaymentRequest
+	}
+	if !closed {
+		return appErr.ErrPaymentRequestClosed
+	}
+	return nil
+}
+
+// GetPayLink shows a payment request to whoever holds its link.
+func (s *Service) GetPayLink(ctx context.Context, code string) (*PayLinkResponse, error) {
+	request, w, err := s.loadPayLink(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PayLinkResponse{
+		Code:          request.Code,
+		RequesterName: w.AccountName,
+		Amount:        float64(request.Amount) / 100,
+		Memo:          request.Memo,
+		Status:        effectivePaymentRequestStatus(request, time.Now()),
+		Account: AccountObj{
+			AccountNumber: w.AccountNumber,
+			AccountName:   w.AccountName,
+			BankName:      w.BankName,
+			BankCode:      w.BankCode,
+		},
+		ExpiresAt: request.ExpiresAt,
+	}, nil
+}
+
+// PayPaymentRequest pays a request from the payer's wallet with an in-app
+// transfer, moved at the provider like P2PTransfer. The request is claimed
+// while the money moves and reopened if the provider refuses.
+func (s *Service) PayPaymentRequest(ctx context.Context, payerID, code string, req *PayPaymentRequestRequest) (*P2PTransferResponse, error) {
+	if err := s.pinVerifier.Verify(ctx, payerID, req.TransactionPin); err != nil {
+		return nil, err
+	}
+
+	request, requester, err := s.loadPayLink(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if request.MobileUserID == payerID {
+		return nil, appErr.ErrSelfTransfer
+	}
+	if effectivePaymentRequestStatus(request, time.Now()) != PaymentRequestStatusPending {
+		return nil, appErr.ErrPaymentRequestClosed
+	}
+	if err := s.checkSecondFactor(ctx, payerID, request.Amount, req.OTPCode); err != nil {
+		return nil, err
+	}
+
+	payer, err := s.loadP2PSender(ctx, payerID)
+	if err != nil {
+		return nil, err
+	}
+	if !creditAllowed(requester.Status) {
+		return nil, appErr.ErrRecipientWalletUnavailable
+	}
+
+	debit, credit := newP2PTransactions(payer, requester, request.Amount, request.Memo)
+	debit.Metadata["payment_request_id"] = request.ID
+	credit.Metadata["payment_request_id"] = request.ID
+
+	if err := s.repo.ClaimPaymentRequest(ctx, request.ID, payerID, debit, time.Now().UTC(), s.limitCheck(ctx, payerID, request.Amount)); err != nil {
+		switch {
+		case errors.Is(err, ErrPaymentRequestClosed):
+			return nil, appErr.ErrPaymentRequestClosed
+		case errors.Is(err, ErrInsufficientFunds):
+			return nil, appErr.ErrInsufficientBalance
+		case limits.IsLimitError(err), IsWalletRestricted(err):
+			return nil, err
+		}
+		log.Printf("wallet service: failed to claim payment request %s: %v", request.ID, err)
+		return nil, appErr.ErrPaymentRequest
+	}
+
+	if err := s.moveP2PFunds(ctx, payer, requester, debit, credit); err != nil {
+		if releaseErr := s.repo.ReleasePaymentRequest(ctx, request.ID, debit.ID); releaseErr != nil {
+			log.Printf("wallet service: failed to reopen payment request %s: %v", request.ID, releaseErr)
+		}
+		log.Printf("wallet service: payment request %s failed at the provider: %v", request.ID, err)
+		return nil, appErr.ErrPaymentRequest
+	}
+
+	if err := s.repo.PayPaymentRequest(ctx, request.ID, debit, credit, time.Now().UTC()); err != nil {
+		// the balance sync reports the wallets as drifted until this is booked
+		log.Printf("wallet service: payment request %s moved at the provider but was not booked: %v", request.ID, err)
+		return nil, appErr.ErrPaymentRequest
+	}
+
+	s.notifyP2PTransfer(ctx, debit, credit, request.Memo)
+	s.notifyPaymentRequestPaid(ctx, request)
+
+	return &P2PTransferResponse{
+		TransactionID: debit.ID,
+		Reference:     debit.Reference,
+		Amount:        float64(debit.Amount) / 100,
+		Recipient: P2PRecipient{
+			AccountNumber: requester.AccountNumber,
+			AccountName:   requester.AccountName,
+			BankName:      requester.BankName,
+		},
+		Note:         request.Memo,
+		Status:       debit.Status,
+		BalanceAfter: float64(debit.BalanceAfter) / 100,
+		CreatedAt:    debit.CreatedAt,
+	}, nil
+}
+
+func (s *Service) loadPayLink(ctx context.Context, code string) (*PaymentRequest, *CustomerWallet, error) {
+	request, err := s.repo.GetPaymentRequestByCode(ctx, strings.ToUpper(strings.TrimSpace(code)))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, appErr.ErrPaymentRequestNotFound
+	}
+	if err != nil {
+		log.Printf("wallet service: failed to get payment request by code: %v", err)
+		return nil, nil, appErr.ErrPaymentRequest
+	}
+
+	w, err := s.repo.GetWallet(ctx, request.MobileUserID)
+	if err != nil {
+		log.Printf("wallet service: failed to get requester wallet for payment request %s: %v", request.ID, err)
+		return nil, nil, appErr.ErrPaymentRequest
+	}
+	return request, w, nil
+}
+
+// settlePaymentRequestDeposit closes the payment request behind a matched or
+// expired expected deposit. It reports whether there was one.
+func (s *Service) settlePaymentRequestDeposit(ctx context.Context, deposit *ExpectedDeposit) bool {
+	var status PaymentRequestStatus
+	switch deposit.Status {
+	case ExpectedDepositStatusMatched, ExpectedDepositStatusOverpaid:
+		status = PaymentRequestStatusPaid
+	case ExpectedDepositStatusExpired:
+		status = PaymentRequestStatusExpired
+	default:
+		return false
+	}
+
+	request, err := s.repo.SettlePaymentRequestByDeposit(ctx, deposit.ID, status, deposit.TransactionID, time.Now().UTC())
+	if err != nil {
+		log.Printf("wallet service: failed to settle payment request for deposit %s: %v", deposit.ID, err)
+		return false
+	}
+	if request == nil {
+		return false
+	}
+
+	if status == PaymentRequestStatusPaid {
+		s.notifyPaymentRequestPaid(ctx, request)
+	} else if s.notifier != nil {
+		body := fmt.Sprintf("Your request for NGN %.2f expired without being paid.", float64(request.Amount)/100)
+		if err := s.notifier.SendToUser(ctx, request.MobileUserID, "Payment request expired", "transaction", body,
+			map[string]any{"payment_request_id": request.ID}); err != nil {
+			log.Printf("wallet service: failed to notify user of expired payment request %s: %v", request.ID, err)
+		}
+	}
+	return true
+}
+
+func (s *Service) notifyPaymentRequestPaid(ctx context.Context, request *PaymentRequest) {
+	if s.notifier == nil {
+		return
+	}
+
+	body := fmt.Sprintf("Your request for NGN %.2f has been paid.", float64(request.Amount)/100)
+	if request.Memo != "" {
+		body = fmt.Sprintf("Your request for NGN %.2f (%s) has been paid.", float64(request.Amount)/100, request.Memo)
+	}
+	if err := s.notifier.SendToUser(ctx, request.MobileUserID, "Payment request paid", "transaction", body,
+		map[string]any{"payment_request_id": request.ID}); err != nil {
+		log.Printf("wallet service: failed to notify user about payment request %s: %v", request.ID, err)
+	}
+}
+
+func (s *Service) toPaymentRequestResponse(request *PaymentRequest, w *CustomerWallet) *PaymentRequestResponse {
+	account := AccountObj{
+		AccountNumber: w.AccountNumber,
+		AccountName:   w.AccountName,
+		BankName:      w.BankName,
+		BankCode:      w.BankCode,
+	}
+	link := s.payLinkBaseURL + "/" + request.Code
+
+	return &PaymentRequestResponse{
+		ID:            request.ID,
+		Code:          request.Code,
+		Amount:        float64(request.Amount) / 100,
+		Memo:          request.Memo,
+		Status:        effectivePaymentRequestStatus(request, time.Now()),
+		Link:          link,
+		QRPayload:     paymentRequestQRPayload(request, link, account),
+		Account:       account,
+		TransactionID: request.TransactionID,
+		PaidAt:        request.PaidAt,
+		ExpiresAt:     request.ExpiresAt,
+		CreatedAt:     request.CreatedAt,
+	}
+}
+
+func (s *Service) AddBeneficiary(ctx context.Context, mobileUserID string, req *AddBeneficiaryRequest) (*Beneficiary, error) {
+	mobileUserID = strings.TrimSpace(mobileUserID)
+
+	user, err := s.repo.GetUserWalletID(ctx, mobileUserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErr.ErrUnauthorized
+		}
+		return nil, appErr.ErrAddingBeneficiary
+	}
+
+	walletID := strings.TrimSpace(user.WalletID)
+	accountNumber := strings.TrimSpace(req.AccountNumber)
+	accountName := strings.TrimSpace(req.AccountName)
+	bankCode := strings.TrimSpace(req.BankCode)
+
+	if mobileUserID == "" {
+		return nil, appErr.ErrInvalidRequestBody
+	}
+
+	if walletID == "" {
+		return nil, appErr.ErrInvalidRequestBody
+	}
+
+	if accountNumber == "" {
+		return nil, appErr.ErrInvalidRequestBody
+	}
+
+	if accountName == "" {
+		return nil, appErr.ErrInvalidRequestBody
+	}
+
+	if bankCode == "" {
+		return nil, appErr.ErrInvalidRequestBody
+	}
+
+	if _, err := s.repo.FindBeneficiary(ctx, mobileUserID, bankCode, accountNumber, ""); err == nil {
+		return nil, appErr.ErrBeneficiaryExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("wallet service: failed to check for existing beneficiary: %v", err)
+		return nil, appErr.ErrAddingBeneficiary
+	}
+
+	beneficiary := &Beneficiary{
+		ID:            uuid.NewString(),
+		MobileUserID:  mobileUserID,
+		WalletID:      walletID,
+		BankCode:      bankCode,
+		AccountNumber: accountNumber,
+		AccountName:   accountName,
+		Nickname:      strings.TrimSpace(req.Nickname),
+		IsFavourite:   req.IsFavourite,
+	}
+
+	if err := s.repo.CreateBeneficiary(ctx, beneficiary); err != nil {
+		return nil, appErr.ErrAddingBeneficiary
+	}
+
+	return beneficiary, nil
+}
+
+// UpdateBeneficiary applies the fields set in req. Changing the bank, account
+// number or name re-resolves the account with the bank, and the change is
+// refused when the bank's name does not match.
+func (s *Service) UpdateBeneficiary(ctx context.Context, mobileUserID, id string, req *UpdateBeneficiaryRequest) (*Beneficiary, error) {
+	beneficiary, err := s.loadBeneficiary(ctx, mobileUserID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]any{}
+	if req.Nickname != nil {
+		updates["nickname"] = strings.TrimSpace(*req.Nickname)
+	}
+	if req.IsFavourite != nil {
+		updates["is_favourite"] = *req.IsFavourite
+	}
+
+	bankCode, accountNumber, accountName := beneficiary.BankCode, beneficiary.AccountNumber, beneficiary.AccountName
+	if req.BankCode != nil {
+		bankCode = strings.TrimSpace(*req.BankCode)
+	}
+	if req.AccountNumber != nil {
+		accountNumber = strings.TrimSpace(*req.AccountNumber)
+	}
+	if req.AccountName != nil {
+		accountName = strings.TrimSpace(*req.AccountName)
+	}
+	if bankCode == "" || accountNumber == "" || accountName == "" {
+		return nil, appErr.ErrInvalidRequestBody
+	}
+
+	if bankCode != beneficiary.BankCode || accountNumber != beneficiary.AccountNumber || accountName != beneficiary.AccountName {
+		details, err := s.FetchBankDetails(ctx, accountNumber, bankCode)
+		if err != nil {
+			return nil, err
+		}
+		if !sameAccountName(details.AccountName, accountName) {
+			return nil, appErr.ErrBeneficiaryNameMismatch
+		}
+
+		if _, err := s.repo.FindBeneficiary(ctx, mobileUserID, bankCode, accountNumber, beneficiary.ID); err == nil {
+			return nil, appErr.ErrBeneficiaryExists
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("wallet service: failed to check for existing beneficiary: %v", err)
+			return nil, appErr.ErrUpdatingBeneficiary
+		}
+
+		updates["bank_code"] = bankCode
+		updates["account_number"] = accountNumber
+		updates["account_name"] = strings.TrimSpace(details.AccountName)
+		updates["verified_at"] = time.Now().UTC()
+	}
+
+	if len(updates) == 0 {
+		return beneficiary, nil
+	}
+	if err := s.repo.UpdateBeneficiary(ctx, beneficiary.ID, updates); err != nil {
+		log.Printf("wallet service: failed to update beneficiary %s: %v", beneficiary.ID, err)
+		return nil, appErr.ErrUpdatingBeneficiary
+	}
+
+	return s.loadBeneficiary(ctx, mobileUserID, beneficiary.ID)
+}
+
+// VerifyBeneficiary re-resolves a saved account with the bank and takes the
+// bank's name when it has changed since the beneficiary was saved.
+func (s *Service) VerifyBeneficiary(ctx context.Context, mobileUserID, id string) (*VerifyBeneficiaryResponse, error) {
+	beneficiary, err := s.loadBeneficiary(ctx, mobileUserID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := s.FetchBankDetails(ctx, beneficiary.AccountNumber, beneficiary.BankCode)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &VerifyBeneficiaryResponse{}
+	now := time.Now().UTC()
+	updates := map[string]any{"verified_at": now}
+	bankName := strings.TrimSpace(details.AccountName)
+	if bankName != "" && !sameAccountName(bankName, beneficiary.AccountName) {
+		resp.NameChanged = true
+		resp.PreviousName = beneficiary.AccountName
+		updates["account_name"] = bankName
+		beneficiary.AccountName = bankName
+	}
+	if err := s.repo.UpdateBeneficiary(ctx, beneficiary.ID, updates); err != nil {
+		log.Printf("wallet service: failed to save beneficiary verification %s: %v", beneficiary.ID, err)
+		return nil, appErr.ErrUpdatingBeneficiary
+	}
+	beneficiary.VerifiedAt = &now
+
+	resp.Beneficiary = toBeneficiaryResponse(beneficiary)
+	return resp, nil
+}
+
+func (s *Service) DeleteBeneficiary(ctx context.Context, mobileUserID, id string) error {
+	deleted, err := s.repo.DeleteBeneficiary(ctx, mobileUserID, strings.TrimSpace(id))
+	if err != nil {
+		log.Printf("wallet service: failed to delete beneficiary %s: %v", id, err)
+		return appErr.ErrUpdatingBeneficiary
+	}
+	if !deleted {
+		return appErr.ErrBeneficiaryNotFound
+	}
+	return nil
+}
+
+func (s *Service) loadBeneficiary(ctx context.Context, mobileUserID, id string) (*Beneficiary, error) {
+	beneficiary, err := s.repo.GetBeneficiary(ctx, mobileUserID, strings.TrimSpace(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErr.ErrBeneficiaryNotFound
+		}
+		log.Printf("wallet service: failed to load beneficiary %s: %v", id, err)
+		return nil, appErr.ErrFetchingBeneficiaries
+	}
+	return beneficiary, nil
+}
+
+func (s *Service) InitiateDeposit(ctx context.Context, deviceID, mobileUserID string, req InitiatedDepositRequest) (*InitiatedDepositResponse, error) {
+	mobileUserID = strings.TrimSpace(mobileUserID)
+	if mobileUserID == "" {
+		return nil, appErr.ErrMissingUserID
+	}
+
+	_, err := s.repo.GetDevice(ctx, mobileUserID, deviceID)
+	if err != nil {
+		log.Printf("wallet service: failed to verify device: %v", err)
+		return nil, appErr.ErrUnrecognizedDevice
+	}
+
+	wallet, err := s.repo.GetWallet(ctx, mobileUserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErr.ErrMissingUserWallet
+		}
+		log.Printf("wallet service: error fetching wallet: %v", err)
+		return nil, appErr.ErrInitiatingDeposit
+	}
+
+	trackingID := uuid.NewString()
+	now := time.Now().UTC()
+	expiresAt := now.Add(30 * time.Minute)
+
+	expectedDeposit := &ExpectedDeposit{
+		ID:             uuid.NewString(),
+		TrackingID:     trackingID,
+		MobileUserID:   mobileUserID,
+		ExpectedAmount: req.ExpectedAmount * 100,
+		WalletID:       wallet.InternalWalletID,
+		Status:         ExpectedDepositStatusPending,
+		ExpiresAt:      expiresAt,
+		CreatedAt:      now,
+	}
+
+	if err := s.repo.CreateExpectedDeposit(ctx, expectedDeposit); err != nil {
+		log.Printf("wallet service: could not create deposit: %v", err)
+		return nil, appErr.ErrInitiatingDeposit
+	}
+
+	account := &AccountObj{
+		AccountNumber: wallet.AccountNumber,
+		AccountName:   wallet.AccountName,
+		BankName:      wallet.BankName,
+		BankCode:      wallet.BankCode,
+	}
+
+	return &InitiatedDepositResponse{
+		Status:     true,
+		TrackingID: trackingID,
+		ExpiresAt:  expiresAt,
+		Account:    *account,
+	}, nil
+
+}
+
+func (s *Service) GetExpectedDeposit(ctx context.Context, mobileUserID, trackingID string) (*DepositObj, error) {
+	deposit, err := s.repo.GetExpectedDeposit(ctx, strings.TrimSpace(mobileUserID), strings.TrimSpace(trackingID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErr.ErrExpectedDepositNotFound
+		}
+		log.Printf("wallet service: failed to get expected deposit: %v", err)
+		return nil, appErr.ErrFetchingExpectedDeposit
+	}
+
+	result := &DepositObj{
+		TrackingID:     deposit.TrackingID,
+		Status:         deposit.Status,
+		ExpectedAmount: float64(deposit.ExpectedAmount) / 100,
+		ActualAmount:   float64(deposit.ActualAmount) / 100,
+		ExpiresAt:      deposit.ExpiresAt,
+	}
+
+	if deposit.TransactionID != nil {
+		tx, err := s.repo.GetTransactionByID(ctx, *deposit.TransactionID)
+		if err != nil {
+			log.Printf("wallet service: failed to get deposit transaction: %v", err)
+			return nil, appErr.ErrFetchingExpectedDeposit
+		}
+		narration := ""
+		if tx.Narration != nil {
+			narration = *tx.Narration
+		}
+		result.Transaction = TransactionObj{
+			ID:        tx.ID,
+			Amount:    tx.Amount,
+			Reference: tx.Reference,
+			Narration: narration,
+			CreatedAt: tx.CreatedAt,
+		}
+	}
+
+	return result, nil
+}
+
+// ExpireStaleDeposits closes expected deposits whose grace window has passed
+// and tells the user what, if anything, arrived.
+func (s *Service) ExpireStaleDeposits(ctx context.Context, limit int) error {
+	deposits, err := s.repo.ListStaleExpectedDeposits(ctx, time.Now().UTC().Add(-expectedDepositGrace), limit)
+	if err != nil {
+		return err
+	}
+
+	for _, deposit := range deposits {
+		expired, err := s.repo.ExpireExpectedDeposit(ctx, deposit.ID)
+		if err != nil {
+			log.Printf("wallet service: failed to expire deposit %s: %v", deposit.ID, err)
+			continue
+		}
+		if !expired {
+			continue
+		}
+		deposit.Status = ExpectedDepositStatusExpired
+		if s.settlePaymentRequestDeposit(ctx, &deposit) || s.notifier == nil {
+			continue
+		}
+
+		body := "Your deposit request expired before any funds were received."
+		if deposit.ActualAmount > 0 {
+			body = fmt.Sprintf("Your deposit request expired after receiving ₦%.2f of ₦%.2f.",
+				float64(deposit.ActualAmount)/100, float64(deposit.ExpectedAmount)/100)
+		}
+		if err := s.notifier.SendToUser(ctx, deposit.MobileUserID, "Deposit request expired", "transaction", body,
+			map[string]any{"tracking_id": deposit.TrackingID}); err != nil {
+			log.Printf("wallet service: failed to notify user of expired deposit %s: %v", deposit.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// HandleCreditWebhook records the callback in the inbox before doing anything
+// else. A callback whose provider reference was seen before is answered from the
+// stored outcome instead of crediting the wallet again.
+func (s *Service) HandleCreditWebhook(ctx context.Context, payload *ProvidusCredit) (*WebhookOutcome, error) {
+	providerRef := creditProviderRef(payload)
+	if providerRef == "" {
+		return nil, errors.New("no usable provider reference in payload")
+	}
+
+	entry := &WebhookInbox{
+		ID:                uuid.NewString(),
+		Provider:          WebhookProviderProvidus,
+		ProviderReference: providerRef,
+		SessionID:         strings.TrimSpace(payload.SessionID),
+		AccountNumber:     strings.TrimSpace(payload.AccountNumber),
+		Payload:           creditPayloadMap(payload),
+		Status:            WebhookInboxStatusReceived,
+		Attempts:          1,
+	}
+
+	created, err := s.repo.RecordWebhook(ctx, entry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record webhook: %w", err)
+	}
+	if !created {
+		existing, err := s.repo.GetWebhookByProviderRef(ctx, WebhookProviderProvidus, providerRef)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load recorded webhook: %w", err)
+		}
+		return &WebhookOutcome{
+			Status:        existing.Status,
+			TransactionID: existing.TransactionID,
+			Duplicate:     true,
+		}, nil
+	}
+
+	return s.processCreditWebhook(ctx, entry, payload)
+}
+
+// ReplayFailedCreditWebhooks reprocesses inbox rows that failed, and rows left
+// in received for longer than webhookStaleAfter, oldest first.
+func (s *Service) ReplayFailedCreditWebhooks(ctx context.Context, limit int) (*WebhookReplaySummary, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	staleBefore := time.Now().Add(-webhookStaleAfter)
+	entries, err := s.repo.ListReplayableWebhooks(ctx, WebhookProviderProvidus, staleBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &WebhookReplaySummary{Scanned: len(entries)}
+	for i := range entries {
+		entry := &entries[i]
+
+		payload, err := creditPayloadFromMap(entry.Payload)
+		if err != nil {
+			log.Printf("wallet service: skipping unreadable webhook %s: %v", entry.ID, err)
+			summary.Failed++
+			continue
+		}
+
+		if err := s.repo.IncrementWebhookAttempts(ctx, entry.ID); err != nil {
+			return summary, err
+		}
+
+		outcome, err := s.processCreditWebhook(ctx, entry, payload)
+		if err != nil {
+			log.Printf("wallet service: replay of webhook %s failed: %v", entry.ID, err)
+		}
+
+		switch outcome.Status {
+		case WebhookInboxStatusProcessed:
+			summary.Processed++
+		case WebhookInboxStatusIgnored:
+			summary.Ignored++
+		default:
+			summary.Failed++
+		}
+	}
+
+	return summary, nil
+}
+
+func (s *Service) processCreditWebhook(ctx context.Context, entry *WebhookInbox, payload *ProvidusCredit) (*WebhookOutcome, error) {
+	txID, status, procErr := s.creditFromWebhook(ctx, payload, entry.ProviderReference)
+
+	lastError := ""
+	if procErr != nil {
+		status = WebhookInboxStatusFailed
+		lastError = procErr.Error()
+	}
+
+	if err := s.repo.FinishWebhook(ctx, entry.ID, status, txID, lastError); err != nil {
+		log.Printf("wallet service: failed to update webhook %s: %v", entry.ID, err)
+	}
+
+	return &WebhookOutcome{Status: status, TransactionID: txID}, procErr
+}
+
+func (s *Service) creditFromWebhook(ctx context.Context, payload *ProvidusCredit, providerRef string) (*string, WebhookInboxStatus, error) {
+	if strings.TrimSpace(payload.TranType) != "C" {
+		return nil, WebhookInboxStatusIgnored, nil
+	}
+
+	amountFloat, err := strconv.ParseFloat(strings.TrimSpace(payload.TransactionAmount), 64)
+	if err != nil || amountFloat <= 0 {
+		return nil, WebhookInboxStatusFailed, fmt.Errorf("invalid transaction amount: %s", payload.TransactionAmount)
+	}
+	amountKobo := int64(math.Round(amountFloat * 100))
+
+	wallet, err := s.repo.GetWalletByAccountNumber(ctx, strings.TrimSpace(payload.AccountNumber))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// account not ours — nothing to credit
+			return nil, WebhookInboxStatusIgnored, nil
+		}
+		return nil, WebhookInboxStatusFailed, fmt.Errorf("failed to get wallet: %w", err)
+	}
+
+	if existing, err := s.repo.GetCreditByProviderRef(ctx, providerRef); err == nil {
+		return &existing.ID, WebhookInboxStatusProcessed, nil
+	}
+
+	narration := strings.TrimSpace(payload.TranRemarks)
+	transfer := &transaction.Transaction{
+		ID:                  uuid.NewString(),
+		MobileUserID:        wallet.MobileUserID,
+		WalletID:            wallet.WalletID,
+		Reference:           uuid.NewString(),
+		ProviderReference:   providerRef,
+		SessionID:           payload.SessionID,
+		Amount:              amountKobo,
+		Narration:           &narration,
+		CounterpartyAccount: payload.AccountNumber,
+		Description:         payload.TranRemarks,
+		Status:              transaction.TransactionStatusSuccessful,
+		Type:                transaction.TransactionTypeCredit,
+		Category:            transaction.TransactionCategoryTransferFrom,
+		Source:              transaction.TransactionSourceCredit,
+	}
+
+	if err := s.repo.CreditWalletAtomically(ctx, transfer, amountKobo); err != nil {
+		// the unique provider_reference index rejects a credit that raced us
+		if existing, lookupErr := s.repo.GetCreditByProviderRef(ctx, providerRef); lookupErr == nil {
+			return &existing.ID, WebhookInboxStatusProcessed, nil
+		}
+		return nil, WebhookInboxStatusFailed, fmt.Errorf("failed to credit wallet: %w", err)
+	}
+
+	deposit, err := s.repo.MatchExpectedDeposit(ctx, wallet.InternalWalletID, transfer.ID, amountKobo, narration, s.depositTolerance, time.Now().UTC())
+	if err != nil {
+		// the credit itself has landed; a missed match only leaves the deposit open
+		log.Printf("wallet service: failed to match expected deposit for %s: %v", providerRef, err)
+	} else if deposit != nil {
+		s.settlePaymentRequestDeposit(ctx, deposit)
+	}
+
+	return &transfer.ID, WebhookInboxStatusProcessed, nil
+}
+
+func (s *Service) GetBeneficiaries(ctx context.Context, mobileUserID string) ([]Beneficiary, error) {
+	beneficiaries, err := s.repo.GetBeneficiaries(ctx, mobileUserID)
+	if err != nil {
+		return nil, appErr.ErrFetchingBeneficiaries
 	}
 
 	return beneficiaries, nil
 }
 
-func (s *Service) GetUserWalletBalance(ctx context.Context, mobileUserID string) (*CustomerWallet, error) {
-	wallet, err := s.repo.GetWallet(ctx, mobileUserID)
+func (s *Service) GetUserWalletBalance(ctx context.Context, mobileUserID string) (*CustomerWallet, error) {
+	wallet, err := s.repo.GetWallet(ctx, mobileUserID)
+	if err != nil {
+		log.Printf("wallet service: failed to get user wallet - %s", err)
+		return nil, err
+	}
+	return wallet, nil
+}
+
+// CreateScheduledTransfer stores a one-off or recurring transfer. The PIN is
+// checked here, once; the executor sends each run without asking again.
+func (s *Service) CreateScheduledTransfer(ctx context.Context, mobileUserID string, req *CreateScheduledTransferRequest) (*ScheduledTransferResponse, error) {
+	if err := s.pinVerifier.Verify(ctx, mobileUserID, req.TransactionPin); err != nil {
+		return nil, err
+	}
+
+	if req.Amount <= 50 {
+		return nil, appErr.ErrInvalidTransferAmount
+	}
+	if err := s.checkSecondFactor(ctx, mobileUserID, req.Amount*100, req.OTPCode); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	startAt := req.StartAt.UTC()
+	if startAt.Before(now.Add(-time.Minute)) {
+		return nil, appErr.ErrInvalidSchedule
+	}
+	if startAt.Before(now) {
+		startAt = now
+	}
+
+	var endAt *time.Time
+	if req.EndAt != nil {
+		if req.Frequency == ScheduleFrequencyOnce || !req.EndAt.After(startAt) {
+			return nil, appErr.ErrInvalidSchedule
+		}
+		end := req.EndAt.UTC()
+		endAt = &end
+	}
+
+	accountNumber := strings.TrimSpace(req.AccountNumber)
+	accountName := strings.TrimSpace(req.AccountName)
+	sortCode := strings.TrimSpace(req.SortCode)
+	if accountNumber == "" || accountName == "" || sortCode == "" {
+		return nil, appErr.ErrInvalidRequestBody
+	}
+
+	if _, err := s.repo.GetWallet(ctx, mobileUserID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErr.ErrMissingUserWallet
+		}
+		log.Printf("wallet service: failed to get wallet for scheduled transfer: %v", err)
+		return nil, appErr.ErrScheduledTransfer
+	}
+
+	narration := ""
+	if req.Narration != nil {
+		narration = strings.TrimSpace(*req.Narration)
+	}
+
+	schedule := &ScheduledTransfer{
+		ID:            uuid.NewString(),
+		MobileUserID:  mobileUserID,
+		Amount:        req.Amount * 100,
+		SortCode:      sortCode,
+		AccountNumber: accountNumber,
+		AccountName:   accountName,
+		Narration:     narration,
+		Frequency:     req.Frequency,
+		DayOfMonth:    startAt.Day(),
+		StartAt:       startAt,
+		EndAt:         endAt,
+		OccurrenceAt:  startAt,
+		NextRunAt:     startAt,
+		Status:        ScheduledTransferStatusActive,
+	}
+	if err := s.repo.CreateScheduledTransfer(ctx, schedule); err != nil {
+		log.Printf("wallet service: failed to create scheduled transfer: %v", err)
+		return nil, appErr.ErrScheduledTransfer
+	}
+
+	return toScheduledTransferResponse(schedule, nil), nil
+}
+
+func (s *Service) ListScheduledTransfers(ctx context.Context, mobileUserID string) ([]ScheduledTransferResponse, error) {
+	schedules, err := s.repo.ListScheduledTransfers(ctx, mobileUserID)
+	if err != nil {
+		log.Printf("wallet service: failed to list scheduled transfers: %v", err)
+		return nil, appErr.ErrScheduledTransfer
+	}
+
+	out := make([]ScheduledTransferResponse, 0, len(schedules))
+	for i := range schedules {
+		out = append(out, *toScheduledTransferResponse(&schedules[i], nil))
+	}
+	return out, nil
+}
+
+func (s *Service) GetScheduledTransfer(ctx context.Context, mobileUserID, id string) (*ScheduledTransferResponse, error) {
+	schedule, err := s.loadScheduledTransfer(ctx, mobileUserID, id)
 	if err != nil {
-		log.Printf("wallet service: failed to get user wallet - %s", err)
 		return nil, err
 	}
-	return wallet, nil
+
+	runs, err := s.repo.ListScheduledTransferRuns(ctx, schedule.ID, 20)
+	if err != nil {
+		log.Printf("wallet service: failed to list scheduled transfer runs: %v", err)
+		return nil, appErr.ErrScheduledTransfer
+	}
+
+	return toScheduledTransferResponse(schedule, runs), nil
+}
+
+func (s *Service) UpdateScheduledTransfer(ctx context.Context, mobileUserID, id string, req *UpdateScheduledTransferRequest) (*ScheduledTransferResponse, error) {
+	if err := s.pinVerifier.Verify(ctx, mobileUserID, req.TransactionPin); err != nil {
+		return nil, err
+	}
+
+	schedule, err := s.loadOpenScheduledTransfer(ctx, mobileUserID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if req.Amount != nil {
+		if *req.Amount <= 50 {
+			return nil, appErr.ErrInvalidTransferAmount
+		}
+		schedule.Amount = *req.Amount * 100
+		updates["amount"] = schedule.Amount
+	}
+	// checked against the amount that will run from now on
+	if err := s.checkSecondFactor(ctx, mobileUserID, schedule.Amount, req.OTPCode); err != nil {
+		return nil, err
+	}
+	if req.Narration != nil {
+		schedule.Narration = strings.TrimSpace(*req.Narration)
+		updates["narration"] = schedule.Narration
+	}
+	if req.EndAt != nil {
+		if schedule.Frequency == ScheduleFrequencyOnce || !req.EndAt.After(schedule.NextRunAt) {
+			return nil, appErr.ErrInvalidSchedule
+		}
+		end := req.EndAt.UTC()
+		schedule.EndAt = &end
+		updates["end_at"] = end
+	}
+	if len(updates) == 0 {
+		return toScheduledTransferResponse(schedule, nil), nil
+	}
+
+	if err := s.repo.UpdateScheduledTransfer(ctx, schedule.ID, updates); err != nil {
+		log.Printf("wallet service: failed to update scheduled transfer: %v", err)
+		return nil, appErr.ErrScheduledTransfer
+	}
+	return toScheduledTransferResponse(schedule, nil), nil
+}
+
+func (s *Service) PauseScheduledTransfer(ctx context.Context, mobileUserID, id string) (*ScheduledTransferResponse, error) {
+	schedule, err := s.loadOpenScheduledTransfer(ctx, mobileUserID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule.Status = ScheduledTransferStatusPaused
+	if err := s.repo.UpdateScheduledTransfer(ctx, schedule.ID, map[string]interface{}{"status": schedule.Status}); err != nil {
+		log.Printf("wallet service: failed to pause scheduled transfer: %v", err)
+		return nil, appErr.ErrScheduledTransfer
+	}
+	return toScheduledTransferResponse(schedule, nil), nil
+}
+
+// ResumeScheduledTransfer re-activates a paused schedule. Occurrences missed
+// while paused are skipped, except a one-off, which runs straight away.
+func (s *Service) ResumeScheduledTransfer(ctx context.Context, mobileUserID, id string) (*ScheduledTransferResponse, error) {
+	schedule, err := s.loadOpenScheduledTransfer(ctx, mobileUserID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	updates := map[string]interface{}{
+		"status":          ScheduledTransferStatusActive,
+		"failed_attempts": 0,
+	}
+	if schedule.NextRunAt.Before(now) {
+		if schedule.Frequency == ScheduleFrequencyOnce {
+			schedule.NextRunAt = now
+		} else {
+			next, ok := nextOccurrence(schedule, now)
+			if !ok {
+				return nil, appErr.ErrScheduledTransferClosed
+			}
+			schedule.OccurrenceAt, schedule.NextRunAt = next, next
+		}
+		updates["occurrence_at"] = schedule.OccurrenceAt
+		updates["next_run_at"] = schedule.NextRunAt
+	}
+
+	schedule.Status = ScheduledTransferStatusActive
+	if err := s.repo.UpdateScheduledTransfer(ctx, schedule.ID, updates); err != nil {
+		log.Printf("wallet service: failed to resume scheduled transfer: %v", err)
+		return nil, appErr.ErrScheduledTransfer
+	}
+	return toScheduledTransferResponse(schedule, nil), nil
+}
+
+func (s *Service) CancelScheduledTransfer(ctx context.Context, mobileUserID, id string) error {
+	schedule, err := s.loadOpenScheduledTransfer(ctx, mobileUserID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.UpdateScheduledTransfer(ctx, schedule.ID, map[string]interface{}{"status": ScheduledTransferStatusCancelled}); err != nil {
+		log.Printf("wallet service: failed to cancel scheduled transfer: %v", err)
+		return appErr.ErrScheduledTransfer
+	}
+	return nil
+}
+
+func (s *Service) loadScheduledTransfer(ctx context.Context, mobileUserID, id string) (*ScheduledTransfer, error) {
+	schedule, err := s.repo.GetScheduledTransfer(ctx, mobileUserID, strings.TrimSpace(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, appErr.ErrScheduledTransferNotFound
+	}
+	if err != nil {
+		log.Printf("wallet service: failed to get scheduled transfer: %v", err)
+		return nil, appErr.ErrScheduledTransfer
+	}
+	return schedule, nil
+}
+
+func (s *Service) loadOpenScheduledTransfer(ctx context.Context, mobileUserID, id string) (*ScheduledTransfer, error) {
+	schedule, err := s.loadScheduledTransfer(ctx, mobileUserID, id)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.Status != ScheduledTransferStatusActive && schedule.Status != ScheduledTransferStatusPaused {
+		return nil, appErr.ErrScheduledTransferClosed
+	}
+	return schedule, nil
+}
+
+// ProcessDueScheduledTransfers runs every schedule whose next run is due.
+// Insufficient funds and provider failures are retried a few times before the
+// occurrence is given up on.
+func (s *Service) ProcessDueScheduledTransfers(ctx context.Context, limit int) (*ScheduledTransferSummary, error) {
+	now := time.Now().UTC()
+	schedules, err := s.repo.ListDueScheduledTransfers(ctx, now, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &ScheduledTransferSummary{Due: len(schedules)}
+	for i := range schedules {
+		schedule := &schedules[i]
+		claimed, err := s.repo.ClaimScheduledTransfer(ctx, schedule.ID, schedule.NextRunAt, now)
+		if err != nil {
+			log.Printf("wallet service: failed to claim scheduled transfer %s: %v", schedule.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		status, retrying, err := s.runScheduledTransfer(ctx, schedule, now)
+		if err != nil {
+			log.Printf("wallet service: failed to record scheduled transfer %s run: %v", schedule.ID, err)
+		}
+
+		switch {
+		case status == ScheduledRunStatusSuccessful:
+			summary.Successful++
+		case status == ScheduledRunStatusProcessing:
+			summary.Processing++
+		case retrying:
+			summary.Retrying++
+		default:
+			summary.Failed++
+		}
+	}
+
+	return summary, nil
+}
+
+func (s *Service) runScheduledTransfer(ctx context.Context, schedule *ScheduledTransfer, now time.Time) (ScheduledRunStatus, bool, error) {
+	attempt := schedule.FailedAttempts + 1
+	status, txID, runErr := s.attemptScheduledTransfer(ctx, schedule)
+
+	run := &ScheduledTransferRun{
+		ID:            uuid.NewString(),
+		ScheduleID:    schedule.ID,
+		TransactionID: txID,
+		ScheduledFor:  schedule.OccurrenceAt,
+		Attempt:       attempt,
+		Status:        status,
+	}
+	if runErr != nil {
+		run.Error = runErr.Error()
+	}
+
+	updates := map[string]interface{}{
+		"last_run_at":     now,
+		"last_run_status": status,
+	}
+
+	sent := status == ScheduledRunStatusSuccessful || status == ScheduledRunStatusProcessing
+	retrying := !sent && attempt < scheduledTransferMaxAttempts && !terminalTransferError(runErr)
+	switch {
+	case retrying:
+		updates["failed_attempts"] = attempt
+		updates["next_run_at"] = now.Add(scheduledTransferRetryDelay)
+	default:
+		if sent {
+			updates["run_count"] = schedule.RunCount + 1
+		}
+		updates["failed_attempts"] = 0
+		if next, ok := nextOccurrence(schedule, now); ok {
+			updates["occurrence_at"] = next
+			updates["next_run_at"] = next
+		} else if sent || schedule.Frequency != ScheduleFrequencyOnce {
+			updates["status"] = ScheduledTransferStatusCompleted
+		} else {
+			updates["status"] = ScheduledTransferStatusFailed
+		}
+	}
+
+	err := s.repo.FinishScheduledRun(ctx, run, updates)
+	s.notifyScheduledRun(ctx, schedule, status, retrying)
+	return status, retrying, err
+}
+
+// attemptScheduledTransfer sends one run. The balance, charges included, is
+// checked first so an underfunded wallet is retried without a failed
+// transaction on the statement.
+func (s *Service) attemptScheduledTransfer(ctx context.Context, schedule *ScheduledTransfer) (ScheduledRunStatus, *string, error) {
+	w, err := s.repo.GetWallet(ctx, schedule.MobileUserID)
+	if err != nil {
+		return ScheduledRunStatusFailed, nil, err
+	}
+	if err := DebitAllowed(w.Status); err != nil {
+		return ScheduledRunStatusFailed, nil, err
+	}
+	if w.AvailableBalance < schedule.Amount+TransferChargeKobo(schedule.Amount) {
+		return ScheduledRunStatusInsufficientFunds, nil, appErr.ErrInsufficientBalance
+	}
+
+	txID := uuid.NewString()
+	narration := schedule.Narration
+	accountName := schedule.AccountName
+	req := &TransferRequest{
+		Amount:        schedule.Amount / 100,
+		SortCode:      schedule.SortCode,
+		Narration:     &narration,
+		AccountNumber: schedule.AccountNumber,
+		AccountName:   &accountName,
+	}
+
+	_, err = s.executeTransfer(ctx, schedule.MobileUserID, txID, req)
+	switch {
+	case err == nil:
+		return ScheduledRunStatusSuccessful, &txID, nil
+	case errors.Is(err, appErr.ErrTransferAmbiguous):
+		// the pending-transfer reconciler settles it
+		return ScheduledRunStatusProcessing, &txID, nil
+	}
+
+	// link the failed transaction if the transfer got far enough to record one
+	if _, lookupErr := s.repo.GetTransactionByID(ctx, txID); lookupErr == nil {
+		return ScheduledRunStatusFailed, &txID, err
+	}
+	return ScheduledRunStatusFailed, nil, err
+}
+
+func (s *Service) notifyScheduledRun(ctx context.Context, schedule *ScheduledTransfer, status ScheduledRunStatus, retrying bool) {
+	if s.notifier == nil {
+		return
+	}
+
+	amount := fmt.Sprintf("NGN %.2f", float64(schedule.Amount)/100)
+	var title, body string
+	switch {
+	case status == ScheduledRunStatusSuccessful:
+		title = "Scheduled transfer sent"
+		body = fmt.Sprintf("%s has been sent to %s.", amount, schedule.AccountName)
+	case status == ScheduledRunStatusProcessing:
+		title = "Scheduled transfer processing"
+		body = fmt.Sprintf("Your scheduled transfer of %s to %s is processing.", amount, schedule.AccountName)
+	case status == ScheduledRunStatusInsufficientFunds && retrying:
+		title = "Scheduled transfer delayed"
+		body = fmt.Sprintf("Your wallet balance is too low to send %s to %s. We will try again in an hour.", amount, schedule.AccountName)
+	case retrying:
+		title = "Scheduled transfer delayed"
+		body = fmt.Sprintf("We could not send %s to %s. We will try again in an hour.", amount, schedule.AccountName)
+	default:
+		title = "Scheduled transfer failed"
+		body = fmt.Sprintf("We could not send %s to %s after several attempts.", amount, schedule.AccountName)
+	}
+
+	if err := s.notifier.SendToUser(ctx, schedule.MobileUserID, title, "transaction", body,
+		map[string]any{"scheduled_transfer_id": schedule.ID, "status": string(status)}); err != nil {
+		log.Printf("wallet service: failed to notify user about scheduled transfer %s: %v", schedule.ID, err)
+	}
+}
+
+func toScheduledTransferResponse(schedule *ScheduledTransfer, runs []ScheduledTransferRun) *ScheduledTransferResponse {
+	resp := &ScheduledTransferResponse{
+		ID:            schedule.ID,
+		Amount:        float64(schedule.Amount) / 100,
+		SortCode:      schedule.SortCode,
+		AccountNumber: schedule.AccountNumber,
+		AccountName:   schedule.AccountName,
+		Narration:     schedule.Narration,
+		Frequency:     schedule.Frequency,
+		StartAt:       schedule.StartAt,
+		EndAt:         schedule.EndAt,
+		Status:        schedule.Status,
+		RunCount:      schedule.RunCount,
+		LastRunAt:     schedule.LastRunAt,
+		LastRunStatus: schedule.LastRunStatus,
+		CreatedAt:     schedule.CreatedAt,
+	}
+	if schedule.Status == ScheduledTransferStatusActive || schedule.Status == ScheduledTransferStatusPaused {
+		next := schedule.NextRunAt
+		resp.NextRunAt = &next
+	}
+
+	for _, run := range runs {
+		resp.Runs = append(resp.Runs, ScheduledTransferRunResponse{
+			ID:            run.ID,
+			TransactionID: run.TransactionID,
+			ScheduledFor:  run.ScheduledFor,
+			Attempt:       run.Attempt,
+			Status:        run.Status,
+			Error:         run.Error,
+			CreatedAt:     run.CreatedAt,
+		})
+	}
+	return resp
+}
+
+// ChangeWalletStatus applies a support action to the wallet behind
+// accountNumber and records it in the audit trail.
+func (s *Service) ChangeWalletStatus(ctx context.Context, accountNumber string, req *ChangeWalletStatusRequest) (*WalletStatusResponse, error) {
+	target, ok := walletStatusActionTargets[req.Action]
+	if !ok {
+		return nil, appErr.ErrInvalidRequestBody
+	}
+	if !walletStatusReasons[req.ReasonCode] {
+		return nil, appErr.ErrInvalidWalletStatusReason
+	}
+
+	w, err := s.loadWalletByAccountNumber(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &WalletStatusEvent{
+		ID:           uuid.NewString(),
+		WalletID:     w.InternalWalletID,
+		MobileUserID: w.MobileUserID,
+		FromStatus:   NormalizeWalletStatus(w.Status),
+		ToStatus:     target,
+		ReasonCode:   req.ReasonCode,
+		Note:         strings.TrimSpace(req.Note),
+		Actor:        strings.TrimSpace(req.Actor),
+	}
+	if err := s.applyWalletStatus(ctx, event, false); err != nil {
+		return nil, err
+	}
+	if target == WalletStatusClosed {
+		s.cancelSchedulesOnClose(ctx, w.MobileUserID)
+	}
+
+	s.notifyWalletStatus(ctx, event)
+	return s.walletStatus(ctx, w)
+}
+
+func (s *Service) GetWalletStatus(ctx context.Context, accountNumber string) (*WalletStatusResponse, error) {
+	w, err := s.loadWalletByAccountNumber(ctx, accountNumber)
+	if err != nil {
+		return nil, err
+	}
+	return s.walletStatus(ctx, w)
+}
+
+// CloseWallet closes the user's own wallet. Any balance is first swept to an
+// account the bank confirms is in the user's name; a residue too small to send
+// stays on the closed wallet for support to settle.
+func (s *Service) CloseWallet(ctx context.Context, mobileUserID string, req *CloseWalletRequest) (*CloseWalletResponse, error) {
+	if err := s.pinVerifier.Verify(ctx, mobileUserID, req.TransactionPin); err != nil {
+		return nil, err
+	}
+
+	w, err := s.repo.GetWallet(ctx, mobileUserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErr.ErrMissingUserWallet
+		}
+		log.Printf("wallet service: failed to get wallet for closure: %v", err)
+		return nil, appErr.ErrWalletStatus
+	}
+	if err := DebitAllowed(w.Status); err != nil {
+		return nil, err
+	}
+	if err := s.checkSecondFactor(ctx, mobileUserID, closureSweepAmount(w.AvailableBalance)*100, req.OTPCode); err != nil {
+		return nil, err
+	}
+
+	bankCode := strings.TrimSpace(req.BankCode)
+	accountNumber := strings.TrimSpace(req.AccountNumber)
+	details, err := s.FetchBankDetails(ctx, accountNumber, bankCode)
+	if err != nil {
+		return nil, err
+	}
+	accountName := strings.TrimSpace(details.AccountName)
+	if !accountHeldBy(accountName, w.FirstName, w.LastName) {
+		return nil, appErr.ErrClosureAccountMismatch
+	}
+
+	resp := &CloseWalletResponse{
+		Status:             WalletStatusClosed,
+		DestinationAccount: accountNumber,
+		DestinationName:    accountName,
+	}
+
+	// executeTransfer rejects anything up to ₦50
+	if amount := closureSweepAmount(w.AvailableBalance); amount > 50 {
+		txID := uuid.NewString()
+		narration := "Account closure balance sweep"
+		if _, err := s.executeTransfer(ctx, mobileUserID, txID, &TransferRequest{
+			Amount:        amount,
+			SortCode:      bankCode,
+			AccountNumber: accountNumber,
+			AccountName:   &accountName,
+			Narration:     &narration,
+		}); err != nil {
+			return nil, err
+		}
+		resp.SweptAmount = float64(amount)
+		resp.SweepTransactionID = txID
+
+		if w, err = s.repo.GetWallet(ctx, mobileUserID); err != nil {
+			log.Printf("wallet service: failed to reload wallet after closure sweep: %v", err)
+			return nil, appErr.ErrWalletStatus
+		}
+	}
+
+	note := strings.TrimSpace(req.Reason)
+	if resp.SweepTransactionID != "" {
+		note = strings.TrimSpace(fmt.Sprintf("%s (balance swept in %s)", note, resp.SweepTransactionID))
+	}
+	event := &WalletStatusEvent{
+		ID:           uuid.NewString(),
+		WalletID:     w.InternalWalletID,
+		MobileUserID: mobileUserID,
+		FromStatus:   NormalizeWalletStatus(w.Status),
+		ToStatus:     WalletStatusClosed,
+		ReasonCode:   WalletStatusReasonCustomerRequest,
+		Note:         note,
+		Actor:        walletStatusActorCustomer,
+	}
+	if err := s.applyWalletStatus(ctx, event, true); err != nil {
+		return nil, err
+	}
+	s.cancelSchedulesOnClose(ctx, mobileUserID)
+
+	resp.RemainingBalance = float64(event.BalanceAtChange) / 100
+	s.notifyWalletStatus(ctx, event)
+	return resp, nil
+}
+
+func (s *Service) applyWalletStatus(ctx context.Context, event *WalletStatusEvent, allowBalance bool) error {
+	if !canChangeWalletStatus(event.FromStatus, event.ToStatus) {
+		return appErr.ErrInvalidWalletStatusChange
+	}
+
+	err := s.repo.SetWalletStatus(ctx, event, allowBalance)
+	switch {
+	case errors.Is(err, ErrWalletStatusConflict):
+		return appErr.ErrInvalidWalletStatusChange
+	case errors.Is(err, ErrWalletNotEmpty):
+		return appErr.ErrWalletBalanceNotZero
+	case err != nil:
+		log.Printf("wallet service: failed to move wallet %s to %s: %v", event.WalletID, event.ToStatus, err)
+		return appErr.ErrWalletStatus
+	}
+	return nil
+}
+
+func (s *Service) cancelSchedulesOnClose(ctx context.Context, mobileUserID string) {
+	if err := s.repo.CancelOpenScheduledTransfers(ctx, mobileUserID); err != nil {
+		log.Printf("wallet service: failed to cancel scheduled transfers for closed wallet of %s: %v", mobileUserID, err)
+	}
+}
+
+func (s *Service) loadWalletByAccountNumber(ctx context.Context, accountNumber string) (*CustomerWallet, error) {
+	w, err := s.repo.GetWalletByAccountNumber(ctx, strings.TrimSpace(accountNumber))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErr.ErrMissingUserWallet
+		}
+		log.Printf("wallet service: failed to load wallet %s: %v", accountNumber, err)
+		return nil, appErr.ErrWalletStatus
+	}
+	return w, nil
+}
+
+func (s *Service) walletStatus(ctx context.Context, w *CustomerWallet) (*WalletStatusResponse, error) {
+	events, err := s.repo.ListWalletStatusEvents(ctx, w.InternalWalletID)
+	if err != nil {
+		log.Printf("wallet service: failed to list status events for %s: %v", w.InternalWalletID, err)
+		return nil, appErr.ErrWalletStatus
+	}
+
+	resp := &WalletStatusResponse{
+		AccountNumber: w.AccountNumber,
+		Status:        NormalizeWalletStatus(w.Status),
+		History:       make([]WalletStatusEventResponse, len(events)),
+	}
+	if len(events) > 0 {
+		resp.Status = events[0].ToStatus
+	}
+	for i := range events {
+		resp.History[i] = toWalletStatusEventResponse(&events[i])
+	}
+	return resp, nil
+}
+
+func (s *Service) notifyWalletStatus(ctx context.Context, event *WalletStatusEvent) {
+	if s.notifier == nil {
+		return
+	}
+
+	var title, body string
+	switch event.ToStatus {
+	case WalletStatusFrozen:
+		title, body = "Wallet frozen", "Your wallet has been frozen. Please contact support for help."
+	case WalletStatusPostNoDebit:
+		title, body = "Wallet restricted", "Debits on your wallet are on hold. You can still receive money. Please contact support for help."
+	case WalletStatusActive:
+		title, body = "Wallet restored", "Your wallet restriction has been lifted and you can transact again."
+	case WalletStatusClosed:
+		title, body = "Account closed", "Your account has been closed."
+	default:
+		return
+	}
+
+	if err := s.notifier.SendToUser(ctx, event.MobileUserID, title, "account", body,
+		map[string]any{"wallet_status": string(event.ToStatus), "reason_code": string(event.ReasonCode)}); err != nil {
+		log.Printf("wallet service: failed to notify user about wallet status %s: %v", event.ToStatus, err)
+	}
 }
@@ -2,6 +2,7 @@ package wallet
 
 import (
 	"errors"
+	"time"
 )
 
 var (
@@ -9,6 +10,13 @@ var (
 	ErrWalletNotFound           = errors.New("wallet not found")
 	ErrDeviceVerificationFailed = errors.New("device verification failed")
 	ErrTransferProviderFailed   = errors.New("transfer provider failed")
+	ErrNotReversible            = errors.New("transaction cannot be reversed")
+	ErrAlreadyReversed          = errors.New("transaction already reversed")
+	ErrInsufficientFunds        = errors.New("insufficient available balance")
+	ErrPaymentRequestClosed     = errors.New("payment request is no longer payable")
+	ErrWalletStatusConflict     = errors.New("wallet status changed concurrently")
+	ErrWalletNotEmpty           = errors.New("wallet still holds funds")
+	ErrDebitNotPending          = errors.New("debit is no longer pending")
 )
 
 type TransferStatus string
@@ -30,7 +38,202 @@ const (
 type ExpectedDepositStatus string
 
 const (
-	ExpectedDepositStatusPending   ExpectedDepositStatus = "pending"
-	ExpectedDepositStatusExpired   ExpectedDepositStatus = "expired"
-	ExpectedDepositStatusFulfilled ExpectedDepositStatus = "fulfilled"
+	ExpectedDepositStatusPending       ExpectedDepositStatus = "pending"
+	ExpectedDepositStatusMatched       ExpectedDepositStatus = "matched"
+	ExpectedDepositStatusPartiallyPaid ExpectedDepositStatus = "partially_paid"
+	ExpectedDepositStatusOverpaid      ExpectedDepositStatus = "overpaid"
+	ExpectedDepositStatusExpired       ExpectedDepositStatus = "expired"
+	// ExpectedDepositStatusCancelled closes a deposit that will never be paid
+	// into, such as one behind a cancelled or in-app paid payment request.
+	ExpectedDepositStatusCancelled ExpectedDepositStatus = "cancelled"
 )
+
+// openExpectedDepositStatuses can still take credits or expire.
+var openExpectedDepositStatuses = []ExpectedDepositStatus{
+	ExpectedDepositStatusPending,
+	ExpectedDepositStatusPartiallyPaid,
+}
+
+const (
+	// defaultExpectedDepositTolerance absorbs rounding and small fee deductions
+	// by the sending bank, in kobo, unless ConfigureDepositMatching sets one.
+	defaultExpectedDepositTolerance int64 = 100
+	// expectedDepositGrace keeps an expected deposit matchable for a while after
+	// ExpiresAt, since interbank credits often land late.
+	expectedDepositGrace = 15 * time.Minute
+)
+
+type WebhookInboxStatus string
+
+const (
+	WebhookInboxStatusReceived  WebhookInboxStatus = "received"
+	WebhookInboxStatusProcessed WebhookInboxStatus = "processed"
+	WebhookInboxStatusIgnored   WebhookInboxStatus = "ignored"
+	WebhookInboxStatusFailed    WebhookInboxStatus = "failed"
+)
+
+const WebhookProviderProvidus = "providus"
+
+// webhookStaleAfter is how long an inbox row may sit in received before a
+// replay treats its original processing as lost, e.g. to a crash.
+const webhookStaleAfter = 10 * time.Minute
+
+// ProviderTransferStatus is the provider's final word on a transfer, as
+// returned by a requery.
+type ProviderTransferStatus string
+
+const (
+	ProviderTransferStatusSuccessful ProviderTransferStatus = "successful"
+	ProviderTransferStatusFailed     ProviderTransferStatus = "failed"
+	ProviderTransferStatusReversed   ProviderTransferStatus = "reversed"
+	ProviderTransferStatusPending    ProviderTransferStatus = "pending"
+	ProviderTransferStatusNotFound   ProviderTransferStatus = "not_found"
+)
+
+// transferNotFoundCutoff is how long a transfer the provider has no record of
+// stays pending before the reconciler treats it as never sent.
+const transferNotFoundCutoff = 30 * time.Minute
+
+type ScheduleFrequency string
+
+const (
+	ScheduleFrequencyOnce    ScheduleFrequency = "once"
+	ScheduleFrequencyDaily   ScheduleFrequency = "daily"
+	ScheduleFrequencyWeekly  ScheduleFrequency = "weekly"
+	ScheduleFrequencyMonthly ScheduleFrequency = "monthly"
+)
+
+type ScheduledTransferStatus string
+
+const (
+	ScheduledTransferStatusActive    ScheduledTransferStatus = "active"
+	ScheduledTransferStatusPaused    ScheduledTransferStatus = "paused"
+	ScheduledTransferStatusCompleted ScheduledTransferStatus = "completed"
+	ScheduledTransferStatusCancelled ScheduledTransferStatus = "cancelled"
+	ScheduledTransferStatusFailed    ScheduledTransferStatus = "failed"
+)
+
+type ScheduledRunStatus string
+
+const (
+	ScheduledRunStatusSuccessful        ScheduledRunStatus = "successful"
+	ScheduledRunStatusProcessing        ScheduledRunStatus = "processing"
+	ScheduledRunStatusInsufficientFunds ScheduledRunStatus = "insufficient_funds"
+	ScheduledRunStatusFailed            ScheduledRunStatus = "failed"
+)
+
+const (
+	// scheduledTransferMaxAttempts is how many times one occurrence is tried
+	// before it is skipped (recurring) or the schedule fails (one-off).
+	scheduledTransferMaxAttempts = 3
+	scheduledTransferRetryDelay  = time.Hour
+	// scheduledTransferClaimTTL keeps other instances off a schedule while it runs.
+	scheduledTransferClaimTTL = 5 * time.Minute
+)
+
+type TransferBatchStatus string
+
+const (
+	TransferBatchStatusProcessing         TransferBatchStatus = "processing"
+	TransferBatchStatusCompleted          TransferBatchStatus = "completed"
+	TransferBatchStatusPartiallyCompleted TransferBatchStatus = "partially_completed"
+	TransferBatchStatusFailed             TransferBatchStatus = "failed"
+)
+
+const (
+	maxBulkTransferRecipients = 100
+	// bulkLookupConcurrency bounds parallel account name lookups per batch.
+	bulkLookupConcurrency = 5
+)
+
+// P2PRecipientType says how a P2P transfer addresses the receiving Neat user.
+type P2PRecipientType string
+
+const (
+	P2PRecipientPhone         P2PRecipientType = "phone"
+	P2PRecipientUsername      P2PRecipientType = "username"
+	P2PRecipientAccountNumber P2PRecipientType = "account_number"
+)
+
+type PaymentRequestStatus string
+
+const (
+	PaymentRequestStatusPending    PaymentRequestStatus = "pending"
+	PaymentRequestStatusProcessing PaymentRequestStatus = "processing" // an in-app payment is in flight
+	PaymentRequestStatusPaid       PaymentRequestStatus = "paid"
+	PaymentRequestStatusExpired    PaymentRequestStatus = "expired"
+	PaymentRequestStatusCancelled  PaymentRequestStatus = "cancelled"
+)
+
+const (
+	defaultPaymentRequestTTL = 72 * time.Hour
+	maxPaymentRequestTTL     = 30 * 24 * time.Hour
+	paymentRequestCodeLength = 10
+)
+
+const (
+	// accountNameLookupTTL bounds how long a resolved account name is served
+	// from cache; short enough that renamed accounts are picked up quickly.
+	accountNameLookupTTL = 10 * time.Minute
+	// bankDirectoryStaleAfter is how long a bank missing from refreshes is
+	// kept before it is dropped from the directory.
+	bankDirectoryStaleAfter = 7 * 24 * time.Hour
+)
+
+// WalletStatus is our control state for a wallet. Statuses the provider
+// reports that we do not recognise count as active.
+type WalletStatus string
+
+const (
+	WalletStatusActive WalletStatus = "active"
+	// WalletStatusFrozen blocks debits and in-app credits.
+	WalletStatusFrozen WalletStatus = "frozen"
+	// WalletStatusPostNoDebit blocks debits but still takes credits.
+	WalletStatusPostNoDebit WalletStatus = "post_no_debit"
+	WalletStatusClosed      WalletStatus = "closed"
+)
+
+// WalletStatusAction is an operation support can apply to a wallet.
+type WalletStatusAction string
+
+const (
+	WalletStatusActionFreeze      WalletStatusAction = "freeze"
+	WalletStatusActionUnfreeze    WalletStatusAction = "unfreeze"
+	WalletStatusActionPostNoDebit WalletStatusAction = "post_no_debit"
+	WalletStatusActionClose       WalletStatusAction = "close"
+)
+
+var walletStatusActionTargets = map[WalletStatusAction]WalletStatus{
+	WalletStatusActionFreeze:      WalletStatusFrozen,
+	WalletStatusActionUnfreeze:    WalletStatusActive,
+	WalletStatusActionPostNoDebit: WalletStatusPostNoDebit,
+	WalletStatusActionClose:       WalletStatusClosed,
+}
+
+// WalletStatusReason is the reason code recorded with every status change.
+type WalletStatusReason string
+
+const (
+	WalletStatusReasonFraudSuspected  WalletStatusReason = "fraud_suspected"
+	WalletStatusReasonCourtOrder      WalletStatusReason = "court_order"
+	WalletStatusReasonRegulatory      WalletStatusReason = "regulatory"
+	WalletStatusReasonKYCIncomplete   WalletStatusReason = "kyc_incomplete"
+	WalletStatusReasonDormant         WalletStatusReason = "dormant"
+	WalletStatusReasonCustomerRequest WalletStatusReason = "customer_request"
+	WalletStatusReasonResolved        WalletStatusReason = "resolved"
+	WalletStatusReasonOther           WalletStatusReason = "other"
+)
+
+var walletStatusReasons = map[WalletStatusReason]bool{
+	WalletStatusReasonFraudSuspected:  true,
+	WalletStatusReasonCourtOrder:      true,
+	WalletStatusReasonRegulatory:      true,
+	WalletStatusReasonKYCIncomplete:   true,
+	WalletStatusReasonDormant:         true,
+	WalletStatusReasonCustomerRequest: true,
+	WalletStatusReasonResolved:        true,
+	WalletStatusReasonOther:           true,
+}
+
+// walletStatusActorCustomer marks changes the customer made from the app.
+const walletStatusActorCustomer = "customer"
@@ -32,8 +32,8 @@ func NewRouter(cfg config.Config) (*gin.Engine, func(), error) {
 		return nil, nil, err
 	}
 
-	if cfg.JWTSecret == "" {
-		return nil, nil, errors.New("jwt secret can't be empty")
+	if cfg.JWTSecret == "" && strings.TrimSpace(cfg.JWTJWKSURL) == "" {
+		return nil, nil, errors.New("either a jwt secret or a jwks url is required")
 	}
 
 	r := gin.New()
@@ -52,8 +52,17 @@ func NewRouter(cfg config.Config) (*gin.Engine, func(), error) {
 	apiV1 := api.Group("/v1")
 	internalV1 := r.Group("/internal/v1")
 
-	tokenSigner := jwt.NewSigner(cfg.JWTSecret)
-	authGuard := middleware.AuthGuard(tokenSigner, nil)
+	// Prefer verifying against the API's published public keys so this
+	// service needs no signing secret.
+	var tokenVerifier middleware.AccessTokenSigner = jwt.NewSigner(cfg.JWTSecret)
+	if jwksURL := strings.TrimSpace(cfg.JWTJWKSURL); jwksURL != "" {
+		jwksVerifier := jwt.NewJWKSVerifier(jwksURL)
+		if err := jwksVerifier.Refresh(context.Background()); err != nil {
+			log.Printf("initial jwks fetch failed, will retry on first request: %v", err)
+		}
+		tokenVerifier = jwksVerifier
+	}
+	authGuard := middleware.AuthGuard(tokenVerifier, nil)
 
 	deviceRepo := device.NewRepository(db)
 	deviceService := device.NewService(*deviceRepo)
@@ -768,6 +768,33 @@ func MapError(err error) ErrorMapping {
 			},
 		}
 
+	case appErr.ErrExpectedDepositNotFound:
+		return ErrorMapping{
+			Status: http.StatusNotFound,
+			Error: APIError{
+				Code:    "DEPOSIT_REQUEST_NOT_FOUND",
+				Message: appErr.ErrExpectedDepositNotFound.Error(),
+			},
+		}
+
+	case appErr.ErrFetchingExpectedDeposit:
+		return ErrorMapping{
+			Status: http.StatusInternalServerError,
+			Error: APIError{
+				Code:    "FETCHING_DEPOSIT_REQUEST_ERROR",
+				Message: appErr.ErrFetchingExpectedDeposit.Error(),
+			},
+		}
+
+	case appErr.ErrInitiatingDeposit:
+		return ErrorMapping{
+			Status: http.StatusInternalServerError,
+			Error: APIError{
+				Code:    "DEPOSIT_INITIATION_ERROR",
+				Message: appErr.ErrInitiatingDeposit.Error(),
+			},
+		}
+
 	case appErr.ErrSMSDeliveryFailed:
 		return ErrorMapping{
 			Status: http.StatusBadGateway,
@@ -876,6 +903,591 @@ func MapError(err error) ErrorMapping {
 			},
 		}
 
+	case appErr.ErrTransferAmbiguous:
+		return ErrorMapping{
+			Status: http.StatusAccepted,
+			Error: APIError{
+				Code:    "TRANSFER_PROCESSING",
+				Message: appErr.ErrTransferAmbiguous.Error(),
+			},
+		}
+
+	case appErr.ErrTransactionNotFound:
+		return ErrorMapping{
+			Status: http.StatusNotFound,
+			Error: APIError{
+				Code:    "TRANSACTION_NOT_FOUND",
+				Message: appErr.ErrTransactionNotFound.Error(),
+			},
+		}
+
+	case appErr.ErrTransactionNotReversible:
+		return ErrorMapping{
+			Status: http.StatusConflict,
+			Error: APIError{
+				Code:    "TRANSACTION_NOT_REVERSIBLE",
+				Message: appErr.ErrTransactionNotReversible.Error(),
+			},
+		}
+
+	case appErr.ErrTransactionAlreadyReversed:
+		return ErrorMapping{
+			Status: http.StatusConflict,
+			Error: APIError{
+				Code:    "TRANSACTION_ALREADY_REVERSED",
+				Message: appErr.ErrTransactionAlreadyReversed.Error(),
+			},
+		}
+
+	case appErr.ErrTransferOutcomePending:
+		return ErrorMapping{
+			Status: http.StatusConflict,
+			Error: APIError{
+				Code:    "TRANSFER_OUTCOME_PENDING",
+				Message: appErr.ErrTransferOutcomePending.Error(),
+			},
+		}
+
+	case appErr.ErrReversingTransaction:
+		return ErrorMapping{
+			Status: http.StatusInternalServerError,
+			Error: APIError{
+				Code:    "TRANSACTION_REVERSAL_ERROR",
+				Message: appErr.ErrReversingTransaction.Error(),
+			},
+		}
+
+	case appErr.ErrScheduledTransferNotFound:
+		return ErrorMapping{
+			Status: http.StatusNotFound,
+			Error: APIError{
+				Code:    "SCHEDULED_TRANSFER_NOT_FOUND",
+				Message: appErr.ErrScheduledTransferNotFound.Error(),
+			},
+		}
+
+	case appErr.ErrInvalidSchedule:
+		return ErrorMapping{
+			Status: http.StatusBadRequest,
+			Error: APIError{
+				Code:    "INVALID_SCHEDULE",
+				Message: appErr.ErrInvalidSchedule.Error(),
+			},
+		}
+
+	case appErr.ErrScheduledTransferClosed:
+		return ErrorMapping{
+			Status: http.StatusConflict,
+			Error: APIError{
+				Code:    "SCHEDULED_TRANSFER_CLOSED",
+				Message: appErr.ErrScheduledTransferClosed.Error(),
+			},
+		}
+
+	case appErr.ErrScheduledTransfer:
+		return ErrorMapping{
+			Status: http.StatusInternalServerError,
+			Error: APIError{
+				Code:    "SCHEDULED_TRANSFER_ERROR",
+				Message: appErr.ErrScheduledTransfer.Error(),
+			},
+		}
+
+	case appErr.ErrInvalidBulkRecipients:
+		return ErrorMapping{
+			Status: http.StatusUnprocessableEntity,
+			Error: APIError{
+				Code:    "INVALID_BULK_RECIPIENTS",
+				Message: appErr.ErrInvalidBulkRecipients.Error(),
+			},
+		}
+
+	case appErr.ErrTooManyBulkRecipients:
+		return ErrorMapping{
+			Status: http.StatusBadRequest,
+			Error: APIError{
+				Code:    "TOO_MANY_BULK_RECIPIENTS",
+				Message: appErr.ErrTooManyBulkRecipients.Error(),
+			},
+		}
+
+	case appErr.ErrTransferBatchNotFound:
+		return ErrorMapping{
+			Status: http.StatusNotFound,
+			Error: APIError{
+				Code:    "TRANSFER_BATCH_NOT_FOUND",
+				Message: appErr.ErrTransferBatchNotFound.Error(),
+			},
+		}
+
+	case appErr.ErrFetchingTransferBatch:
+		return ErrorMapping{
+			Status: http.StatusInternalServerError,
+			Error: APIError{
+				Code:    "TRANSFER_BATCH_FETCH_ERROR",
+				Message: appErr.ErrFetchingTransferBatch.Error(),
+			},
+		}
+
+	case appErr.ErrP2PRecipientNotFound:
+		return ErrorMapping{
+			Status: http.StatusNotFound,
+			Error: APIError{
+				Code:    "P2P_RECIPIENT_NOT_FOUND",
+				Message: appErr.ErrP2PRecipientNotFound.Error(),
+			},
+		}
+
+	case appErr.ErrSelfTransfer:
+		return ErrorMapping{
+			Status: http.StatusBadRequest,
+			Error: APIError{
+				Code:    "SELF_TRANSFER_NOT_ALLOWED",
+				Message: appErr.ErrSelfTransfer.Error(),
+			},
+		}
+
+	case appErr.ErrPaymentRequestNotFound:
+		return ErrorMapping{
+			Status: http.StatusNotFound,
+			Error: APIError{
+				Code:    "PAYMENT_REQUEST_NOT_FOUND",
+				Message: appErr.ErrPaymentRequestNotFound.Error(),
+			},
+		}
+
+	case appErr.ErrPaymentRequestClosed:
+		return ErrorMapping{
+			Status: http.StatusConflict,
+			Error: APIError{
+				Code:    "PAYMENT_REQUEST_CLOSED",
+				Message: appErr.ErrPaymentRequestClosed.Error(),
+			},
+		}
+
+	case appErr.ErrPaymentRequest:
+		return ErrorMapping{
+			Status: http.StatusInternalServerError,
+			Error: APIError{
+				Code:    "PAYMENT_REQUEST_ERROR",
+				Message: appErr.ErrPaymentRequest.Error(),
+			},
+		}
+
+	case appErr.ErrPerTransactionLimitExceeded:
+		return ErrorMapping{
+			Status: http.StatusForbidden,
+			Error: APIError{
+				Code:    "PER_TRANSACTION_LIMIT_EXCEEDED",
+				Message: appErr.ErrPerTransactionLimitExceeded.Error(),
+			},
+		}
+
+	case appErr.ErrDailyLimitExceeded:
+		return ErrorMapping{
+			Status: http.StatusForbidden,
+			Error: APIError{
+				Code:    "DAILY_LIMIT_EXCEEDED",
+				Message: appErr.ErrDailyLimitExceeded.Error(),
+			},
+		}
+
+	case appErr.ErrMonthlyLimitExceeded:
+		return ErrorMapping{
+			Status: http.StatusForbidden,
+			Error: APIError{
+				Code:    "MONTHLY_LIMIT_EXCEEDED",
+				Message: appErr.ErrMonthlyLimitExceeded.Error(),
+			},
+		}
+
+	case appErr.ErrActivationCapExceeded:
+		return ErrorMapping{
+			Status: http.StatusForbidden,
+			Error: APIError{
+				Code:    "ACTIVATION_CAP_EXCEEDED",
+				Message: appErr.ErrActivationCapExceeded.Error(),
+			},
+		}
+
+	case appErr.ErrCheckingLimits:
+		return ErrorMapping{
+			Status: http.StatusInternalServerError,
+			Error: APIError{
+				Code:    "LIMITS_CHECK_ERROR",
+				Message: appErr.ErrCheckingLimits.Error(),
+			},
+		}
+
+	case appErr.ErrTierUpgradePending:
+		return ErrorMapping{
+			Status: http.StatusConflict,
+			Error: APIError{
+				Code:    "TIER_UPGRADE_PENDING",
+				Message: appErr.ErrTierUpgradePending.Error(),
+			},
+		}
+
+	case appErr.ErrInvalidTierUpgrade:
+		return ErrorMapping{
+			Status: http.StatusUnprocessableEntity,
+			Error: APIError{
+				Code:    "INVALID_TIER_UPGRADE",
+				Message: appErr.ErrInvalidTierUpgrade.Error(),
+			},
+		}
+
+	case appErr.ErrMissingKYCDocument:
+		return ErrorMapping{
+			Status: http.StatusBadRequest,
+			Error: APIError{
+				Code:    "MISSING_KYC_DOCUMENT",
+				Message: appErr.ErrMissingKYCDocument.Error(),
+			},
+		}
+
+	case appErr.ErrInvalidKYCDocument:
+		return ErrorMapping{
+			Status: http.StatusBadRequest,
+			Error: APIError{
+				Code:    "INVALID_KYC_DOCUMENT",
+				Message: appErr.ErrInvalidKYCDocument.Error(),
+			},
+		}
+
+	case appErr.ErrNINRequired:
+		return ErrorMapping{
+			Status: http.StatusBadRequest,
+			Error: APIError{
+				Code:    "NIN_REQUIRED",
+				Message: appErr.ErrNINRequired.Error(),
+			},
+		}
+
+	case appErr.ErrTierUpgradeNotFound:
+		return ErrorMapping{
+			Status: http.StatusNotFound,
+			Error: APIError{
+				Code:    "TIER_UPGRADE_NOT_FOUND",
+				Message: appErr.ErrTierUpgradeNotFound.Error(),
+			},
+		}
+
+	case appErr.ErrTierUpgradeReviewed:
+		return ErrorMapping{
+			Status: http.StatusConflict,
+			Error: APIError{
+				Code:    "TIER_UPGRADE_REVIEWED",
+				Message: appErr.ErrTierUpgradeReviewed.Error(),
+			},
+		}
+
+	case appErr.ErrTierUpgrade:
+		return ErrorMapping{
+			Status: http.StatusInternalServerError,
+			Error: APIError{
+				Code:    "TIER_UPGRADE_ERROR",
+				Message: appErr.ErrTierUpgrade.Error(),
+			},
+		}
+
+	case appErr.ErrBudgetNotFound:
+		return ErrorMapping{
+			Status: http.StatusNotFound,
+			Error: APIError{
+				Code:    "BUDGET_NOT_FOUND",
+				Message: appErr.ErrBudgetNotFound.Error(),
+			},
+		}
+
+	case appErr.ErrInvalidBudgetCategory:
+		return ErrorMapping{
+			Status: http.StatusBadRequest,
+			Error: APIError{
+				Code:    "INVALID_BUDGET_CATEGORY",
+				Message: appErr.ErrInvalidBudgetCategory.Error(),
+			},
+		}
+
+	case appErr.ErrSavingBudget:
+		return ErrorMapping{
+			Status: http.StatusInternalServerError,
+			Error: APIError{
+				Code:    "SAVING_BUDGET_FAILED",
+				Message: appErr.ErrSavingBudget.Error(),
+			},
+		}
+
+	case appErr.ErrFetchingInsights:
+		return ErrorMapping{
+			Status: http.StatusInternalServerError,
+			Error: APIError{
+				Code:    "FETCHING_INSIGHTS_FAILED",
+				Message: appErr.ErrFetchingInsights.Error(),
+			},
+		}
+
+	case appErr.ErrReceiptUnavailable:
+		return ErrorMapping{
+			Status: http.StatusConflict,
+			Error: APIError{
+				Code:    "RECEIPT_UNAVAILABLE",
+				Message: appErr.ErrReceiptUnavailable.Error(),
+			},
+		}
+
+	case appErr.ErrGeneratingReceipt:
+		return ErrorMapping{
+			Status: http.StatusInternalServerError,
+			Error: APIError{
+				Code:    "GENERATING_RECEIPT_FAILED",
+				Message: appErr.ErrGeneratingReceipt.Error(),
+			},
+		}
+
+	case appErr.ErrDisputeNotFound:
+		return ErrorMapping{
+			Status: http.StatusNotFound,
+			Error: APIError{
+				Code:    "DISPUTE_NOT_FOUND",
+				Message: appErr.ErrDisputeNotFound.Error(),
+			},
+		}
+
+	case appErr.ErrDisputeAlreadyOpen:
+		return ErrorMapping{
+			Status: http.StatusConflict,
+			Error: APIError{
+				Code:    "DISPUTE_ALREADY_OPEN",
+				Message: appErr.ErrDisputeAlreadyOpen.Error(),
+			},
+		}
+
+	case appErr.ErrInvalidDisputeAttachment:
+		return ErrorMapping{
+			Status: http.StatusBadRequest,
+			Error: APIError{
+				Code:    "INVALID_DISPUTE_ATTACHMENT",
+				Message: appErr.ErrInvalidDisputeAttachment.Error(),
+			},
+		}
+
+	case appErr.ErrDisputeClosed:
+		return ErrorMapping{
+			Status: http.StatusConflict,
+			Error: APIError{
+				Code:    "DISPUTE_CLOSED",
+				Message: appErr.ErrDisputeClosed.Error(),
+			},
+		}
+
+	case appErr.ErrInvalidDisputeTransition:
+		return ErrorMapping{
+			Status: http.StatusConflict,
+			Error: APIError{
+				Code:    "INVALID_DISPUTE_TRANSITION",
+				Message: appErr.ErrInvalidDisputeTransition.Error(),
+			},
+		}
+
+	case appErr.ErrDispute:
+		return ErrorMapping{
+			Status: http.StatusInternalServerError,
+			Error: APIError{
+				Code:    "DISPUTE_FAILED",
+				Message: appErr.ErrDispute.Error(),
+			},
+		}
+
+	case appErr.ErrBeneficiaryNotFound:
+		return ErrorMapping{
+			Status: http.StatusNotFound,
+			Error: APIError{
+				Code:    "BENEFICIARY_NOT_FOUND",
+				Message: appErr.ErrBeneficiaryNotFound.Error(),
+			},
+		}
+
+	case appErr.ErrBeneficiaryExists:
+		return ErrorMapping{
+			Status: http.StatusConflict,
+			Error: APIError{
+				Code:    "BENEFICIARY_EXISTS",
+				Message: appErr.ErrBeneficiaryExists.Error(),
+			},
+		}
+
+	case appErr.ErrBeneficiaryNameMismatch:
+		return ErrorMapping{
+			Status: http.StatusUnprocessableEntity,
+			Error: APIError{
+				Code:    "BENEFICIARY_NAME_MISMATCH",
+				Message: appErr.ErrBeneficiaryNameMismatch.Error(),
+			},
+		}
+
+	case appErr.ErrUpdatingBeneficiary:
+		return ErrorMapping{
+			Status: http.StatusInternalServerError,
+			Error: APIError{
+				Code:    "UPDATING_BENEFICIARY_ERROR",
+				Message: appErr.ErrUpdatingBeneficiary.Error(),
+			},
+		}
+
+	case appErr.ErrWalletFrozen:
+		return ErrorMapping{
+			Status: http.StatusForbidden,
+			Error: APIError{
+				Code:    "WALLET_FROZEN",
+				Message: appErr.ErrWalletFrozen.Error(),
+			},
+		}
+
+	case appErr.ErrWalletPostNoDebit:
+		return ErrorMapping{
+			Status: http.StatusForbidden,
+			Error: APIError{
+				Code:    "WALLET_POST_NO_DEBIT",
+				Message: appErr.ErrWalletPostNoDebit.Error(),
+			},
+		}
+
+	case appErr.ErrWalletClosed:
+		return ErrorMapping{
+			Status: http.StatusForbidden,
+			Error: APIError{
+				Code:    "WALLET_CLOSED",
+				Message: appErr.ErrWalletClosed.Error(),
+			},
+		}
+
+	case appErr.ErrRecipientWalletUnavailable:
+		return ErrorMapping{
+			Status: http.StatusUnprocessableEntity,
+			Error: APIError{
+				Code:    "RECIPIENT_WALLET_UNAVAILABLE",
+				Message: appErr.ErrRecipientWalletUnavailable.Error(),
+			},
+		}
+
+	case appErr.ErrInvalidWalletStatusChange:
+		return ErrorMapping{
+			Status: http.StatusConflict,
+			Error: APIError{
+				Code:    "INVALID_WALLET_STATUS_CHANGE",
+				Message: appErr.ErrInvalidWalletStatusChange.Error(),
+			},
+		}
+
+	case appErr.ErrInvalidWalletStatusReason:
+		return ErrorMapping{
+			Status: http.StatusBadRequest,
+			Error: APIError{
+				Code:    "INVALID_WALLET_STATUS_REASON",
+				Message: appErr.ErrInvalidWalletStatusReason.Error(),
+			},
+		}
+
+	case appErr.ErrWalletBalanceNotZero:
+		return ErrorMapping{
+			Status: http.StatusConflict,
+			Error: APIError{
+				Code:    "WALLET_BALANCE_NOT_ZERO",
+				Message: appErr.ErrWalletBalanceNotZero.Error(),
+			},
+		}
+
+	case appErr.ErrClosureAccountMismatch:
+		return ErrorMapping{
+			Status: http.StatusUnprocessableEntity,
+			Error: APIError{
+				Code:    "CLOSURE_ACCOUNT_MISMATCH",
+				Message: appErr.ErrClosureAccountMismatch.Error(),
+			},
+		}
+
+	case appErr.ErrWalletStatus:
+		return ErrorMapping{
+			Status: http.StatusInternalServerError,
+			Error: APIError{
+				Code:    "WALLET_STATUS_ERROR",
+				Message: appErr.ErrWalletStatus.Error(),
+			},
+		}
+
+	case appErr.ErrSessionNotFound:
+		return ErrorMapping{
+			Status: http.StatusNotFound,
+			Error: APIError{
+				Code:    "SESSION_NOT_FOUND",
+				Message: appErr.ErrSessionNotFound.Error(),
+			},
+		}
+
+	case appErr.ErrDeviceNotFound:
+		return ErrorMapping{
+			Status: http.StatusNotFound,
+			Error: APIError{
+				Code:    "DEVICE_NOT_FOUND",
+				Message: appErr.ErrDeviceNotFound.Error(),
+			},
+		}
+
+	case appErr.ErrRevokingSession:
+		return ErrorMapping{
+			Status: http.StatusInternalServerError,
+			Error: APIError{
+				Code:    "REVOKE_SESSION_FAILED",
+				Message: appErr.ErrRevokingSession.Error(),
+			},
+		}
+
+	case appErr.ErrTOTPNotEnabled:
+		return ErrorMapping{
+			Status: http.StatusBadRequest,
+			Error: APIError{
+				Code:    "TOTP_NOT_ENABLED",
+				Message: appErr.ErrTOTPNotEnabled.Error(),
+			},
+		}
+
+	case appErr.ErrTOTPAlreadyEnabled:
+		return ErrorMapping{
+			Status: http.StatusConflict,
+			Error: APIError{
+				Code:    "TOTP_ALREADY_ENABLED",
+				Message: appErr.ErrTOTPAlreadyEnabled.Error(),
+			},
+		}
+
+	case appErr.ErrTOTPEnrollmentNotFound:
+		return ErrorMapping{
+			Status: http.StatusNotFound,
+			Error: APIError{
+				Code:    "TOTP_ENROLLMENT_NOT_FOUND",
+				Message: appErr.ErrTOTPEnrollmentNotFound.Error(),
+			},
+		}
+
+	case appErr.ErrInvalidTOTPCode:
+		return ErrorMapping{
+			Status: http.StatusUnauthorized,
+			Error: APIError{
+				Code:    "INVALID_TOTP_CODE",
+				Message: appErr.ErrInvalidTOTPCode.Error(),
+			},
+		}
+
+	case appErr.ErrSecondFactorRequired:
+		return ErrorMapping{
+			Status: http.StatusForbidden,
+			Error: APIError{
+				Code:    "SECOND_FACTOR_REQUIRED",
+				Message: appErr.ErrSecondFactorRequired.Error(),
+			},
+		}
+
 	case appErr.ErrGettingData:
 		return ErrorMapping{
 			Status: http.StatusBadGateway,
@@ -17,9 +17,14 @@ import (
 	"neat_mobile_app_backend/internal/modules/auth/verification"
 	"neat_mobile_app_backend/internal/modules/card"
 	"neat_mobile_app_backend/internal/modules/device"
+	"neat_mobile_app_backend/internal/modules/dispute"
+	"neat_mobile_app_backend/internal/modules/kyc"
+	"neat_mobile_app_backend/internal/modules/ledger"
+	"neat_mobile_app_backend/internal/modules/limits"
 	"neat_mobile_app_backend/internal/modules/loanproduct"
 	"neat_mobile_app_backend/internal/modules/neatsave"
 	"neat_mobile_app_backend/internal/modules/notification"
+	"neat_mobile_app_backend/internal/modules/reconciliation"
 	"neat_mobile_app_backend/internal/modules/reporting"
 	"neat_mobile_app_backend/internal/modules/transaction"
 	"neat_mobile_app_backend/internal/modules/vas"
@@ -29,6 +34,7 @@ import (
 	"neat_mobile_app_backend/providers/bvn/tendar"
 	cardprovider "neat_mobile_app_backend/providers/card"
 	"neat_mobile_app_backend/providers/email"
+	"neat_mobile_app_backend/providers/geoip"
 	"neat_mobile_app_backend/providers/jwt"
 	"neat_mobile_app_backend/providers/nin"
 	"neat_mobile_app_backend/providers/push"
@@ -78,8 +84,8 @@ func NewRouter(cfg config.Config) (*gin.Engine, func(), error) {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
 	})
 
-	if cfg.JWTSecret == "" {
-		return nil, nil, errors.New("jwt secret can't be empty")
+	if cfg.JWTSecret == "" && strings.TrimSpace(cfg.JWTKeyEncryptionKey) == "" {
+		return nil, nil, errors.New("either a jwt secret or a jwt key encryption key is required")
 	}
 
 	s3bucketConfig := s3bucket.BackblazeConfig{
@@ -114,6 +120,26 @@ func NewRouter(cfg config.Config) (*gin.Engine, func(), error) {
 	deviceService := device.NewService(*deviceRepo)
 
 	authRepo := auth.NewRespository(db)
+	if strings.TrimSpace(cfg.JWTKeyEncryptionKey) != "" {
+		rotateEvery := time.Duration(cfg.JWTKeyRotationDays) * 24 * time.Hour
+		if err := tokenSigner.EnableKeyRotation(context.Background(), authRepo, cfg.JWTKeyEncryptionKey, cfg.JWTSigningAlgorithm, rotateEvery); err != nil {
+			return nil, nil, fmt.Errorf("failed to enable jwt key rotation: %w", err)
+		}
+		if until := strings.TrimSpace(cfg.JWTHMACAcceptUntil); until != "" {
+			cutoff, err := time.Parse(time.RFC3339, until)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid JWT_HMAC_ACCEPT_UNTIL: %w", err)
+			}
+			tokenSigner.AcceptHMACUntil(cutoff)
+		}
+	} else {
+		log.Print("jwt key encryption key is not configured; tokens will be signed with the shared secret")
+	}
+
+	r.GET("/.well-known/jwks.json", func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, tokenSigner.JWKS())
+	})
 	verificationRepo := verification.NewVerification(db)
 	ninProvider := nin.NewNIN(cfg.PremblyAPIKey)
 	loginRateLimiter := middleware.NewLoginRateLimiter(middleware.LoginRateLimiterConfig{
@@ -136,19 +162,31 @@ func NewRouter(cfg config.Config) (*gin.Engine, func(), error) {
 	}
 
 	otpRepo := otp.NewRepository(db)
-	otpManager := otp.NewOTPManager(otpRepo, verificationRepo, transactor, smsSender, emailSender, cfg.Pepper, cfg.AppName)
-	otpHandler := otp.NewOTPHandler(otpManager)
+	otpService := otp.NewOTPService(otpRepo, verificationRepo, transactor, smsSender, emailSender, cfg.Pepper, cfg.AppName)
+	otpHandler := otp.NewOTPHandler(otpService)
 	otp.RegisterRoutes(apiV1, otpHandler)
 
 	cbaSyncSem := make(chan struct{}, 10)
 	cbaWalletUpdateSem := make(chan struct{}, 10)
-	authService := auth.NewService(authRepo, cbaClient, cbaClient, verificationRepo, transactor, deviceRepo, smsSender, cfg.Pepper, tokenSigner, bvnProvider, premblyProvider, ninProvider, providerSource, otpManager, walletRegistrationService, cfg.WalletPayloadSeedKey, deviceService, cbaSyncSem, cbaWalletUpdateSem, optimusProductID, cfg.ActivationCapKobo)
+	authService := auth.NewService(authRepo, cbaClient, cbaClient, verificationRepo, transactor, deviceRepo, smsSender, cfg.Pepper, tokenSigner, bvnProvider, premblyProvider, ninProvider, providerSource, otpService, walletRegistrationService, cfg.WalletPayloadSeedKey, deviceService, cbaSyncSem, cbaWalletUpdateSem, optimusProductID, cfg.ActivationCapKobo)
 	authHandler := auth.NewHandler(authService)
 	authGuard := middleware.AuthGuard(tokenSigner, authService)
 	deviceValidator := middleware.DeviceValidator(deviceService)
 	auth.RegisterRoutes(apiV1, authHandler, authGuard, deviceValidator, loginRateLimiter.Middleware())
 
-	authService.ConfigureOTPManager(otpManager)
+	authService.ConfigureOTPManager(otpService)
+	var geolocator auth.IPGeolocator
+	var geoLite *geoip.GeoLite
+	if path := strings.TrimSpace(cfg.GeoIPDatabasePath); path != "" {
+		if geoLite, err = geoip.NewGeoLite(path); err != nil {
+			return nil, nil, err
+		}
+		geolocator = geoLite
+	} else {
+		log.Print("geoip database is not configured; login risk will skip new-country and impossible-travel checks")
+	}
+	authService.ConfigureLoginRisk(geolocator, loginRateLimiter)
+	authService.ConfigureTOTP(otpService)
 
 	c := cron.New(cron.WithLocation(time.UTC))
 
@@ -202,13 +240,167 @@ func NewRouter(cfg config.Config) (*gin.Engine, func(), error) {
 		}
 	})
 
+	var keyRotationMu sync.Mutex
+	var keyRotationRunning bool
+
+	c.AddFunc("@every 5m", func() {
+		keyRotationMu.Lock()
+		if keyRotationRunning {
+			keyRotationMu.Unlock()
+			return
+		}
+		keyRotationRunning = true
+		keyRotationMu.Unlock()
+
+		defer func() {
+			keyRotationMu.Lock()
+			keyRotationRunning = false
+			keyRotationMu.Unlock()
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if err := tokenSigner.RotateKeys(ctx); err != nil {
+			log.Printf("jwt key rotation: %v", err)
+		}
+	})
+
+	expoSender := push.NewExpoClient(cfg.ExpoPushBaseURL, cfg.ExpoAccessToken)
+	notificationRepo := notification.NewRepository(db)
+	notificationService := notification.NewService(notificationRepo, expoSender, cfg.ExpoPushChannelID, deviceService)
+	authService.ConfigureNotifier(notificationService)
+
+	limitsService := limits.NewService(limits.NewRepository(db), cfg.TransferLimitAmount*100)
+
 	walletRepo := wallet.NewRepository(db)
 	walletPinVerifier := authchecker.New(walletRepo)
 	walletService := wallet.NewService(walletRepo, providusWalletService, walletPinVerifier, wallet.SettlementAccount{
 		AccountNumber: cfg.LoanRepaymentAccountNumber,
 		BankCode:      cfg.LoanRepaymentBankCode,
 		AccountName:   cfg.LoanRepaymentAccountName,
-	}, deviceService)
+	}, deviceService, notificationService, cfg.PayLinkBaseURL, limitsService)
+	walletService.ConfigureSecondFactor(otpService, cfg.HighValueTransferAmount*100)
+	walletService.ConfigureDepositMatching(cfg.DepositToleranceKobo)
+
+	var depositExpiryMu sync.Mutex
+	var depositExpiryRunning bool
+
+	c.AddFunc("@every 1m", func() {
+		depositExpiryMu.Lock()
+		if depositExpiryRunning {
+			depositExpiryMu.Unlock()
+			return
+		}
+		depositExpiryRunning = true
+		depositExpiryMu.Unlock()
+
+		defer func() {
+			depositExpiryMu.Lock()
+			depositExpiryRunning = false
+			depositExpiryMu.Unlock()
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		if err := walletService.ExpireStaleDeposits(ctx, 200); err != nil {
+			log.Printf("expected deposit expiry sweep: %v", err)
+		}
+	})
+
+	var bankDirectoryMu sync.Mutex
+	var bankDirectoryRunning bool
+
+	c.AddFunc("@every 6h", func() {
+		bankDirectoryMu.Lock()
+		if bankDirectoryRunning {
+			bankDirectoryMu.Unlock()
+			return
+		}
+		bankDirectoryRunning = true
+		bankDirectoryMu.Unlock()
+
+		defer func() {
+			bankDirectoryMu.Lock()
+			bankDirectoryRunning = false
+			bankDirectoryMu.Unlock()
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+
+		if err := walletService.RefreshBankDirectory(ctx); err != nil {
+			log.Printf("bank directory refresh: %v", err)
+		}
+		if err := walletService.PurgeAccountNameLookups(ctx); err != nil {
+			log.Printf("bank directory refresh: %v", err)
+		}
+	})
+
+	var scheduledTransferMu sync.Mutex
+	var scheduledTransferRunning bool
+
+	c.AddFunc("@every 1m", func() {
+		scheduledTransferMu.Lock()
+		if scheduledTransferRunning {
+			scheduledTransferMu.Unlock()
+			return
+		}
+		scheduledTransferRunning = true
+		scheduledTransferMu.Unlock()
+
+		defer func() {
+			scheduledTransferMu.Lock()
+			scheduledTransferRunning = false
+			scheduledTransferMu.Unlock()
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 4*time.Minute)
+		defer cancel()
+
+		summary, err := walletService.ProcessDueScheduledTransfers(ctx, 50)
+		if err != nil {
+			log.Printf("scheduled transfer sweep: %v", err)
+			return
+		}
+		if summary.Due > 0 {
+			log.Printf("scheduled transfer sweep: due=%d successful=%d processing=%d retrying=%d failed=%d",
+				summary.Due, summary.Successful, summary.Processing, summary.Retrying, summary.Failed)
+		}
+	})
+
+	var transferRequeryMu sync.Mutex
+	var transferRequeryRunning bool
+
+	c.AddFunc("@every 2m", func() {
+		transferRequeryMu.Lock()
+		if transferRequeryRunning {
+			transferRequeryMu.Unlock()
+			return
+		}
+		transferRequeryRunning = true
+		transferRequeryMu.Unlock()
+
+		defer func() {
+			transferRequeryMu.Lock()
+			transferRequeryRunning = false
+			transferRequeryMu.Unlock()
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
+		defer cancel()
+
+		olderThan := time.Duration(cfg.TransferRequeryDelayMinutes) * time.Minute
+		summary, err := walletService.ReconcilePendingTransfers(ctx, olderThan, 100)
+		if err != nil {
+			log.Printf("pending transfer reconciliation: %v", err)
+			return
+		}
+		if summary.Scanned > 0 {
+			log.Printf("pending transfer reconciliation: scanned=%d successful=%d failed=%d reversed=%d pending=%d",
+				summary.Scanned, summary.Successful, summary.Failed, summary.Reversed, summary.Pending)
+		}
+	})
 
 	loanRepo := loanproduct.NewRepository(db)
 	loanService := loanproduct.NewService(loanRepo, cbaClient, cbaClient, cbaClient, authchecker.New(loanRepo), walletService, deviceService)
@@ -217,17 +409,12 @@ func NewRouter(cfg config.Config) (*gin.Engine, func(), error) {
 	walletHandler := wallet.NewHandler(walletService)
 	wallet.RegisterRoutes(apiV1, walletHandler, authGuard, deviceValidator)
 
-	transactionRepo := transaction.NewRepository(db)
-	transactionService := transaction.NewServie(transactionRepo)
-	transactionHandler := transaction.NewHandler(transactionService)
-	transaction.RegisterRoutes(apiV1, transactionHandler, authGuard, deviceValidator)
-
 	xpressPayments, xpressErr := vasprovider.NewXpressPayments(cfg.XpressPublicKey, cfg.XpressPrivateKey, cfg.XpressBaseURL)
 	if xpressErr != nil {
 		log.Printf("xpress payments not configured: %v — VAS endpoints will be unavailable", xpressErr)
 	} else {
 		vasRepo := vas.NewRepository(db)
-		vasService := vas.NewService(vasRepo, xpressPayments, vasRepo, vasRepo, providusWalletService, authService)
+		vasService := vas.NewService(vasRepo, xpressPayments, vasRepo, vasRepo, providusWalletService, authService, walletService, limitsService)
 		vasHandler := vas.NewHandler(vasService)
 		vas.RegisterRoutes(apiV1, authGuard, deviceValidator, vasHandler)
 	}
@@ -238,17 +425,58 @@ func NewRouter(cfg config.Config) (*gin.Engine, func(), error) {
 	}
 	wallet.RegisterWebhookRoutes(webhooksGroup, walletHandler, middleware.ProvidusWebhookAuth(cfg.ProvidusWebhookSecret))
 
-	expoSender := push.NewExpoClient(cfg.ExpoPushBaseURL, cfg.ExpoAccessToken)
-	notificationRepo := notification.NewRepository(db)
-	notificationService := notification.NewService(notificationRepo, expoSender, cfg.ExpoPushChannelID, deviceService)
 	notificationHandler := notification.NewHandler(notificationService)
 	notification.RegisterRoutes(apiV1, notificationHandler, authGuard, deviceValidator)
 
 	accountRepo := account.NewRepository(db)
-	accountService := account.NewService(accountRepo, s3bucketClient, notificationService, cfg.PDFShiftAPIKey, deviceService, cfg.TransferLimitAmount)
+	accountService := account.NewService(accountRepo, s3bucketClient, notificationService, cfg.PDFShiftAPIKey, deviceService, limitsService)
 	accountHandler := account.NewHandler(accountService)
 	account.RegisterRoutes(apiV1, accountHandler, authGuard, deviceValidator)
 
+	transactionRepo := transaction.NewRepository(db)
+	transactionService := transaction.NewServie(transactionRepo, notificationService, accountService)
+	transactionHandler := transaction.NewHandler(transactionService)
+	transaction.RegisterRoutes(apiV1, transactionHandler, authGuard, deviceValidator)
+
+	var budgetAlertMu sync.Mutex
+	var budgetAlertRunning bool
+
+	c.AddFunc("@every 5m", func() {
+		budgetAlertMu.Lock()
+		if budgetAlertRunning {
+			budgetAlertMu.Unlock()
+			return
+		}
+		budgetAlertRunning = true
+		budgetAlertMu.Unlock()
+
+		defer func() {
+			budgetAlertMu.Lock()
+			budgetAlertRunning = false
+			budgetAlertMu.Unlock()
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+
+		sent, err := transactionService.ProcessBudgetAlerts(ctx)
+		if err != nil {
+			log.Printf("budget alert sweep: %v", err)
+			return
+		}
+		if sent > 0 {
+			log.Printf("budget alert sweep: sent=%d", sent)
+		}
+	})
+
+	kycService := kyc.NewService(kyc.NewRepository(db), s3bucketClient, notificationService, ninProvider, providusWalletService)
+	kycHandler := kyc.NewHandler(kycService)
+	kyc.RegisterRoutes(apiV1, kycHandler, authGuard, deviceValidator)
+
+	disputeService := dispute.NewService(dispute.NewRepository(db), s3bucketClient, notificationService, walletService)
+	disputeHandler := dispute.NewHandler(disputeService)
+	dispute.RegisterRoutes(apiV1, disputeHandler, authGuard, deviceValidator)
+
 	const statementWorkerCount = 4
 
 	statementJobQueue := make(chan account.AccountReportJob, statementWorkerCount)
@@ -306,6 +534,9 @@ func NewRouter(cfg config.Config) (*gin.Engine, func(), error) {
 		<-c.Stop().Done()
 		close(statementJobQueue)
 		statementWorkerWG.Wait()
+		if geoLite != nil {
+			_ = geoLite.Close()
+		}
 	}
 
 	internalLoanRepo := loanproduct.NewInternalRepository(db)
@@ -318,11 +549,56 @@ func NewRouter(cfg config.Config) (*gin.Engine, func(), error) {
 	loanproduct.RegisterInternalRoutes(internalV1, internalLoanHandler, internalAuth)
 
 	reportingRepo := reporting.NewRepository(db)
-	reportingService := reporting.NewService(reportingRepo)
+	reportingService := reporting.NewService(reportingRepo, providusWalletService)
 	reportingHandler := reporting.NewHandler(reportingService)
 	reporting.RegisterInternalRoutes(internalV1, reportingHandler, internalAuth)
+
+	var balanceSyncMu sync.Mutex
+	var balanceSyncRunning bool
+
+	c.AddFunc("@every 1h", func() {
+		balanceSyncMu.Lock()
+		if balanceSyncRunning {
+			balanceSyncMu.Unlock()
+			return
+		}
+		balanceSyncRunning = true
+		balanceSyncMu.Unlock()
+
+		defer func() {
+			balanceSyncMu.Lock()
+			balanceSyncRunning = false
+			balanceSyncMu.Unlock()
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Minute)
+		defer cancel()
+
+		summary, err := reportingService.SyncProviderBalances(ctx)
+		if err != nil {
+			log.Printf("balance sync: %v", err)
+		}
+		if summary != nil && summary.WalletsDrifted > 0 {
+			log.Printf("balance sync: run=%s checked=%d drifted=%d flagged=%d skipped=%d failed=%d",
+				summary.RunID, summary.WalletsChecked, summary.WalletsDrifted, summary.WalletsFlagged, summary.WalletsSkipped, summary.WalletsFailed)
+		}
+	})
 	notification.RegisterInternalRoutes(internalV1, notificationHandler, internalAuth)
 
+	ledgerRepo := ledger.NewRepository(db)
+	ledgerService := ledger.NewService(ledgerRepo)
+	ledgerHandler := ledger.NewHandler(ledgerService)
+	ledger.RegisterInternalRoutes(internalV1, ledgerHandler, internalAuth)
+
+	wallet.RegisterInternalRoutes(internalV1, walletHandler, internalAuth)
+	kyc.RegisterInternalRoutes(internalV1, kycHandler, internalAuth)
+	dispute.RegisterInternalRoutes(internalV1, disputeHandler, internalAuth)
+
+	reconciliationRepo := reconciliation.NewRepository(db)
+	reconciliationService := reconciliation.NewService(reconciliationRepo)
+	reconciliationHandler := reconciliation.NewHandler(reconciliationService)
+	reconciliation.RegisterInternalRoutes(internalV1, reconciliationHandler, internalAuth)
+
 	go func() {
 		c.Start()
 	}()
@@ -335,7 +611,7 @@ func NewRouter(cfg config.Config) (*gin.Engine, func(), error) {
 
 	optimusCardProvider := cardprovider.NewOptimus(cfg.OptimusWalletBaseURL, cfg.OptimusSecretKey, nil)
 	cardRepo := card.NewRepository(db)
-	cardService := card.NewService(cardRepo, deviceService, optimusCardProvider)
+	cardService := card.NewService(cardRepo, deviceService, optimusCardProvider, limitsService, cfg.CardIssuanceFee)
 	cardHandler := card.NewHandler(cardService)
 	card.RegisterRoutes(apiV1, authGuard, cardHandler)
 
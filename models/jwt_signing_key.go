@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// JWTSigningKey is an asymmetric key used to sign access and refresh tokens.
+// The private half is stored encrypted; the public half is published through
+// the JWKS endpoint until ExpiresAt.
+type JWTSigningKey struct {
+	KID           string     `gorm:"column:kid;type:text;primaryKey"`
+	Algorithm     string     `gorm:"column:algorithm;type:text;not null"`
+	PrivateKeyEnc string     `gorm:"column:private_key_enc;type:text;not null"`
+	PublicKeyPEM  string     `gorm:"column:public_key_pem;type:text;not null"`
+	CreatedAt     time.Time  `gorm:"column:created_at;not null;autoCreateTime"`
+	ActivatesAt   time.Time  `gorm:"column:activates_at;not null;index"`
+	ExpiresAt     *time.Time `gorm:"column:expires_at;index"`
+}
+
+func (JWTSigningKey) TableName() string {
+	return "wallet_jwt_signing_keys"
+}
@@ -12,6 +12,10 @@ type RefreshToken struct {
 	LastUsedAt    *time.Time `gorm:"column:last_used_at"`
 	RevokedAt     *time.Time `gorm:"column:revoked_at;index"`
 	ReplacedByJTI *string    `gorm:"column:replaced_by_jti;type:text"`
+	// FamilyID is the JTI of the token that started the rotation chain; every
+	// rotation keeps it and records its direct ancestor in ParentJTI.
+	FamilyID  string  `gorm:"column:family_id;type:text;index"`
+	ParentJTI *string `gorm:"column:parent_jti;type:text"`
 
 	Session AuthSession `gorm:"foreignKey:SessionID;references:SID;constraint:OnDelete:CASCADE"`
 }
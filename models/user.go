@@ -22,6 +22,7 @@ type User struct {
 	Email                        *string         `gorm:"column:email;unique"`
 	Phone                        string          `gorm:"column:phone;unique;index;not null"`
 	PasswordHash                 string          `gorm:"column:password_hash"`
+	PasswordChangedAt            *time.Time      `gorm:"column:password_changed_at;type:timestamptz"`
 	PinHash                      string          `gorm:"column:pin_hash;not null"`
 	FailedTransactionPinAttempts int             `gorm:"column:failed_transaction_pin_attempts;not null;default:0"`
 	TransactionPinLockedUntil    *time.Time      `gorm:"column:transaction_pin_locked_until"`
@@ -58,3 +58,17 @@ type ProvidusWalletCreditResponse struct {
 	Reference string `json:"reference"`
 	Amount    int64  `json:"amount"`
 }
+
+type ProvidusTransferRequeryResponse struct {
+	Status   bool   `json:"status"`
+	Message  string `json:"message"`
+	Transfer struct {
+		Status               string  `json:"status"`
+		Amount               float64 `json:"amount"`
+		Charges              float64 `json:"charges"`
+		Vat                  float64 `json:"vat"`
+		Reference            string  `json:"reference"`
+		TransactionReference string  `json:"transactionReference"`
+		SessionID            string  `json:"sessionId"`
+	} `json:"transfer"`
+}
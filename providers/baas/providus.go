@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	appErr "neat_mobile_app_backend/internal/errors"
 	"neat_mobile_app_backend/internal/modules/auth"
 	"neat_mobile_app_backend/internal/modules/wallet"
 	"net/http"
@@ -460,6 +461,9 @@ func (p *Providus) InitiateTransfer(ctx context.Context, providusCustomerID stri
 		"accountName":   transferInfo.AccountName,
 		"metadata":      transferInfo.Metadata,
 	}
+	if ref := strings.TrimSpace(transferInfo.Reference); ref != "" {
+		payload["reference"] = ref
+	}
 
 	body, err := json.Marshal(payload)
 	if err != nil {
@@ -477,10 +481,16 @@ func (p *Providus) InitiateTransfer(ctx context.Context, providusCustomerID stri
 
 	resp, err := p.Client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("providus transfer request failed: %w", err)
+		// the request may have reached Providus; only a requery can tell
+		return nil, fmt.Errorf("%w: providus transfer request failed: %v", appErr.ErrTransferAmbiguous, err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode >= 500 {
+		log.Printf("providus: transfer server error %d — outcome unknown", resp.StatusCode)
+		return nil, fmt.Errorf("%w: providus transfer failed with status: %d", appErr.ErrTransferAmbiguous, resp.StatusCode)
+	}
+
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
 		if len(respBody) == 0 {
@@ -497,6 +507,71 @@ func (p *Providus) InitiateTransfer(ctx context.Context, providusCustomerID stri
 	return &result, nil
 }
 
+// RequeryTransfer asks Providus for the final state of a transfer we initiated
+// with the given reference.
+func (p *Providus) RequeryTransfer(ctx context.Context, reference string) (*wallet.TransferRequeryResult, error) {
+	if strings.TrimSpace(p.APIKey) == "" || strings.TrimSpace(p.BaseURL) == "" {
+		return nil, errors.New("providus service not configured")
+	}
+
+	endpoint := p.BaseURL + "/transfer/requery?reference=" + url.QueryEscape(strings.TrimSpace(reference))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.APIKey)
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("providus transfer requery failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return &wallet.TransferRequeryResult{Status: wallet.ProviderTransferStatusNotFound}, nil
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		if len(respBody) == 0 {
+			return nil, fmt.Errorf("providus transfer requery failed with status: %d", resp.StatusCode)
+		}
+		return nil, fmt.Errorf("providus transfer requery failed: %s", extractErrorMessage(respBody))
+	}
+
+	var result ProvidusTransferRequeryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode providus transfer requery response: %w", err)
+	}
+
+	return &wallet.TransferRequeryResult{
+		Status:               mapProvidusTransferStatus(result.Transfer.Status),
+		Amount:               result.Transfer.Amount,
+		Charges:              result.Transfer.Charges,
+		Vat:                  result.Transfer.Vat,
+		TransactionReference: result.Transfer.TransactionReference,
+		SessionID:            result.Transfer.SessionID,
+		Message:              result.Message,
+	}, nil
+}
+
+func mapProvidusTransferStatus(status string) wallet.ProviderTransferStatus {
+	switch strings.ToUpper(strings.TrimSpace(status)) {
+	case "SUCCESS", "SUCCESSFUL", "COMPLETED":
+		return wallet.ProviderTransferStatusSuccessful
+	case "FAILED", "DECLINED":
+		return wallet.ProviderTransferStatusFailed
+	case "REVERSED":
+		return wallet.ProviderTransferStatusReversed
+	default:
+		return wallet.ProviderTransferStatusPending
+	}
+}
+
 func (p *Providus) InitiateBulkTransfer(ctx context.Context, info []wallet.BulkTransferRecipientInfo) (*wallet.ProvidusBatchTransferResponse, error) {
 	if strings.TrimSpace(p.APIKey) == "" || strings.TrimSpace(p.BaseURL) == "" {
 		return nil, errors.New("providus service not configured")
@@ -510,6 +585,7 @@ func (p *Providus) InitiateBulkTransfer(ctx context.Context, info []wallet.BulkT
 		Narration     *string        `json:"narration,omitempty"`
 		AccountNumber string         `json:"accountNumber"`
 		AccountName   *string        `json:"accountName,omitempty"`
+		Reference     string         `json:"reference,omitempty"`
 		Metadata      map[string]any `json:"metadata,omitempty"`
 	}
 
@@ -521,6 +597,7 @@ func (p *Providus) InitiateBulkTransfer(ctx context.Context, info []wallet.BulkT
 			Narration:     trfReq.Narration,
 			AccountNumber: trfReq.AccountNumber,
 			AccountName:   trfReq.AccountName,
+			Reference:     trfReq.Reference,
 			Metadata:      trfReq.Metadata,
 		})
 	}
@@ -541,10 +618,13 @@ func (p *Providus) InitiateBulkTransfer(ctx context.Context, info []wallet.BulkT
 
 	resp, err := p.Client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("providus bulk transfer request failed: %w", err)
+		return nil, fmt.Errorf("%w: providus bulk transfer request failed: %v", appErr.ErrTransferAmbiguous, err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode >= 500 {
+		return nil, fmt.Errorf("%w: providus bulk transfer failed with status: %d", appErr.ErrTransferAmbiguous, resp.StatusCode)
+	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
 		if len(respBody) == 0 {
@@ -596,7 +676,7 @@ func (p *Providus) DebitCustomer(ctx context.Context, amount int64, customerID,
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		log.Printf("providus: request failed with status code: %s", resp.StatusCode)
+		log.Printf("providus: request failed with status code: %d", resp.StatusCode)
 		return nil, err
 	}
 
@@ -644,7 +724,7 @@ func (p *Providus) CreditCustomer(ctx context.Context, amount int64, referenceID
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		log.Printf("providus: request failed with status code: %s", resp.StatusCode)
+		log.Printf("providus: request failed with status code: %d", resp.StatusCode)
 		return nil, err
 	}
 
@@ -656,3 +736,80 @@ func (p *Providus) CreditCustomer(ctx context.Context, amount int64, referenceID
 
 	return &result, nil
 }
+
+// DebitWallet takes amount kobo out of a customer's wallet. In-app transfers
+// use it with CreditWallet to move money between two customers' wallets.
+func (p *Providus) DebitWallet(ctx context.Context, customerID string, amount int64, reference string) error {
+	return p.walletAction(ctx, "/wallet/debit", customerID, amount, reference)
+}
+
+// CreditWallet pays amount kobo into a customer's wallet.
+func (p *Providus) CreditWallet(ctx context.Context, customerID string, amount int64, reference string) error {
+	return p.walletAction(ctx, "/wallet/credit", customerID, amount, reference)
+}
+
+// UpgradeWalletTier raises a customer's wallet to tier, written in the
+// provider's own label format ("TIER_2").
+func (p *Providus) UpgradeWalletTier(ctx context.Context, customerID, tier string) error {
+	return p.postWalletRequest(ctx, "/wallet/upgrade", customerID, map[string]any{
+		"customerId": customerID,
+		"tier":       tier,
+	})
+}
+
+func (p *Providus) walletAction(ctx context.Context, path, customerID string, amount int64, reference string) error {
+	return p.postWalletRequest(ctx, path, reference, map[string]any{
+		"amount":     float64(amount) / 100,
+		"reference":  reference,
+		"customerId": customerID,
+	})
+}
+
+// postWalletRequest sends a wallet operation and checks the provider's status
+// flag. reference only labels errors.
+func (p *Providus) postWalletRequest(ctx context.Context, path, reference string, payload map[string]any) error {
+	if strings.TrimSpace(p.APIKey) == "" || strings.TrimSpace(p.BaseURL) == "" {
+		return errors.New("providus service not configured")
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.BaseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.APIKey)
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: providus wallet request %s failed: %v", appErr.ErrTransferAmbiguous, reference, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("%w: providus wallet request %s failed with status: %d", appErr.ErrTransferAmbiguous, reference, resp.StatusCode)
+	}
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("providus wallet request %s failed: %s", reference, extractErrorMessage(respBody))
+	}
+
+	var result struct {
+		Status  bool   `json:"status"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return fmt.Errorf("failed to decode providus wallet response: %w", err)
+	}
+	if !result.Status {
+		return fmt.Errorf("providus wallet request %s was not successful: %s", reference, result.Message)
+	}
+	return nil
+}
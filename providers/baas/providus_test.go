@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"neat_mobile_app_backend/internal/modules/auth"
+	"neat_mobile_app_backend/internal/modules/wallet"
 )
 
 func TestLookupWalletByCustomerID_Success(t *testing.T) {
@@ -203,3 +204,91 @@ func TestSeedWalletPayload_SecretKeyChange(t *testing.T) {
 		t.Fatal("expected seeded BVN to be 11 digits")
 	}
 }
+
+func TestRequeryTransfer_Reversed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/transfer/requery" {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("reference"); got != "ref-123" {
+			t.Fatalf("unexpected reference query: %q", got)
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"status":  true,
+			"message": "ok",
+			"transfer": map[string]any{
+				"status":               "REVERSED",
+				"amount":               1500,
+				"charges":              10,
+				"vat":                  0.75,
+				"transactionReference": "prv-1",
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := NewProvidus("secret-key", server.URL)
+	result, err := client.RequeryTransfer(context.Background(), "ref-123")
+	if err != nil {
+		t.Fatalf("RequeryTransfer returned error: %v", err)
+	}
+	if result.Status != wallet.ProviderTransferStatusReversed {
+		t.Fatalf("expected reversed status, got %q", result.Status)
+	}
+	if result.TransactionReference != "prv-1" || result.Vat != 0.75 {
+		t.Fatalf("unexpected requery result: %+v", result)
+	}
+}
+
+func TestRequeryTransfer_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewProvidus("secret-key", server.URL)
+	result, err := client.RequeryTransfer(context.Background(), "missing")
+	if err != nil {
+		t.Fatalf("RequeryTransfer returned error: %v", err)
+	}
+	if result.Status != wallet.ProviderTransferStatusNotFound {
+		t.Fatalf("expected not_found status, got %q", result.Status)
+	}
+}
+
+func TestUpgradeWalletTier(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/wallet/upgrade" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		if body["customerId"] != "customer-123" || body["tier"] != "TIER_2" {
+			t.Fatalf("unexpected body: %v", body)
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{"status": true, "message": "ok"})
+	}))
+	defer server.Close()
+
+	client := NewProvidus("secret-key", server.URL)
+	if err := client.UpgradeWalletTier(context.Background(), "customer-123", "TIER_2"); err != nil {
+		t.Fatalf("UpgradeWalletTier returned error: %v", err)
+	}
+}
+
+func TestUpgradeWalletTier_Refused(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"status": false, "message": "BVN required for tier 2"})
+	}))
+	defer server.Close()
+
+	client := NewProvidus("secret-key", server.URL)
+	err := client.UpgradeWalletTier(context.Background(), "customer-123", "TIER_2")
+	if err == nil || !strings.Contains(err.Error(), "BVN required") {
+		t.Fatalf("expected the provider's refusal, got %v", err)
+	}
+}
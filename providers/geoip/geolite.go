@@ -0,0 +1,53 @@
+package geoip
+
+import (
+	"context"
+	"fmt"
+	"neat_mobile_app_backend/internal/modules/auth"
+	"net"
+
+	"github.com/oschwald/geoip2-golang"
+)
+
+// GeoLite looks IP addresses up in a MaxMind GeoLite2 or GeoIP2 City database
+// on disk, so login risk scoring never waits on a remote service.
+type GeoLite struct {
+	reader *geoip2.Reader
+}
+
+// NewGeoLite opens the .mmdb database at path. The whole file is loaded into
+// memory; replace the file and restart to pick up a newer edition.
+func NewGeoLite(path string) (*GeoLite, error) {
+	reader, err := geoip2.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open geoip database: %w", err)
+	}
+	return &GeoLite{reader: reader}, nil
+}
+
+// Locate returns the country and coordinates for ip, or nil when the database
+// has no location for it, as with private and reserved addresses.
+func (g *GeoLite) Locate(_ context.Context, ip string) (*auth.GeoLocation, error) {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return nil, fmt.Errorf("invalid ip address %q", ip)
+	}
+
+	record, err := g.reader.City(addr)
+	if err != nil {
+		return nil, err
+	}
+	if record.Country.IsoCode == "" && record.Location.Latitude == 0 && record.Location.Longitude == 0 {
+		return nil, nil
+	}
+
+	return &auth.GeoLocation{
+		Country:   record.Country.IsoCode,
+		Latitude:  record.Location.Latitude,
+		Longitude: record.Location.Longitude,
+	}, nil
+}
+
+func (g *GeoLite) Close() error {
+	return g.reader.Close()
+}
@@ -0,0 +1,126 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	jwksRefreshInterval = 5 * time.Minute
+	jwksFetchTimeout    = 10 * time.Second
+	jwksMaxBodyBytes    = 1 << 20
+)
+
+// JWKSVerifier checks access tokens against the public keys served by the
+// API's /.well-known/jwks.json, so a service can authenticate users without
+// holding any signing secret. Tokens signed with the legacy HMAC secret are
+// rejected.
+type JWKSVerifier struct {
+	url    string
+	client *http.Client
+
+	mu        sync.RWMutex
+	keys      map[string]verifyKey
+	fetchedAt time.Time
+}
+
+func NewJWKSVerifier(url string) *JWKSVerifier {
+	return &JWKSVerifier{
+		url:    url,
+		client: &http.Client{Timeout: jwksFetchTimeout},
+		keys:   map[string]verifyKey{},
+	}
+}
+
+// Refresh fetches the key set. Keys that fail to parse are skipped.
+func (v *JWKSVerifier) Refresh(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("jwks fetch returned status %d", resp.StatusCode)
+	}
+
+	var set JWKS
+	if err := json.NewDecoder(io.LimitReader(resp.Body, jwksMaxBodyBytes)).Decode(&set); err != nil {
+		return fmt.Errorf("decode jwks: %w", err)
+	}
+
+	keys := make(map[string]verifyKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		key, err := jwk.verifyKey()
+		if err != nil {
+			log.Printf("jwks verifier: skipping key: %v", err)
+			continue
+		}
+		keys[key.kid] = key
+	}
+
+	v.mu.Lock()
+	v.keys = keys
+	v.fetchedAt = time.Now()
+	v.mu.Unlock()
+	return nil
+}
+
+func (v *JWKSVerifier) lookupKey(kid string) (verifyKey, bool) {
+	v.mu.RLock()
+	key, ok := v.keys[kid]
+	age := time.Since(v.fetchedAt)
+	v.mu.RUnlock()
+
+	// Refetch when the set is stale, or sooner when the kid is new to us.
+	if ok && age < jwksRefreshInterval {
+		return key, true
+	}
+	if !ok && age < keyReloadMinInterval {
+		return verifyKey{}, false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), jwksFetchTimeout)
+	defer cancel()
+	if err := v.Refresh(ctx); err != nil {
+		log.Printf("jwks verifier: refresh failed: %v", err)
+		// Keep serving the last known keys while the API is unreachable.
+		return key, ok
+	}
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	key, ok = v.keys[kid]
+	return key, ok
+}
+
+func (v *JWKSVerifier) parse(tokenString string) (jwt.MapClaims, error) {
+	methods := []string{jwt.SigningMethodES256.Alg(), jwt.SigningMethodRS256.Alg()}
+	return parseClaims(tokenString, TokenTypeAccess, methods, keyfuncFor(v.lookupKey))
+}
+
+func (v *JWKSVerifier) ValidAccessToken(tokenString string) bool {
+	_, err := v.parse(tokenString)
+	return err == nil
+}
+
+func (v *JWKSVerifier) ExtractAccessTokenIdentifiers(tokenString string) (sub string, sid string, err error) {
+	claims, err := v.parse(tokenString)
+	if err != nil {
+		return "", "", err
+	}
+	return accessTokenIdentifiers(claims)
+}
@@ -0,0 +1,297 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"neat_mobile_app_backend/models"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	AlgorithmES256 = "ES256"
+	AlgorithmRS256 = "RS256"
+
+	rsaKeyBits = 2048
+)
+
+// verifyKey is the public half of a signing key, enough to check a token.
+type verifyKey struct {
+	kid       string
+	algorithm string
+	public    crypto.PublicKey
+}
+
+// signingKey is a key this process can sign with.
+type signingKey struct {
+	verifyKey
+	private     crypto.Signer
+	activatesAt time.Time
+	expiresAt   *time.Time
+}
+
+func signingMethod(algorithm string) (jwt.SigningMethod, error) {
+	switch algorithm {
+	case AlgorithmES256:
+		return jwt.SigningMethodES256, nil
+	case AlgorithmRS256:
+		return jwt.SigningMethodRS256, nil
+	default:
+		return nil, fmt.Errorf("unsupported jwt signing algorithm %q", algorithm)
+	}
+}
+
+func generateSigningKey(algorithm string, activatesAt time.Time) (*signingKey, error) {
+	var private crypto.Signer
+	var err error
+	switch algorithm {
+	case AlgorithmES256:
+		private, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case AlgorithmRS256:
+		private, err = rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	default:
+		_, err = signingMethod(algorithm)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &signingKey{
+		verifyKey: verifyKey{
+			kid:       uuid.NewString(),
+			algorithm: algorithm,
+			public:    private.Public(),
+		},
+		private:     private,
+		activatesAt: activatesAt,
+	}, nil
+}
+
+// toModel seals the private key with kek for storage.
+func (k *signingKey) toModel(kek []byte) (*models.JWTSigningKey, error) {
+	privateDER, err := x509.MarshalPKCS8PrivateKey(k.private)
+	if err != nil {
+		return nil, err
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(k.public)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := sealPrivateKey(kek, privateDER)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.JWTSigningKey{
+		KID:           k.kid,
+		Algorithm:     k.algorithm,
+		PrivateKeyEnc: sealed,
+		PublicKeyPEM:  string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})),
+		ActivatesAt:   k.activatesAt,
+		ExpiresAt:     k.expiresAt,
+	}, nil
+}
+
+func signingKeyFromModel(row models.JWTSigningKey, kek []byte) (*signingKey, error) {
+	if _, err := signingMethod(row.Algorithm); err != nil {
+		return nil, err
+	}
+
+	privateDER, err := openPrivateKey(kek, row.PrivateKeyEnc)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt jwt key %s: %w", row.KID, err)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(privateDER)
+	if err != nil {
+		return nil, fmt.Errorf("parse jwt key %s: %w", row.KID, err)
+	}
+
+	private, ok := parsed.(crypto.Signer)
+	if !ok || !matchesAlgorithm(row.Algorithm, private.Public()) {
+		return nil, fmt.Errorf("jwt key %s does not match algorithm %s", row.KID, row.Algorithm)
+	}
+
+	return &signingKey{
+		verifyKey: verifyKey{
+			kid:       row.KID,
+			algorithm: row.Algorithm,
+			public:    private.Public(),
+		},
+		private:     private,
+		activatesAt: row.ActivatesAt,
+		expiresAt:   row.ExpiresAt,
+	}, nil
+}
+
+func matchesAlgorithm(algorithm string, public crypto.PublicKey) bool {
+	switch key := public.(type) {
+	case *ecdsa.PublicKey:
+		return algorithm == AlgorithmES256 && key.Curve == elliptic.P256()
+	case *rsa.PublicKey:
+		return algorithm == AlgorithmRS256
+	default:
+		return false
+	}
+}
+
+// keyEncryptionKey stretches the configured secret into an AES-256 key.
+func keyEncryptionKey(secret string) []byte {
+	sum := sha256.Sum256([]byte(secret))
+	return sum[:]
+}
+
+func sealPrivateKey(kek, plaintext []byte) (string, error) {
+	gcm, err := newGCM(kek)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, plaintext, nil)), nil
+}
+
+func openPrivateKey(kek []byte, sealed string) ([]byte, error) {
+	raw, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(kek)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) < gcm.NonceSize() {
+		return nil, errors.New("sealed key is too short")
+	}
+	return gcm.Open(nil, raw[:gcm.NonceSize()], raw[gcm.NonceSize():], nil)
+}
+
+func newGCM(kek []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// JWK is a public key in RFC 7517 form.
+type JWK struct {
+	KTY string `json:"kty"`
+	KID string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+func (k verifyKey) jwk() (JWK, error) {
+	out := JWK{KID: k.kid, Use: "sig", Alg: k.algorithm}
+	switch key := k.public.(type) {
+	case *ecdsa.PublicKey:
+		point, err := key.Bytes()
+		if err != nil {
+			return JWK{}, err
+		}
+		// Uncompressed P-256 point: 0x04 || X(32) || Y(32).
+		out.KTY = "EC"
+		out.Crv = "P-256"
+		out.X = base64.RawURLEncoding.EncodeToString(point[1:33])
+		out.Y = base64.RawURLEncoding.EncodeToString(point[33:])
+	case *rsa.PublicKey:
+		out.KTY = "RSA"
+		out.N = base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+		out.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	default:
+		return JWK{}, fmt.Errorf("unsupported public key type %T", k.public)
+	}
+	return out, nil
+}
+
+func (k JWK) verifyKey() (verifyKey, error) {
+	if k.KID == "" {
+		return verifyKey{}, errors.New("jwk is missing kid")
+	}
+	if k.Use != "" && k.Use != "sig" {
+		return verifyKey{}, fmt.Errorf("jwk %s is not a signing key", k.KID)
+	}
+
+	var public crypto.PublicKey
+	switch k.KTY {
+	case "EC":
+		if k.Crv != "P-256" {
+			return verifyKey{}, fmt.Errorf("jwk %s uses unsupported curve %q", k.KID, k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != 32 {
+			return verifyKey{}, fmt.Errorf("jwk %s has an invalid x coordinate", k.KID)
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil || len(y) != 32 {
+			return verifyKey{}, fmt.Errorf("jwk %s has an invalid y coordinate", k.KID)
+		}
+		point := append(append([]byte{4}, x...), y...)
+		key, err := ecdsa.ParseUncompressedPublicKey(elliptic.P256(), point)
+		if err != nil {
+			return verifyKey{}, fmt.Errorf("jwk %s: %w", k.KID, err)
+		}
+		public = key
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil || len(n) == 0 {
+			return verifyKey{}, fmt.Errorf("jwk %s has an invalid modulus", k.KID)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return verifyKey{}, fmt.Errorf("jwk %s has an invalid exponent", k.KID)
+		}
+		public = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	default:
+		return verifyKey{}, fmt.Errorf("jwk %s has unsupported key type %q", k.KID, k.KTY)
+	}
+
+	if !matchesAlgorithm(k.Alg, public) {
+		return verifyKey{}, fmt.Errorf("jwk %s does not match algorithm %q", k.KID, k.Alg)
+	}
+	return verifyKey{kid: k.KID, algorithm: k.Alg, public: public}, nil
+}
+
+// keyfuncFor resolves the verification key named by a token's kid header,
+// refusing tokens whose alg doesn't match the key it names.
+func keyfuncFor(lookup func(kid string) (verifyKey, bool)) jwt.Keyfunc {
+	return func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		if kid == "" {
+			return nil, errors.New("missing kid")
+		}
+		key, ok := lookup(kid)
+		if !ok {
+			return nil, errors.New("unknown kid")
+		}
+		if t.Method.Alg() != key.algorithm {
+			return nil, errors.New("unexpected signing method")
+		}
+		return key.public, nil
+	}
+}
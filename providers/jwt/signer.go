@@ -1,8 +1,13 @@
 package jwt
 
 import (
+	"context"
 	"errors"
+	"log"
 	"neat_mobile_app_backend/internal/modules/auth"
+	"neat_mobile_app_backend/models"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -14,23 +19,255 @@ const (
 	TokenTypeRefresh auth.TokenType = "refresh_token"
 )
 
-// type RefreshTokenClaims struct {
-// 	SessionID string `json:"sid"`
-// 	JTI       string `json:"typ"`
-// }
+const (
+	accessTokenTTL  = 15 * time.Minute
+	refreshTokenTTL = 30 * 24 * time.Hour
+
+	// keyPublishLead is how long a new key sits in the JWKS before it signs
+	// anything, so every API instance and JWKS cache learns it first.
+	keyPublishLead = 10 * time.Minute
+	// retiredKeyTTL keeps a superseded key verifiable until the last token it
+	// signed has expired, so rotation never forces a logout.
+	retiredKeyTTL = refreshTokenTTL + time.Hour
+	// keyReloadMinInterval throttles reloads triggered by an unknown kid.
+	keyReloadMinInterval = 30 * time.Second
+)
+
+// KeyStore persists signing keys so every API instance signs and verifies
+// with the same set.
+type KeyStore interface {
+	// ListSigningKeys returns every key that has not expired at now.
+	ListSigningKeys(ctx context.Context, now time.Time) ([]models.JWTSigningKey, error)
+	// RotateSigningKey stores next and schedules the keys it supersedes to
+	// expire at supersededExpiresAt. It reports false without storing
+	// anything when another key is already waiting to activate.
+	RotateSigningKey(ctx context.Context, next *models.JWTSigningKey, supersededExpiresAt, now time.Time) (bool, error)
+}
 
+// Signer issues and verifies tokens. Without a key store it signs with the
+// HMAC secret; once EnableKeyRotation is called it signs with the active
+// asymmetric key and only accepts HMAC tokens issued before that, until the
+// last of them has expired.
 type Signer struct {
 	secret string
+	// hmacCutoff, when set, stops HMAC tokens being accepted earlier than
+	// their natural expiry once key rotation is on.
+	hmacCutoff time.Time
+
+	store       KeyStore
+	kek         []byte
+	algorithm   string
+	rotateEvery time.Duration
+
+	mu         sync.RWMutex
+	keys       []*signingKey
+	reloadedAt time.Time
 }
 
 func NewSigner(secret string) *Signer {
 	return &Signer{secret: secret}
 }
 
+// EnableKeyRotation switches signing to asymmetric keys held in store. The
+// private keys are sealed with encryptionKey. A first key is created when the
+// store is empty.
+func (s *Signer) EnableKeyRotation(ctx context.Context, store KeyStore, encryptionKey, algorithm string, rotateEvery time.Duration) error {
+	if store == nil || encryptionKey == "" {
+		return errors.New("jwt key store and encryption key are required")
+	}
+	if _, err := signingMethod(algorithm); err != nil {
+		return err
+	}
+	if rotateEvery <= keyPublishLead {
+		return errors.New("jwt key rotation interval is too short")
+	}
+
+	s.store = store
+	s.kek = keyEncryptionKey(encryptionKey)
+	s.algorithm = algorithm
+	s.rotateEvery = rotateEvery
+
+	if err := s.ReloadKeys(ctx); err != nil {
+		return err
+	}
+	if _, ok := s.activeKey(time.Now().UTC()); ok {
+		return nil
+	}
+	return s.rotate(ctx, time.Now().UTC(), 0)
+}
+
+// AcceptHMACUntil stops verifying HMAC tokens at cutoff once key rotation is
+// enabled, even if some issued before rotation have not expired yet.
+func (s *Signer) AcceptHMACUntil(cutoff time.Time) {
+	s.hmacCutoff = cutoff
+}
+
+// ReloadKeys refreshes the key set from the store.
+func (s *Signer) ReloadKeys(ctx context.Context) error {
+	if s.store == nil {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	rows, err := s.store.ListSigningKeys(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]*signingKey, 0, len(rows))
+	for _, row := range rows {
+		key, err := signingKeyFromModel(row, s.kek)
+		if err != nil {
+			log.Printf("jwt signer: skipping key %s: %v", row.KID, err)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].activatesAt.Before(keys[j].activatesAt) })
+
+	s.mu.Lock()
+	s.keys = keys
+	s.reloadedAt = now
+	s.mu.Unlock()
+	return nil
+}
+
+// RotateKeys reloads the key set and, once the active key is older than the
+// rotation interval, publishes its successor. The successor starts signing
+// after keyPublishLead; the old key stays valid for verification until every
+// token it signed has expired.
+func (s *Signer) RotateKeys(ctx context.Context) error {
+	if s.store == nil {
+		return nil
+	}
+	if err := s.ReloadKeys(ctx); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	s.mu.RLock()
+	var newest *signingKey
+	if len(s.keys) > 0 {
+		newest = s.keys[len(s.keys)-1]
+	}
+	s.mu.RUnlock()
+
+	if newest != nil && (newest.activatesAt.After(now) || now.Sub(newest.activatesAt) < s.rotateEvery) {
+		return nil
+	}
+	return s.rotate(ctx, now, keyPublishLead)
+}
+
+func (s *Signer) rotate(ctx context.Context, now time.Time, lead time.Duration) error {
+	next, err := generateSigningKey(s.algorithm, now.Add(lead))
+	if err != nil {
+		return err
+	}
+	row, err := next.toModel(s.kek)
+	if err != nil {
+		return err
+	}
+
+	stored, err := s.store.RotateSigningKey(ctx, row, next.activatesAt.Add(retiredKeyTTL), now)
+	if err != nil {
+		return err
+	}
+	if stored {
+		log.Printf("jwt signer: published key %s, active from %s", next.kid, next.activatesAt.Format(time.RFC3339))
+	}
+	return s.ReloadKeys(ctx)
+}
+
+// JWKS returns the public keys tokens may currently be signed with,
+// including a successor that has been published but is not yet active.
+func (s *Signer) JWKS() JWKS {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now().UTC()
+	out := JWKS{Keys: make([]JWK, 0, len(s.keys))}
+	for _, key := range s.keys {
+		if key.expiresAt != nil && !key.expiresAt.After(now) {
+			continue
+		}
+		jwk, err := key.jwk()
+		if err != nil {
+			log.Printf("jwt signer: failed to encode key %s: %v", key.kid, err)
+			continue
+		}
+		out.Keys = append(out.Keys, jwk)
+	}
+	return out
+}
+
+func (s *Signer) activeKey(now time.Time) (*signingKey, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for i := len(s.keys) - 1; i >= 0; i-- {
+		if !s.keys[i].activatesAt.After(now) {
+			return s.keys[i], true
+		}
+	}
+	return nil, false
+}
+
+func (s *Signer) lookupKey(kid string) (verifyKey, bool) {
+	find := func() (verifyKey, bool) {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		now := time.Now().UTC()
+		for _, key := range s.keys {
+			if key.kid == kid && (key.expiresAt == nil || key.expiresAt.After(now)) {
+				return key.verifyKey, true
+			}
+		}
+		return verifyKey{}, false
+	}
+
+	if key, ok := find(); ok {
+		return key, true
+	}
+
+	// Another instance may have published a key we haven't loaded yet.
+	s.mu.RLock()
+	stale := time.Since(s.reloadedAt) >= keyReloadMinInterval
+	s.mu.RUnlock()
+	if s.store == nil || !stale {
+		return verifyKey{}, false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.ReloadKeys(ctx); err != nil {
+		log.Printf("jwt signer: failed to reload keys: %v", err)
+		return verifyKey{}, false
+	}
+	return find()
+}
+
+func (s *Signer) sign(claims jwt.MapClaims) (string, error) {
+	if key, ok := s.activeKey(time.Now().UTC()); ok {
+		method, err := signingMethod(key.algorithm)
+		if err != nil {
+			return "", err
+		}
+		token := jwt.NewWithClaims(method, claims)
+		token.Header["kid"] = key.kid
+		return token.SignedString(key.private)
+	}
+
+	if s.store != nil {
+		return "", errors.New("no active jwt signing key")
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(s.secret))
+}
+
 // IssueAccessToken takes userID and sessionID and returns access token or error if any
 func (s *Signer) IssueAccessToken(userID, sid string) (string, error) {
 	now := time.Now().UTC()
-	expiresAt := now.Add(15 * time.Minute)
+	expiresAt := now.Add(accessTokenTTL)
 	claims := jwt.MapClaims{
 		"sub": userID,
 		"sid": sid,
@@ -39,14 +276,13 @@ func (s *Signer) IssueAccessToken(userID, sid string) (string, error) {
 		"exp": expiresAt.Unix(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.secret))
+	return s.sign(claims)
 }
 
 // IssueRefreshToken takes userID, sessionID and jti and returns refresh token or error if any
 func (s *Signer) IssueRefreshToken(userID, sid string) (string, string, time.Time, error) {
 	now := time.Now().UTC()
-	expiresAt := now.Add(30 * 24 * time.Hour)
+	expiresAt := now.Add(refreshTokenTTL)
 
 	jti := uuid.NewString()
 
@@ -59,9 +295,7 @@ func (s *Signer) IssueRefreshToken(userID, sid string) (string, string, time.Tim
 		"exp": expiresAt.Unix(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	signedToken, err := token.SignedString([]byte(s.secret))
+	signedToken, err := s.sign(claims)
 	if err != nil {
 		return "", "", time.Time{}, err
 	}
@@ -71,14 +305,66 @@ func (s *Signer) IssueRefreshToken(userID, sid string) (string, string, time.Tim
 
 // ParseAndValidate takes tokenString and expectedType and returns claims or error if any
 func (s *Signer) ParseAndValidate(tokenString string, expectedType auth.TokenType) (jwt.MapClaims, error) {
-	parser := jwt.NewParser(
-		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
-	)
+	methods := []string{jwt.SigningMethodES256.Alg(), jwt.SigningMethodRS256.Alg()}
+	if s.secret != "" {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
 
-	token, err := parser.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
-		return []byte(s.secret), nil
+	byKID := keyfuncFor(s.lookupKey)
+	return parseClaims(tokenString, expectedType, methods, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); ok {
+			if !s.acceptsHMAC(t.Claims, time.Now().UTC()) {
+				return nil, errors.New("hmac tokens are no longer accepted")
+			}
+			return []byte(s.secret), nil
+		}
+		return byKID(t)
 	})
+}
+
+// acceptsHMAC reports whether an HMAC token with claims may still be verified.
+// Once key rotation is on, only tokens issued before the first asymmetric key
+// started signing qualify, and none do after the configured cutoff or once a
+// refresh token issued then would have expired. The lifetime check stops
+// anyone holding the secret from minting a backdated token that never expires.
+func (s *Signer) acceptsHMAC(claims jwt.Claims, now time.Time) bool {
+	if s.store == nil {
+		return true
+	}
+
+	s.mu.RLock()
+	var rotatedAt time.Time
+	if len(s.keys) > 0 {
+		rotatedAt = s.keys[0].activatesAt
+	}
+	s.mu.RUnlock()
+	if rotatedAt.IsZero() {
+		return false
+	}
+
+	cutoff := rotatedAt.Add(refreshTokenTTL)
+	if !s.hmacCutoff.IsZero() && s.hmacCutoff.Before(cutoff) {
+		cutoff = s.hmacCutoff
+	}
+	if !now.Before(cutoff) {
+		return false
+	}
 
+	issuedAt, err := claims.GetIssuedAt()
+	if err != nil || issuedAt == nil {
+		return false
+	}
+	expiresAt, err := claims.GetExpirationTime()
+	if err != nil || expiresAt == nil {
+		return false
+	}
+	return !issuedAt.After(rotatedAt) && expiresAt.Sub(issuedAt.Time) <= refreshTokenTTL
+}
+
+func parseClaims(tokenString string, expectedType auth.TokenType, methods []string, keyfunc jwt.Keyfunc) (jwt.MapClaims, error) {
+	parser := jwt.NewParser(jwt.WithValidMethods(methods))
+
+	token, err := parser.Parse(tokenString, keyfunc)
 	if err != nil || token == nil || !token.Valid {
 		return nil, errors.New("invalid token")
 	}
@@ -152,6 +438,10 @@ func (s *Signer) ExtractAccessTokenIdentifiers(tokenString string) (sub string,
 		return "", "", err
 	}
 
+	return accessTokenIdentifiers(claims)
+}
+
+func accessTokenIdentifiers(claims jwt.MapClaims) (sub string, sid string, err error) {
 	sub, ok := claims["sub"].(string)
 	if !ok || sub == "" {
 		return "", "", errors.New("invalid subject")
@@ -0,0 +1,235 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"neat_mobile_app_backend/models"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type memoryKeyStore struct {
+	keys map[string]models.JWTSigningKey
+}
+
+func newMemoryKeyStore() *memoryKeyStore {
+	return &memoryKeyStore{keys: map[string]models.JWTSigningKey{}}
+}
+
+func (m *memoryKeyStore) ListSigningKeys(_ context.Context, now time.Time) ([]models.JWTSigningKey, error) {
+	var out []models.JWTSigningKey
+	for _, key := range m.keys {
+		if key.ExpiresAt == nil || key.ExpiresAt.After(now) {
+			out = append(out, key)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ActivatesAt.Before(out[j].ActivatesAt) })
+	return out, nil
+}
+
+func (m *memoryKeyStore) RotateSigningKey(_ context.Context, next *models.JWTSigningKey, supersededExpiresAt, now time.Time) (bool, error) {
+	for _, key := range m.keys {
+		if key.ExpiresAt == nil && key.ActivatesAt.After(now) {
+			return false, nil
+		}
+	}
+	for kid, key := range m.keys {
+		if key.ExpiresAt == nil {
+			key.ExpiresAt = &supersededExpiresAt
+			m.keys[kid] = key
+		}
+	}
+	m.keys[next.KID] = *next
+	return true, nil
+}
+
+// activateAll pretends the publish lead has passed for every stored key.
+func (m *memoryKeyStore) activateAll(at time.Time) {
+	for kid, key := range m.keys {
+		key.ActivatesAt = at
+		m.keys[kid] = key
+	}
+}
+
+func newRotatingSigner(t *testing.T, store *memoryKeyStore, algorithm string) *Signer {
+	t.Helper()
+
+	signer := NewSigner("legacy-secret")
+	if err := signer.EnableKeyRotation(context.Background(), store, "key-encryption-key", algorithm, time.Hour); err != nil {
+		t.Fatalf("EnableKeyRotation returned error: %v", err)
+	}
+	return signer
+}
+
+func tokenKID(t *testing.T, token string) string {
+	t.Helper()
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		t.Fatalf("parse token header: %v", err)
+	}
+	kid, _ := parsed.Header["kid"].(string)
+	return kid
+}
+
+func TestSignerIssuesAsymmetricTokensWithKID(t *testing.T) {
+	for _, algorithm := range []string{AlgorithmES256, AlgorithmRS256} {
+		t.Run(algorithm, func(t *testing.T) {
+			signer := newRotatingSigner(t, newMemoryKeyStore(), algorithm)
+
+			token, err := signer.IssueAccessToken("user-1", "sid-1")
+			if err != nil {
+				t.Fatalf("IssueAccessToken returned error: %v", err)
+			}
+			if tokenKID(t, token) == "" {
+				t.Fatal("expected token to carry a kid header")
+			}
+
+			sub, sid, err := signer.ExtractAccessTokenIdentifiers(token)
+			if err != nil || sub != "user-1" || sid != "sid-1" {
+				t.Fatalf("unexpected identifiers: sub=%q sid=%q err=%v", sub, sid, err)
+			}
+		})
+	}
+}
+
+func TestSignerRotationKeepsOldTokensValid(t *testing.T) {
+	store := newMemoryKeyStore()
+	signer := newRotatingSigner(t, store, AlgorithmES256)
+
+	oldToken, err := signer.IssueAccessToken("user-1", "sid-1")
+	if err != nil {
+		t.Fatalf("IssueAccessToken returned error: %v", err)
+	}
+
+	// Age the active key past the rotation interval.
+	store.activateAll(time.Now().UTC().Add(-2 * time.Hour))
+	if err := signer.RotateKeys(context.Background()); err != nil {
+		t.Fatalf("RotateKeys returned error: %v", err)
+	}
+	if len(signer.JWKS().Keys) != 2 {
+		t.Fatalf("expected successor to be published alongside the active key, got %d keys", len(signer.JWKS().Keys))
+	}
+
+	pendingToken, err := signer.IssueAccessToken("user-1", "sid-1")
+	if err != nil {
+		t.Fatalf("IssueAccessToken returned error: %v", err)
+	}
+	if tokenKID(t, pendingToken) != tokenKID(t, oldToken) {
+		t.Fatal("successor key must not sign before its publish lead has passed")
+	}
+
+	// Let the successor's publish lead pass.
+	for kid, key := range store.keys {
+		if key.ExpiresAt == nil {
+			key.ActivatesAt = time.Now().UTC().Add(-time.Minute)
+			store.keys[kid] = key
+		}
+	}
+	if err := signer.ReloadKeys(context.Background()); err != nil {
+		t.Fatalf("ReloadKeys returned error: %v", err)
+	}
+
+	newToken, err := signer.IssueAccessToken("user-1", "sid-1")
+	if err != nil {
+		t.Fatalf("IssueAccessToken returned error: %v", err)
+	}
+	if tokenKID(t, newToken) == tokenKID(t, oldToken) {
+		t.Fatal("expected the successor key to sign after activation")
+	}
+	if !signer.ValidAccessToken(oldToken) {
+		t.Fatal("token signed with the superseded key should still verify")
+	}
+	if !signer.ValidAccessToken(newToken) {
+		t.Fatal("token signed with the new key should verify")
+	}
+}
+
+func TestSignerAcceptsLegacyHMACTokens(t *testing.T) {
+	legacy := NewSigner("legacy-secret")
+	token, err := legacy.IssueAccessToken("user-1", "sid-1")
+	if err != nil {
+		t.Fatalf("IssueAccessToken returned error: %v", err)
+	}
+
+	signer := newRotatingSigner(t, newMemoryKeyStore(), AlgorithmES256)
+	if !signer.ValidAccessToken(token) {
+		t.Fatal("tokens signed before key rotation was enabled should still verify")
+	}
+}
+
+func TestSignerLimitsLegacyHMACTokensAfterRotation(t *testing.T) {
+	signer := newRotatingSigner(t, newMemoryKeyStore(), AlgorithmES256)
+	now := time.Now().UTC()
+
+	hmacToken := func(issuedAt, expiresAt time.Time) string {
+		t.Helper()
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sub": "user-1",
+			"sid": "sid-1",
+			"typ": TokenTypeAccess,
+			"iat": issuedAt.Unix(),
+			"exp": expiresAt.Unix(),
+		}).SignedString([]byte("legacy-secret"))
+		if err != nil {
+			t.Fatalf("sign hmac token: %v", err)
+		}
+		return token
+	}
+
+	if !signer.ValidAccessToken(hmacToken(now.Add(-time.Minute), now.Add(time.Minute))) {
+		t.Fatal("an hmac token issued before rotation should still verify")
+	}
+	if signer.ValidAccessToken(hmacToken(now.Add(time.Minute), now.Add(time.Hour))) {
+		t.Fatal("an hmac token issued after rotation must not verify")
+	}
+	if signer.ValidAccessToken(hmacToken(now.Add(-time.Minute), now.Add(365*24*time.Hour))) {
+		t.Fatal("an hmac token living longer than a refresh token must not verify")
+	}
+
+	signer.AcceptHMACUntil(now.Add(-time.Second))
+	if signer.ValidAccessToken(hmacToken(now.Add(-time.Minute), now.Add(time.Minute))) {
+		t.Fatal("hmac tokens must not verify after the configured cutoff")
+	}
+}
+
+func TestJWKSVerifierChecksTokensWithPublicKeys(t *testing.T) {
+	signer := newRotatingSigner(t, newMemoryKeyStore(), AlgorithmES256)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(signer.JWKS())
+	}))
+	defer server.Close()
+
+	verifier := NewJWKSVerifier(server.URL)
+
+	token, err := signer.IssueAccessToken("user-1", "sid-1")
+	if err != nil {
+		t.Fatalf("IssueAccessToken returned error: %v", err)
+	}
+	sub, sid, err := verifier.ExtractAccessTokenIdentifiers(token)
+	if err != nil || sub != "user-1" || sid != "sid-1" {
+		t.Fatalf("unexpected identifiers: sub=%q sid=%q err=%v", sub, sid, err)
+	}
+
+	refresh, _, _, err := signer.IssueRefreshToken("user-1", "sid-1")
+	if err != nil {
+		t.Fatalf("IssueRefreshToken returned error: %v", err)
+	}
+	if verifier.ValidAccessToken(refresh) {
+		t.Fatal("refresh tokens must not pass as access tokens")
+	}
+
+	legacy, err := NewSigner("legacy-secret").IssueAccessToken("user-1", "sid-1")
+	if err != nil {
+		t.Fatalf("IssueAccessToken returned error: %v", err)
+	}
+	if verifier.ValidAccessToken(legacy) {
+		t.Fatal("JWKS verifier must reject HMAC tokens")
+	}
+}